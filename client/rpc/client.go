@@ -0,0 +1,161 @@
+// Package rpc is a Go client of the RPC API of the daemon.
+//
+// The methods of Client are generated from the definitions of the commands
+// the daemon serves, see internal/gen: each command takes the same
+// arguments and options as on the command line, and returns the values it
+// emits, decoded into copies of their Go types: the client doesn't link the
+// daemon. Run 'go generate' in this directory after adding or changing a
+// command; the tests fail while the client is out of date.
+package rpc
+
+//go:generate go run ./gen -o commands.go
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	cmdhttp "github.com/ipfs/go-ipfs-cmds/http"
+	files "github.com/ipfs/go-ipfs-files"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+// apiPath is the path at which the daemon serves the API.
+const apiPath = "/api/v0"
+
+// Client calls the commands of a daemon over its RPC API.
+type Client struct {
+	exe cmds.Executor
+}
+
+// NewClient returns a client of the RPC API listening on addr, either a
+// multiaddr like /ip4/127.0.0.1/tcp/5001 or a host:port.
+func NewClient(addr string, opts ...cmdhttp.ClientOpt) (*Client, error) {
+	host := addr
+	var clientOpts []cmdhttp.ClientOpt
+	if strings.HasPrefix(addr, "/") {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return nil, err
+		}
+		network, h, err := manet.DialArgs(maddr)
+		if err != nil {
+			return nil, err
+		}
+		switch network {
+		case "tcp", "tcp4", "tcp6":
+			host = h
+		case "unix":
+			host = "unix"
+			clientOpts = append(clientOpts, cmdhttp.ClientWithHTTPClient(&http.Client{
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						var d net.Dialer
+						return d.DialContext(ctx, "unix", h)
+					},
+				},
+			}))
+		default:
+			return nil, fmt.Errorf("unsupported API address: %s", addr)
+		}
+	}
+
+	clientOpts = append(clientOpts, cmdhttp.ClientWithAPIPrefix(apiPath))
+	return &Client{exe: cmdhttp.NewClient(host, append(clientOpts, opts...)...)}, nil
+}
+
+// call runs the command at path, defined by cmd, on the daemon. The files of
+// the file arguments are sent as the entries of a directory, like the CLI
+// does.
+func (c *Client) call(ctx context.Context, path []string, cmd *cmds.Command, opts cmds.OptMap, args []string, nodes []files.Node) (*Response, error) {
+	var dir files.Directory
+	if len(nodes) > 0 {
+		entries := make([]files.DirEntry, len(nodes))
+		for i, nd := range nodes {
+			entries[i] = files.FileEntry("", nd)
+		}
+		dir = files.NewSliceDirectory(entries)
+	}
+
+	// the request resolves the command from the root
+	root := cmd
+	for i := len(path) - 1; i >= 0; i-- {
+		root = &cmds.Command{Subcommands: map[string]*cmds.Command{path[i]: root}}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	req, err := cmds.NewRequest(ctx, path, opts, args, dir, root)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	re, res := cmds.NewChanResponsePair(req)
+	go func() {
+		// the emitter is already closed when the command succeeded
+		_ = re.CloseWithError(c.exe.Execute(req, re, nil))
+	}()
+	return &Response{res: res, cancel: cancel}, nil
+}
+
+// Response is the output of a command.
+type Response struct {
+	res    cmds.Response
+	cancel context.CancelFunc
+	reader io.Reader
+}
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r *Response) Next() (interface{}, error) {
+	return r.res.Next()
+}
+
+// Read reads the output of the commands that emit a stream of bytes, like
+// Cat.
+func (r *Response) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		v, err := r.res.Next()
+		if err != nil {
+			return 0, err
+		}
+		rd, ok := v.(io.Reader)
+		if !ok {
+			return 0, fmt.Errorf("the command emitted a %T, not a stream of bytes", v)
+		}
+		r.reader = rd
+	}
+	return r.reader.Read(p)
+}
+
+// Close stops the command, and releases the response.
+func (r *Response) Close() error {
+	r.cancel()
+	return nil
+}
+
+// Bool returns a pointer to b, to set boolean options.
+func Bool(b bool) *bool { return &b }
+
+// Int returns a pointer to i, to set integer options.
+func Int(i int) *int { return &i }
+
+// Int64 returns a pointer to i, to set 64-bit integer options.
+func Int64(i int64) *int64 { return &i }
+
+// Uint returns a pointer to i, to set unsigned integer options.
+func Uint(i uint) *uint { return &i }
+
+// Uint64 returns a pointer to i, to set 64-bit unsigned integer options.
+func Uint64(i uint64) *uint64 { return &i }
+
+// Float64 returns a pointer to f, to set float options.
+func Float64(f float64) *float64 { return &f }
+
+// String returns a pointer to s, to set string options.
+func String(s string) *string { return &s }
@@ -0,0 +1,138 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	syncds "github.com/ipfs/go-datastore/sync"
+	files "github.com/ipfs/go-ipfs-files"
+
+	"github.com/ipfs/go-ipfs/client/rpc/internal/gen"
+	oldcmds "github.com/ipfs/go-ipfs/commands"
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/core/commands"
+	"github.com/ipfs/go-ipfs/core/corehttp"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+func TestGenerated(t *testing.T) {
+	src, err := gen.Generate(commands.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := ioutil.ReadFile("commands.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, current) {
+		t.Fatal("commands.go is out of date, run 'go generate' in client/rpc")
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	// the go command of the toolchain running the tests
+	gocmd := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(gocmd); err != nil {
+		t.Skip("the go command is not installed")
+	}
+	out, err := exec.Command(gocmd, "list", "-deps", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	for _, pkg := range strings.Fields(string(out)) {
+		if strings.HasPrefix(pkg, "github.com/ipfs/go-ipfs/") && pkg != "github.com/ipfs/go-ipfs/client/rpc" {
+			t.Errorf("the client links %s of the daemon", pkg)
+		}
+	}
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	r := &repo.Mock{
+		C: config.Config{
+			Identity: config.Identity{
+				PeerID: "QmTFauExutTsy4XP6JbMFcw2Wa9645HJt2bTqL6qYDCKfe", // required by offline node
+			},
+		},
+		D: syncds.MutexWrap(datastore.NewMapDatastore()),
+	}
+	n, err := core.NewNode(context.Background(), &core.BuildCfg{Repo: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { n.Close() })
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cctx := oldcmds.Context{
+		ReqLog:        &oldcmds.ReqLog{},
+		ConstructNode: func() (*core.IpfsNode, error) { return n, nil },
+	}
+	go corehttp.Serve(n, l, corehttp.CommandsOption(cctx))
+
+	c, err := NewClient(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	res, err := c.Add(ctx, []files.Node{files.NewBytesFile([]byte("hello"))}, &AddOptions{
+		Pin:      Bool(false),
+		Progress: Bool(false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+	added, err := res.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Hash == "" {
+		t.Fatalf("expected the hash of the added file, got %+v", added)
+	}
+	if _, err := res.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF after the last value, got %v", err)
+	}
+
+	cat, err := c.Cat(ctx, []string{added.Hash}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cat.Close()
+	data, err := ioutil.ReadAll(cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("expected hello, got %q", data)
+	}
+
+	// errors of the commands are returned by Next
+	pins, err := c.PinLs(ctx, []string{added.Hash}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pins.Close()
+	if _, err := pins.Next(); err == nil {
+		t.Fatal("expected an error listing a file that is not pinned")
+	}
+}
@@ -0,0 +1,8108 @@
+// Code generated by internal/gen. DO NOT EDIT.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	files "github.com/ipfs/go-ipfs-files"
+	"github.com/libp2p/go-libp2p-core/metrics"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+)
+
+// AddResponse is the output of Add.
+type AddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r AddResponse) Next() (*AddEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*AddEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// AddOptions are the options of Add.
+type AddOptions struct {
+	// Chunking algorithm, size-[bytes], rabin-[min]-[avg]-[max] or buzhash. Default: size-262144, or as set in Import.ChunkerByContentType.
+	Chunker *string
+	// CID version. Defaults to 0 unless an option that depends on CIDv1 is passed. Passing version 1 will cause the raw-leaves option to default to true.
+	CIDVersion *int
+	// Symlinks supplied in arguments are dereferenced.
+	DereferenceArgs *bool
+	// Check the filestore for pre-existing blocks. (experimental).
+	Fscache *bool
+	// Hash function to use. Implies CIDv1 if not sha2-256. (experimental). Default: sha2-256.
+	Hash *string
+	// Include files that are hidden. Only takes effect on recursive add.
+	Hidden *bool
+	// A rule (.gitignore-stype) defining which file(s) should be ignored (variadic, experimental).
+	Ignore []string
+	// A path to a file with .gitignore-style ignore rules (experimental).
+	IgnoreRulesPath *string
+	// Inline small blocks into CIDs. (experimental).
+	Inline *bool
+	// Maximum block size to inline. (experimental). Default: 32.
+	InlineLimit *int
+	// Add the file using filestore. Implies raw-leaves. (experimental).
+	Nocopy *bool
+	// Only chunk and hash - do not write to disk.
+	OnlyHash *bool
+	// Pin this object when adding. Default: true.
+	Pin *bool
+	// Stream progress data.
+	Progress *bool
+	// Write minimal output.
+	Quiet *bool
+	// Write only final hash.
+	Quieter *bool
+	// Use raw blocks for leaf nodes.
+	RawLeaves *bool
+	// Add directory paths recursively.
+	Recursive *bool
+	// Write no output.
+	Silent *bool
+	// Assign a name if the file source is stdin.
+	StdinName *string
+	// Use trickle-dag format for dag generation.
+	Trickle *bool
+	// Wrap files with a directory object.
+	WrapWithDirectory *bool
+}
+
+// Add runs 'ipfs add': add a file or directory to IPFS.
+//
+// path: The path to a file to be added to IPFS.
+func (c *Client) Add(ctx context.Context, path []files.Node, opts *AddOptions) (AddResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Chunker != nil {
+			o["chunker"] = *opts.Chunker
+		}
+		if opts.CIDVersion != nil {
+			o["cid-version"] = *opts.CIDVersion
+		}
+		if opts.DereferenceArgs != nil {
+			o["dereference-args"] = *opts.DereferenceArgs
+		}
+		if opts.Fscache != nil {
+			o["fscache"] = *opts.Fscache
+		}
+		if opts.Hash != nil {
+			o["hash"] = *opts.Hash
+		}
+		if opts.Hidden != nil {
+			o["hidden"] = *opts.Hidden
+		}
+		if opts.Ignore != nil {
+			o["ignore"] = opts.Ignore
+		}
+		if opts.IgnoreRulesPath != nil {
+			o["ignore-rules-path"] = *opts.IgnoreRulesPath
+		}
+		if opts.Inline != nil {
+			o["inline"] = *opts.Inline
+		}
+		if opts.InlineLimit != nil {
+			o["inline-limit"] = *opts.InlineLimit
+		}
+		if opts.Nocopy != nil {
+			o["nocopy"] = *opts.Nocopy
+		}
+		if opts.OnlyHash != nil {
+			o["only-hash"] = *opts.OnlyHash
+		}
+		if opts.Pin != nil {
+			o["pin"] = *opts.Pin
+		}
+		if opts.Progress != nil {
+			o["progress"] = *opts.Progress
+		}
+		if opts.Quiet != nil {
+			o["quiet"] = *opts.Quiet
+		}
+		if opts.Quieter != nil {
+			o["quieter"] = *opts.Quieter
+		}
+		if opts.RawLeaves != nil {
+			o["raw-leaves"] = *opts.RawLeaves
+		}
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+		if opts.Silent != nil {
+			o["silent"] = *opts.Silent
+		}
+		if opts.StdinName != nil {
+			o["stdin-name"] = *opts.StdinName
+		}
+		if opts.Trickle != nil {
+			o["trickle"] = *opts.Trickle
+		}
+		if opts.WrapWithDirectory != nil {
+			o["wrap-with-directory"] = *opts.WrapWithDirectory
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	nodes = append(nodes, path...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgFile, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*AddEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"add"}, cmd, o, args, nodes)
+	return AddResponse{res}, err
+}
+
+// BenchmarkResponse is the output of Benchmark.
+type BenchmarkResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BenchmarkResponse) Next() (*BenchmarkReport, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BenchmarkReport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BenchmarkOptions are the options of Benchmark.
+type BenchmarkOptions struct {
+	// CID of a file to fetch from --peer.
+	CID *string
+	// Number of DHT lookups, 0 to skip the DHT benchmark. Default: 3.
+	DHTLookups *int
+	// Peer ID or multiaddr of the peer to fetch --cid from.
+	Peer *string
+	// Size of the data added and read. Default: 16MiB.
+	Size *string
+}
+
+// Benchmark runs 'ipfs benchmark': measure the performance of the node.
+func (c *Client) Benchmark(ctx context.Context, opts *BenchmarkOptions) (BenchmarkResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.CID != nil {
+			o["cid"] = *opts.CID
+		}
+		if opts.DHTLookups != nil {
+			o["dht-lookups"] = *opts.DHTLookups
+		}
+		if opts.Peer != nil {
+			o["peer"] = *opts.Peer
+		}
+		if opts.Size != nil {
+			o["size"] = *opts.Size
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BenchmarkReport)(nil),
+	}
+	res, err := c.call(ctx, []string{"benchmark"}, cmd, o, args, nodes)
+	return BenchmarkResponse{res}, err
+}
+
+// BitswapLedgerResponse is the output of BitswapLedger.
+type BitswapLedgerResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BitswapLedgerResponse) Next() (*DecisionReceipt, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*DecisionReceipt)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BitswapLedger runs 'ipfs bitswap ledger': show the current ledger for a peer.
+//
+// peer: The PeerID (B58) of the ledger to inspect.
+func (c *Client) BitswapLedger(ctx context.Context, peer string) (BitswapLedgerResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, peer)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peer", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*DecisionReceipt)(nil),
+	}
+	res, err := c.call(ctx, []string{"bitswap", "ledger"}, cmd, o, args, nodes)
+	return BitswapLedgerResponse{res}, err
+}
+
+// BitswapReprovide runs 'ipfs bitswap reprovide': trigger reprovider.
+func (c *Client) BitswapReprovide(ctx context.Context) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{}
+	res, err := c.call(ctx, []string{"bitswap", "reprovide"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// BitswapStatResponse is the output of BitswapStat.
+type BitswapStatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BitswapStatResponse) Next() (*BitswapStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BitswapStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BitswapStatOptions are the options of BitswapStat.
+type BitswapStatOptions struct {
+	// Print sizes in human readable format (e.g., 1K 234M 2G).
+	Human *bool
+	// Print extra information.
+	Verbose *bool
+}
+
+// BitswapStat runs 'ipfs bitswap stat': show some diagnostic information on the bitswap agent.
+func (c *Client) BitswapStat(ctx context.Context, opts *BitswapStatOptions) (BitswapStatResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Human != nil {
+			o["human"] = *opts.Human
+		}
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BitswapStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"bitswap", "stat"}, cmd, o, args, nodes)
+	return BitswapStatResponse{res}, err
+}
+
+// BitswapWantlistResponse is the output of BitswapWantlist.
+type BitswapWantlistResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BitswapWantlistResponse) Next() (*KeyList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*KeyList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BitswapWantlistOptions are the options of BitswapWantlist.
+type BitswapWantlistOptions struct {
+	// Specify which peer to show wantlist for. Default: self.
+	Peer *string
+}
+
+// BitswapWantlist runs 'ipfs bitswap wantlist': show blocks currently on the wantlist.
+func (c *Client) BitswapWantlist(ctx context.Context, opts *BitswapWantlistOptions) (BitswapWantlistResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Peer != nil {
+			o["peer"] = *opts.Peer
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*KeyList)(nil),
+	}
+	res, err := c.call(ctx, []string{"bitswap", "wantlist"}, cmd, o, args, nodes)
+	return BitswapWantlistResponse{res}, err
+}
+
+// BlockGet runs 'ipfs block get': get a raw IPFS block.
+//
+// cid: The CID of an existing block to get.
+func (c *Client) BlockGet(ctx context.Context, cid string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"block", "get"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// BlockPutResponse is the output of BlockPut.
+type BlockPutResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BlockPutResponse) Next() (*BlockStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BlockStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BlockPutOptions are the options of BlockPut.
+type BlockPutOptions struct {
+	// Disable block size check and allow creation of blocks bigger than 1MiB. WARNING: such blocks won't be transferable over the standard bitswap. Default: false.
+	AllowBigBlock *bool
+	// Multicodec to use in returned CID. Default: raw.
+	CIDCodec *string
+	// Use legacy format for returned CID (DEPRECATED).
+	Format *string
+	// Multihash hash length. Default: -1.
+	Mhlen *int
+	// Multihash hash function. Default: sha2-256.
+	Mhtype *string
+	// Pin added blocks recursively. Default: false.
+	Pin *bool
+}
+
+// BlockPut runs 'ipfs block put': store input as an IPFS block.
+//
+// data: The data to be stored as an IPFS block.
+func (c *Client) BlockPut(ctx context.Context, data []files.Node, opts *BlockPutOptions) (BlockPutResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowBigBlock != nil {
+			o["allow-big-block"] = *opts.AllowBigBlock
+		}
+		if opts.CIDCodec != nil {
+			o["cid-codec"] = *opts.CIDCodec
+		}
+		if opts.Format != nil {
+			o["format"] = *opts.Format
+		}
+		if opts.Mhlen != nil {
+			o["mhlen"] = *opts.Mhlen
+		}
+		if opts.Mhtype != nil {
+			o["mhtype"] = *opts.Mhtype
+		}
+		if opts.Pin != nil {
+			o["pin"] = *opts.Pin
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	nodes = append(nodes, data...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "data", Type: cmds.ArgFile, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*BlockStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"block", "put"}, cmd, o, args, nodes)
+	return BlockPutResponse{res}, err
+}
+
+// BlockRmResponse is the output of BlockRm.
+type BlockRmResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BlockRmResponse) Next() (*RemovedBlock, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RemovedBlock)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BlockRmOptions are the options of BlockRm.
+type BlockRmOptions struct {
+	// Ignore nonexistent blocks.
+	Force *bool
+	// Write minimal output.
+	Quiet *bool
+}
+
+// BlockRm runs 'ipfs block rm': remove IPFS block(s) from the local datastore.
+//
+// cid: CIDs of block(s) to remove.
+func (c *Client) BlockRm(ctx context.Context, cid []string, opts *BlockRmOptions) (BlockRmResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Force != nil {
+			o["force"] = *opts.Force
+		}
+		if opts.Quiet != nil {
+			o["quiet"] = *opts.Quiet
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+		Type: (*RemovedBlock)(nil),
+	}
+	res, err := c.call(ctx, []string{"block", "rm"}, cmd, o, args, nodes)
+	return BlockRmResponse{res}, err
+}
+
+// BlockStatResponse is the output of BlockStat.
+type BlockStatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BlockStatResponse) Next() (*BlockStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BlockStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BlockStat runs 'ipfs block stat': print information of a raw IPFS block.
+//
+// cid: The CID of an existing block to stat.
+func (c *Client) BlockStat(ctx context.Context, cid string) (BlockStatResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*BlockStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"block", "stat"}, cmd, o, args, nodes)
+	return BlockStatResponse{res}, err
+}
+
+// BootstrapResponse is the output of Bootstrap.
+type BootstrapResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BootstrapResponse) Next() (*BootstrapOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BootstrapOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// Bootstrap runs 'ipfs bootstrap': show or edit the list of bootstrap peers.
+func (c *Client) Bootstrap(ctx context.Context) (BootstrapResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BootstrapOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"bootstrap"}, cmd, o, args, nodes)
+	return BootstrapResponse{res}, err
+}
+
+// BootstrapAddResponse is the output of BootstrapAdd.
+type BootstrapAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BootstrapAddResponse) Next() (*BootstrapOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BootstrapOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BootstrapAddOptions are the options of BootstrapAdd.
+type BootstrapAddOptions struct {
+	// Add default bootstrap nodes. (Deprecated, use 'default' subcommand instead).
+	Default *bool
+}
+
+// BootstrapAdd runs 'ipfs bootstrap add': add peers to the bootstrap list.
+//
+// peer: A peer to add to the bootstrap list (in the format '<multiaddr>/<peerID>')
+func (c *Client) BootstrapAdd(ctx context.Context, peer []string, opts *BootstrapAddOptions) (BootstrapAddResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Default != nil {
+			o["default"] = *opts.Default
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, peer...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peer", Type: cmds.ArgString, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*BootstrapOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"bootstrap", "add"}, cmd, o, args, nodes)
+	return BootstrapAddResponse{res}, err
+}
+
+// BootstrapAddDefaultResponse is the output of BootstrapAddDefault.
+type BootstrapAddDefaultResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BootstrapAddDefaultResponse) Next() (*BootstrapOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BootstrapOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BootstrapAddDefaultOptions are the options of BootstrapAddDefault.
+type BootstrapAddDefaultOptions struct {
+	// Add default bootstrap nodes. (Deprecated, use 'default' subcommand instead).
+	Default *bool
+}
+
+// BootstrapAddDefault runs 'ipfs bootstrap add default': add default peers to the bootstrap list.
+func (c *Client) BootstrapAddDefault(ctx context.Context, opts *BootstrapAddDefaultOptions) (BootstrapAddDefaultResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Default != nil {
+			o["default"] = *opts.Default
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BootstrapOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"bootstrap", "add", "default"}, cmd, o, args, nodes)
+	return BootstrapAddDefaultResponse{res}, err
+}
+
+// BootstrapListResponse is the output of BootstrapList.
+type BootstrapListResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BootstrapListResponse) Next() (*BootstrapOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BootstrapOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BootstrapList runs 'ipfs bootstrap list': show peers in the bootstrap list.
+func (c *Client) BootstrapList(ctx context.Context) (BootstrapListResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BootstrapOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"bootstrap", "list"}, cmd, o, args, nodes)
+	return BootstrapListResponse{res}, err
+}
+
+// BootstrapRmResponse is the output of BootstrapRm.
+type BootstrapRmResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BootstrapRmResponse) Next() (*BootstrapOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BootstrapOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BootstrapRmOptions are the options of BootstrapRm.
+type BootstrapRmOptions struct {
+	// Remove all bootstrap peers. (Deprecated, use 'all' subcommand).
+	All *bool
+}
+
+// BootstrapRm runs 'ipfs bootstrap rm': remove peers from the bootstrap list.
+//
+// peer: A peer to add to the bootstrap list (in the format '<multiaddr>/<peerID>')
+func (c *Client) BootstrapRm(ctx context.Context, peer []string, opts *BootstrapRmOptions) (BootstrapRmResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.All != nil {
+			o["all"] = *opts.All
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, peer...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peer", Type: cmds.ArgString, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*BootstrapOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"bootstrap", "rm"}, cmd, o, args, nodes)
+	return BootstrapRmResponse{res}, err
+}
+
+// BootstrapRmAllResponse is the output of BootstrapRmAll.
+type BootstrapRmAllResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r BootstrapRmAllResponse) Next() (*BootstrapOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BootstrapOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// BootstrapRmAllOptions are the options of BootstrapRmAll.
+type BootstrapRmAllOptions struct {
+	// Remove all bootstrap peers. (Deprecated, use 'all' subcommand).
+	All *bool
+}
+
+// BootstrapRmAll runs 'ipfs bootstrap rm all': remove all peers from the bootstrap list.
+func (c *Client) BootstrapRmAll(ctx context.Context, opts *BootstrapRmAllOptions) (BootstrapRmAllResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.All != nil {
+			o["all"] = *opts.All
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BootstrapOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"bootstrap", "rm", "all"}, cmd, o, args, nodes)
+	return BootstrapRmAllResponse{res}, err
+}
+
+// CatOptions are the options of Cat.
+type CatOptions struct {
+	// Maximum number of bytes to read.
+	Length *int64
+	// Byte offset to begin reading from.
+	Offset *int64
+	// Stream progress data. Default: true.
+	Progress *bool
+}
+
+// Cat runs 'ipfs cat': show IPFS object data.
+//
+// ipfsPath: The path to the IPFS object(s) to be outputted.
+func (c *Client) Cat(ctx context.Context, ipfsPath []string, opts *CatOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Length != nil {
+			o["length"] = *opts.Length
+		}
+		if opts.Offset != nil {
+			o["offset"] = *opts.Offset
+		}
+		if opts.Progress != nil {
+			o["progress"] = *opts.Progress
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"cat"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// CIDBase32Response is the output of CIDBase32.
+type CIDBase32Response struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r CIDBase32Response) Next() (*CidFormatRes, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*CidFormatRes)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// CIDBase32 runs 'ipfs cid base32': convert CIDs to Base32 CID version 1.
+//
+// cid: CIDs to convert.
+func (c *Client) CIDBase32(ctx context.Context, cid []string) (CIDBase32Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*CidFormatRes)(nil),
+	}
+	res, err := c.call(ctx, []string{"cid", "base32"}, cmd, o, args, nodes)
+	return CIDBase32Response{res}, err
+}
+
+// CIDBasesResponse is the output of CIDBases.
+type CIDBasesResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r CIDBasesResponse) Next() (*[]CodeAndName, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*[]CodeAndName)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// CIDBasesOptions are the options of CIDBases.
+type CIDBasesOptions struct {
+	// also include numeric codes.
+	Numeric *bool
+	// also include the single letter prefixes in addition to the code.
+	Prefix *bool
+}
+
+// CIDBases runs 'ipfs cid bases': list available multibase encodings.
+func (c *Client) CIDBases(ctx context.Context, opts *CIDBasesOptions) (CIDBasesResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Numeric != nil {
+			o["numeric"] = *opts.Numeric
+		}
+		if opts.Prefix != nil {
+			o["prefix"] = *opts.Prefix
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*[]CodeAndName)(nil),
+	}
+	res, err := c.call(ctx, []string{"cid", "bases"}, cmd, o, args, nodes)
+	return CIDBasesResponse{res}, err
+}
+
+// CIDCodecsResponse is the output of CIDCodecs.
+type CIDCodecsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r CIDCodecsResponse) Next() (*[]CodeAndName, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*[]CodeAndName)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// CIDCodecsOptions are the options of CIDCodecs.
+type CIDCodecsOptions struct {
+	// also include numeric codes.
+	Numeric *bool
+	// list only codecs supported by go-ipfs commands.
+	Supported *bool
+}
+
+// CIDCodecs runs 'ipfs cid codecs': list available CID codecs.
+func (c *Client) CIDCodecs(ctx context.Context, opts *CIDCodecsOptions) (CIDCodecsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Numeric != nil {
+			o["numeric"] = *opts.Numeric
+		}
+		if opts.Supported != nil {
+			o["supported"] = *opts.Supported
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*[]CodeAndName)(nil),
+	}
+	res, err := c.call(ctx, []string{"cid", "codecs"}, cmd, o, args, nodes)
+	return CIDCodecsResponse{res}, err
+}
+
+// CIDFormatResponse is the output of CIDFormat.
+type CIDFormatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r CIDFormatResponse) Next() (*CidFormatRes, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*CidFormatRes)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// CIDFormatOptions are the options of CIDFormat.
+type CIDFormatOptions struct {
+	// Multibase to display CID in.
+	B *string
+	// CID codec to convert to.
+	Codec *string
+	// Printf style format string. Default: %s.
+	F *string
+	// CID version to convert to.
+	V *string
+}
+
+// CIDFormat runs 'ipfs cid format': format and convert a CID in various useful ways.
+//
+// cid: CIDs to format.
+func (c *Client) CIDFormat(ctx context.Context, cid []string, opts *CIDFormatOptions) (CIDFormatResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.B != nil {
+			o["b"] = *opts.B
+		}
+		if opts.Codec != nil {
+			o["codec"] = *opts.Codec
+		}
+		if opts.F != nil {
+			o["f"] = *opts.F
+		}
+		if opts.V != nil {
+			o["v"] = *opts.V
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*CidFormatRes)(nil),
+	}
+	res, err := c.call(ctx, []string{"cid", "format"}, cmd, o, args, nodes)
+	return CIDFormatResponse{res}, err
+}
+
+// CIDHashesResponse is the output of CIDHashes.
+type CIDHashesResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r CIDHashesResponse) Next() (*[]CodeAndName, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*[]CodeAndName)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// CIDHashesOptions are the options of CIDHashes.
+type CIDHashesOptions struct {
+	// also include numeric codes.
+	Numeric *bool
+	// list only codecs supported by go-ipfs commands.
+	Supported *bool
+}
+
+// CIDHashes runs 'ipfs cid hashes': list available multihashes.
+func (c *Client) CIDHashes(ctx context.Context, opts *CIDHashesOptions) (CIDHashesResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Numeric != nil {
+			o["numeric"] = *opts.Numeric
+		}
+		if opts.Supported != nil {
+			o["supported"] = *opts.Supported
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*[]CodeAndName)(nil),
+	}
+	res, err := c.call(ctx, []string{"cid", "hashes"}, cmd, o, args, nodes)
+	return CIDHashesResponse{res}, err
+}
+
+// CommandsResponse is the output of Commands.
+type CommandsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r CommandsResponse) Next() (*Command, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*Command)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// CommandsOptions are the options of Commands.
+type CommandsOptions struct {
+	// Show command flags.
+	Flags *bool
+}
+
+// Commands runs 'ipfs commands': list all available commands.
+func (c *Client) Commands(ctx context.Context, opts *CommandsOptions) (CommandsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Flags != nil {
+			o["flags"] = *opts.Flags
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*Command)(nil),
+	}
+	res, err := c.call(ctx, []string{"commands"}, cmd, o, args, nodes)
+	return CommandsResponse{res}, err
+}
+
+// ConfigResponse is the output of Config.
+type ConfigResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ConfigResponse) Next() (*ConfigField, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ConfigField)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ConfigOptions are the options of Config.
+type ConfigOptions struct {
+	// Set a boolean value.
+	Bool *bool
+	// Parse stringified JSON.
+	JSON *bool
+}
+
+// Config runs 'ipfs config': get and set IPFS config values.
+//
+// key: The key of the config entry (e.g. "Addresses.API").
+//
+// value: The value to set the config entry to.
+func (c *Client) Config(ctx context.Context, key string, value string, opts *ConfigOptions) (ConfigResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Bool != nil {
+			o["bool"] = *opts.Bool
+		}
+		if opts.JSON != nil {
+			o["json"] = *opts.JSON
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key)
+	if value != "" {
+		args = append(args, value)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true},
+			{Name: "value", Type: cmds.ArgString},
+		},
+		Type: (*ConfigField)(nil),
+	}
+	res, err := c.call(ctx, []string{"config"}, cmd, o, args, nodes)
+	return ConfigResponse{res}, err
+}
+
+// ConfigProfileApplyResponse is the output of ConfigProfileApply.
+type ConfigProfileApplyResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ConfigProfileApplyResponse) Next() (*ConfigUpdateOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ConfigUpdateOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ConfigProfileApplyOptions are the options of ConfigProfileApply.
+type ConfigProfileApplyOptions struct {
+	// Set a boolean value.
+	Bool *bool
+	// print difference between the current config and the config that would be generated.
+	DryRun *bool
+	// Parse stringified JSON.
+	JSON *bool
+}
+
+// ConfigProfileApply runs 'ipfs config profile apply': apply profile to config.
+//
+// profile: The profile to apply to the config.
+func (c *Client) ConfigProfileApply(ctx context.Context, profile string, opts *ConfigProfileApplyOptions) (ConfigProfileApplyResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Bool != nil {
+			o["bool"] = *opts.Bool
+		}
+		if opts.DryRun != nil {
+			o["dry-run"] = *opts.DryRun
+		}
+		if opts.JSON != nil {
+			o["json"] = *opts.JSON
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, profile)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "profile", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*ConfigUpdateOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"config", "profile", "apply"}, cmd, o, args, nodes)
+	return ConfigProfileApplyResponse{res}, err
+}
+
+// ConfigReplaceOptions are the options of ConfigReplace.
+type ConfigReplaceOptions struct {
+	// Set a boolean value.
+	Bool *bool
+	// Parse stringified JSON.
+	JSON *bool
+}
+
+// ConfigReplace runs 'ipfs config replace': replace the config with <file>.
+//
+// file: The file to use as the new config.
+func (c *Client) ConfigReplace(ctx context.Context, file files.Node, opts *ConfigReplaceOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Bool != nil {
+			o["bool"] = *opts.Bool
+		}
+		if opts.JSON != nil {
+			o["json"] = *opts.JSON
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if file != nil {
+		nodes = append(nodes, file)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "file", Type: cmds.ArgFile, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"config", "replace"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// ConfigShowResponse is the output of ConfigShow.
+type ConfigShowResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ConfigShowResponse) Next() (*map[string]interface{}, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ConfigShowOptions are the options of ConfigShow.
+type ConfigShowOptions struct {
+	// Set a boolean value.
+	Bool *bool
+	// Parse stringified JSON.
+	JSON *bool
+}
+
+// ConfigShow runs 'ipfs config show': output config file contents.
+func (c *Client) ConfigShow(ctx context.Context, opts *ConfigShowOptions) (ConfigShowResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Bool != nil {
+			o["bool"] = *opts.Bool
+		}
+		if opts.JSON != nil {
+			o["json"] = *opts.JSON
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*map[string]interface{})(nil),
+	}
+	res, err := c.call(ctx, []string{"config", "show"}, cmd, o, args, nodes)
+	return ConfigShowResponse{res}, err
+}
+
+// DagExportOptions are the options of DagExport.
+type DagExportOptions struct {
+	// Display progress on CLI. Defaults to true when STDERR is a TTY.
+	Progress *bool
+}
+
+// DagExport runs 'ipfs dag export': streams the selected DAG as a .car stream on stdout.
+//
+// root: CID of a root to recursively export
+func (c *Client) DagExport(ctx context.Context, root string, opts *DagExportOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Progress != nil {
+			o["progress"] = *opts.Progress
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, root)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "root", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"dag", "export"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// DagGetOptions are the options of DagGet.
+type DagGetOptions struct {
+	// Format that the object will be encoded as. Default: dag-json.
+	OutputCodec *string
+}
+
+// DagGet runs 'ipfs dag get': get a DAG node from IPFS.
+//
+// ref: The object to get
+func (c *Client) DagGet(ctx context.Context, ref string, opts *DagGetOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.OutputCodec != nil {
+			o["output-codec"] = *opts.OutputCodec
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ref)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ref", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"dag", "get"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// DagImportResponse is the output of DagImport.
+type DagImportResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DagImportResponse) Next() (*DagcmdCarImportOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*DagcmdCarImportOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DagImportOptions are the options of DagImport.
+type DagImportOptions struct {
+	// Disable block size check and allow creation of blocks bigger than 1MiB. WARNING: such blocks won't be transferable over the standard bitswap. Default: false.
+	AllowBigBlock *bool
+	// Pin optional roots listed in the .car headers after importing. Default: true.
+	PinRoots *bool
+	// No output.
+	Silent *bool
+	// Output stats.
+	Stats *bool
+}
+
+// DagImport runs 'ipfs dag import': import the contents of .car files
+//
+// path: The path of a .car file.
+func (c *Client) DagImport(ctx context.Context, path []files.Node, opts *DagImportOptions) (DagImportResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowBigBlock != nil {
+			o["allow-big-block"] = *opts.AllowBigBlock
+		}
+		if opts.PinRoots != nil {
+			o["pin-roots"] = *opts.PinRoots
+		}
+		if opts.Silent != nil {
+			o["silent"] = *opts.Silent
+		}
+		if opts.Stats != nil {
+			o["stats"] = *opts.Stats
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	nodes = append(nodes, path...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgFile, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*DagcmdCarImportOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"dag", "import"}, cmd, o, args, nodes)
+	return DagImportResponse{res}, err
+}
+
+// DagPutResponse is the output of DagPut.
+type DagPutResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DagPutResponse) Next() (*DagcmdOutputObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*DagcmdOutputObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DagPutOptions are the options of DagPut.
+type DagPutOptions struct {
+	// Disable block size check and allow creation of blocks bigger than 1MiB. WARNING: such blocks won't be transferable over the standard bitswap. Default: false.
+	AllowBigBlock *bool
+	// Hash function to use. Default: sha2-256.
+	Hash *string
+	// Codec that the input object is encoded in. Default: dag-json.
+	InputCodec *string
+	// Pin this object when adding.
+	Pin *bool
+	// Codec that the stored object will be encoded with. Default: dag-cbor.
+	StoreCodec *string
+}
+
+// DagPut runs 'ipfs dag put': add a DAG node to IPFS.
+//
+// objectData: The object to put
+func (c *Client) DagPut(ctx context.Context, objectData []files.Node, opts *DagPutOptions) (DagPutResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowBigBlock != nil {
+			o["allow-big-block"] = *opts.AllowBigBlock
+		}
+		if opts.Hash != nil {
+			o["hash"] = *opts.Hash
+		}
+		if opts.InputCodec != nil {
+			o["input-codec"] = *opts.InputCodec
+		}
+		if opts.Pin != nil {
+			o["pin"] = *opts.Pin
+		}
+		if opts.StoreCodec != nil {
+			o["store-codec"] = *opts.StoreCodec
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	nodes = append(nodes, objectData...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "object data", Type: cmds.ArgFile, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*DagcmdOutputObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"dag", "put"}, cmd, o, args, nodes)
+	return DagPutResponse{res}, err
+}
+
+// DagResolveResponse is the output of DagResolve.
+type DagResolveResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DagResolveResponse) Next() (*DagcmdResolveOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*DagcmdResolveOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DagResolve runs 'ipfs dag resolve': resolve IPLD block.
+//
+// ref: The path to resolve
+func (c *Client) DagResolve(ctx context.Context, ref string) (DagResolveResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, ref)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ref", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*DagcmdResolveOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"dag", "resolve"}, cmd, o, args, nodes)
+	return DagResolveResponse{res}, err
+}
+
+// DagStatResponse is the output of DagStat.
+type DagStatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DagStatResponse) Next() (*DagcmdDagStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*DagcmdDagStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DagStatOptions are the options of DagStat.
+type DagStatOptions struct {
+	// Return progressive data while reading through the DAG. Default: true.
+	Progress *bool
+}
+
+// DagStat runs 'ipfs dag stat': gets stats for a DAG.
+//
+// root: CID of a DAG root to get statistics for
+func (c *Client) DagStat(ctx context.Context, root string, opts *DagStatOptions) (DagStatResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Progress != nil {
+			o["progress"] = *opts.Progress
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, root)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "root", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*DagcmdDagStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"dag", "stat"}, cmd, o, args, nodes)
+	return DagStatResponse{res}, err
+}
+
+// DagWalkResponse is the output of DagWalk.
+type DagWalkResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DagWalkResponse) Next() (*DagcmdDagWalkOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*DagcmdDagWalkOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DagWalkOptions are the options of DagWalk.
+type DagWalkOptions struct {
+	// Only output the nodes with this codec, e.g. dag-pb. May be repeated.
+	Codec []string
+	// Only follow the links whose name matches this glob.
+	LinkName *string
+	// The depth of the nodes whose links are not followed, -1 for none. Default: -1.
+	MaxDepth *int
+	// Only output the nodes of at most this size, in bytes.
+	MaxSize *int
+	// Output the metadata of each node.
+	Meta *bool
+	// Only output the nodes of at least this size, in bytes.
+	MinSize *int
+}
+
+// DagWalk runs 'ipfs dag walk': stream the CIDs of the nodes of a DAG.
+//
+// root: CID of a DAG root to walk
+func (c *Client) DagWalk(ctx context.Context, root string, opts *DagWalkOptions) (DagWalkResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Codec != nil {
+			o["codec"] = opts.Codec
+		}
+		if opts.LinkName != nil {
+			o["link-name"] = *opts.LinkName
+		}
+		if opts.MaxDepth != nil {
+			o["max-depth"] = *opts.MaxDepth
+		}
+		if opts.MaxSize != nil {
+			o["max-size"] = *opts.MaxSize
+		}
+		if opts.Meta != nil {
+			o["meta"] = *opts.Meta
+		}
+		if opts.MinSize != nil {
+			o["min-size"] = *opts.MinSize
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, root)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "root", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*DagcmdDagWalkOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"dag", "walk"}, cmd, o, args, nodes)
+	return DagWalkResponse{res}, err
+}
+
+// DHTFindpeerResponse is the output of DHTFindpeer.
+type DHTFindpeerResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DHTFindpeerResponse) Next() (*routing.QueryEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*routing.QueryEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DHTFindpeerOptions are the options of DHTFindpeer.
+type DHTFindpeerOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// DHTFindpeer runs 'ipfs dht findpeer': find the multiaddresses associated with a Peer ID.
+//
+// peerid: The ID of the peer to search for.
+func (c *Client) DHTFindpeer(ctx context.Context, peerid []string, opts *DHTFindpeerOptions) (DHTFindpeerResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, peerid...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peerID", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+		Type: (*routing.QueryEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"dht", "findpeer"}, cmd, o, args, nodes)
+	return DHTFindpeerResponse{res}, err
+}
+
+// DHTFindprovsResponse is the output of DHTFindprovs.
+type DHTFindprovsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DHTFindprovsResponse) Next() (*routing.QueryEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*routing.QueryEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DHTFindprovsOptions are the options of DHTFindprovs.
+type DHTFindprovsOptions struct {
+	// The number of providers to find. Default: 20.
+	NumProviders *int
+	// Print extra information.
+	Verbose *bool
+}
+
+// DHTFindprovs runs 'ipfs dht findprovs': find peers that can provide a specific value, given a key.
+//
+// key: The key to find providers for.
+func (c *Client) DHTFindprovs(ctx context.Context, key []string, opts *DHTFindprovsOptions) (DHTFindprovsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.NumProviders != nil {
+			o["num-providers"] = *opts.NumProviders
+		}
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+		Type: (*routing.QueryEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"dht", "findprovs"}, cmd, o, args, nodes)
+	return DHTFindprovsResponse{res}, err
+}
+
+// DHTGetResponse is the output of DHTGet.
+type DHTGetResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DHTGetResponse) Next() (*routing.QueryEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*routing.QueryEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DHTGetOptions are the options of DHTGet.
+type DHTGetOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// DHTGet runs 'ipfs dht get': given a key, query the routing system for its best value.
+//
+// key: The key to find a value for.
+func (c *Client) DHTGet(ctx context.Context, key []string, opts *DHTGetOptions) (DHTGetResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+		Type: (*routing.QueryEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"dht", "get"}, cmd, o, args, nodes)
+	return DHTGetResponse{res}, err
+}
+
+// DHTProvideResponse is the output of DHTProvide.
+type DHTProvideResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DHTProvideResponse) Next() (*routing.QueryEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*routing.QueryEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DHTProvideOptions are the options of DHTProvide.
+type DHTProvideOptions struct {
+	// Recursively provide entire graph.
+	Recursive *bool
+	// Print extra information.
+	Verbose *bool
+}
+
+// DHTProvide runs 'ipfs dht provide': announce to the network that you are providing given values.
+//
+// key: The key[s] to send provide records for.
+func (c *Client) DHTProvide(ctx context.Context, key []string, opts *DHTProvideOptions) (DHTProvideResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*routing.QueryEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"dht", "provide"}, cmd, o, args, nodes)
+	return DHTProvideResponse{res}, err
+}
+
+// DHTPutResponse is the output of DHTPut.
+type DHTPutResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DHTPutResponse) Next() (*routing.QueryEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*routing.QueryEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DHTPutOptions are the options of DHTPut.
+type DHTPutOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// DHTPut runs 'ipfs dht put': write a key/value pair to the routing system.
+//
+// key: The key to store the value at.
+//
+// valueFile: A path to a file containing the value to store.
+func (c *Client) DHTPut(ctx context.Context, key string, valueFile files.Node, opts *DHTPutOptions) (DHTPutResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key)
+	if valueFile != nil {
+		nodes = append(nodes, valueFile)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true},
+			{Name: "value-file", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+		Type: (*routing.QueryEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"dht", "put"}, cmd, o, args, nodes)
+	return DHTPutResponse{res}, err
+}
+
+// DHTQueryResponse is the output of DHTQuery.
+type DHTQueryResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DHTQueryResponse) Next() (*routing.QueryEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*routing.QueryEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DHTQueryOptions are the options of DHTQuery.
+type DHTQueryOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// DHTQuery runs 'ipfs dht query': find the closest Peer IDs to a given Peer ID by querying the DHT.
+//
+// peerid: The peerID to run the query against.
+func (c *Client) DHTQuery(ctx context.Context, peerid []string, opts *DHTQueryOptions) (DHTQueryResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, peerid...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peerID", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+		Type: (*routing.QueryEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"dht", "query"}, cmd, o, args, nodes)
+	return DHTQueryResponse{res}, err
+}
+
+// DiagCmdsResponse is the output of DiagCmds.
+type DiagCmdsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DiagCmdsResponse) Next() (*[]*cmds.ReqLogEntry, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*[]*cmds.ReqLogEntry)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DiagCmdsOptions are the options of DiagCmds.
+type DiagCmdsOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// DiagCmds runs 'ipfs diag cmds': list commands run on this IPFS node.
+func (c *Client) DiagCmds(ctx context.Context, opts *DiagCmdsOptions) (DiagCmdsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*[]*cmds.ReqLogEntry)(nil),
+	}
+	res, err := c.call(ctx, []string{"diag", "cmds"}, cmd, o, args, nodes)
+	return DiagCmdsResponse{res}, err
+}
+
+// DiagCmdsClearOptions are the options of DiagCmdsClear.
+type DiagCmdsClearOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// DiagCmdsClear runs 'ipfs diag cmds clear': clear inactive requests from the log.
+func (c *Client) DiagCmdsClear(ctx context.Context, opts *DiagCmdsClearOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{}
+	res, err := c.call(ctx, []string{"diag", "cmds", "clear"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// DiagCmdsSetTimeOptions are the options of DiagCmdsSetTime.
+type DiagCmdsSetTimeOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// DiagCmdsSetTime runs 'ipfs diag cmds set-time': set how long to keep inactive requests in the log.
+//
+// time: Time to keep inactive requests in log.
+func (c *Client) DiagCmdsSetTime(ctx context.Context, time string, opts *DiagCmdsSetTimeOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, time)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "time", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"diag", "cmds", "set-time"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// DiagContentResponse is the output of DiagContent.
+type DiagContentResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DiagContentResponse) Next() (*ContentDiagReport, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ContentDiagReport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DiagContentOptions are the options of DiagContent.
+type DiagContentOptions struct {
+	// URL of a gateway to check, can be repeated. Default: [https://ipfs.io https://dweb.link].
+	Gateway []string
+	// Number of providers to check. Default: 5.
+	NumProviders *int
+	// Timeout of each check. Default: 30s.
+	ProbeTimeout *string
+	// Do not check the gateways.
+	SkipGateways *bool
+}
+
+// DiagContent runs 'ipfs diag content': check whether content is findable and retrievable by others.
+//
+// cid: CID of the content to check.
+func (c *Client) DiagContent(ctx context.Context, cid string, opts *DiagContentOptions) (DiagContentResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Gateway != nil {
+			o["gateway"] = opts.Gateway
+		}
+		if opts.NumProviders != nil {
+			o["num-providers"] = *opts.NumProviders
+		}
+		if opts.ProbeTimeout != nil {
+			o["probe-timeout"] = *opts.ProbeTimeout
+		}
+		if opts.SkipGateways != nil {
+			o["skip-gateways"] = *opts.SkipGateways
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*ContentDiagReport)(nil),
+	}
+	res, err := c.call(ctx, []string{"diag", "content"}, cmd, o, args, nodes)
+	return DiagContentResponse{res}, err
+}
+
+// DiagPeerResponse is the output of DiagPeer.
+type DiagPeerResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DiagPeerResponse) Next() (*PeerDiagEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PeerDiagEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DiagPeerOptions are the options of DiagPeer.
+type DiagPeerOptions struct {
+	// Duration of the capture. Default: 30s.
+	Capture *string
+}
+
+// DiagPeer runs 'ipfs diag peer': capture a timeline of the activity with a peer.
+//
+// peer: ID of the peer to watch.
+func (c *Client) DiagPeer(ctx context.Context, peer string, opts *DiagPeerOptions) (DiagPeerResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Capture != nil {
+			o["capture"] = *opts.Capture
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, peer)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peer", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*PeerDiagEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"diag", "peer"}, cmd, o, args, nodes)
+	return DiagPeerResponse{res}, err
+}
+
+// DiagProfileOptions are the options of DiagProfile.
+type DiagProfileOptions struct {
+	// The duration to wait between sampling goroutine-blocking events for the blocking profile. Default: 1ms.
+	BlockProfileRate *string
+	// The list of collectors to use for collecting diagnostic data. Default: [goroutines-stack goroutines-pprof version heap bin cpu mutex block].
+	Collectors []string
+	// The fraction 1/n of mutex contention events that are reported in the mutex profile. Default: 4.
+	MutexProfileFraction *int
+	// The path where the output .zip should be stored. Default: ./ipfs-profile-[timestamp].zip.
+	Output *string
+	// The amount of time spent profiling. If this is set to 0, then sampling profiles are skipped. Default: 30s.
+	ProfileTime *string
+}
+
+// DiagProfile runs 'ipfs diag profile': collect a performance profile for debugging.
+func (c *Client) DiagProfile(ctx context.Context, opts *DiagProfileOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.BlockProfileRate != nil {
+			o["block-profile-rate"] = *opts.BlockProfileRate
+		}
+		if opts.Collectors != nil {
+			o["collectors"] = opts.Collectors
+		}
+		if opts.MutexProfileFraction != nil {
+			o["mutex-profile-fraction"] = *opts.MutexProfileFraction
+		}
+		if opts.Output != nil {
+			o["output"] = *opts.Output
+		}
+		if opts.ProfileTime != nil {
+			o["profile-time"] = *opts.ProfileTime
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{}
+	res, err := c.call(ctx, []string{"diag", "profile"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// DiagRcmgrDumpResponse is the output of DiagRcmgrDump.
+type DiagRcmgrDumpResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DiagRcmgrDumpResponse) Next() (*Libp2pNetDumpOut, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*Libp2pNetDumpOut)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DiagRcmgrDump runs 'ipfs diag rcmgr dump': dump the usage of every resource manager scope.
+func (c *Client) DiagRcmgrDump(ctx context.Context) (DiagRcmgrDumpResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*Libp2pNetDumpOut)(nil),
+	}
+	res, err := c.call(ctx, []string{"diag", "rcmgr", "dump"}, cmd, o, args, nodes)
+	return DiagRcmgrDumpResponse{res}, err
+}
+
+// DiagSys runs 'ipfs diag sys': print system diagnostic information.
+func (c *Client) DiagSys(ctx context.Context) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{}
+	res, err := c.call(ctx, []string{"diag", "sys"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// DNSResponse is the output of DNS.
+type DNSResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r DNSResponse) Next() (*NameResolvedPath, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameResolvedPath)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// DNSOptions are the options of DNS.
+type DNSOptions struct {
+	// Resolve until the result is not a DNS link. Default: true.
+	Recursive *bool
+}
+
+// DNS runs 'ipfs dns': resolve DNSLink records.
+//
+// domainName: The domain-name name to resolve.
+//
+// Deprecated: see 'ipfs dns --help'.
+func (c *Client) DNS(ctx context.Context, domainName string, opts *DNSOptions) (DNSResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, domainName)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "domain-name", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*NameResolvedPath)(nil),
+	}
+	res, err := c.call(ctx, []string{"dns"}, cmd, o, args, nodes)
+	return DNSResponse{res}, err
+}
+
+// FileLsResponse is the output of FileLs.
+type FileLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r FileLsResponse) Next() (*UnixfsLsOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*UnixfsLsOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// FileLs runs 'ipfs file ls': list directory contents for Unix filesystem objects. Deprecated: Use 'ipfs ls' and 'ipfs files ls' instead.
+//
+// ipfsPath: The path to the IPFS object(s) to list links from.
+//
+// Deprecated: see 'ipfs file ls --help'.
+func (c *Client) FileLs(ctx context.Context, ipfsPath []string) (FileLsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*UnixfsLsOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"file", "ls"}, cmd, o, args, nodes)
+	return FileLsResponse{res}, err
+}
+
+// FilesChcidOptions are the options of FilesChcid.
+type FilesChcidOptions struct {
+	// Cid version to use. (experimental).
+	CIDVersion *int
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Hash function to use. Will set Cid version to 1 if used. (experimental).
+	Hash *string
+}
+
+// FilesChcid runs 'ipfs files chcid': change the CID version or hash function of the root node of a given path.
+//
+// path: Path to change. Default: '/'.
+func (c *Client) FilesChcid(ctx context.Context, path string, opts *FilesChcidOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.CIDVersion != nil {
+			o["cid-version"] = *opts.CIDVersion
+		}
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Hash != nil {
+			o["hash"] = *opts.Hash
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if path != "" {
+		args = append(args, path)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString},
+		},
+	}
+	res, err := c.call(ctx, []string{"files", "chcid"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// FilesCpOptions are the options of FilesCp.
+type FilesCpOptions struct {
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Make parent directories as needed.
+	Parents *bool
+}
+
+// FilesCp runs 'ipfs files cp': add references to IPFS files and directories in MFS (or copy within MFS).
+//
+// source: Source IPFS or MFS path to copy.
+//
+// dest: Destination within MFS.
+func (c *Client) FilesCp(ctx context.Context, source string, dest string, opts *FilesCpOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Parents != nil {
+			o["parents"] = *opts.Parents
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, source)
+	args = append(args, dest)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "source", Type: cmds.ArgString, Required: true},
+			{Name: "dest", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"files", "cp"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// FilesFlushResponse is the output of FilesFlush.
+type FilesFlushResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r FilesFlushResponse) Next() (*FlushRes, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*FlushRes)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// FilesFlushOptions are the options of FilesFlush.
+type FilesFlushOptions struct {
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+}
+
+// FilesFlush runs 'ipfs files flush': flush a given path's data to disk.
+//
+// path: Path to flush. Default: '/'.
+func (c *Client) FilesFlush(ctx context.Context, path string, opts *FilesFlushOptions) (FilesFlushResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if path != "" {
+		args = append(args, path)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString},
+		},
+		Type: (*FlushRes)(nil),
+	}
+	res, err := c.call(ctx, []string{"files", "flush"}, cmd, o, args, nodes)
+	return FilesFlushResponse{res}, err
+}
+
+// FilesLsResponse is the output of FilesLs.
+type FilesLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r FilesLsResponse) Next() (*FilesLsOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*FilesLsOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// FilesLsOptions are the options of FilesLs.
+type FilesLsOptions struct {
+	// Do not sort; list entries in directory order.
+	U *bool
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Use long listing format.
+	Long *bool
+}
+
+// FilesLs runs 'ipfs files ls': list directories in the local mutable namespace.
+//
+// path: Path to show listing for. Defaults to '/'.
+func (c *Client) FilesLs(ctx context.Context, path string, opts *FilesLsOptions) (FilesLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.U != nil {
+			o["U"] = *opts.U
+		}
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Long != nil {
+			o["long"] = *opts.Long
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if path != "" {
+		args = append(args, path)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString},
+		},
+		Type: (*FilesLsOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"files", "ls"}, cmd, o, args, nodes)
+	return FilesLsResponse{res}, err
+}
+
+// FilesMkdirOptions are the options of FilesMkdir.
+type FilesMkdirOptions struct {
+	// Cid version to use. (experimental).
+	CIDVersion *int
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Hash function to use. Will set Cid version to 1 if used. (experimental).
+	Hash *string
+	// No error if existing, make parent directories as needed.
+	Parents *bool
+}
+
+// FilesMkdir runs 'ipfs files mkdir': make directories.
+//
+// path: Path to dir to make.
+func (c *Client) FilesMkdir(ctx context.Context, path string, opts *FilesMkdirOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.CIDVersion != nil {
+			o["cid-version"] = *opts.CIDVersion
+		}
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Hash != nil {
+			o["hash"] = *opts.Hash
+		}
+		if opts.Parents != nil {
+			o["parents"] = *opts.Parents
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, path)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"files", "mkdir"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// FilesMvOptions are the options of FilesMv.
+type FilesMvOptions struct {
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+}
+
+// FilesMv runs 'ipfs files mv': move files.
+//
+// source: Source file to move.
+//
+// dest: Destination path for file to be moved to.
+func (c *Client) FilesMv(ctx context.Context, source string, dest string, opts *FilesMvOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, source)
+	args = append(args, dest)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "source", Type: cmds.ArgString, Required: true},
+			{Name: "dest", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"files", "mv"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// FilesReadOptions are the options of FilesRead.
+type FilesReadOptions struct {
+	// Maximum number of bytes to read.
+	Count *int64
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Byte offset to begin reading from.
+	Offset *int64
+}
+
+// FilesRead runs 'ipfs files read': read a file in a given MFS.
+//
+// path: Path to file to be read.
+func (c *Client) FilesRead(ctx context.Context, path string, opts *FilesReadOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Count != nil {
+			o["count"] = *opts.Count
+		}
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Offset != nil {
+			o["offset"] = *opts.Offset
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, path)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"files", "read"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// FilesRmOptions are the options of FilesRm.
+type FilesRmOptions struct {
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Forcibly remove target at path; implies -r for directories.
+	Force *bool
+	// Recursively remove directories.
+	Recursive *bool
+}
+
+// FilesRm runs 'ipfs files rm': remove a file.
+//
+// path: File to remove.
+func (c *Client) FilesRm(ctx context.Context, path []string, opts *FilesRmOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Force != nil {
+			o["force"] = *opts.Force
+		}
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, path...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"files", "rm"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// FilesStatResponse is the output of FilesStat.
+type FilesStatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r FilesStatResponse) Next() (*StatOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StatOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// FilesStatOptions are the options of FilesStat.
+type FilesStatOptions struct {
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Print statistics in given format. Allowed tokens: <hash> <size> <cumulsize> <type> <childs>. Conflicts with other format options. Default: <hash> Size: <size> CumulativeSize: <cumulsize> ChildBlocks: <childs> Type: <type>.
+	Format *string
+	// Print only hash. Implies '--format=<hash>'. Conflicts with other format options.
+	Hash *bool
+	// Print only size. Implies '--format=<cumulsize>'. Conflicts with other format options.
+	Size *bool
+	// Compute the amount of the dag that is local, and if possible the total size.
+	WithLocal *bool
+}
+
+// FilesStat runs 'ipfs files stat': display file status.
+//
+// path: Path to node to stat.
+func (c *Client) FilesStat(ctx context.Context, path string, opts *FilesStatOptions) (FilesStatResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Format != nil {
+			o["format"] = *opts.Format
+		}
+		if opts.Hash != nil {
+			o["hash"] = *opts.Hash
+		}
+		if opts.Size != nil {
+			o["size"] = *opts.Size
+		}
+		if opts.WithLocal != nil {
+			o["with-local"] = *opts.WithLocal
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, path)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*StatOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"files", "stat"}, cmd, o, args, nodes)
+	return FilesStatResponse{res}, err
+}
+
+// FilesWriteOptions are the options of FilesWrite.
+type FilesWriteOptions struct {
+	// Cid version to use. (experimental).
+	CIDVersion *int
+	// Maximum number of bytes to read.
+	Count *int64
+	// Create the file if it does not exist.
+	Create *bool
+	// Flush target and ancestors after write. Default: true.
+	Flush *bool
+	// Hash function to use. Will set Cid version to 1 if used. (experimental).
+	Hash *string
+	// Byte offset to begin writing at.
+	Offset *int64
+	// Make parent directories as needed.
+	Parents *bool
+	// Use raw blocks for newly created leaf nodes. (experimental).
+	RawLeaves *bool
+	// Truncate the file to size zero before writing.
+	Truncate *bool
+}
+
+// FilesWrite runs 'ipfs files write': write to a mutable file in a given filesystem.
+//
+// path: Path to write to.
+//
+// data: Data to write.
+func (c *Client) FilesWrite(ctx context.Context, path string, data files.Node, opts *FilesWriteOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.CIDVersion != nil {
+			o["cid-version"] = *opts.CIDVersion
+		}
+		if opts.Count != nil {
+			o["count"] = *opts.Count
+		}
+		if opts.Create != nil {
+			o["create"] = *opts.Create
+		}
+		if opts.Flush != nil {
+			o["flush"] = *opts.Flush
+		}
+		if opts.Hash != nil {
+			o["hash"] = *opts.Hash
+		}
+		if opts.Offset != nil {
+			o["offset"] = *opts.Offset
+		}
+		if opts.Parents != nil {
+			o["parents"] = *opts.Parents
+		}
+		if opts.RawLeaves != nil {
+			o["raw-leaves"] = *opts.RawLeaves
+		}
+		if opts.Truncate != nil {
+			o["truncate"] = *opts.Truncate
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, path)
+	if data != nil {
+		nodes = append(nodes, data)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString, Required: true},
+			{Name: "data", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"files", "write"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// FilestoreDupsResponse is the output of FilestoreDups.
+type FilestoreDupsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r FilestoreDupsResponse) Next() (*RefWrapper, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RefWrapper)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// FilestoreDups runs 'ipfs filestore dups': list blocks that are both in the filestore and standard block storage.
+func (c *Client) FilestoreDups(ctx context.Context) (FilestoreDupsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*RefWrapper)(nil),
+	}
+	res, err := c.call(ctx, []string{"filestore", "dups"}, cmd, o, args, nodes)
+	return FilestoreDupsResponse{res}, err
+}
+
+// FilestoreLsResponse is the output of FilestoreLs.
+type FilestoreLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r FilestoreLsResponse) Next() (*FilestoreListRes, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*FilestoreListRes)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// FilestoreLsOptions are the options of FilestoreLs.
+type FilestoreLsOptions struct {
+	// sort the results based on the path of the backing file.
+	FileOrder *bool
+}
+
+// FilestoreLs runs 'ipfs filestore ls': list objects in filestore.
+//
+// obj: Cid of objects to list.
+func (c *Client) FilestoreLs(ctx context.Context, obj []string, opts *FilestoreLsOptions) (FilestoreLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.FileOrder != nil {
+			o["file-order"] = *opts.FileOrder
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, obj...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "obj", Type: cmds.ArgString, Variadic: true},
+		},
+		Type: (*FilestoreListRes)(nil),
+	}
+	res, err := c.call(ctx, []string{"filestore", "ls"}, cmd, o, args, nodes)
+	return FilestoreLsResponse{res}, err
+}
+
+// FilestoreVerifyResponse is the output of FilestoreVerify.
+type FilestoreVerifyResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r FilestoreVerifyResponse) Next() (*FilestoreListRes, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*FilestoreListRes)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// FilestoreVerifyOptions are the options of FilestoreVerify.
+type FilestoreVerifyOptions struct {
+	// verify the objects based on the order of the backing file.
+	FileOrder *bool
+}
+
+// FilestoreVerify runs 'ipfs filestore verify': verify objects in filestore.
+//
+// obj: Cid of objects to verify.
+func (c *Client) FilestoreVerify(ctx context.Context, obj []string, opts *FilestoreVerifyOptions) (FilestoreVerifyResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.FileOrder != nil {
+			o["file-order"] = *opts.FileOrder
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, obj...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "obj", Type: cmds.ArgString, Variadic: true},
+		},
+		Type: (*FilestoreListRes)(nil),
+	}
+	res, err := c.call(ctx, []string{"filestore", "verify"}, cmd, o, args, nodes)
+	return FilestoreVerifyResponse{res}, err
+}
+
+// GatewayAliasAddResponse is the output of GatewayAliasAdd.
+type GatewayAliasAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r GatewayAliasAddResponse) Next() (*GatewayAlias, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*GatewayAlias)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// GatewayAliasAdd runs 'ipfs gateway alias add': redirect a moved content path to its new location.
+//
+// from: Content path that has moved.
+//
+// to: Content path to redirect to.
+func (c *Client) GatewayAliasAdd(ctx context.Context, from string, to string) (GatewayAliasAddResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, from)
+	args = append(args, to)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "from", Type: cmds.ArgString, Required: true},
+			{Name: "to", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*GatewayAlias)(nil),
+	}
+	res, err := c.call(ctx, []string{"gateway", "alias", "add"}, cmd, o, args, nodes)
+	return GatewayAliasAddResponse{res}, err
+}
+
+// GatewayAliasLsResponse is the output of GatewayAliasLs.
+type GatewayAliasLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r GatewayAliasLsResponse) Next() (*GatewayAliasList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*GatewayAliasList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// GatewayAliasLs runs 'ipfs gateway alias ls': list gateway aliases.
+func (c *Client) GatewayAliasLs(ctx context.Context) (GatewayAliasLsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*GatewayAliasList)(nil),
+	}
+	res, err := c.call(ctx, []string{"gateway", "alias", "ls"}, cmd, o, args, nodes)
+	return GatewayAliasLsResponse{res}, err
+}
+
+// GatewayAliasRm runs 'ipfs gateway alias rm': remove a gateway alias.
+//
+// from: Content path of the alias to remove.
+func (c *Client) GatewayAliasRm(ctx context.Context, from string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, from)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "from", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"gateway", "alias", "rm"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// GetOptions are the options of Get.
+type GetOptions struct {
+	// Output a TAR archive.
+	Archive *bool
+	// Compress the output with GZIP compression.
+	Compress *bool
+	// The level of compression (1-9).
+	CompressionLevel *int
+	// The path where the output should be stored.
+	Output *string
+	// Stream progress data. Default: true.
+	Progress *bool
+}
+
+// Get runs 'ipfs get': download IPFS objects.
+//
+// ipfsPath: The path to the IPFS object(s) to be outputted.
+func (c *Client) Get(ctx context.Context, ipfsPath string, opts *GetOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Archive != nil {
+			o["archive"] = *opts.Archive
+		}
+		if opts.Compress != nil {
+			o["compress"] = *opts.Compress
+		}
+		if opts.CompressionLevel != nil {
+			o["compression-level"] = *opts.CompressionLevel
+		}
+		if opts.Output != nil {
+			o["output"] = *opts.Output
+		}
+		if opts.Progress != nil {
+			o["progress"] = *opts.Progress
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"get"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// IDResponse is the output of ID.
+type IDResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r IDResponse) Next() (*IdOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*IdOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// IDOptions are the options of ID.
+type IDOptions struct {
+	// Optional output format.
+	Format *string
+	// Encoding used for peer IDs: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: b58mh.
+	PeeridBase *string
+}
+
+// ID runs 'ipfs id': show IPFS node id info.
+//
+// peerid: Peer.ID of node to look up.
+func (c *Client) ID(ctx context.Context, peerid string, opts *IDOptions) (IDResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Format != nil {
+			o["format"] = *opts.Format
+		}
+		if opts.PeeridBase != nil {
+			o["peerid-base"] = *opts.PeeridBase
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if peerid != "" {
+		args = append(args, peerid)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peerid", Type: cmds.ArgString},
+		},
+		Type: (*IdOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"id"}, cmd, o, args, nodes)
+	return IDResponse{res}, err
+}
+
+// JournalLsResponse is the output of JournalLs.
+type JournalLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r JournalLsResponse) Next() (*JournalEntry, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*JournalEntry)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// JournalLsOptions are the options of JournalLs.
+type JournalLsOptions struct {
+	// List the entries recorded since this time or duration ago.
+	Since *string
+	// List the entries of these types (pin,publish,gc,config,quarantine,scan).
+	Type []string
+}
+
+// JournalLs runs 'ipfs journal ls': list the entries of the event journal.
+func (c *Client) JournalLs(ctx context.Context, opts *JournalLsOptions) (JournalLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Since != nil {
+			o["since"] = *opts.Since
+		}
+		if opts.Type != nil {
+			o["type"] = opts.Type
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*JournalEntry)(nil),
+	}
+	res, err := c.call(ctx, []string{"journal", "ls"}, cmd, o, args, nodes)
+	return JournalLsResponse{res}, err
+}
+
+// KeyGenResponse is the output of KeyGen.
+type KeyGenResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r KeyGenResponse) Next() (*KeyOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*KeyOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// KeyGenOptions are the options of KeyGen.
+type KeyGenOptions struct {
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+	// size of the key to generate.
+	Size *int
+	// type of the key to create: rsa, ed25519. Default: ed25519.
+	Type *string
+}
+
+// KeyGen runs 'ipfs key gen': create a new keypair
+//
+// name: name of key to create
+func (c *Client) KeyGen(ctx context.Context, name string, opts *KeyGenOptions) (KeyGenResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+		if opts.Size != nil {
+			o["size"] = *opts.Size
+		}
+		if opts.Type != nil {
+			o["type"] = *opts.Type
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, name)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*KeyOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"key", "gen"}, cmd, o, args, nodes)
+	return KeyGenResponse{res}, err
+}
+
+// KeyImportResponse is the output of KeyImport.
+type KeyImportResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r KeyImportResponse) Next() (*KeyOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*KeyOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// KeyImportOptions are the options of KeyImport.
+type KeyImportOptions struct {
+	// Allow importing any key type. Default: false.
+	AllowAnyKeyType *bool
+	// The format of the private key to import, libp2p-protobuf-cleartext or pem-pkcs8-cleartext. Default: libp2p-protobuf-cleartext.
+	Format *string
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+}
+
+// KeyImport runs 'ipfs key import': import a key and prints imported key id
+//
+// name: name to associate with key in keychain
+//
+// key: key provided by generate or export
+func (c *Client) KeyImport(ctx context.Context, name string, key files.Node, opts *KeyImportOptions) (KeyImportResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowAnyKeyType != nil {
+			o["allow-any-key-type"] = *opts.AllowAnyKeyType
+		}
+		if opts.Format != nil {
+			o["format"] = *opts.Format
+		}
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, name)
+	if key != nil {
+		nodes = append(nodes, key)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true},
+			{Name: "key", Type: cmds.ArgFile, Required: true},
+		},
+		Type: (*KeyOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"key", "import"}, cmd, o, args, nodes)
+	return KeyImportResponse{res}, err
+}
+
+// KeyListResponse is the output of KeyList.
+type KeyListResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r KeyListResponse) Next() (*KeyOutputList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*KeyOutputList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// KeyListOptions are the options of KeyList.
+type KeyListOptions struct {
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+	// Show extra information about keys.
+	L *bool
+}
+
+// KeyList runs 'ipfs key list': list all local keypairs.
+func (c *Client) KeyList(ctx context.Context, opts *KeyListOptions) (KeyListResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+		if opts.L != nil {
+			o["l"] = *opts.L
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*KeyOutputList)(nil),
+	}
+	res, err := c.call(ctx, []string{"key", "list"}, cmd, o, args, nodes)
+	return KeyListResponse{res}, err
+}
+
+// KeyRenameResponse is the output of KeyRename.
+type KeyRenameResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r KeyRenameResponse) Next() (*KeyRenameOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*KeyRenameOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// KeyRenameOptions are the options of KeyRename.
+type KeyRenameOptions struct {
+	// Allow to overwrite an existing key.
+	Force *bool
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+}
+
+// KeyRename runs 'ipfs key rename': rename a keypair.
+//
+// name: name of key to rename
+//
+// newname: new name of the key
+func (c *Client) KeyRename(ctx context.Context, name string, newname string, opts *KeyRenameOptions) (KeyRenameResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Force != nil {
+			o["force"] = *opts.Force
+		}
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, name)
+	args = append(args, newname)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true},
+			{Name: "newName", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*KeyRenameOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"key", "rename"}, cmd, o, args, nodes)
+	return KeyRenameResponse{res}, err
+}
+
+// KeyRmResponse is the output of KeyRm.
+type KeyRmResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r KeyRmResponse) Next() (*KeyOutputList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*KeyOutputList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// KeyRmOptions are the options of KeyRm.
+type KeyRmOptions struct {
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+	// Show extra information about keys.
+	L *bool
+}
+
+// KeyRm runs 'ipfs key rm': remove a keypair.
+//
+// name: names of keys to remove
+func (c *Client) KeyRm(ctx context.Context, name []string, opts *KeyRmOptions) (KeyRmResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+		if opts.L != nil {
+			o["l"] = *opts.L
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, name...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*KeyOutputList)(nil),
+	}
+	res, err := c.call(ctx, []string{"key", "rm"}, cmd, o, args, nodes)
+	return KeyRmResponse{res}, err
+}
+
+// LogLevelResponse is the output of LogLevel.
+type LogLevelResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r LogLevelResponse) Next() (*MessageOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*MessageOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// LogLevel runs 'ipfs log level': change the logging level.
+//
+// subsystem: The subsystem logging identifier. Use 'all' for all subsystems.
+//
+// level: The log level, with 'debug' the most verbose and 'fatal' the least verbose. One of: debug, info, warn, error, dpanic, panic, fatal.
+func (c *Client) LogLevel(ctx context.Context, subsystem string, level string) (LogLevelResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, subsystem)
+	args = append(args, level)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "subsystem", Type: cmds.ArgString, Required: true},
+			{Name: "level", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*MessageOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"log", "level"}, cmd, o, args, nodes)
+	return LogLevelResponse{res}, err
+}
+
+// LogLsResponse is the output of LogLs.
+type LogLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r LogLsResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// LogLs runs 'ipfs log ls': list the logging subsystems.
+func (c *Client) LogLs(ctx context.Context) (LogLsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"log", "ls"}, cmd, o, args, nodes)
+	return LogLsResponse{res}, err
+}
+
+// LogTail runs 'ipfs log tail': read the event log.
+func (c *Client) LogTail(ctx context.Context) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{}
+	res, err := c.call(ctx, []string{"log", "tail"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// LsResponse is the output of Ls.
+type LsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r LsResponse) Next() (*LsOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*LsOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// LsOptions are the options of Ls.
+type LsOptions struct {
+	// Print table headers (Hash, Size, Name).
+	Headers *bool
+	// Resolve linked objects to find out their types. Default: true.
+	ResolveType *bool
+	// Resolve linked objects to find out their file size. Default: true.
+	Size *bool
+	// Enable experimental streaming of directory entries as they are traversed.
+	Stream *bool
+}
+
+// Ls runs 'ipfs ls': list directory contents for Unix filesystem objects.
+//
+// ipfsPath: The path to the IPFS object(s) to list links from.
+func (c *Client) Ls(ctx context.Context, ipfsPath []string, opts *LsOptions) (LsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Headers != nil {
+			o["headers"] = *opts.Headers
+		}
+		if opts.ResolveType != nil {
+			o["resolve-type"] = *opts.ResolveType
+		}
+		if opts.Size != nil {
+			o["size"] = *opts.Size
+		}
+		if opts.Stream != nil {
+			o["stream"] = *opts.Stream
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*LsOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"ls"}, cmd, o, args, nodes)
+	return LsResponse{res}, err
+}
+
+// MountResponse is the output of Mount.
+type MountResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r MountResponse) Next() (*ConfigMounts, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ConfigMounts)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// MountOptions are the options of Mount.
+type MountOptions struct {
+	// The path where IPFS should be mounted.
+	IPFSPath *string
+	// The path where IPNS should be mounted.
+	IPNSPath *string
+}
+
+// Mount runs 'ipfs mount': mounts IPFS to the filesystem (read-only).
+func (c *Client) Mount(ctx context.Context, opts *MountOptions) (MountResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.IPFSPath != nil {
+			o["ipfs-path"] = *opts.IPFSPath
+		}
+		if opts.IPNSPath != nil {
+			o["ipns-path"] = *opts.IPNSPath
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*ConfigMounts)(nil),
+	}
+	res, err := c.call(ctx, []string{"mount"}, cmd, o, args, nodes)
+	return MountResponse{res}, err
+}
+
+// MultibaseDecode runs 'ipfs multibase decode': decode multibase string
+//
+// encodedFile: encoded data to decode
+func (c *Client) MultibaseDecode(ctx context.Context, encodedFile files.Node) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	if encodedFile != nil {
+		nodes = append(nodes, encodedFile)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "encoded_file", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"multibase", "decode"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// MultibaseEncodeOptions are the options of MultibaseEncode.
+type MultibaseEncodeOptions struct {
+	// multibase encoding. Default: base64url.
+	B *string
+}
+
+// MultibaseEncode runs 'ipfs multibase encode': encode data into multibase string
+//
+// file: data to encode
+func (c *Client) MultibaseEncode(ctx context.Context, file files.Node, opts *MultibaseEncodeOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.B != nil {
+			o["b"] = *opts.B
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if file != nil {
+		nodes = append(nodes, file)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "file", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"multibase", "encode"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// MultibaseListResponse is the output of MultibaseList.
+type MultibaseListResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r MultibaseListResponse) Next() (*[]CodeAndName, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*[]CodeAndName)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// MultibaseListOptions are the options of MultibaseList.
+type MultibaseListOptions struct {
+	// also include numeric codes.
+	Numeric *bool
+	// also include the single letter prefixes in addition to the code.
+	Prefix *bool
+}
+
+// MultibaseList runs 'ipfs multibase list': list available multibase encodings.
+func (c *Client) MultibaseList(ctx context.Context, opts *MultibaseListOptions) (MultibaseListResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Numeric != nil {
+			o["numeric"] = *opts.Numeric
+		}
+		if opts.Prefix != nil {
+			o["prefix"] = *opts.Prefix
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*[]CodeAndName)(nil),
+	}
+	res, err := c.call(ctx, []string{"multibase", "list"}, cmd, o, args, nodes)
+	return MultibaseListResponse{res}, err
+}
+
+// MultibaseTranscodeOptions are the options of MultibaseTranscode.
+type MultibaseTranscodeOptions struct {
+	// multibase encoding. Default: base64url.
+	B *string
+}
+
+// MultibaseTranscode runs 'ipfs multibase transcode': transcode multibase string between bases
+//
+// encodedFile: encoded data to decode
+func (c *Client) MultibaseTranscode(ctx context.Context, encodedFile files.Node, opts *MultibaseTranscodeOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.B != nil {
+			o["b"] = *opts.B
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if encodedFile != nil {
+		nodes = append(nodes, encodedFile)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "encoded_file", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"multibase", "transcode"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// NameInspectResponse is the output of NameInspect.
+type NameInspectResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r NameInspectResponse) Next() (*NameIpnsInspectEntry, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameIpnsInspectEntry)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// NameInspectOptions are the options of NameInspect.
+type NameInspectOptions struct {
+	// Look up the current record in the routing system.
+	CheckNetwork *bool
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+	// Name of the key to inspect or a valid PeerID, as listed by 'ipfs key list -l'. Default: self.
+	Key *string
+}
+
+// NameInspect runs 'ipfs name inspect': inspect the publishing state of an IPNS name.
+func (c *Client) NameInspect(ctx context.Context, opts *NameInspectOptions) (NameInspectResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.CheckNetwork != nil {
+			o["check-network"] = *opts.CheckNetwork
+		}
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+		if opts.Key != nil {
+			o["key"] = *opts.Key
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*NameIpnsInspectEntry)(nil),
+	}
+	res, err := c.call(ctx, []string{"name", "inspect"}, cmd, o, args, nodes)
+	return NameInspectResponse{res}, err
+}
+
+// NamePublishResponse is the output of NamePublish.
+type NamePublishResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r NamePublishResponse) Next() (*NameIpnsEntry, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameIpnsEntry)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// NamePublishOptions are the options of NamePublish.
+type NamePublishOptions struct {
+	// When offline, save the IPNS record to the the local datastore without broadcasting to the network instead of simply failing.
+	AllowOffline *bool
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+	// Name of the key to be used or a valid PeerID, as listed by 'ipfs key list -l'. Default: self.
+	Key *string
+	// Time duration that the record will be valid for. Default: 24h. This accepts durations such as "300s", "1.5h" or "2h45m". Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
+	Lifetime *string
+	// Publish a new directory built from <name>=<ipfs-path> arguments.
+	Paths *bool
+	// Pin the directory built with --paths. Default: true.
+	Pin *bool
+	// Write only final hash.
+	Quieter *bool
+	// Check if the given path can be resolved before publishing. Default: true.
+	Resolve *bool
+	// Time duration this record should be cached for. Uses the same syntax as the lifetime option. (caution: experimental).
+	Ttl *string
+}
+
+// NamePublish runs 'ipfs name publish': publish IPNS names.
+//
+// ipfsPath: ipfs path of the object to be published, or <name>=<ipfs-path> pairs with --paths.
+func (c *Client) NamePublish(ctx context.Context, ipfsPath []string, opts *NamePublishOptions) (NamePublishResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowOffline != nil {
+			o["allow-offline"] = *opts.AllowOffline
+		}
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+		if opts.Key != nil {
+			o["key"] = *opts.Key
+		}
+		if opts.Lifetime != nil {
+			o["lifetime"] = *opts.Lifetime
+		}
+		if opts.Paths != nil {
+			o["paths"] = *opts.Paths
+		}
+		if opts.Pin != nil {
+			o["pin"] = *opts.Pin
+		}
+		if opts.Quieter != nil {
+			o["quieter"] = *opts.Quieter
+		}
+		if opts.Resolve != nil {
+			o["resolve"] = *opts.Resolve
+		}
+		if opts.Ttl != nil {
+			o["ttl"] = *opts.Ttl
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*NameIpnsEntry)(nil),
+	}
+	res, err := c.call(ctx, []string{"name", "publish"}, cmd, o, args, nodes)
+	return NamePublishResponse{res}, err
+}
+
+// NamePubsubCancelResponse is the output of NamePubsubCancel.
+type NamePubsubCancelResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r NamePubsubCancelResponse) Next() (*NameIpnsPubsubCancel, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameIpnsPubsubCancel)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// NamePubsubCancel runs 'ipfs name pubsub cancel': cancel a name subscription.
+//
+// name: Name to cancel the subscription for.
+func (c *Client) NamePubsubCancel(ctx context.Context, name string) (NamePubsubCancelResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, name)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*NameIpnsPubsubCancel)(nil),
+	}
+	res, err := c.call(ctx, []string{"name", "pubsub", "cancel"}, cmd, o, args, nodes)
+	return NamePubsubCancelResponse{res}, err
+}
+
+// NamePubsubStateResponse is the output of NamePubsubState.
+type NamePubsubStateResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r NamePubsubStateResponse) Next() (*NameIpnsPubsubState, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameIpnsPubsubState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// NamePubsubState runs 'ipfs name pubsub state': query the state of IPNS pubsub.
+func (c *Client) NamePubsubState(ctx context.Context) (NamePubsubStateResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*NameIpnsPubsubState)(nil),
+	}
+	res, err := c.call(ctx, []string{"name", "pubsub", "state"}, cmd, o, args, nodes)
+	return NamePubsubStateResponse{res}, err
+}
+
+// NamePubsubSubsResponse is the output of NamePubsubSubs.
+type NamePubsubSubsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r NamePubsubSubsResponse) Next() (*NameStringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameStringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// NamePubsubSubsOptions are the options of NamePubsubSubs.
+type NamePubsubSubsOptions struct {
+	// Encoding used for keys: Can either be a multibase encoded CID or a base58btc encoded multihash. Takes {b58mh|base36|k|base32|b...}. Default: base36.
+	IPNSBase *string
+}
+
+// NamePubsubSubs runs 'ipfs name pubsub subs': show current name subscriptions.
+func (c *Client) NamePubsubSubs(ctx context.Context, opts *NamePubsubSubsOptions) (NamePubsubSubsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.IPNSBase != nil {
+			o["ipns-base"] = *opts.IPNSBase
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*NameStringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"name", "pubsub", "subs"}, cmd, o, args, nodes)
+	return NamePubsubSubsResponse{res}, err
+}
+
+// NameResolveResponse is the output of NameResolve.
+type NameResolveResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r NameResolveResponse) Next() (*NameResolvedPath, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameResolvedPath)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// NameResolveOptions are the options of NameResolve.
+type NameResolveOptions struct {
+	// Number of records to request for DHT resolution.
+	DHTRecordCount *uint
+	// Max time to collect values during DHT resolution eg "30s". Pass 0 for no timeout.
+	DHTTimeout *string
+	// Do not use cached entries.
+	Nocache *bool
+	// Resolve until the result is not an IPNS name. Default: true.
+	Recursive *bool
+	// Stream entries as they are found.
+	Stream *bool
+}
+
+// NameResolve runs 'ipfs name resolve': resolve IPNS names.
+//
+// name: The IPNS name to resolve. Defaults to your node's peerID.
+func (c *Client) NameResolve(ctx context.Context, name string, opts *NameResolveOptions) (NameResolveResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.DHTRecordCount != nil {
+			o["dht-record-count"] = *opts.DHTRecordCount
+		}
+		if opts.DHTTimeout != nil {
+			o["dht-timeout"] = *opts.DHTTimeout
+		}
+		if opts.Nocache != nil {
+			o["nocache"] = *opts.Nocache
+		}
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+		if opts.Stream != nil {
+			o["stream"] = *opts.Stream
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if name != "" {
+		args = append(args, name)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString},
+		},
+		Type: (*NameResolvedPath)(nil),
+	}
+	res, err := c.call(ctx, []string{"name", "resolve"}, cmd, o, args, nodes)
+	return NameResolveResponse{res}, err
+}
+
+// ObjectData runs 'ipfs object data': deprecated way to read the raw bytes of a dag-pb object: use 'dag get' instead.
+//
+// key: Key of the object to retrieve, in base58-encoded multihash format.
+//
+// Deprecated: see 'ipfs object data --help'.
+func (c *Client) ObjectData(ctx context.Context, key string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, key)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"object", "data"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// ObjectDiffResponse is the output of ObjectDiff.
+type ObjectDiffResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectDiffResponse) Next() (*ObjectcmdChanges, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdChanges)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectDiffOptions are the options of ObjectDiff.
+type ObjectDiffOptions struct {
+	// Print extra information.
+	Verbose *bool
+}
+
+// ObjectDiff runs 'ipfs object diff': display the diff between two IPFS objects.
+//
+// objA: Object to diff against.
+//
+// objB: Object to diff.
+//
+// Deprecated: see 'ipfs object diff --help'.
+func (c *Client) ObjectDiff(ctx context.Context, objA string, objB string, opts *ObjectDiffOptions) (ObjectDiffResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, objA)
+	args = append(args, objB)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "obj_a", Type: cmds.ArgString, Required: true},
+			{Name: "obj_b", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*ObjectcmdChanges)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "diff"}, cmd, o, args, nodes)
+	return ObjectDiffResponse{res}, err
+}
+
+// ObjectGetResponse is the output of ObjectGet.
+type ObjectGetResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectGetResponse) Next() (*ObjectcmdNode, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdNode)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectGetOptions are the options of ObjectGet.
+type ObjectGetOptions struct {
+	// Encoding type of the data field, either "text" or "base64". Default: text.
+	DataEncoding *string
+}
+
+// ObjectGet runs 'ipfs object get': deprecated way to get and serialize the dag-pb node. Use 'dag get' instead
+//
+// key: Key of the dag-pb object to retrieve, in base58-encoded multihash format.
+//
+// Deprecated: see 'ipfs object get --help'.
+func (c *Client) ObjectGet(ctx context.Context, key string, opts *ObjectGetOptions) (ObjectGetResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.DataEncoding != nil {
+			o["data-encoding"] = *opts.DataEncoding
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*ObjectcmdNode)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "get"}, cmd, o, args, nodes)
+	return ObjectGetResponse{res}, err
+}
+
+// ObjectLinksResponse is the output of ObjectLinks.
+type ObjectLinksResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectLinksResponse) Next() (*ObjectcmdObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectLinksOptions are the options of ObjectLinks.
+type ObjectLinksOptions struct {
+	// Print table headers (Hash, Size, Name).
+	Headers *bool
+}
+
+// ObjectLinks runs 'ipfs object links': deprecated way to output links in the specified dag-pb object: use 'dag get' instead.
+//
+// key: Key of the dag-pb object to retrieve, in base58-encoded multihash format.
+//
+// Deprecated: see 'ipfs object links --help'.
+func (c *Client) ObjectLinks(ctx context.Context, key string, opts *ObjectLinksOptions) (ObjectLinksResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Headers != nil {
+			o["headers"] = *opts.Headers
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*ObjectcmdObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "links"}, cmd, o, args, nodes)
+	return ObjectLinksResponse{res}, err
+}
+
+// ObjectNewResponse is the output of ObjectNew.
+type ObjectNewResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectNewResponse) Next() (*ObjectcmdObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectNew runs 'ipfs object new': deprecated way to create a new dag-pb object from a template.
+//
+// template: Template to use. Optional.
+//
+// Deprecated: see 'ipfs object new --help'.
+func (c *Client) ObjectNew(ctx context.Context, template string) (ObjectNewResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	if template != "" {
+		args = append(args, template)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "template", Type: cmds.ArgString},
+		},
+		Type: (*ObjectcmdObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "new"}, cmd, o, args, nodes)
+	return ObjectNewResponse{res}, err
+}
+
+// ObjectPatchAddLinkResponse is the output of ObjectPatchAddLink.
+type ObjectPatchAddLinkResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectPatchAddLinkResponse) Next() (*ObjectcmdObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectPatchAddLinkOptions are the options of ObjectPatchAddLink.
+type ObjectPatchAddLinkOptions struct {
+	// Disable block size check and allow creation of blocks bigger than 1MiB. WARNING: such blocks won't be transferable over the standard bitswap. Default: false.
+	AllowBigBlock *bool
+	// Create intermediary nodes.
+	Create *bool
+}
+
+// ObjectPatchAddLink runs 'ipfs object patch add-link': deprecated way to add a link to a given dag-pb.
+//
+// root: The hash of the node to modify.
+//
+// name: Name of link to create.
+//
+// ref: IPFS object to add link to.
+//
+// Deprecated: see 'ipfs object patch add-link --help'.
+func (c *Client) ObjectPatchAddLink(ctx context.Context, root string, name string, ref string, opts *ObjectPatchAddLinkOptions) (ObjectPatchAddLinkResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowBigBlock != nil {
+			o["allow-big-block"] = *opts.AllowBigBlock
+		}
+		if opts.Create != nil {
+			o["create"] = *opts.Create
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, root)
+	args = append(args, name)
+	args = append(args, ref)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "root", Type: cmds.ArgString, Required: true},
+			{Name: "name", Type: cmds.ArgString, Required: true},
+			{Name: "ref", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*ObjectcmdObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "patch", "add-link"}, cmd, o, args, nodes)
+	return ObjectPatchAddLinkResponse{res}, err
+}
+
+// ObjectPatchAppendDataResponse is the output of ObjectPatchAppendData.
+type ObjectPatchAppendDataResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectPatchAppendDataResponse) Next() (*ObjectcmdObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectPatchAppendDataOptions are the options of ObjectPatchAppendData.
+type ObjectPatchAppendDataOptions struct {
+	// Disable block size check and allow creation of blocks bigger than 1MiB. WARNING: such blocks won't be transferable over the standard bitswap. Default: false.
+	AllowBigBlock *bool
+}
+
+// ObjectPatchAppendData runs 'ipfs object patch append-data': deprecated way to append data to the data segment of a DAG node.
+//
+// root: The hash of the node to modify.
+//
+// data: Data to append.
+//
+// Deprecated: see 'ipfs object patch append-data --help'.
+func (c *Client) ObjectPatchAppendData(ctx context.Context, root string, data files.Node, opts *ObjectPatchAppendDataOptions) (ObjectPatchAppendDataResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowBigBlock != nil {
+			o["allow-big-block"] = *opts.AllowBigBlock
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, root)
+	if data != nil {
+		nodes = append(nodes, data)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "root", Type: cmds.ArgString, Required: true},
+			{Name: "data", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+		Type: (*ObjectcmdObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "patch", "append-data"}, cmd, o, args, nodes)
+	return ObjectPatchAppendDataResponse{res}, err
+}
+
+// ObjectPatchRmLinkResponse is the output of ObjectPatchRmLink.
+type ObjectPatchRmLinkResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectPatchRmLinkResponse) Next() (*ObjectcmdObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectPatchRmLinkOptions are the options of ObjectPatchRmLink.
+type ObjectPatchRmLinkOptions struct {
+	// Disable block size check and allow creation of blocks bigger than 1MiB. WARNING: such blocks won't be transferable over the standard bitswap. Default: false.
+	AllowBigBlock *bool
+}
+
+// ObjectPatchRmLink runs 'ipfs object patch rm-link': deprecated way to remove a link from dag-pb object.
+//
+// root: The hash of the node to modify.
+//
+// name: Name of the link to remove.
+//
+// Deprecated: see 'ipfs object patch rm-link --help'.
+func (c *Client) ObjectPatchRmLink(ctx context.Context, root string, name string, opts *ObjectPatchRmLinkOptions) (ObjectPatchRmLinkResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowBigBlock != nil {
+			o["allow-big-block"] = *opts.AllowBigBlock
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, root)
+	args = append(args, name)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "root", Type: cmds.ArgString, Required: true},
+			{Name: "name", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*ObjectcmdObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "patch", "rm-link"}, cmd, o, args, nodes)
+	return ObjectPatchRmLinkResponse{res}, err
+}
+
+// ObjectPatchSetDataResponse is the output of ObjectPatchSetData.
+type ObjectPatchSetDataResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectPatchSetDataResponse) Next() (*ObjectcmdObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectPatchSetDataOptions are the options of ObjectPatchSetData.
+type ObjectPatchSetDataOptions struct {
+	// Disable block size check and allow creation of blocks bigger than 1MiB. WARNING: such blocks won't be transferable over the standard bitswap. Default: false.
+	AllowBigBlock *bool
+}
+
+// ObjectPatchSetData runs 'ipfs object patch set-data': deprecated way to set the data field of dag-pb object.
+//
+// root: The hash of the node to modify.
+//
+// data: The data to set the object to.
+//
+// Deprecated: see 'ipfs object patch set-data --help'.
+func (c *Client) ObjectPatchSetData(ctx context.Context, root string, data files.Node, opts *ObjectPatchSetDataOptions) (ObjectPatchSetDataResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowBigBlock != nil {
+			o["allow-big-block"] = *opts.AllowBigBlock
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, root)
+	if data != nil {
+		nodes = append(nodes, data)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "root", Type: cmds.ArgString, Required: true},
+			{Name: "data", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+		Type: (*ObjectcmdObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "patch", "set-data"}, cmd, o, args, nodes)
+	return ObjectPatchSetDataResponse{res}, err
+}
+
+// ObjectPutResponse is the output of ObjectPut.
+type ObjectPutResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectPutResponse) Next() (*ObjectcmdObject, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ObjectcmdObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectPutOptions are the options of ObjectPut.
+type ObjectPutOptions struct {
+	// Encoding type of the data field, either "text" or "base64". Default: text.
+	Datafieldenc *string
+	// Encoding type of input data. One of: {"protobuf", "json"}. Default: json.
+	Inputenc *string
+	// Pin this object when adding.
+	Pin *bool
+	// Write minimal output.
+	Quiet *bool
+}
+
+// ObjectPut runs 'ipfs object put': deprecated way to store input as a DAG object. Use 'dag put' instead.
+//
+// data: Data to be stored as a dag-pb object.
+//
+// Deprecated: see 'ipfs object put --help'.
+func (c *Client) ObjectPut(ctx context.Context, data files.Node, opts *ObjectPutOptions) (ObjectPutResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Datafieldenc != nil {
+			o["datafieldenc"] = *opts.Datafieldenc
+		}
+		if opts.Inputenc != nil {
+			o["inputenc"] = *opts.Inputenc
+		}
+		if opts.Pin != nil {
+			o["pin"] = *opts.Pin
+		}
+		if opts.Quiet != nil {
+			o["quiet"] = *opts.Quiet
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if data != nil {
+		nodes = append(nodes, data)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "data", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+		Type: (*ObjectcmdObject)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "put"}, cmd, o, args, nodes)
+	return ObjectPutResponse{res}, err
+}
+
+// ObjectStatResponse is the output of ObjectStat.
+type ObjectStatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ObjectStatResponse) Next() (*FormatNodeStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*FormatNodeStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ObjectStatOptions are the options of ObjectStat.
+type ObjectStatOptions struct {
+	// Print sizes in human readable format (e.g., 1K 234M 2G).
+	Human *bool
+}
+
+// ObjectStat runs 'ipfs object stat': deprecated way to read stats for the dag-pb node. Use 'files stat' instead.
+//
+// key: Key of the object to retrieve, in base58-encoded multihash format.
+//
+// Deprecated: see 'ipfs object stat --help'.
+func (c *Client) ObjectStat(ctx context.Context, key string, opts *ObjectStatOptions) (ObjectStatResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Human != nil {
+			o["human"] = *opts.Human
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*FormatNodeStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"object", "stat"}, cmd, o, args, nodes)
+	return ObjectStatResponse{res}, err
+}
+
+// P2PCloseResponse is the output of P2PClose.
+type P2PCloseResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r P2PCloseResponse) Next() (*int, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// P2PCloseOptions are the options of P2PClose.
+type P2PCloseOptions struct {
+	// Close all listeners.
+	All *bool
+	// Match listen address.
+	ListenAddress *string
+	// Match protocol name.
+	Protocol *string
+	// Match target address.
+	TargetAddress *string
+}
+
+// P2PClose runs 'ipfs p2p close': stop listening for new connections to forward.
+func (c *Client) P2PClose(ctx context.Context, opts *P2PCloseOptions) (P2PCloseResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.All != nil {
+			o["all"] = *opts.All
+		}
+		if opts.ListenAddress != nil {
+			o["listen-address"] = *opts.ListenAddress
+		}
+		if opts.Protocol != nil {
+			o["protocol"] = *opts.Protocol
+		}
+		if opts.TargetAddress != nil {
+			o["target-address"] = *opts.TargetAddress
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*int)(nil),
+	}
+	res, err := c.call(ctx, []string{"p2p", "close"}, cmd, o, args, nodes)
+	return P2PCloseResponse{res}, err
+}
+
+// P2PForwardOptions are the options of P2PForward.
+type P2PForwardOptions struct {
+	// Don't require /x/ prefix.
+	AllowCustomProtocol *bool
+	// Close the connections idle for this long, like 10m.
+	IdleTimeout *string
+	// Maximum number of connections forwarded at once, 0 for no limit.
+	MaxStreams *int
+}
+
+// P2PForward runs 'ipfs p2p forward': forward connections to libp2p service.
+//
+// protocol: Protocol name.
+//
+// listenAddress: Listening endpoint.
+//
+// targetAddress: Target endpoint.
+func (c *Client) P2PForward(ctx context.Context, protocol string, listenAddress string, targetAddress string, opts *P2PForwardOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowCustomProtocol != nil {
+			o["allow-custom-protocol"] = *opts.AllowCustomProtocol
+		}
+		if opts.IdleTimeout != nil {
+			o["idle-timeout"] = *opts.IdleTimeout
+		}
+		if opts.MaxStreams != nil {
+			o["max-streams"] = *opts.MaxStreams
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, protocol)
+	args = append(args, listenAddress)
+	args = append(args, targetAddress)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "protocol", Type: cmds.ArgString, Required: true},
+			{Name: "listen-address", Type: cmds.ArgString, Required: true},
+			{Name: "target-address", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"p2p", "forward"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// P2PListenOptions are the options of P2PListen.
+type P2PListenOptions struct {
+	// Don't require /x/ prefix.
+	AllowCustomProtocol *bool
+	// Only allow this peer to open streams. Can be given multiple times.
+	AllowPeer []string
+	// Close the streams idle for this long, like 10m.
+	IdleTimeout *string
+	// Maximum number of streams forwarded at once, 0 for no limit.
+	MaxStreams *int
+	// Send remote base58 peerid to target when a new connection is established.
+	ReportPeerID *bool
+}
+
+// P2PListen runs 'ipfs p2p listen': create libp2p service.
+//
+// protocol: Protocol name.
+//
+// targetAddress: Target endpoint.
+func (c *Client) P2PListen(ctx context.Context, protocol string, targetAddress string, opts *P2PListenOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.AllowCustomProtocol != nil {
+			o["allow-custom-protocol"] = *opts.AllowCustomProtocol
+		}
+		if opts.AllowPeer != nil {
+			o["allow-peer"] = opts.AllowPeer
+		}
+		if opts.IdleTimeout != nil {
+			o["idle-timeout"] = *opts.IdleTimeout
+		}
+		if opts.MaxStreams != nil {
+			o["max-streams"] = *opts.MaxStreams
+		}
+		if opts.ReportPeerID != nil {
+			o["report-peer-id"] = *opts.ReportPeerID
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, protocol)
+	args = append(args, targetAddress)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "protocol", Type: cmds.ArgString, Required: true},
+			{Name: "target-address", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"p2p", "listen"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// P2PLsResponse is the output of P2PLs.
+type P2PLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r P2PLsResponse) Next() (*P2PLsOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*P2PLsOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// P2PLsOptions are the options of P2PLs.
+type P2PLsOptions struct {
+	// Print table headers (Protocol, Listen, Target).
+	Headers *bool
+}
+
+// P2PLs runs 'ipfs p2p ls': list active p2p listeners.
+func (c *Client) P2PLs(ctx context.Context, opts *P2PLsOptions) (P2PLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Headers != nil {
+			o["headers"] = *opts.Headers
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*P2PLsOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"p2p", "ls"}, cmd, o, args, nodes)
+	return P2PLsResponse{res}, err
+}
+
+// P2PStreamCloseOptions are the options of P2PStreamClose.
+type P2PStreamCloseOptions struct {
+	// Close all streams.
+	All *bool
+}
+
+// P2PStreamClose runs 'ipfs p2p stream close': close active p2p stream.
+//
+// id: Stream identifier
+func (c *Client) P2PStreamClose(ctx context.Context, id string, opts *P2PStreamCloseOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.All != nil {
+			o["all"] = *opts.All
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	if id != "" {
+		args = append(args, id)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "id", Type: cmds.ArgString},
+		},
+	}
+	res, err := c.call(ctx, []string{"p2p", "stream", "close"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// P2PStreamLsResponse is the output of P2PStreamLs.
+type P2PStreamLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r P2PStreamLsResponse) Next() (*P2PStreamsOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*P2PStreamsOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// P2PStreamLsOptions are the options of P2PStreamLs.
+type P2PStreamLsOptions struct {
+	// Print table headers (ID, Protocol, Local, Remote).
+	Headers *bool
+}
+
+// P2PStreamLs runs 'ipfs p2p stream ls': list active p2p streams.
+func (c *Client) P2PStreamLs(ctx context.Context, opts *P2PStreamLsOptions) (P2PStreamLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Headers != nil {
+			o["headers"] = *opts.Headers
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*P2PStreamsOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"p2p", "stream", "ls"}, cmd, o, args, nodes)
+	return P2PStreamLsResponse{res}, err
+}
+
+// PetnameLsResponse is the output of PetnameLs.
+type PetnameLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PetnameLsResponse) Next() (*PetnameList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PetnameList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PetnameLs runs 'ipfs petname ls': list petnames.
+func (c *Client) PetnameLs(ctx context.Context) (PetnameLsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*PetnameList)(nil),
+	}
+	res, err := c.call(ctx, []string{"petname", "ls"}, cmd, o, args, nodes)
+	return PetnameLsResponse{res}, err
+}
+
+// PetnameRm runs 'ipfs petname rm': remove a petname.
+//
+// name: The petname to remove.
+func (c *Client) PetnameRm(ctx context.Context, name string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, name)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"petname", "rm"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// PetnameSetResponse is the output of PetnameSet.
+type PetnameSetResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PetnameSetResponse) Next() (*Petname, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*Petname)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PetnameSet runs 'ipfs petname set': set the content path of a petname.
+//
+// name: The petname.
+//
+// path: Content path the petname stands for, /ipfs/ or /ipns/.
+func (c *Client) PetnameSet(ctx context.Context, name string, path string) (PetnameSetResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, name)
+	args = append(args, path)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true},
+			{Name: "path", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*Petname)(nil),
+	}
+	res, err := c.call(ctx, []string{"petname", "set"}, cmd, o, args, nodes)
+	return PetnameSetResponse{res}, err
+}
+
+// PinAddResponse is the output of PinAdd.
+type PinAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinAddResponse) Next() (*PinAddPinOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinAddPinOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinAddOptions are the options of PinAdd.
+type PinAddOptions struct {
+	// Show progress.
+	Progress *bool
+	// Recursively pin the object linked to by the specified object(s). Default: true.
+	Recursive *bool
+}
+
+// PinAdd runs 'ipfs pin add': pin objects to local storage.
+//
+// ipfsPath: Path to object(s) to be pinned.
+func (c *Client) PinAdd(ctx context.Context, ipfsPath []string, opts *PinAddOptions) (PinAddResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Progress != nil {
+			o["progress"] = *opts.Progress
+		}
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*PinAddPinOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "add"}, cmd, o, args, nodes)
+	return PinAddResponse{res}, err
+}
+
+// PinLsResponse is the output of PinLs.
+type PinLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinLsResponse) Next() (*PinLsOutputWrapper, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinLsOutputWrapper)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinLsOptions are the options of PinLs.
+type PinLsOptions struct {
+	// Write just hashes of objects.
+	Quiet *bool
+	// Enable streaming of pins as they are discovered.
+	Stream *bool
+	// The type of pinned keys to list. Can be "direct", "indirect", "recursive", or "all". Default: all.
+	Type *string
+}
+
+// PinLs runs 'ipfs pin ls': list objects pinned to local storage.
+//
+// ipfsPath: Path to object(s) to be listed.
+func (c *Client) PinLs(ctx context.Context, ipfsPath []string, opts *PinLsOptions) (PinLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Quiet != nil {
+			o["quiet"] = *opts.Quiet
+		}
+		if opts.Stream != nil {
+			o["stream"] = *opts.Stream
+		}
+		if opts.Type != nil {
+			o["type"] = *opts.Type
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Variadic: true},
+		},
+		Type: (*PinLsOutputWrapper)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "ls"}, cmd, o, args, nodes)
+	return PinLsResponse{res}, err
+}
+
+// PinRemoteAddResponse is the output of PinRemoteAdd.
+type PinRemoteAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinRemoteAddResponse) Next() (*PinRemotePinOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinRemotePinOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinRemoteAddOptions are the options of PinRemoteAdd.
+type PinRemoteAddOptions struct {
+	// Add to the queue on the remote service and return immediately (does not wait for pinned status). Default: false.
+	Background *bool
+	// An optional name for the pin.
+	Name *string
+	// Name of the remote pinning service to use (mandatory).
+	Service *string
+}
+
+// PinRemoteAdd runs 'ipfs pin remote add': pin object to remote pinning service.
+//
+// ipfsPath: Path to object(s) to be pinned.
+func (c *Client) PinRemoteAdd(ctx context.Context, ipfsPath string, opts *PinRemoteAddOptions) (PinRemoteAddResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Background != nil {
+			o["background"] = *opts.Background
+		}
+		if opts.Name != nil {
+			o["name"] = *opts.Name
+		}
+		if opts.Service != nil {
+			o["service"] = *opts.Service
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*PinRemotePinOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "remote", "add"}, cmd, o, args, nodes)
+	return PinRemoteAddResponse{res}, err
+}
+
+// PinRemoteLsResponse is the output of PinRemoteLs.
+type PinRemoteLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinRemoteLsResponse) Next() (*PinRemotePinOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinRemotePinOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinRemoteLsOptions are the options of PinRemoteLs.
+type PinRemoteLsOptions struct {
+	// Return pins for the specified CIDs (comma-separated).
+	CID []string
+	// Return pins with names that contain the value provided (case-sensitive, exact match).
+	Name *string
+	// Name of the remote pinning service to use (mandatory).
+	Service *string
+	// Return pins with the specified statuses (queued,pinning,pinned,failed). Default: [pinned].
+	Status []string
+}
+
+// PinRemoteLs runs 'ipfs pin remote ls': list objects pinned to remote pinning service.
+func (c *Client) PinRemoteLs(ctx context.Context, opts *PinRemoteLsOptions) (PinRemoteLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.CID != nil {
+			o["cid"] = opts.CID
+		}
+		if opts.Name != nil {
+			o["name"] = *opts.Name
+		}
+		if opts.Service != nil {
+			o["service"] = *opts.Service
+		}
+		if opts.Status != nil {
+			o["status"] = opts.Status
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*PinRemotePinOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "remote", "ls"}, cmd, o, args, nodes)
+	return PinRemoteLsResponse{res}, err
+}
+
+// PinRemoteRmOptions are the options of PinRemoteRm.
+type PinRemoteRmOptions struct {
+	// Remove pins for the specified CIDs.
+	CID []string
+	// Allow removal of multiple pins matching the query without additional confirmation. Default: false.
+	Force *bool
+	// Remove pins with names that contain provided value (case-sensitive, exact match).
+	Name *string
+	// Name of the remote pinning service to use (mandatory).
+	Service *string
+	// Remove pins with the specified statuses (queued,pinning,pinned,failed). Default: [pinned].
+	Status []string
+}
+
+// PinRemoteRm runs 'ipfs pin remote rm': remove pins from remote pinning service.
+func (c *Client) PinRemoteRm(ctx context.Context, opts *PinRemoteRmOptions) (*Response, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.CID != nil {
+			o["cid"] = opts.CID
+		}
+		if opts.Force != nil {
+			o["force"] = *opts.Force
+		}
+		if opts.Name != nil {
+			o["name"] = *opts.Name
+		}
+		if opts.Service != nil {
+			o["service"] = *opts.Service
+		}
+		if opts.Status != nil {
+			o["status"] = opts.Status
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{}
+	res, err := c.call(ctx, []string{"pin", "remote", "rm"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// PinRemoteServiceAdd runs 'ipfs pin remote service add': add remote pinning service.
+//
+// service: Service name.
+//
+// endpoint: Service endpoint.
+//
+// key: Service key.
+func (c *Client) PinRemoteServiceAdd(ctx context.Context, service string, endpoint string, key string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, service)
+	args = append(args, endpoint)
+	args = append(args, key)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "service", Type: cmds.ArgString, Required: true},
+			{Name: "endpoint", Type: cmds.ArgString, Required: true},
+			{Name: "key", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"pin", "remote", "service", "add"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// PinRemoteServiceLsResponse is the output of PinRemoteServiceLs.
+type PinRemoteServiceLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinRemoteServiceLsResponse) Next() (*PinServicesList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinServicesList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinRemoteServiceLsOptions are the options of PinRemoteServiceLs.
+type PinRemoteServiceLsOptions struct {
+	// Try to fetch and display current pin count on remote service (queued/pinning/pinned/failed). Default: false.
+	Stat *bool
+}
+
+// PinRemoteServiceLs runs 'ipfs pin remote service ls': list remote pinning services.
+func (c *Client) PinRemoteServiceLs(ctx context.Context, opts *PinRemoteServiceLsOptions) (PinRemoteServiceLsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Stat != nil {
+			o["stat"] = *opts.Stat
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*PinServicesList)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "remote", "service", "ls"}, cmd, o, args, nodes)
+	return PinRemoteServiceLsResponse{res}, err
+}
+
+// PinRemoteServiceRm runs 'ipfs pin remote service rm': remove remote pinning service.
+//
+// service: Name of remote pinning service to remove.
+func (c *Client) PinRemoteServiceRm(ctx context.Context, service string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, service)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "service", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"pin", "remote", "service", "rm"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// PinRmResponse is the output of PinRm.
+type PinRmResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinRmResponse) Next() (*PinOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinRmOptions are the options of PinRm.
+type PinRmOptions struct {
+	// Recursively unpin the object linked to by the specified object(s). Default: true.
+	Recursive *bool
+}
+
+// PinRm runs 'ipfs pin rm': remove pinned objects from local storage.
+//
+// ipfsPath: Path to object(s) to be unpinned.
+func (c *Client) PinRm(ctx context.Context, ipfsPath []string, opts *PinRmOptions) (PinRmResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*PinOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "rm"}, cmd, o, args, nodes)
+	return PinRmResponse{res}, err
+}
+
+// PinUpdateResponse is the output of PinUpdate.
+type PinUpdateResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinUpdateResponse) Next() (*PinOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinUpdateOptions are the options of PinUpdate.
+type PinUpdateOptions struct {
+	// Remove the old pin. Default: true.
+	Unpin *bool
+}
+
+// PinUpdate runs 'ipfs pin update': update a recursive pin.
+//
+// fromPath: Path to old object.
+//
+// toPath: Path to a new object to be pinned.
+func (c *Client) PinUpdate(ctx context.Context, fromPath string, toPath string, opts *PinUpdateOptions) (PinUpdateResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Unpin != nil {
+			o["unpin"] = *opts.Unpin
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, fromPath)
+	args = append(args, toPath)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "from-path", Type: cmds.ArgString, Required: true},
+			{Name: "to-path", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*PinOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "update"}, cmd, o, args, nodes)
+	return PinUpdateResponse{res}, err
+}
+
+// PinVerifyResponse is the output of PinVerify.
+type PinVerifyResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PinVerifyResponse) Next() (*PinVerifyRes, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PinVerifyRes)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PinVerifyOptions are the options of PinVerify.
+type PinVerifyOptions struct {
+	// Write just hashes of broken pins.
+	Quiet *bool
+	// Also write the hashes of non-broken pins.
+	Verbose *bool
+}
+
+// PinVerify runs 'ipfs pin verify': verify that recursive pins are complete.
+func (c *Client) PinVerify(ctx context.Context, opts *PinVerifyOptions) (PinVerifyResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Quiet != nil {
+			o["quiet"] = *opts.Quiet
+		}
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*PinVerifyRes)(nil),
+	}
+	res, err := c.call(ctx, []string{"pin", "verify"}, cmd, o, args, nodes)
+	return PinVerifyResponse{res}, err
+}
+
+// PingResponse is the output of Ping.
+type PingResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PingResponse) Next() (*PingResult, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PingResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PingOptions are the options of Ping.
+type PingOptions struct {
+	// Number of ping messages to send. Default: 10.
+	Count *int
+}
+
+// Ping runs 'ipfs ping': send echo request packets to IPFS hosts.
+//
+// peerID: ID of peer to be pinged.
+func (c *Client) Ping(ctx context.Context, peerID []string, opts *PingOptions) (PingResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Count != nil {
+			o["count"] = *opts.Count
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, peerID...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peer ID", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*PingResult)(nil),
+	}
+	res, err := c.call(ctx, []string{"ping"}, cmd, o, args, nodes)
+	return PingResponse{res}, err
+}
+
+// PubsubLsResponse is the output of PubsubLs.
+type PubsubLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PubsubLsResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PubsubLs runs 'ipfs pubsub ls': list subscribed topics by name.
+func (c *Client) PubsubLs(ctx context.Context) (PubsubLsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"pubsub", "ls"}, cmd, o, args, nodes)
+	return PubsubLsResponse{res}, err
+}
+
+// PubsubPeersResponse is the output of PubsubPeers.
+type PubsubPeersResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PubsubPeersResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PubsubPeers runs 'ipfs pubsub peers': list peers we are currently pubsubbing with.
+//
+// topic: Topic to list connected peers of.
+func (c *Client) PubsubPeers(ctx context.Context, topic string) (PubsubPeersResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	if topic != "" {
+		args = append(args, topic)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "topic", Type: cmds.ArgString},
+		},
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"pubsub", "peers"}, cmd, o, args, nodes)
+	return PubsubPeersResponse{res}, err
+}
+
+// PubsubPub runs 'ipfs pubsub pub': publish data to a given pubsub topic.
+//
+// topic: Topic to publish to.
+//
+// data: The data to be published.
+func (c *Client) PubsubPub(ctx context.Context, topic string, data files.Node) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, topic)
+	if data != nil {
+		nodes = append(nodes, data)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "topic", Type: cmds.ArgString, Required: true},
+			{Name: "data", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"pubsub", "pub"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// PubsubSubResponse is the output of PubsubSub.
+type PubsubSubResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r PubsubSubResponse) Next() (*PubsubMessage, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PubsubMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// PubsubSub runs 'ipfs pubsub sub': subscribe to messages on a given topic.
+//
+// topic: Name of topic to subscribe to.
+func (c *Client) PubsubSub(ctx context.Context, topic string) (PubsubSubResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, topic)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "topic", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*PubsubMessage)(nil),
+	}
+	res, err := c.call(ctx, []string{"pubsub", "sub"}, cmd, o, args, nodes)
+	return PubsubSubResponse{res}, err
+}
+
+// RefsResponse is the output of Refs.
+type RefsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RefsResponse) Next() (*RefWrapper, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RefWrapper)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RefsOptions are the options of Refs.
+type RefsOptions struct {
+	// Emit edge format: `<from> -> <to>`.
+	Edges *bool
+	// Emit edges with given format. Available tokens: <src> <dst> <linkname>. Default: <dst>.
+	Format *string
+	// Only for recursive refs, limits fetch and listing to the given depth. Default: -1.
+	MaxDepth *int
+	// Recursively list links of child nodes.
+	Recursive *bool
+	// Omit duplicate refs from output.
+	Unique *bool
+}
+
+// Refs runs 'ipfs refs': list links (references) from an object.
+//
+// ipfsPath: Path to the object(s) to list refs from.
+func (c *Client) Refs(ctx context.Context, ipfsPath []string, opts *RefsOptions) (RefsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Edges != nil {
+			o["edges"] = *opts.Edges
+		}
+		if opts.Format != nil {
+			o["format"] = *opts.Format
+		}
+		if opts.MaxDepth != nil {
+			o["max-depth"] = *opts.MaxDepth
+		}
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+		if opts.Unique != nil {
+			o["unique"] = *opts.Unique
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, ipfsPath...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ipfs-path", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*RefWrapper)(nil),
+	}
+	res, err := c.call(ctx, []string{"refs"}, cmd, o, args, nodes)
+	return RefsResponse{res}, err
+}
+
+// RefsLocalResponse is the output of RefsLocal.
+type RefsLocalResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RefsLocalResponse) Next() (*RefWrapper, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RefWrapper)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RefsLocalOptions are the options of RefsLocal.
+type RefsLocalOptions struct {
+	// Emit edge format: `<from> -> <to>`.
+	Edges *bool
+	// Emit edges with given format. Available tokens: <src> <dst> <linkname>. Default: <dst>.
+	Format *string
+	// Only for recursive refs, limits fetch and listing to the given depth. Default: -1.
+	MaxDepth *int
+	// Recursively list links of child nodes.
+	Recursive *bool
+	// Omit duplicate refs from output.
+	Unique *bool
+}
+
+// RefsLocal runs 'ipfs refs local': list all local references.
+func (c *Client) RefsLocal(ctx context.Context, opts *RefsLocalOptions) (RefsLocalResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Edges != nil {
+			o["edges"] = *opts.Edges
+		}
+		if opts.Format != nil {
+			o["format"] = *opts.Format
+		}
+		if opts.MaxDepth != nil {
+			o["max-depth"] = *opts.MaxDepth
+		}
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+		if opts.Unique != nil {
+			o["unique"] = *opts.Unique
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*RefWrapper)(nil),
+	}
+	res, err := c.call(ctx, []string{"refs", "local"}, cmd, o, args, nodes)
+	return RefsLocalResponse{res}, err
+}
+
+// RepoGcResponse is the output of RepoGc.
+type RepoGcResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RepoGcResponse) Next() (*GcResult, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*GcResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RepoGcOptions are the options of RepoGc.
+type RepoGcOptions struct {
+	// Write minimal output.
+	Quiet *bool
+	// Write no output.
+	Silent *bool
+	// Stream errors.
+	StreamErrors *bool
+}
+
+// RepoGc runs 'ipfs repo gc': perform a garbage collection sweep on the repo.
+func (c *Client) RepoGc(ctx context.Context, opts *RepoGcOptions) (RepoGcResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Quiet != nil {
+			o["quiet"] = *opts.Quiet
+		}
+		if opts.Silent != nil {
+			o["silent"] = *opts.Silent
+		}
+		if opts.StreamErrors != nil {
+			o["stream-errors"] = *opts.StreamErrors
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*GcResult)(nil),
+	}
+	res, err := c.call(ctx, []string{"repo", "gc"}, cmd, o, args, nodes)
+	return RepoGcResponse{res}, err
+}
+
+// RepoQuarantineLsResponse is the output of RepoQuarantineLs.
+type RepoQuarantineLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RepoQuarantineLsResponse) Next() (*QuarantineEntry, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*QuarantineEntry)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RepoQuarantineLs runs 'ipfs repo quarantine ls': list the quarantined blocks.
+func (c *Client) RepoQuarantineLs(ctx context.Context) (RepoQuarantineLsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*QuarantineEntry)(nil),
+	}
+	res, err := c.call(ctx, []string{"repo", "quarantine", "ls"}, cmd, o, args, nodes)
+	return RepoQuarantineLsResponse{res}, err
+}
+
+// RepoQuarantineRm runs 'ipfs repo quarantine rm': remove blocks from the quarantine.
+//
+// cid: CIDs of the quarantined blocks to remove.
+func (c *Client) RepoQuarantineRm(ctx context.Context, cid []string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"repo", "quarantine", "rm"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// RepoStatResponse is the output of RepoStat.
+type RepoStatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RepoStatResponse) Next() (*CorerepoStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*CorerepoStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RepoStatOptions are the options of RepoStat.
+type RepoStatOptions struct {
+	// Print sizes in human readable format (e.g., 1K 234M 2G).
+	Human *bool
+	// Only report RepoSize and StorageMax.
+	SizeOnly *bool
+}
+
+// RepoStat runs 'ipfs repo stat': get stats for the currently used repo.
+func (c *Client) RepoStat(ctx context.Context, opts *RepoStatOptions) (RepoStatResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Human != nil {
+			o["human"] = *opts.Human
+		}
+		if opts.SizeOnly != nil {
+			o["size-only"] = *opts.SizeOnly
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*CorerepoStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"repo", "stat"}, cmd, o, args, nodes)
+	return RepoStatResponse{res}, err
+}
+
+// RepoVerifyResponse is the output of RepoVerify.
+type RepoVerifyResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RepoVerifyResponse) Next() (*VerifyProgress, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*VerifyProgress)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RepoVerify runs 'ipfs repo verify': verify all blocks in repo are not corrupted.
+func (c *Client) RepoVerify(ctx context.Context) (RepoVerifyResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*VerifyProgress)(nil),
+	}
+	res, err := c.call(ctx, []string{"repo", "verify"}, cmd, o, args, nodes)
+	return RepoVerifyResponse{res}, err
+}
+
+// RepoVersionResponse is the output of RepoVersion.
+type RepoVersionResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RepoVersionResponse) Next() (*RepoVersion, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RepoVersion)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RepoVersionOptions are the options of RepoVersion.
+type RepoVersionOptions struct {
+	// Write minimal output.
+	Quiet *bool
+}
+
+// RepoVersion runs 'ipfs repo version': show the repo version.
+func (c *Client) RepoVersion(ctx context.Context, opts *RepoVersionOptions) (RepoVersionResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Quiet != nil {
+			o["quiet"] = *opts.Quiet
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*RepoVersion)(nil),
+	}
+	res, err := c.call(ctx, []string{"repo", "version"}, cmd, o, args, nodes)
+	return RepoVersionResponse{res}, err
+}
+
+// ResolveResponse is the output of Resolve.
+type ResolveResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r ResolveResponse) Next() (*NameResolvedPath, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NameResolvedPath)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// ResolveOptions are the options of Resolve.
+type ResolveOptions struct {
+	// Number of records to request for DHT resolution.
+	DHTRecordCount *int
+	// Max time to collect values during DHT resolution eg "30s". Pass 0 for no timeout.
+	DHTTimeout *string
+	// Resolve until the result is an IPFS name. Default: true.
+	Recursive *bool
+}
+
+// Resolve runs 'ipfs resolve': resolve the value of names to IPFS.
+//
+// name: The name to resolve.
+func (c *Client) Resolve(ctx context.Context, name string, opts *ResolveOptions) (ResolveResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.DHTRecordCount != nil {
+			o["dht-record-count"] = *opts.DHTRecordCount
+		}
+		if opts.DHTTimeout != nil {
+			o["dht-timeout"] = *opts.DHTTimeout
+		}
+		if opts.Recursive != nil {
+			o["recursive"] = *opts.Recursive
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, name)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "name", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+		Type: (*NameResolvedPath)(nil),
+	}
+	res, err := c.call(ctx, []string{"resolve"}, cmd, o, args, nodes)
+	return ResolveResponse{res}, err
+}
+
+// RoutingFindprovsResponse is the output of RoutingFindprovs.
+type RoutingFindprovsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RoutingFindprovsResponse) Next() (*RoutingFindProvsOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RoutingFindProvsOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RoutingFindprovsOptions are the options of RoutingFindprovs.
+type RoutingFindprovsOptions struct {
+	// The number of providers to find. Default: 20.
+	NumProviders *int
+	// Report the routers queried.
+	Trace *bool
+}
+
+// RoutingFindprovs runs 'ipfs routing findprovs': find the providers of a CID with all the routers.
+//
+// cid: The CID to find the providers of.
+func (c *Client) RoutingFindprovs(ctx context.Context, cid string, opts *RoutingFindprovsOptions) (RoutingFindprovsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.NumProviders != nil {
+			o["num-providers"] = *opts.NumProviders
+		}
+		if opts.Trace != nil {
+			o["trace"] = *opts.Trace
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, cid)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "cid", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*RoutingFindProvsOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"routing", "findprovs"}, cmd, o, args, nodes)
+	return RoutingFindprovsResponse{res}, err
+}
+
+// RoutingStatResponse is the output of RoutingStat.
+type RoutingStatResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RoutingStatResponse) Next() (*RoutingStatOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RoutingStatOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RoutingStat runs 'ipfs routing stat': show the health of the routers.
+func (c *Client) RoutingStat(ctx context.Context) (RoutingStatResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*RoutingStatOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"routing", "stat"}, cmd, o, args, nodes)
+	return RoutingStatResponse{res}, err
+}
+
+// RoutingTraceResponse is the output of RoutingTrace.
+type RoutingTraceResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r RoutingTraceResponse) Next() (*RoutingTrace, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*RoutingTrace)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// RoutingTraceOptions are the options of RoutingTrace.
+type RoutingTraceOptions struct {
+	// The number of providers to find. Default: 20.
+	NumProviders *int
+	// Look up the providers of a CID.
+	Providers *bool
+}
+
+// RoutingTrace runs 'ipfs routing trace': trace a DHT lookup, hop by hop.
+//
+// key: The peer ID, or the CID with --providers, to look up.
+func (c *Client) RoutingTrace(ctx context.Context, key string, opts *RoutingTraceOptions) (RoutingTraceResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.NumProviders != nil {
+			o["num-providers"] = *opts.NumProviders
+		}
+		if opts.Providers != nil {
+			o["providers"] = *opts.Providers
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, key)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "key", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*RoutingTrace)(nil),
+	}
+	res, err := c.call(ctx, []string{"routing", "trace"}, cmd, o, args, nodes)
+	return RoutingTraceResponse{res}, err
+}
+
+// Shutdown runs 'ipfs shutdown': shut down the IPFS daemon.
+func (c *Client) Shutdown(ctx context.Context) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{}
+	res, err := c.call(ctx, []string{"shutdown"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// StandbyPromoteResponse is the output of StandbyPromote.
+type StandbyPromoteResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r StandbyPromoteResponse) Next() (*StandbyPromoteOutput, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StandbyPromoteOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// StandbyPromote runs 'ipfs standby promote': promote this standby to take over from its primary.
+func (c *Client) StandbyPromote(ctx context.Context) (StandbyPromoteResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*StandbyPromoteOutput)(nil),
+	}
+	res, err := c.call(ctx, []string{"standby", "promote"}, cmd, o, args, nodes)
+	return StandbyPromoteResponse{res}, err
+}
+
+// StandbyStatusResponse is the output of StandbyStatus.
+type StandbyStatusResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r StandbyStatusResponse) Next() (*NodeStandbyStatus, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*NodeStandbyStatus)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// StandbyStatus runs 'ipfs standby status': show the state of the mirroring of the primary.
+func (c *Client) StandbyStatus(ctx context.Context) (StandbyStatusResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*NodeStandbyStatus)(nil),
+	}
+	res, err := c.call(ctx, []string{"standby", "status"}, cmd, o, args, nodes)
+	return StandbyStatusResponse{res}, err
+}
+
+// StatsBitswapResponse is the output of StatsBitswap.
+type StatsBitswapResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r StatsBitswapResponse) Next() (*BitswapStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BitswapStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// StatsBitswapOptions are the options of StatsBitswap.
+type StatsBitswapOptions struct {
+	// Print sizes in human readable format (e.g., 1K 234M 2G).
+	Human *bool
+	// Print extra information.
+	Verbose *bool
+}
+
+// StatsBitswap runs 'ipfs stats bitswap': show some diagnostic information on the bitswap agent.
+func (c *Client) StatsBitswap(ctx context.Context, opts *StatsBitswapOptions) (StatsBitswapResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Human != nil {
+			o["human"] = *opts.Human
+		}
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BitswapStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"stats", "bitswap"}, cmd, o, args, nodes)
+	return StatsBitswapResponse{res}, err
+}
+
+// StatsBwResponse is the output of StatsBw.
+type StatsBwResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r StatsBwResponse) Next() (*metrics.Stats, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*metrics.Stats)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// StatsBwOptions are the options of StatsBw.
+type StatsBwOptions struct {
+	// Time interval to wait between updating output, if 'poll' is true. This accepts durations such as "300s", "1.5h" or "2h45m". Valid time units are: "ns", "us" (or "µs"), "ms", "s", "m", "h". Default: 1s.
+	Interval *string
+	// Specify a peer to print bandwidth for.
+	Peer *string
+	// Print bandwidth at an interval.
+	Poll *bool
+	// Specify a protocol to print bandwidth for.
+	Proto *string
+}
+
+// StatsBw runs 'ipfs stats bw': print IPFS bandwidth information.
+func (c *Client) StatsBw(ctx context.Context, opts *StatsBwOptions) (StatsBwResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Interval != nil {
+			o["interval"] = *opts.Interval
+		}
+		if opts.Peer != nil {
+			o["peer"] = *opts.Peer
+		}
+		if opts.Poll != nil {
+			o["poll"] = *opts.Poll
+		}
+		if opts.Proto != nil {
+			o["proto"] = *opts.Proto
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*metrics.Stats)(nil),
+	}
+	res, err := c.call(ctx, []string{"stats", "bw"}, cmd, o, args, nodes)
+	return StatsBwResponse{res}, err
+}
+
+// StatsDHTResponse is the output of StatsDHT.
+type StatsDHTResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r StatsDHTResponse) Next() (*DhtStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*DhtStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// StatsDHT runs 'ipfs stats dht': returns statistics about the node's DHT(s).
+//
+// dht: The DHT whose table should be listed (wanserver, lanserver, wan, lan). wan and lan refer to client routing tables. When using the experimental DHT client only WAN is supported. Defaults to wan and lan.
+func (c *Client) StatsDHT(ctx context.Context, dht []string) (StatsDHTResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, dht...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "dht", Type: cmds.ArgString, Variadic: true},
+		},
+		Type: (*DhtStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"stats", "dht"}, cmd, o, args, nodes)
+	return StatsDHTResponse{res}, err
+}
+
+// StatsProvideResponse is the output of StatsProvide.
+type StatsProvideResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r StatsProvideResponse) Next() (*BatchedProviderStats, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BatchedProviderStats)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// StatsProvide runs 'ipfs stats provide': returns statistics about the node's (re)provider system.
+func (c *Client) StatsProvide(ctx context.Context) (StatsProvideResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*BatchedProviderStats)(nil),
+	}
+	res, err := c.call(ctx, []string{"stats", "provide"}, cmd, o, args, nodes)
+	return StatsProvideResponse{res}, err
+}
+
+// StatsRepoResponse is the output of StatsRepo.
+type StatsRepoResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r StatsRepoResponse) Next() (*CorerepoStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*CorerepoStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// StatsRepoOptions are the options of StatsRepo.
+type StatsRepoOptions struct {
+	// Print sizes in human readable format (e.g., 1K 234M 2G).
+	Human *bool
+	// Only report RepoSize and StorageMax.
+	SizeOnly *bool
+}
+
+// StatsRepo runs 'ipfs stats repo': get stats for the currently used repo.
+func (c *Client) StatsRepo(ctx context.Context, opts *StatsRepoOptions) (StatsRepoResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Human != nil {
+			o["human"] = *opts.Human
+		}
+		if opts.SizeOnly != nil {
+			o["size-only"] = *opts.SizeOnly
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*CorerepoStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"stats", "repo"}, cmd, o, args, nodes)
+	return StatsRepoResponse{res}, err
+}
+
+// SwarmAddrsResponse is the output of SwarmAddrs.
+type SwarmAddrsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmAddrsResponse) Next() (*AddrMap, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*AddrMap)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmAddrs runs 'ipfs swarm addrs': list known addresses. Useful for debugging.
+func (c *Client) SwarmAddrs(ctx context.Context) (SwarmAddrsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*AddrMap)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "addrs"}, cmd, o, args, nodes)
+	return SwarmAddrsResponse{res}, err
+}
+
+// SwarmAddrsListenResponse is the output of SwarmAddrsListen.
+type SwarmAddrsListenResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmAddrsListenResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmAddrsListen runs 'ipfs swarm addrs listen': list interface listening addresses.
+func (c *Client) SwarmAddrsListen(ctx context.Context) (SwarmAddrsListenResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "addrs", "listen"}, cmd, o, args, nodes)
+	return SwarmAddrsListenResponse{res}, err
+}
+
+// SwarmAddrsLocalResponse is the output of SwarmAddrsLocal.
+type SwarmAddrsLocalResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmAddrsLocalResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmAddrsLocalOptions are the options of SwarmAddrsLocal.
+type SwarmAddrsLocalOptions struct {
+	// Show peer ID in addresses.
+	ID *bool
+}
+
+// SwarmAddrsLocal runs 'ipfs swarm addrs local': list local addresses.
+func (c *Client) SwarmAddrsLocal(ctx context.Context, opts *SwarmAddrsLocalOptions) (SwarmAddrsLocalResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.ID != nil {
+			o["id"] = *opts.ID
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "addrs", "local"}, cmd, o, args, nodes)
+	return SwarmAddrsLocalResponse{res}, err
+}
+
+// SwarmAllowlistResponse is the output of SwarmAllowlist.
+type SwarmAllowlistResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmAllowlistResponse) Next() (*Libp2pInboundAllowlistStatus, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*Libp2pInboundAllowlistStatus)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmAllowlist runs 'ipfs swarm allowlist': show the peers admitted by the inbound allowlist.
+func (c *Client) SwarmAllowlist(ctx context.Context) (SwarmAllowlistResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*Libp2pInboundAllowlistStatus)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "allowlist"}, cmd, o, args, nodes)
+	return SwarmAllowlistResponse{res}, err
+}
+
+// SwarmAllowlistCertifyResponse is the output of SwarmAllowlistCertify.
+type SwarmAllowlistCertifyResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmAllowlistCertifyResponse) Next() (*AllowlistCertificate, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*AllowlistCertificate)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmAllowlistCertifyOptions are the options of SwarmAllowlistCertify.
+type SwarmAllowlistCertifyOptions struct {
+	// The name of the key to sign the certificate with. Default: self.
+	Key *string
+	// How long the certificate is valid for. Default: 8760h.
+	Lifetime *string
+}
+
+// SwarmAllowlistCertify runs 'ipfs swarm allowlist certify': issue the certificate of a peer for the inbound allowlists.
+//
+// peerID: The peer to certify.
+func (c *Client) SwarmAllowlistCertify(ctx context.Context, peerID string, opts *SwarmAllowlistCertifyOptions) (SwarmAllowlistCertifyResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Key != nil {
+			o["key"] = *opts.Key
+		}
+		if opts.Lifetime != nil {
+			o["lifetime"] = *opts.Lifetime
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, peerID)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "peer-id", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*AllowlistCertificate)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "allowlist", "certify"}, cmd, o, args, nodes)
+	return SwarmAllowlistCertifyResponse{res}, err
+}
+
+// SwarmConnectResponse is the output of SwarmConnect.
+type SwarmConnectResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmConnectResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmConnect runs 'ipfs swarm connect': open connection to a given address.
+//
+// address: Address of peer to connect to.
+func (c *Client) SwarmConnect(ctx context.Context, address []string) (SwarmConnectResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, address...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "address", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "connect"}, cmd, o, args, nodes)
+	return SwarmConnectResponse{res}, err
+}
+
+// SwarmDisconnectResponse is the output of SwarmDisconnect.
+type SwarmDisconnectResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmDisconnectResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmDisconnectOptions are the options of SwarmDisconnect.
+type SwarmDisconnectOptions struct {
+	// Close the connections to every peer.
+	All *bool
+	// With --all, only close the connections in this direction: inbound or outbound.
+	Direction *string
+	// List the connections that would be closed, without closing them.
+	DryRun *bool
+	// With --all, keep the connections to the peers of the peering subsystem.
+	ExceptPeering *bool
+	// With --all, keep the connections to the peers protected in the connection manager.
+	ExceptProtected *bool
+	// With --all, only close the connections to the peers with this connection manager tag.
+	Tag *string
+	// With --all, only close the connections over this transport (e.g. quic, tcp, ws).
+	Transport *string
+}
+
+// SwarmDisconnect runs 'ipfs swarm disconnect': close connection to a given address.
+//
+// address: Address of peer to disconnect from.
+func (c *Client) SwarmDisconnect(ctx context.Context, address []string, opts *SwarmDisconnectOptions) (SwarmDisconnectResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.All != nil {
+			o["all"] = *opts.All
+		}
+		if opts.Direction != nil {
+			o["direction"] = *opts.Direction
+		}
+		if opts.DryRun != nil {
+			o["dry-run"] = *opts.DryRun
+		}
+		if opts.ExceptPeering != nil {
+			o["except-peering"] = *opts.ExceptPeering
+		}
+		if opts.ExceptProtected != nil {
+			o["except-protected"] = *opts.ExceptProtected
+		}
+		if opts.Tag != nil {
+			o["tag"] = *opts.Tag
+		}
+		if opts.Transport != nil {
+			o["transport"] = *opts.Transport
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, address...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "address", Type: cmds.ArgString, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "disconnect"}, cmd, o, args, nodes)
+	return SwarmDisconnectResponse{res}, err
+}
+
+// SwarmFiltersResponse is the output of SwarmFilters.
+type SwarmFiltersResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmFiltersResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmFilters runs 'ipfs swarm filters': manipulate address filters.
+func (c *Client) SwarmFilters(ctx context.Context) (SwarmFiltersResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "filters"}, cmd, o, args, nodes)
+	return SwarmFiltersResponse{res}, err
+}
+
+// SwarmFiltersAddResponse is the output of SwarmFiltersAdd.
+type SwarmFiltersAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmFiltersAddResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmFiltersAdd runs 'ipfs swarm filters add': add an address filter.
+//
+// address: Multiaddr to filter.
+func (c *Client) SwarmFiltersAdd(ctx context.Context, address []string) (SwarmFiltersAddResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, address...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "address", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "filters", "add"}, cmd, o, args, nodes)
+	return SwarmFiltersAddResponse{res}, err
+}
+
+// SwarmFiltersRmResponse is the output of SwarmFiltersRm.
+type SwarmFiltersRmResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmFiltersRmResponse) Next() (*StringList, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*StringList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmFiltersRm runs 'ipfs swarm filters rm': remove an address filter.
+//
+// address: Multiaddr filter to remove.
+func (c *Client) SwarmFiltersRm(ctx context.Context, address []string) (SwarmFiltersRmResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, address...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "address", Type: cmds.ArgString, Required: true, Variadic: true, SupportsStdin: true},
+		},
+		Type: (*StringList)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "filters", "rm"}, cmd, o, args, nodes)
+	return SwarmFiltersRmResponse{res}, err
+}
+
+// SwarmLimit runs 'ipfs swarm limit': get or set resource limits for a scope.
+//
+// scope: scope of the limit
+//
+// limitJSON: limits to be set
+func (c *Client) SwarmLimit(ctx context.Context, scope string, limitJSON files.Node) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, scope)
+	if limitJSON != nil {
+		nodes = append(nodes, limitJSON)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "scope", Type: cmds.ArgString, Required: true},
+			{Name: "limit.json", Type: cmds.ArgFile, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"swarm", "limit"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// SwarmPauseResponse is the output of SwarmPause.
+type SwarmPauseResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmPauseResponse) Next() (*Libp2pSwarmBrakeStatus, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*Libp2pSwarmBrakeStatus)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmPause runs 'ipfs swarm pause': stop accepting and dialing new connections.
+func (c *Client) SwarmPause(ctx context.Context) (SwarmPauseResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*Libp2pSwarmBrakeStatus)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "pause"}, cmd, o, args, nodes)
+	return SwarmPauseResponse{res}, err
+}
+
+// SwarmPeeringAddResponse is the output of SwarmPeeringAdd.
+type SwarmPeeringAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmPeeringAddResponse) Next() (*PeeringResult, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PeeringResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmPeeringAdd runs 'ipfs swarm peering add': add peers into the peering subsystem.
+//
+// address: address of peer to add into the peering subsystem
+func (c *Client) SwarmPeeringAdd(ctx context.Context, address []string) (SwarmPeeringAddResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, address...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "address", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+		Type: (*PeeringResult)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "peering", "add"}, cmd, o, args, nodes)
+	return SwarmPeeringAddResponse{res}, err
+}
+
+// SwarmPeeringLsResponse is the output of SwarmPeeringLs.
+type SwarmPeeringLsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmPeeringLsResponse) Next() (*AddrInfos, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*AddrInfos)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmPeeringLs runs 'ipfs swarm peering ls': list peers registered in the peering subsystem.
+func (c *Client) SwarmPeeringLs(ctx context.Context) (SwarmPeeringLsResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*AddrInfos)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "peering", "ls"}, cmd, o, args, nodes)
+	return SwarmPeeringLsResponse{res}, err
+}
+
+// SwarmPeeringRmResponse is the output of SwarmPeeringRm.
+type SwarmPeeringRmResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmPeeringRmResponse) Next() (*PeeringResult, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*PeeringResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmPeeringRm runs 'ipfs swarm peering rm': remove a peer from the peering subsystem.
+//
+// id: ID of peer to remove from the peering subsystem
+func (c *Client) SwarmPeeringRm(ctx context.Context, id []string) (SwarmPeeringRmResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, id...)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "ID", Type: cmds.ArgString, Required: true, Variadic: true},
+		},
+		Type: (*PeeringResult)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "peering", "rm"}, cmd, o, args, nodes)
+	return SwarmPeeringRmResponse{res}, err
+}
+
+// SwarmPeersResponse is the output of SwarmPeers.
+type SwarmPeersResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmPeersResponse) Next() (*ConnInfos, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*ConnInfos)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmPeersOptions are the options of SwarmPeers.
+type SwarmPeersOptions struct {
+	// Also list information about the direction of connection.
+	Direction *bool
+	// Also list information about latency to each peer.
+	Latency *bool
+	// Also list information about open streams for each peer.
+	Streams *bool
+	// display all extra information.
+	Verbose *bool
+}
+
+// SwarmPeers runs 'ipfs swarm peers': list peers with open connections.
+func (c *Client) SwarmPeers(ctx context.Context, opts *SwarmPeersOptions) (SwarmPeersResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Direction != nil {
+			o["direction"] = *opts.Direction
+		}
+		if opts.Latency != nil {
+			o["latency"] = *opts.Latency
+		}
+		if opts.Streams != nil {
+			o["streams"] = *opts.Streams
+		}
+		if opts.Verbose != nil {
+			o["verbose"] = *opts.Verbose
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*ConnInfos)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "peers"}, cmd, o, args, nodes)
+	return SwarmPeersResponse{res}, err
+}
+
+// SwarmResumeResponse is the output of SwarmResume.
+type SwarmResumeResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r SwarmResumeResponse) Next() (*Libp2pSwarmBrakeStatus, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*Libp2pSwarmBrakeStatus)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// SwarmResume runs 'ipfs swarm resume': accept and dial new connections again after 'ipfs swarm pause'.
+func (c *Client) SwarmResume(ctx context.Context) (SwarmResumeResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*Libp2pSwarmBrakeStatus)(nil),
+	}
+	res, err := c.call(ctx, []string{"swarm", "resume"}, cmd, o, args, nodes)
+	return SwarmResumeResponse{res}, err
+}
+
+// SwarmStats runs 'ipfs swarm stats': report resource usage for a scope.
+//
+// scope: scope of the stat report
+func (c *Client) SwarmStats(ctx context.Context, scope string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, scope)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "scope", Type: cmds.ArgString, Required: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"swarm", "stats"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// TarAddResponse is the output of TarAdd.
+type TarAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r TarAddResponse) Next() (*AddEvent, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*AddEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// TarAdd runs 'ipfs tar add': import a tar file into IPFS.
+//
+// file: Tar file to add.
+//
+// Deprecated: see 'ipfs tar add --help'.
+func (c *Client) TarAdd(ctx context.Context, file files.Node) (TarAddResponse, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	if file != nil {
+		nodes = append(nodes, file)
+	}
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "file", Type: cmds.ArgFile, Required: true, SupportsStdin: true},
+		},
+		Type: (*AddEvent)(nil),
+	}
+	res, err := c.call(ctx, []string{"tar", "add"}, cmd, o, args, nodes)
+	return TarAddResponse{res}, err
+}
+
+// TarCat runs 'ipfs tar cat': export a tar file from IPFS.
+//
+// path: ipfs path of archive to export.
+//
+// Deprecated: see 'ipfs tar cat --help'.
+func (c *Client) TarCat(ctx context.Context, path string) (*Response, error) {
+	var o cmds.OptMap
+	var args []string
+	var nodes []files.Node
+	args = append(args, path)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "path", Type: cmds.ArgString, Required: true, SupportsStdin: true},
+		},
+	}
+	res, err := c.call(ctx, []string{"tar", "cat"}, cmd, o, args, nodes)
+	return res, err
+}
+
+// UrlstoreAddResponse is the output of UrlstoreAdd.
+type UrlstoreAddResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r UrlstoreAddResponse) Next() (*BlockStat, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*BlockStat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// UrlstoreAddOptions are the options of UrlstoreAdd.
+type UrlstoreAddOptions struct {
+	// Pin this object when adding. Default: true.
+	Pin *bool
+	// Use trickle-dag format for dag generation.
+	Trickle *bool
+}
+
+// UrlstoreAdd runs 'ipfs urlstore add': add URL via urlstore.
+//
+// url: URL to add to IPFS
+//
+// Deprecated: see 'ipfs urlstore add --help'.
+func (c *Client) UrlstoreAdd(ctx context.Context, url string, opts *UrlstoreAddOptions) (UrlstoreAddResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.Pin != nil {
+			o["pin"] = *opts.Pin
+		}
+		if opts.Trickle != nil {
+			o["trickle"] = *opts.Trickle
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	args = append(args, url)
+	cmd := &cmds.Command{
+		Arguments: []cmds.Argument{
+			{Name: "url", Type: cmds.ArgString, Required: true},
+		},
+		Type: (*BlockStat)(nil),
+	}
+	res, err := c.call(ctx, []string{"urlstore", "add"}, cmd, o, args, nodes)
+	return UrlstoreAddResponse{res}, err
+}
+
+// VersionResponse is the output of Version.
+type VersionResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r VersionResponse) Next() (*IPFSVersionInfo, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*IPFSVersionInfo)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// VersionOptions are the options of Version.
+type VersionOptions struct {
+	// Show all version information.
+	All *bool
+	// Show the commit hash.
+	Commit *bool
+	// Only show the version number.
+	Number *bool
+	// Show repo version.
+	Repo *bool
+}
+
+// Version runs 'ipfs version': show IPFS version information.
+func (c *Client) Version(ctx context.Context, opts *VersionOptions) (VersionResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.All != nil {
+			o["all"] = *opts.All
+		}
+		if opts.Commit != nil {
+			o["commit"] = *opts.Commit
+		}
+		if opts.Number != nil {
+			o["number"] = *opts.Number
+		}
+		if opts.Repo != nil {
+			o["repo"] = *opts.Repo
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*IPFSVersionInfo)(nil),
+	}
+	res, err := c.call(ctx, []string{"version"}, cmd, o, args, nodes)
+	return VersionResponse{res}, err
+}
+
+// VersionDepsResponse is the output of VersionDeps.
+type VersionDepsResponse struct{ *Response }
+
+// Next returns the next value emitted by the command, or io.EOF after the
+// last one.
+func (r VersionDepsResponse) Next() (*Dependency, error) {
+	v, err := r.Response.Next()
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(*Dependency)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of type %T", v)
+	}
+	return out, nil
+}
+
+// VersionDepsOptions are the options of VersionDeps.
+type VersionDepsOptions struct {
+	// Show all version information.
+	All *bool
+	// Show the commit hash.
+	Commit *bool
+	// Only show the version number.
+	Number *bool
+	// Show repo version.
+	Repo *bool
+}
+
+// VersionDeps runs 'ipfs version deps': shows information about dependencies used for build.
+func (c *Client) VersionDeps(ctx context.Context, opts *VersionDepsOptions) (VersionDepsResponse, error) {
+	var o cmds.OptMap
+	if opts != nil {
+		o = make(cmds.OptMap)
+		if opts.All != nil {
+			o["all"] = *opts.All
+		}
+		if opts.Commit != nil {
+			o["commit"] = *opts.Commit
+		}
+		if opts.Number != nil {
+			o["number"] = *opts.Number
+		}
+		if opts.Repo != nil {
+			o["repo"] = *opts.Repo
+		}
+	}
+	var args []string
+	var nodes []files.Node
+	cmd := &cmds.Command{
+		Type: (*Dependency)(nil),
+	}
+	res, err := c.call(ctx, []string{"version", "deps"}, cmd, o, args, nodes)
+	return VersionDepsResponse{res}, err
+}
+
+// AddEvent mirrors commands.AddEvent from github.com/ipfs/go-ipfs/core/commands.
+type AddEvent struct {
+	Name  string
+	Hash  string `json:",omitempty"`
+	Bytes int64  `json:",omitempty"`
+	Size  string `json:",omitempty"`
+}
+
+// AddrInfos mirrors commands.addrInfos from github.com/ipfs/go-ipfs/core/commands.
+type AddrInfos struct {
+	Peers []peer.AddrInfo
+}
+
+// AddrMap mirrors commands.addrMap from github.com/ipfs/go-ipfs/core/commands.
+type AddrMap struct {
+	Addrs map[string][]string
+}
+
+// AllowlistCertificate mirrors commands.AllowlistCertificate from github.com/ipfs/go-ipfs/core/commands.
+type AllowlistCertificate struct {
+	Certificate string
+	Expires     time.Time
+}
+
+// BatchedProviderStats mirrors batched.BatchedProviderStats from github.com/ipfs/go-ipfs-provider/batched.
+type BatchedProviderStats struct {
+	TotalProvides          int
+	LastReprovideBatchSize int
+	AvgProvideDuration     time.Duration
+	LastReprovideDuration  time.Duration
+}
+
+// BenchmarkLatency mirrors commands.BenchmarkLatency from github.com/ipfs/go-ipfs/core/commands.
+type BenchmarkLatency struct {
+	Lookups int
+	Failed  int
+	Min     time.Duration
+	Mean    time.Duration
+	Max     time.Duration
+}
+
+// BenchmarkReport mirrors commands.BenchmarkReport from github.com/ipfs/go-ipfs/core/commands.
+type BenchmarkReport struct {
+	Time    time.Time
+	Version string
+	Add     *BenchmarkThroughput `json:",omitempty"`
+	Read    *BenchmarkThroughput `json:",omitempty"`
+	Fetch   *BenchmarkThroughput `json:",omitempty"`
+	DHT     *BenchmarkLatency    `json:",omitempty"`
+}
+
+// BenchmarkThroughput mirrors commands.BenchmarkThroughput from github.com/ipfs/go-ipfs/core/commands.
+type BenchmarkThroughput struct {
+	Bytes          uint64
+	Duration       time.Duration
+	BytesPerSecond float64
+}
+
+// BitswapStat mirrors bitswap.Stat from github.com/ipfs/go-bitswap.
+type BitswapStat struct {
+	ProvideBufLen    int
+	Wantlist         []cid.Cid
+	Peers            []string
+	BlocksReceived   uint64
+	DataReceived     uint64
+	BlocksSent       uint64
+	DataSent         uint64
+	DupBlksReceived  uint64
+	DupDataReceived  uint64
+	MessagesReceived uint64
+}
+
+// BlockStat mirrors commands.BlockStat from github.com/ipfs/go-ipfs/core/commands.
+type BlockStat struct {
+	Key  string
+	Size int
+}
+
+// BootstrapOutput mirrors commands.BootstrapOutput from github.com/ipfs/go-ipfs/core/commands.
+type BootstrapOutput struct {
+	Peers []string
+}
+
+// CidFormatRes mirrors commands.CidFormatRes from github.com/ipfs/go-ipfs/core/commands.
+type CidFormatRes struct {
+	CidStr    string
+	Formatted string
+	ErrorMsg  string
+}
+
+// CodeAndName mirrors commands.CodeAndName from github.com/ipfs/go-ipfs/core/commands.
+type CodeAndName struct {
+	Code int
+	Name string
+}
+
+// Command mirrors commands.Command from github.com/ipfs/go-ipfs/core/commands.
+type Command struct {
+	Name        string
+	Subcommands []Command
+	Options     []Option
+}
+
+// ConfigField mirrors commands.ConfigField from github.com/ipfs/go-ipfs/core/commands.
+type ConfigField struct {
+	Key   string
+	Value interface{}
+}
+
+// ConfigMounts mirrors config.Mounts from github.com/ipfs/go-ipfs/config.
+type ConfigMounts struct {
+	IPFS           string
+	IPNS           string
+	FuseAllowOther bool
+}
+
+// ConfigUpdateOutput mirrors commands.ConfigUpdateOutput from github.com/ipfs/go-ipfs/core/commands.
+type ConfigUpdateOutput struct {
+	OldCfg map[string]interface{}
+	NewCfg map[string]interface{}
+}
+
+// ConnInfo mirrors commands.connInfo from github.com/ipfs/go-ipfs/core/commands.
+type ConnInfo struct {
+	Addr      string
+	Peer      string
+	Latency   string
+	Muxer     string
+	Direction network.Direction
+	Streams   []StreamInfo
+}
+
+// ConnInfos mirrors commands.connInfos from github.com/ipfs/go-ipfs/core/commands.
+type ConnInfos struct {
+	Peers []ConnInfo
+}
+
+// ContentDiagGateway mirrors commands.ContentDiagGateway from github.com/ipfs/go-ipfs/core/commands.
+type ContentDiagGateway struct {
+	URL    string
+	Cached bool
+	Status int    `json:",omitempty"`
+	Error  string `json:",omitempty"`
+}
+
+// ContentDiagProvider mirrors commands.ContentDiagProvider from github.com/ipfs/go-ipfs/core/commands.
+type ContentDiagProvider struct {
+	ID        peer.ID
+	Connected bool
+	Bitswap   bool
+	Sent      bool
+	Error     string `json:",omitempty"`
+}
+
+// ContentDiagReport mirrors commands.ContentDiagReport from github.com/ipfs/go-ipfs/core/commands.
+type ContentDiagReport struct {
+	Cid           string
+	Local         bool
+	Routing       []ContentDiagRouter
+	Providers     []ContentDiagProvider
+	Retrieved     bool
+	RetrieveError string `json:",omitempty"`
+	Gateways      []ContentDiagGateway
+}
+
+// ContentDiagRouter mirrors commands.ContentDiagRouter from github.com/ipfs/go-ipfs/core/commands.
+type ContentDiagRouter struct {
+	Router    string
+	Providers int
+	Duration  time.Duration
+	Error     string `json:",omitempty"`
+}
+
+// CorerepoSizeStat mirrors corerepo.SizeStat from github.com/ipfs/go-ipfs/core/corerepo.
+type CorerepoSizeStat struct {
+	RepoSize   uint64
+	StorageMax uint64
+}
+
+// CorerepoStat mirrors corerepo.Stat from github.com/ipfs/go-ipfs/core/corerepo.
+type CorerepoStat struct {
+	CorerepoSizeStat
+	NumObjects uint64
+	RepoPath   string
+	Version    string
+}
+
+// DagcmdCarImportOutput mirrors dagcmd.CarImportOutput from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdCarImportOutput struct {
+	Root  *DagcmdRootMeta       `json:",omitempty"`
+	Stats *DagcmdCarImportStats `json:",omitempty"`
+}
+
+// DagcmdCarImportStats mirrors dagcmd.CarImportStats from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdCarImportStats struct {
+	BlockCount      uint64
+	BlockBytesCount uint64
+}
+
+// DagcmdDagStat mirrors dagcmd.DagStat from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdDagStat struct {
+	Size      uint64
+	NumBlocks int64
+}
+
+// DagcmdDagWalkMeta mirrors dagcmd.DagWalkMeta from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdDagWalkMeta struct {
+	Codec string
+	Size  int
+	Links int
+	Depth int
+	Path  string
+}
+
+// DagcmdDagWalkOutput mirrors dagcmd.DagWalkOutput from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdDagWalkOutput struct {
+	Cid  cid.Cid
+	Meta *DagcmdDagWalkMeta `json:",omitempty"`
+}
+
+// DagcmdOutputObject mirrors dagcmd.OutputObject from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdOutputObject struct {
+	Cid cid.Cid
+}
+
+// DagcmdResolveOutput mirrors dagcmd.ResolveOutput from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdResolveOutput struct {
+	Cid     cid.Cid
+	RemPath string
+}
+
+// DagcmdRootMeta mirrors dagcmd.RootMeta from github.com/ipfs/go-ipfs/core/commands/dag.
+type DagcmdRootMeta struct {
+	Cid         cid.Cid
+	PinErrorMsg string
+}
+
+// DagutilsChange mirrors dagutils.Change from github.com/ipfs/go-merkledag/dagutils.
+type DagutilsChange struct {
+	Type   DagutilsChangeType
+	Path   string
+	Before cid.Cid
+	After  cid.Cid
+}
+
+// DagutilsChangeType mirrors dagutils.ChangeType from github.com/ipfs/go-merkledag/dagutils.
+type DagutilsChangeType int
+
+// DecisionReceipt mirrors decision.Receipt from github.com/ipfs/go-bitswap/internal/decision.
+type DecisionReceipt struct {
+	Peer      string
+	Value     float64
+	Sent      uint64
+	Recv      uint64
+	Exchanged uint64
+}
+
+// Dependency mirrors commands.Dependency from github.com/ipfs/go-ipfs/core/commands.
+type Dependency struct {
+	Path       string
+	Version    string
+	ReplacedBy string
+	Sum        string
+}
+
+// DhtBucket mirrors commands.dhtBucket from github.com/ipfs/go-ipfs/core/commands.
+type DhtBucket struct {
+	LastRefresh string
+	Peers       []DhtPeerInfo
+}
+
+// DhtPeerInfo mirrors commands.dhtPeerInfo from github.com/ipfs/go-ipfs/core/commands.
+type DhtPeerInfo struct {
+	ID            string
+	Connected     bool
+	AgentVersion  string
+	LastUsefulAt  string
+	LastQueriedAt string
+}
+
+// DhtStat mirrors commands.dhtStat from github.com/ipfs/go-ipfs/core/commands.
+type DhtStat struct {
+	Name    string
+	Buckets []DhtBucket
+}
+
+// FilesLsOutput mirrors commands.filesLsOutput from github.com/ipfs/go-ipfs/core/commands.
+type FilesLsOutput struct {
+	Entries []MfsNodeListing
+}
+
+// FilestoreListRes mirrors filestore.ListRes from github.com/ipfs/go-filestore.
+type FilestoreListRes struct {
+	Status   FilestoreStatus
+	ErrorMsg string
+	Key      cid.Cid
+	FilePath string
+	Offset   uint64
+	Size     uint64
+}
+
+// FilestoreStatus mirrors filestore.Status from github.com/ipfs/go-filestore.
+type FilestoreStatus int32
+
+// FlushRes mirrors commands.flushRes from github.com/ipfs/go-ipfs/core/commands.
+type FlushRes struct {
+	Cid string
+}
+
+// FormatNodeStat mirrors format.NodeStat from github.com/ipfs/go-ipld-format.
+type FormatNodeStat struct {
+	Hash           string
+	NumLinks       int
+	BlockSize      int
+	LinksSize      int
+	DataSize       int
+	CumulativeSize int
+}
+
+// GatewayAlias mirrors commands.GatewayAlias from github.com/ipfs/go-ipfs/core/commands.
+type GatewayAlias struct {
+	From string
+	To   string
+}
+
+// GatewayAliasList mirrors commands.GatewayAliasList from github.com/ipfs/go-ipfs/core/commands.
+type GatewayAliasList struct {
+	Aliases []GatewayAlias
+}
+
+// GcResult mirrors commands.GcResult from github.com/ipfs/go-ipfs/core/commands.
+type GcResult struct {
+	Key   cid.Cid
+	Kept  bool   `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+// IPFSVersionInfo mirrors ipfs.VersionInfo from github.com/ipfs/go-ipfs.
+type IPFSVersionInfo struct {
+	Version string
+	Commit  string
+	Repo    string
+	System  string
+	Golang  string
+}
+
+// IdOutput mirrors commands.IdOutput from github.com/ipfs/go-ipfs/core/commands.
+type IdOutput struct {
+	ID              string
+	PublicKey       string
+	Addresses       []string
+	AgentVersion    string
+	ProtocolVersion string
+	Protocols       []string
+}
+
+// JournalEntry mirrors journal.Entry from github.com/ipfs/go-ipfs/journal.
+type JournalEntry struct {
+	Time   time.Time
+	Type   string
+	Fields map[string]string `json:",omitempty"`
+}
+
+// KeyList mirrors commands.KeyList from github.com/ipfs/go-ipfs/core/commands.
+type KeyList struct {
+	Keys []cid.Cid
+}
+
+// KeyOutput mirrors commands.KeyOutput from github.com/ipfs/go-ipfs/core/commands.
+type KeyOutput struct {
+	Name string
+	Id   string
+}
+
+// KeyOutputList mirrors commands.KeyOutputList from github.com/ipfs/go-ipfs/core/commands.
+type KeyOutputList struct {
+	Keys []KeyOutput
+}
+
+// KeyRenameOutput mirrors commands.KeyRenameOutput from github.com/ipfs/go-ipfs/core/commands.
+type KeyRenameOutput struct {
+	Was       string
+	Now       string
+	Id        string
+	Overwrite bool
+}
+
+// Libp2pAdmittedPeer mirrors libp2p.AdmittedPeer from github.com/ipfs/go-ipfs/core/node/libp2p.
+type Libp2pAdmittedPeer struct {
+	Peer    peer.ID
+	Expires time.Time
+}
+
+// Libp2pInboundAllowlistStatus mirrors libp2p.InboundAllowlistStatus from github.com/ipfs/go-ipfs/core/node/libp2p.
+type Libp2pInboundAllowlistStatus struct {
+	Admitted  []Libp2pAdmittedPeer
+	Probation []peer.ID
+	Denied    int64
+}
+
+// Libp2pNetDumpOut mirrors libp2p.NetDumpOut from github.com/ipfs/go-ipfs/core/node/libp2p.
+type Libp2pNetDumpOut struct {
+	Time time.Time
+	Libp2pNetStatOut
+}
+
+// Libp2pNetStatOut mirrors libp2p.NetStatOut from github.com/ipfs/go-ipfs/core/node/libp2p.
+type Libp2pNetStatOut struct {
+	System    *network.ScopeStat           `json:",omitempty"`
+	Transient *network.ScopeStat           `json:",omitempty"`
+	Services  map[string]network.ScopeStat `json:",omitempty"`
+	Protocols map[string]network.ScopeStat `json:",omitempty"`
+	Peers     map[string]network.ScopeStat `json:",omitempty"`
+}
+
+// Libp2pRouterHealth mirrors libp2p.RouterHealth from github.com/ipfs/go-ipfs/core/node/libp2p.
+type Libp2pRouterHealth struct {
+	Name        string
+	Status      string
+	LastProbe   time.Time
+	Latency     time.Duration
+	LastHealthy time.Time
+	Error       string `json:",omitempty"`
+}
+
+// Libp2pSwarmBrakeStatus mirrors libp2p.SwarmBrakeStatus from github.com/ipfs/go-ipfs/core/node/libp2p.
+type Libp2pSwarmBrakeStatus struct {
+	Paused         bool
+	Since          time.Time `json:",omitempty"`
+	DeniedInbound  int64
+	DeniedOutbound int64
+}
+
+// LsLink mirrors commands.LsLink from github.com/ipfs/go-ipfs/core/commands.
+type LsLink struct {
+	Name   string
+	Hash   string
+	Size   uint64
+	Type   UnixfsPbDataDataType
+	Target string
+}
+
+// LsObject mirrors commands.LsObject from github.com/ipfs/go-ipfs/core/commands.
+type LsObject struct {
+	Hash  string
+	Links []LsLink
+}
+
+// LsOutput mirrors commands.LsOutput from github.com/ipfs/go-ipfs/core/commands.
+type LsOutput struct {
+	Objects []LsObject
+}
+
+// MessageOutput mirrors commands.MessageOutput from github.com/ipfs/go-ipfs/core/commands.
+type MessageOutput struct {
+	Message string
+}
+
+// MfsNodeListing mirrors mfs.NodeListing from github.com/ipfs/go-mfs.
+type MfsNodeListing struct {
+	Name string
+	Type int
+	Size int64
+	Hash string
+}
+
+// NameIpnsEntry mirrors name.IpnsEntry from github.com/ipfs/go-ipfs/core/commands/name.
+type NameIpnsEntry struct {
+	Name  string
+	Value string
+}
+
+// NameIpnsInspectEntry mirrors name.IpnsInspectEntry from github.com/ipfs/go-ipfs/core/commands/name.
+type NameIpnsInspectEntry struct {
+	Name            string
+	Value           string
+	Sequence        uint64
+	Validity        *time.Time `json:",omitempty"`
+	NetworkSequence uint64
+	Conflict        bool
+	LastPublished   *time.Time `json:",omitempty"`
+	LastChecked     *time.Time `json:",omitempty"`
+}
+
+// NameIpnsPubsubCancel mirrors name.ipnsPubsubCancel from github.com/ipfs/go-ipfs/core/commands/name.
+type NameIpnsPubsubCancel struct {
+	Canceled bool
+}
+
+// NameIpnsPubsubState mirrors name.ipnsPubsubState from github.com/ipfs/go-ipfs/core/commands/name.
+type NameIpnsPubsubState struct {
+	Enabled bool
+}
+
+// NameResolvedPath mirrors name.ResolvedPath from github.com/ipfs/go-ipfs/core/commands/name.
+type NameResolvedPath struct {
+	Path Path
+}
+
+// NameStringList mirrors name.stringList from github.com/ipfs/go-ipfs/core/commands/name.
+type NameStringList struct {
+	Strings []string
+}
+
+// NodeStandbyStatus mirrors node.StandbyStatus from github.com/ipfs/go-ipfs/core/node.
+type NodeStandbyStatus struct {
+	Primary   string
+	LastSync  time.Time `json:",omitempty"`
+	LastError string    `json:",omitempty"`
+	Recursive int
+	Direct    int
+	FilesRoot string `json:",omitempty"`
+	Records   int
+	Keys      int
+	Promoted  bool
+}
+
+// ObjectcmdChanges mirrors objectcmd.Changes from github.com/ipfs/go-ipfs/core/commands/object.
+type ObjectcmdChanges struct {
+	Changes []*DagutilsChange
+}
+
+// ObjectcmdLink mirrors objectcmd.Link from github.com/ipfs/go-ipfs/core/commands/object.
+type ObjectcmdLink struct {
+	Name string
+	Hash string
+	Size uint64
+}
+
+// ObjectcmdNode mirrors objectcmd.Node from github.com/ipfs/go-ipfs/core/commands/object.
+type ObjectcmdNode struct {
+	Links []ObjectcmdLink
+	Data  string
+}
+
+// ObjectcmdObject mirrors objectcmd.Object from github.com/ipfs/go-ipfs/core/commands/object.
+type ObjectcmdObject struct {
+	Hash  string          `json:"Hash,omitempty"`
+	Links []ObjectcmdLink `json:"Links,omitempty"`
+}
+
+// Option mirrors commands.Option from github.com/ipfs/go-ipfs/core/commands.
+type Option struct {
+	Names []string
+}
+
+// P2PListenerInfoOutput mirrors commands.P2PListenerInfoOutput from github.com/ipfs/go-ipfs/core/commands.
+type P2PListenerInfoOutput struct {
+	Protocol      string
+	ListenAddress string
+	TargetAddress string
+	AllowedPeers  []string `json:",omitempty"`
+	MaxStreams    int      `json:",omitempty"`
+	IdleTimeout   string   `json:",omitempty"`
+	Streams       int
+}
+
+// P2PLsOutput mirrors commands.P2PLsOutput from github.com/ipfs/go-ipfs/core/commands.
+type P2PLsOutput struct {
+	Listeners []P2PListenerInfoOutput
+}
+
+// P2PStreamInfoOutput mirrors commands.P2PStreamInfoOutput from github.com/ipfs/go-ipfs/core/commands.
+type P2PStreamInfoOutput struct {
+	HandlerID     string
+	Protocol      string
+	OriginAddress string
+	TargetAddress string
+	BytesIn       int64
+	BytesOut      int64
+}
+
+// P2PStreamsOutput mirrors commands.P2PStreamsOutput from github.com/ipfs/go-ipfs/core/commands.
+type P2PStreamsOutput struct {
+	Streams []P2PStreamInfoOutput
+}
+
+// Path mirrors path.Path from github.com/ipfs/go-path.
+type Path string
+
+// PeerDiagEvent mirrors commands.PeerDiagEvent from github.com/ipfs/go-ipfs/core/commands.
+type PeerDiagEvent struct {
+	Time      time.Time
+	Event     string
+	Direction string        `json:",omitempty"`
+	Addr      string        `json:",omitempty"`
+	Stream    string        `json:",omitempty"`
+	Protocol  string        `json:",omitempty"`
+	Duration  time.Duration `json:",omitempty"`
+	BytesIn   int64         `json:",omitempty"`
+	BytesOut  int64         `json:",omitempty"`
+	Dropped   int64         `json:",omitempty"`
+}
+
+// PeeringResult mirrors commands.peeringResult from github.com/ipfs/go-ipfs/core/commands.
+type PeeringResult struct {
+	ID     peer.ID
+	Status string
+}
+
+// Petname mirrors commands.Petname from github.com/ipfs/go-ipfs/core/commands.
+type Petname struct {
+	Name string
+	Path string
+}
+
+// PetnameList mirrors commands.PetnameList from github.com/ipfs/go-ipfs/core/commands.
+type PetnameList struct {
+	Petnames []Petname
+}
+
+// PinAddPinOutput mirrors pin.AddPinOutput from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinAddPinOutput struct {
+	Pins     []string `json:",omitempty"`
+	Progress int      `json:",omitempty"`
+}
+
+// PinBadNode mirrors pin.BadNode from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinBadNode struct {
+	Cid string
+	Err string
+}
+
+// PinCount mirrors pin.PinCount from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinCount struct {
+	Queued  int
+	Pinning int
+	Pinned  int
+	Failed  int
+}
+
+// PinLsList mirrors pin.PinLsList from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinLsList struct {
+	Keys map[string]PinLsType
+}
+
+// PinLsObject mirrors pin.PinLsObject from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinLsObject struct {
+	Cid  string `json:",omitempty"`
+	Type string `json:",omitempty"`
+}
+
+// PinLsOutputWrapper mirrors pin.PinLsOutputWrapper from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinLsOutputWrapper struct {
+	PinLsList
+	PinLsObject
+}
+
+// PinLsType mirrors pin.PinLsType from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinLsType struct {
+	Type string
+}
+
+// PinOutput mirrors pin.PinOutput from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinOutput struct {
+	Pins []string
+}
+
+// PinRemotePinOutput mirrors pin.RemotePinOutput from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinRemotePinOutput struct {
+	Status string
+	Cid    string
+	Name   string
+}
+
+// PinServiceDetails mirrors pin.ServiceDetails from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinServiceDetails struct {
+	Service     string
+	ApiEndpoint string
+	Stat        *PinStat `json:",omitempty"`
+}
+
+// PinServicesList mirrors pin.PinServicesList from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinServicesList struct {
+	RemoteServices []PinServiceDetails
+}
+
+// PinStat mirrors pin.Stat from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinStat struct {
+	Status   string
+	PinCount *PinCount `json:",omitempty"`
+}
+
+// PinStatus mirrors pin.PinStatus from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinStatus struct {
+	Ok       bool
+	BadNodes []PinBadNode `json:",omitempty"`
+}
+
+// PinVerifyRes mirrors pin.PinVerifyRes from github.com/ipfs/go-ipfs/core/commands/pin.
+type PinVerifyRes struct {
+	Cid string
+	PinStatus
+}
+
+// PingResult mirrors commands.PingResult from github.com/ipfs/go-ipfs/core/commands.
+type PingResult struct {
+	Success bool
+	Time    time.Duration
+	Text    string
+}
+
+// PubsubMessage mirrors commands.pubsubMessage from github.com/ipfs/go-ipfs/core/commands.
+type PubsubMessage struct {
+	From     string   `json:"from,omitempty"`
+	Data     string   `json:"data,omitempty"`
+	Seqno    string   `json:"seqno,omitempty"`
+	TopicIDs []string `json:"topicIDs,omitempty"`
+}
+
+// QuarantineEntry mirrors quarantine.Entry from github.com/ipfs/go-ipfs/quarantine.
+type QuarantineEntry struct {
+	Cid       cid.Cid
+	Time      time.Time
+	Reason    string
+	Refetched bool
+	Data      []uint8 `json:",omitempty"`
+}
+
+// RefWrapper mirrors commands.RefWrapper from github.com/ipfs/go-ipfs/core/commands.
+type RefWrapper struct {
+	Ref string
+	Err string
+}
+
+// RemovedBlock mirrors commands.removedBlock from github.com/ipfs/go-ipfs/core/commands.
+type RemovedBlock struct {
+	Hash  string `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+// RepoVersion mirrors commands.RepoVersion from github.com/ipfs/go-ipfs/core/commands.
+type RepoVersion struct {
+	Version string
+}
+
+// RoutingFindProvsOutput mirrors commands.RoutingFindProvsOutput from github.com/ipfs/go-ipfs/core/commands.
+type RoutingFindProvsOutput struct {
+	Provider *peer.AddrInfo      `json:",omitempty"`
+	Trace    []RoutingtraceQuery `json:",omitempty"`
+}
+
+// RoutingStatOutput mirrors commands.RoutingStatOutput from github.com/ipfs/go-ipfs/core/commands.
+type RoutingStatOutput struct {
+	Routers      []Libp2pRouterHealth
+	ReadyRouters []string `json:",omitempty"`
+	Ready        bool
+}
+
+// RoutingTrace mirrors commands.RoutingTrace from github.com/ipfs/go-ipfs/core/commands.
+type RoutingTrace struct {
+	Key       string
+	Duration  time.Duration
+	Hops      []*RoutingTraceHop
+	Closest   []peer.ID `json:",omitempty"`
+	Providers []peer.ID `json:",omitempty"`
+	Error     string    `json:",omitempty"`
+}
+
+// RoutingTraceHop mirrors commands.RoutingTraceHop from github.com/ipfs/go-ipfs/core/commands.
+type RoutingTraceHop struct {
+	Peer        peer.ID
+	ReferredBy  peer.ID `json:",omitempty"`
+	Result      string
+	RTT         time.Duration `json:",omitempty"`
+	CloserPeers []peer.ID     `json:",omitempty"`
+	Error       string        `json:",omitempty"`
+}
+
+// RoutingtraceQuery mirrors routingtrace.Query from github.com/ipfs/go-ipfs/routingtrace.
+type RoutingtraceQuery struct {
+	Router  string
+	Method  string
+	Start   time.Duration
+	Latency time.Duration
+	Peers   []peer.ID `json:",omitempty"`
+	Values  int       `json:",omitempty"`
+	Error   string    `json:",omitempty"`
+}
+
+// StandbyPromoteOutput mirrors commands.StandbyPromoteOutput from github.com/ipfs/go-ipfs/core/commands.
+type StandbyPromoteOutput struct {
+	Published []string
+}
+
+// StatOutput mirrors commands.statOutput from github.com/ipfs/go-ipfs/core/commands.
+type StatOutput struct {
+	Hash           string
+	Size           uint64
+	CumulativeSize uint64
+	Blocks         int
+	Type           string
+	WithLocality   bool   `json:",omitempty"`
+	Local          bool   `json:",omitempty"`
+	SizeLocal      uint64 `json:",omitempty"`
+}
+
+// StreamInfo mirrors commands.streamInfo from github.com/ipfs/go-ipfs/core/commands.
+type StreamInfo struct {
+	Protocol string
+}
+
+// StringList mirrors commands.stringList from github.com/ipfs/go-ipfs/core/commands.
+type StringList struct {
+	Strings []string
+}
+
+// UnixfsLsLink mirrors unixfs.LsLink from github.com/ipfs/go-ipfs/core/commands/unixfs.
+type UnixfsLsLink struct {
+	Name string
+	Hash string
+	Size uint64
+	Type string
+}
+
+// UnixfsLsObject mirrors unixfs.LsObject from github.com/ipfs/go-ipfs/core/commands/unixfs.
+type UnixfsLsObject struct {
+	Hash  string
+	Size  uint64
+	Type  string
+	Links []UnixfsLsLink
+}
+
+// UnixfsLsOutput mirrors unixfs.LsOutput from github.com/ipfs/go-ipfs/core/commands/unixfs.
+type UnixfsLsOutput struct {
+	Arguments map[string]string
+	Objects   map[string]*UnixfsLsObject
+}
+
+// UnixfsPbDataDataType mirrors unixfs_pb.Data_DataType from github.com/ipfs/go-unixfs/pb.
+type UnixfsPbDataDataType int32
+
+// VerifyProgress mirrors commands.VerifyProgress from github.com/ipfs/go-ipfs/core/commands.
+type VerifyProgress struct {
+	Msg      string
+	Progress int
+}
@@ -0,0 +1,27 @@
+// Command gen writes the methods of the RPC client generated from the
+// commands of the daemon.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ipfs/go-ipfs/client/rpc/internal/gen"
+	"github.com/ipfs/go-ipfs/core/commands"
+)
+
+func main() {
+	out := flag.String("o", "commands.go", "file to write the generated code to")
+	flag.Parse()
+
+	src, err := gen.Generate(commands.Root)
+	if err == nil {
+		err = ioutil.WriteFile(*out, src, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,653 @@
+// Package gen generates the methods of the RPC client from the definitions
+// of the commands.
+//
+// The values the commands emit are decoded into copies of their types, so
+// the client doesn't link the packages of the daemon: only the types of the
+// standard library and of lightModules are used as they are.
+package gen
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	gopath "path"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+// Generate returns the source of the client methods of the commands under
+// root, in package rpc.
+func Generate(root *cmds.Command) ([]byte, error) {
+	g := newGenerator()
+	if err := g.walk(root, nil, nil); err != nil {
+		return nil, err
+	}
+	g.writeTypes()
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by internal/gen. DO NOT EDIT.\n\npackage rpc\n\nimport (\n")
+	for i, path := range g.imports.paths() {
+		if i > 0 && isStd(path) != isStd(g.imports.paths()[i-1]) {
+			out.WriteString("\n")
+		}
+		if name := g.imports.byPath[path]; name != gopath.Base(path) {
+			fmt.Fprintf(&out, "\t%s %q\n", name, path)
+		} else {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+	}
+	out.WriteString(")\n")
+	out.Write(g.body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting the generated code: %w", err)
+	}
+	return src, nil
+}
+
+// lightModules are the modules whose types are used as they are in the
+// generated code, as they don't depend on the daemon.
+var lightModules = []string{
+	"github.com/ipfs/go-cid",
+	"github.com/ipfs/go-ipfs-cmds",
+	"github.com/libp2p/go-libp2p-core",
+	"github.com/multiformats/go-multiaddr",
+}
+
+// commandsPkg is the package of the commands, whose type names are kept as
+// they are. The types of the other packages take their package name as
+// prefix.
+const commandsPkg = "github.com/ipfs/go-ipfs/core/commands"
+
+type generator struct {
+	imports *imports
+	body    bytes.Buffer
+	names   map[string][]string
+
+	// copies are the names of the types copied into the generated code, and
+	// types their definitions, by name.
+	copies map[reflect.Type]string
+	types  map[string]string
+	// decls are the types declared in package rpc, with what declares them.
+	decls map[string]string
+}
+
+func newGenerator() *generator {
+	g := &generator{
+		imports: newImports(),
+		names:   make(map[string][]string),
+		copies:  make(map[reflect.Type]string),
+		types:   make(map[string]string),
+		decls:   make(map[string]string),
+	}
+	for _, name := range []string{"Client", "Response"} {
+		g.decls[name] = "client.go"
+	}
+	return g
+}
+
+// declare records the type name, declared by what.
+func (g *generator) declare(name, what string) error {
+	if other, ok := g.decls[name]; ok {
+		return fmt.Errorf("type %s of %s already declared by %s", name, what, other)
+	}
+	g.decls[name] = what
+	return nil
+}
+
+// walk generates the methods of cmd and its subcommands, in the order of
+// their names. opts are the options inherited from the parent commands.
+func (g *generator) walk(cmd *cmds.Command, path []string, opts []cmds.Option) error {
+	if len(path) > 0 {
+		opts = append(opts[:len(opts):len(opts)], cmd.Options...)
+		if cmd.Run != nil && !cmd.NoRemote && cmd.Status != cmds.Removed {
+			if err := g.command(cmd, path, opts); err != nil {
+				return fmt.Errorf("ipfs %s: %w", strings.Join(path, " "), err)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(cmd.Subcommands))
+	for name := range cmd.Subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.walk(cmd.Subcommands[name], append(path[:len(path):len(path)], name), opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) command(cmd *cmds.Command, path []string, opts []cmds.Option) error {
+	name := exportedName(path...)
+	if other, ok := g.names[name]; ok {
+		return fmt.Errorf("method %s already generated for 'ipfs %s'", name, strings.Join(other, " "))
+	}
+	g.names[name] = path
+
+	fields, err := g.options(name, opts)
+	if err != nil {
+		return err
+	}
+	params, err := g.arguments(cmd.Arguments)
+	if err != nil {
+		return err
+	}
+
+	// The type emitted by the command. Commands emitting a stream of bytes
+	// don't have a type.
+	var valueType string
+	if t := reflect.TypeOf(cmd.Type); t != nil {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		expr, err := g.typeExpr(t)
+		if err != nil {
+			return err
+		}
+		valueType = "*" + expr
+	}
+
+	w := &g.body
+	resultType := "*Response"
+	if valueType != "" {
+		resultType = name + "Response"
+		if err := g.declare(resultType, "ipfs "+strings.Join(path, " ")); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n// %s is the output of %s.\ntype %s struct{ *Response }\n", resultType, name, resultType)
+		fmt.Fprintf(w, "\n// Next returns the next value emitted by the command, or io.EOF after the\n// last one.\n")
+		fmt.Fprintf(w, "func (r %s) Next() (%s, error) {\n", resultType, valueType)
+		fmt.Fprintf(w, "\tv, err := r.Response.Next()\n\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		fmt.Fprintf(w, "\tout, ok := v.(%s)\n\tif !ok {\n\t\treturn nil, fmt.Errorf(\"unexpected value of type %%T\", v)\n\t}\n\treturn out, nil\n}\n", valueType)
+	}
+
+	if len(fields) > 0 {
+		if err := g.declare(name+"Options", "ipfs "+strings.Join(path, " ")); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n// %sOptions are the options of %s.\ntype %sOptions struct {\n", name, name, name)
+		for _, f := range fields {
+			if f.doc != "" {
+				fmt.Fprintf(w, "\t// %s\n", f.doc)
+			}
+			fmt.Fprintf(w, "\t%s %s\n", f.field, f.typ)
+		}
+		w.WriteString("}\n")
+	}
+
+	fmt.Fprintf(w, "\n// %s runs 'ipfs %s'", name, strings.Join(path, " "))
+	if tagline := strings.TrimSpace(cmd.Helptext.Tagline); tagline != "" {
+		fmt.Fprintf(w, ": %s", comment(lowerFirst(tagline)))
+	} else {
+		w.WriteString(".")
+	}
+	w.WriteString("\n")
+	for _, p := range params {
+		if p.doc != "" {
+			fmt.Fprintf(w, "//\n// %s: %s\n", p.name, comment(p.doc))
+		}
+	}
+	if cmd.Status == cmds.Deprecated {
+		w.WriteString("//\n// Deprecated: see 'ipfs " + strings.Join(path, " ") + " --help'.\n")
+	}
+
+	fmt.Fprintf(w, "func (c *Client) %s(ctx context.Context", name)
+	for _, p := range params {
+		fmt.Fprintf(w, ", %s %s", p.name, p.typ)
+	}
+	if len(fields) > 0 {
+		fmt.Fprintf(w, ", opts *%sOptions", name)
+	}
+	fmt.Fprintf(w, ") (%s, error) {\n", resultType)
+
+	w.WriteString("\tvar o cmds.OptMap\n")
+	if len(fields) > 0 {
+		w.WriteString("\tif opts != nil {\n\t\to = make(cmds.OptMap)\n")
+		for _, f := range fields {
+			if f.slice {
+				fmt.Fprintf(w, "\t\tif opts.%s != nil {\n\t\t\to[%q] = opts.%s\n\t\t}\n", f.field, f.option, f.field)
+			} else {
+				fmt.Fprintf(w, "\t\tif opts.%s != nil {\n\t\t\to[%q] = *opts.%s\n\t\t}\n", f.field, f.option, f.field)
+			}
+		}
+		w.WriteString("\t}\n")
+	}
+	w.WriteString("\tvar args []string\n\tvar nodes []files.Node\n")
+	for _, p := range params {
+		switch {
+		case p.file && p.variadic:
+			fmt.Fprintf(w, "\tnodes = append(nodes, %s...)\n", p.name)
+		case p.file:
+			fmt.Fprintf(w, "\tif %s != nil {\n\t\tnodes = append(nodes, %s)\n\t}\n", p.name, p.name)
+		case p.variadic:
+			fmt.Fprintf(w, "\targs = append(args, %s...)\n", p.name)
+		case p.required:
+			fmt.Fprintf(w, "\targs = append(args, %s)\n", p.name)
+		default:
+			fmt.Fprintf(w, "\tif %s != \"\" {\n\t\targs = append(args, %s)\n\t}\n", p.name, p.name)
+		}
+	}
+
+	// the definition of the command the request needs: its arguments, and
+	// the type of the values it emits
+	w.WriteString("\tcmd := &cmds.Command{\n")
+	if len(cmd.Arguments) > 0 {
+		w.WriteString("\t\tArguments: []cmds.Argument{\n")
+		for _, a := range cmd.Arguments {
+			fmt.Fprintf(w, "\t\t\t{Name: %q, Type: %s", a.Name, argTypes[a.Type])
+			for _, flag := range []struct {
+				name string
+				set  bool
+			}{{"Required", a.Required}, {"Variadic", a.Variadic}, {"SupportsStdin", a.SupportsStdin}} {
+				if flag.set {
+					fmt.Fprintf(w, ", %s: true", flag.name)
+				}
+			}
+			w.WriteString("},\n")
+		}
+		w.WriteString("\t\t},\n")
+	}
+	if valueType != "" {
+		fmt.Fprintf(w, "\t\tType: (%s)(nil),\n", valueType)
+	}
+	w.WriteString("\t}\n")
+	fmt.Fprintf(w, "\tres, err := c.call(ctx, %#v, cmd, o, args, nodes)\n", path)
+	if valueType != "" {
+		fmt.Fprintf(w, "\treturn %s{res}, err\n}\n", resultType)
+	} else {
+		w.WriteString("\treturn res, err\n}\n")
+	}
+	return nil
+}
+
+type field struct {
+	option, field, typ, doc string
+	slice                   bool
+}
+
+var optionTypes = map[reflect.Kind]string{
+	cmds.Bool:    "*bool",
+	cmds.Int:     "*int",
+	cmds.Uint:    "*uint",
+	cmds.Int64:   "*int64",
+	cmds.Uint64:  "*uint64",
+	cmds.Float:   "*float64",
+	cmds.String:  "*string",
+	cmds.Strings: "[]string",
+}
+
+// options returns the fields of the options struct of a command. Options
+// redefined by a subcommand replace the inherited ones.
+func (g *generator) options(name string, opts []cmds.Option) ([]field, error) {
+	byName := make(map[string]cmds.Option)
+	for _, opt := range opts {
+		byName[opt.Name()] = opt
+	}
+	names := make([]string, 0, len(byName))
+	for n := range byName {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	fields := make([]field, 0, len(names))
+	seen := make(map[string]string)
+	for _, n := range names {
+		opt := byName[n]
+		typ, ok := optionTypes[opt.Type()]
+		if !ok {
+			return nil, fmt.Errorf("option %s: unsupported type %s", n, opt.Type())
+		}
+		f := field{option: n, field: exportedName(n), typ: typ, slice: opt.Type() == cmds.Strings}
+		if other, ok := seen[f.field]; ok {
+			return nil, fmt.Errorf("options %s and %s are both field %s", other, n, f.field)
+		}
+		seen[f.field] = n
+
+		// the description includes the default value
+		f.doc = comment(opt.Description())
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+var argTypes = map[cmds.ArgumentType]string{
+	cmds.ArgString: "cmds.ArgString",
+	cmds.ArgFile:   "cmds.ArgFile",
+}
+
+type param struct {
+	name, typ, doc           string
+	file, variadic, required bool
+}
+
+// reserved are the names the parameters and the imported packages can't
+// take in the generated code.
+var reserved = map[string]bool{
+	"c": true, "ctx": true, "opts": true, "o": true, "args": true, "nodes": true, "res": true, "err": true, "cmd": true,
+	"cmds": true, "files": true, "context": true, "fmt": true, "io": true,
+	"string": true, "bool": true, "int": true, "error": true, "nil": true, "true": true, "false": true,
+}
+
+func (g *generator) arguments(defs []cmds.Argument) ([]param, error) {
+	params := make([]param, 0, len(defs))
+	seen := make(map[string]bool)
+	for _, a := range defs {
+		p := param{
+			name:     paramName(a.Name),
+			doc:      a.Description,
+			file:     a.Type == cmds.ArgFile,
+			variadic: a.Variadic,
+			required: a.Required,
+		}
+		if p.name == "" {
+			return nil, fmt.Errorf("invalid argument name %q", a.Name)
+		}
+		if token.IsKeyword(p.name) || reserved[p.name] {
+			p.name += "Arg"
+		}
+		for seen[p.name] {
+			p.name += "_"
+		}
+		seen[p.name] = true
+
+		switch {
+		case p.file && p.variadic:
+			p.typ = "[]files.Node"
+		case p.file:
+			p.typ = "files.Node"
+		case p.variadic:
+			p.typ = "[]string"
+		default:
+			p.typ = "string"
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// typeExpr returns the expression of t in the generated code.
+func (g *generator) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() == "" {
+		return g.literalExpr(t)
+	}
+	if t.PkgPath() == "" {
+		return t.Name(), nil
+	}
+	if !isLight(t.PkgPath()) {
+		return g.copyType(t)
+	}
+	if !token.IsExported(t.Name()) || strings.Contains(t.PkgPath(), "/internal/") {
+		return "", fmt.Errorf("type %s can't be named outside of its package", t)
+	}
+	pkgName := strings.SplitN(t.String(), ".", 2)[0]
+	return g.imports.add(t.PkgPath(), pkgName) + "." + t.Name(), nil
+}
+
+// literalExpr returns the expression of the structure of t, without its
+// name.
+func (g *generator) literalExpr(t reflect.Type) (string, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem, err := g.typeExpr(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := g.typeExpr(t.Elem())
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := g.typeExpr(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), err
+	case reflect.Map:
+		key, err := g.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeExpr(t.Elem())
+		return "map[" + key + "]" + elem, err
+	case reflect.Interface:
+		// the value of any interface is decoded like the one of interface{}
+		return "interface{}", nil
+	case reflect.Struct:
+		return g.structExpr(t)
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return t.Kind().String(), nil
+	}
+	return "", fmt.Errorf("type %s can't be decoded from JSON", t)
+}
+
+// structExpr returns the struct type of the fields of t encoded in JSON,
+// with their tags.
+func (g *generator) structExpr(t reflect.Type) (string, error) {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("json")
+		if tag == "-" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// the fields of the embedded structs are encoded with the others
+		embedded := f.Anonymous && (ft.Kind() == reflect.Struct || f.IsExported())
+		if !f.IsExported() && !embedded {
+			continue
+		}
+		expr, err := g.typeExpr(f.Type)
+		if err != nil {
+			return "", fmt.Errorf("field %s of %s: %w", f.Name, t, err)
+		}
+		if embedded {
+			b.WriteString("\t" + expr)
+		} else {
+			fmt.Fprintf(&b, "\t%s %s", f.Name, expr)
+		}
+		if tagged {
+			fmt.Fprintf(&b, " `json:%q`", tag)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// copyType declares a type with the JSON encoding of t in the generated
+// code, and returns its name.
+func (g *generator) copyType(t reflect.Type) (string, error) {
+	if name, ok := g.copies[t]; ok {
+		return name, nil
+	}
+	for _, m := range []reflect.Type{jsonMarshaler, textMarshaler} {
+		if t.Implements(m) || reflect.PtrTo(t).Implements(m) {
+			return "", fmt.Errorf("type %s has its own encoding, add its module to the light ones", t)
+		}
+	}
+
+	pkgName := strings.SplitN(t.String(), ".", 2)[0]
+	name := exportedName(t.Name())
+	if t.PkgPath() != commandsPkg && !strings.HasPrefix(strings.ToLower(name), strings.ToLower(pkgName)) {
+		name = exportedName(pkgName) + name
+	}
+	if err := g.declare(name, t.PkgPath()+"."+t.Name()); err != nil {
+		return "", err
+	}
+	// declared before its definition, for the types referring to themselves
+	g.copies[t] = name
+
+	def, err := g.literalExpr(t)
+	if err != nil {
+		return "", err
+	}
+	g.types[name] = fmt.Sprintf("\n// %s mirrors %s from %s.\ntype %s %s\n", name, t.String(), t.PkgPath(), name, def)
+	return name, nil
+}
+
+// writeTypes writes the copied types, in the order of their names.
+func (g *generator) writeTypes() {
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.body.WriteString(g.types[name])
+	}
+}
+
+func isLight(path string) bool {
+	if isStd(path) {
+		return true
+	}
+	for _, m := range lightModules {
+		if path == m || strings.HasPrefix(path, m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// imports are the packages imported by the generated code, by path.
+type imports struct {
+	byPath map[string]string
+	byName map[string]string
+}
+
+func newImports() *imports {
+	im := &imports{byPath: make(map[string]string), byName: make(map[string]string)}
+	for path, name := range map[string]string{
+		"context":                       "context",
+		"fmt":                           "fmt",
+		"github.com/ipfs/go-ipfs-cmds":  "cmds",
+		"github.com/ipfs/go-ipfs-files": "files",
+	} {
+		im.byPath[path] = name
+		im.byName[name] = path
+	}
+	return im
+}
+
+// add imports path, and returns its name.
+func (im *imports) add(path, name string) string {
+	if n, ok := im.byPath[path]; ok {
+		return n
+	}
+	base := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+	n := base
+	for i := 2; im.byName[n] != "" || reserved[n]; i++ {
+		n = fmt.Sprintf("%s%d", base, i)
+	}
+	im.byPath[path] = n
+	im.byName[n] = path
+	return n
+}
+
+// paths returns the imported paths, standard library first.
+func (im *imports) paths() []string {
+	paths := make([]string, 0, len(im.byPath))
+	for p := range im.byPath {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if isStd(paths[i]) != isStd(paths[j]) {
+			return isStd(paths[i])
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+func isStd(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+}
+
+// initialisms are the words kept in upper case in the generated names.
+var initialisms = map[string]bool{
+	"API": true, "CID": true, "DHT": true, "DNS": true, "HTTP": true, "ID": true, "IPFS": true,
+	"IPLD": true, "IPNS": true, "JSON": true, "P2P": true, "URL": true,
+}
+
+// nameParts splits words like "cid-version" or "peer ID" into their parts.
+func nameParts(words ...string) []string {
+	var parts []string
+	for _, w := range words {
+		parts = append(parts, strings.FieldsFunc(w, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+	return parts
+}
+
+func capitalize(part string) string {
+	if initialisms[strings.ToUpper(part)] {
+		return strings.ToUpper(part)
+	}
+	r := []rune(part)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// exportedName joins words into an exported Go name, e.g. "cid-version" into
+// CIDVersion.
+func exportedName(words ...string) string {
+	var b strings.Builder
+	for _, part := range nameParts(words...) {
+		b.WriteString(capitalize(part))
+	}
+	return b.String()
+}
+
+// paramName joins words into an unexported Go name, e.g. "ipfs-path" into
+// ipfsPath.
+func paramName(words ...string) string {
+	var b strings.Builder
+	for i, part := range nameParts(words...) {
+		if i == 0 {
+			b.WriteString(strings.ToLower(part))
+		} else {
+			b.WriteString(capitalize(part))
+		}
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	// keep acronyms, like DHT or IPNS
+	if len(r) > 1 && unicode.IsUpper(r[1]) {
+		return s
+	}
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// comment puts s on a single comment line.
+func comment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
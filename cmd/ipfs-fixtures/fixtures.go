@@ -0,0 +1,419 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	proto "github.com/gogo/protobuf/proto"
+	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	chunker "github.com/ipfs/go-ipfs-chunker"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	format "github.com/ipfs/go-ipld-format"
+	ipns "github.com/ipfs/go-ipns"
+	merkledag "github.com/ipfs/go-merkledag"
+	ft "github.com/ipfs/go-unixfs"
+	"github.com/ipfs/go-unixfs/hamt"
+	"github.com/ipfs/go-unixfs/importer/balanced"
+	ihelper "github.com/ipfs/go-unixfs/importer/helpers"
+	"github.com/ipfs/go-unixfs/importer/trickle"
+	uio "github.com/ipfs/go-unixfs/io"
+	car "github.com/ipld/go-car"
+	ci "github.com/libp2p/go-libp2p-core/crypto"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	mbase "github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Types of the fixtures.
+const (
+	TypeUnixFS = "unixfs"
+	TypeIPNS   = "ipns"
+)
+
+// Spec describes the fixtures to generate.
+type Spec struct {
+	// Seed is the seed of the random content of the files and of the keys.
+	Seed     int64
+	Fixtures []Fixture
+}
+
+// Fixture is a fixture of a Spec. Its fields depend on its Type.
+type Fixture struct {
+	Name string
+	Type string
+
+	// Root is the tree of an unixfs fixture, written as a CAR.
+	Root *Node
+	// Chunker is the chunker of the files, like "size-1024" or
+	// "rabin-128-256-512". Default: "size-262144".
+	Chunker string
+	// Layout is the layout of the files: "balanced" or "trickle".
+	// Default: "balanced".
+	Layout       string
+	RawLeaves    bool
+	CidVersion   int
+	HashFunction string
+	// MaxLinks is the largest number of links of the nodes of the files.
+	// Default: 174, like 'ipfs add'.
+	MaxLinks int
+
+	// Key is the name of the key signing an ipns fixture, which is derived
+	// from the seed. Fixtures with the same Key are signed by the same key.
+	// Default: the name of the fixture.
+	Key string
+	// Value is the path the record points to, or Target the name of the
+	// unixfs fixture whose root it points to.
+	Value    string
+	Target   string
+	Sequence uint64
+	// Validity is the end of validity of the record, RFC 3339. Required,
+	// as the fixtures don't depend on the time they are generated at.
+	Validity string
+	// TTL is the TTL of the record, like "1h". Default: "1h".
+	TTL string
+}
+
+// Node is a file, directory or symlink of an unixfs fixture.
+type Node struct {
+	// Content is the content of a file, or Size the size of its random
+	// content.
+	Content *string
+	Size    int64
+
+	// Symlink is the target of a symlink.
+	Symlink *string
+
+	// Entries are the entries of a directory.
+	Entries map[string]*Node
+	// Generate adds Count files of Size random bytes to a directory,
+	// named file-0, file-1...
+	Generate *FileSet
+	// ShardWidth makes a HAMT-sharded directory with this fanout, a power
+	// of 2 from 8. Directories are only sharded when too large otherwise.
+	ShardWidth int
+}
+
+// FileSet are the generated files of a directory.
+type FileSet struct {
+	Count int
+	Size  int64
+}
+
+func (n *Node) isDir() bool {
+	return n.Entries != nil || n.Generate != nil || n.ShardWidth > 0
+}
+
+// Manifest lists the fixtures generated, written to fixtures.json.
+type Manifest struct {
+	Fixtures []Generated
+}
+
+// Generated is a fixture generated.
+type Generated struct {
+	Name string
+	Type string
+	File string
+	// Root is the root CID of an unixfs fixture.
+	Root string `json:",omitempty"`
+	// IPNSName is the IPNS name of an ipns fixture, and Value its value.
+	IPNSName string `json:",omitempty"`
+	Value    string `json:",omitempty"`
+}
+
+// Generate generates the fixtures of spec into outDir.
+func Generate(ctx context.Context, spec Spec, outDir string) (*Manifest, error) {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+
+	g := &generator{seed: spec.Seed, roots: make(map[string]cid.Cid)}
+	manifest := &Manifest{}
+	seen := make(map[string]bool)
+	for _, f := range spec.Fixtures {
+		if f.Name == "" || strings.ContainsAny(f.Name, `/\`) {
+			return nil, fmt.Errorf("invalid fixture name %q", f.Name)
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("fixture %q is listed twice", f.Name)
+		}
+		seen[f.Name] = true
+
+		var out *Generated
+		var err error
+		switch f.Type {
+		case TypeUnixFS:
+			out, err = g.unixfs(ctx, f, outDir)
+		case TypeIPNS:
+			out, err = g.ipns(f, outDir)
+		default:
+			err = fmt.Errorf("unknown type %q", f.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fixture %q: %w", f.Name, err)
+		}
+		manifest.Fixtures = append(manifest.Fixtures, *out)
+	}
+	return manifest, nil
+}
+
+type generator struct {
+	seed int64
+	// roots are the roots of the unixfs fixtures generated, by name
+	roots map[string]cid.Cid
+}
+
+// random returns the random bytes derived from the seed for name.
+func (g *generator) random(name string) io.Reader {
+	sum := g.derive(name)
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+}
+
+func (g *generator) derive(name string) [32]byte {
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], uint64(g.seed))
+	return sha256.Sum256(append(seed[:], name...))
+}
+
+// unixfsBuilder builds the nodes of an unixfs fixture.
+type unixfsBuilder struct {
+	g      *generator
+	f      Fixture
+	dag    format.DAGService
+	prefix cid.Prefix
+}
+
+func (g *generator) unixfs(ctx context.Context, f Fixture, outDir string) (*Generated, error) {
+	if f.Root == nil {
+		return nil, fmt.Errorf("no Root")
+	}
+	switch f.Layout {
+	case "", "balanced", "trickle":
+	default:
+		return nil, fmt.Errorf("unknown layout %q", f.Layout)
+	}
+	prefix, err := merkledag.PrefixForCidVersion(f.CidVersion)
+	if err != nil {
+		return nil, err
+	}
+	if f.HashFunction != "" {
+		code, ok := mh.Names[strings.ToLower(f.HashFunction)]
+		if !ok {
+			return nil, fmt.Errorf("unknown hash function %q", f.HashFunction)
+		}
+		prefix.MhType = code
+		prefix.MhLength = -1
+	}
+
+	bs := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	b := &unixfsBuilder{
+		g:      g,
+		f:      f,
+		dag:    merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs))),
+		prefix: prefix,
+	}
+	root, err := b.build(ctx, f.Name, f.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	file := f.Name + ".car"
+	out, err := os.Create(filepath.Join(outDir, file))
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+	if err := car.WriteCar(ctx, b.dag, []cid.Cid{root.Cid()}, out); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+
+	g.roots[f.Name] = root.Cid()
+	return &Generated{Name: f.Name, Type: TypeUnixFS, File: file, Root: root.Cid().String()}, nil
+}
+
+// build builds n, at path in the fixture.
+func (b *unixfsBuilder) build(ctx context.Context, path string, n *Node) (format.Node, error) {
+	switch {
+	case n.isDir():
+		return b.dir(ctx, path, n)
+	case n.Symlink != nil:
+		data, err := ft.SymlinkData(*n.Symlink)
+		if err != nil {
+			return nil, err
+		}
+		nd := merkledag.NodeWithData(data)
+		nd.SetCidBuilder(b.prefix)
+		return nd, b.dag.Add(ctx, nd)
+	case n.Content != nil:
+		return b.file(strings.NewReader(*n.Content))
+	default:
+		return b.file(io.LimitReader(b.g.random(path), n.Size))
+	}
+}
+
+func (b *unixfsBuilder) file(r io.Reader) (format.Node, error) {
+	chunk := b.f.Chunker
+	if chunk == "" {
+		chunk = "size-262144"
+	}
+	spl, err := chunker.FromString(r, chunk)
+	if err != nil {
+		return nil, err
+	}
+	maxLinks := b.f.MaxLinks
+	if maxLinks == 0 {
+		maxLinks = ihelper.DefaultLinksPerBlock
+	}
+	params := ihelper.DagBuilderParams{
+		Dagserv:    b.dag,
+		RawLeaves:  b.f.RawLeaves,
+		Maxlinks:   maxLinks,
+		CidBuilder: b.prefix,
+	}
+	db, err := params.New(spl)
+	if err != nil {
+		return nil, err
+	}
+	if b.f.Layout == "trickle" {
+		return trickle.Layout(db)
+	}
+	return balanced.Layout(db)
+}
+
+func (b *unixfsBuilder) dir(ctx context.Context, path string, n *Node) (format.Node, error) {
+	entries := make(map[string]*Node, len(n.Entries))
+	for name, e := range n.Entries {
+		if name == "" || strings.Contains(name, "/") || e == nil {
+			return nil, fmt.Errorf("%s: invalid entry %q", path, name)
+		}
+		entries[name] = e
+	}
+	if n.Generate != nil {
+		for i := 0; i < n.Generate.Count; i++ {
+			name := fmt.Sprintf("file-%d", i)
+			if _, ok := entries[name]; ok {
+				return nil, fmt.Errorf("%s: %q is both an entry and generated", path, name)
+			}
+			entries[name] = &Node{Size: n.Generate.Size}
+		}
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var add func(ctx context.Context, name string, nd format.Node) error
+	var node func() (format.Node, error)
+	if n.ShardWidth > 0 {
+		shard, err := hamt.NewShard(b.dag, n.ShardWidth)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		shard.SetCidBuilder(b.prefix)
+		add, node = shard.Set, shard.Node
+	} else {
+		dir := uio.NewDirectory(b.dag)
+		dir.SetCidBuilder(b.prefix)
+		add, node = dir.AddChild, dir.GetNode
+	}
+
+	for _, name := range names {
+		nd, err := b.build(ctx, path+"/"+name, entries[name])
+		if err != nil {
+			return nil, err
+		}
+		if err := add(ctx, name, nd); err != nil {
+			return nil, err
+		}
+	}
+	nd, err := node()
+	if err != nil {
+		return nil, err
+	}
+	return nd, b.dag.Add(ctx, nd)
+}
+
+func (g *generator) ipns(f Fixture, outDir string) (*Generated, error) {
+	value := f.Value
+	if f.Target != "" {
+		if value != "" {
+			return nil, fmt.Errorf("both Value and Target are set")
+		}
+		root, ok := g.roots[f.Target]
+		if !ok {
+			return nil, fmt.Errorf("no unixfs fixture %q before it", f.Target)
+		}
+		value = "/ipfs/" + root.String()
+	}
+	if value == "" {
+		return nil, fmt.Errorf("no Value or Target")
+	}
+	if f.Validity == "" {
+		return nil, fmt.Errorf("no Validity")
+	}
+	eol, err := time.Parse(time.RFC3339, f.Validity)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Validity: %w", err)
+	}
+	ttl := time.Hour
+	if f.TTL != "" {
+		if ttl, err = time.ParseDuration(f.TTL); err != nil {
+			return nil, fmt.Errorf("invalid TTL: %w", err)
+		}
+	}
+
+	keyName := f.Key
+	if keyName == "" {
+		keyName = f.Name
+	}
+	seed := g.derive("key/" + keyName)
+	sk, pk, err := ci.GenerateEd25519Key(bytes.NewReader(seed[:]))
+	if err != nil {
+		return nil, err
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	name, err := peer.ToCid(id).StringOfBase(mbase.Base36)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := ipns.Create(sk, []byte(value), f.Sequence, eol.UTC(), ttl)
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	file := f.Name + ".ipns-record"
+	if err := os.WriteFile(filepath.Join(outDir, file), data, 0644); err != nil {
+		return nil, err
+	}
+	return &Generated{
+		Name:     f.Name,
+		Type:     TypeIPNS,
+		File:     file,
+		IPNSName: name,
+		Value:    value,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	proto "github.com/gogo/protobuf/proto"
+	ipns "github.com/ipfs/go-ipns"
+	pb "github.com/ipfs/go-ipns/pb"
+	car "github.com/ipld/go-car"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+const testSpec = `{"Seed": 7, "Fixtures": [
+	{"Name": "tree", "Type": "unixfs", "CidVersion": 1, "RawLeaves": true, "Chunker": "size-256", "MaxLinks": 2, "Root": {"Entries": {
+		"a.txt": {"Content": "a"},
+		"random": {"Size": 4000},
+		"link": {"Symlink": "a.txt"},
+		"sharded": {"ShardWidth": 8, "Generate": {"Count": 40}}
+	}}},
+	{"Name": "file", "Type": "unixfs", "Layout": "trickle", "Root": {"Size": 1000}},
+	{"Name": "record", "Type": "ipns", "Target": "tree", "Sequence": 1, "Validity": "2030-01-01T00:00:00Z"}
+]}`
+
+func generate(t *testing.T, specJSON string) (*Manifest, string) {
+	t.Helper()
+	var spec Spec
+	if err := json.Unmarshal([]byte(specJSON), &spec); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	m, err := Generate(context.Background(), spec, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, dir
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	m1, dir1 := generate(t, testSpec)
+	m2, dir2 := generate(t, testSpec)
+	if len(m1.Fixtures) != 3 {
+		t.Fatalf("expected 3 fixtures, got %d", len(m1.Fixtures))
+	}
+	for i, f := range m1.Fixtures {
+		if f != m2.Fixtures[i] {
+			t.Fatalf("expected the same fixtures, got %+v and %+v", f, m2.Fixtures[i])
+		}
+		b1, err := os.ReadFile(filepath.Join(dir1, f.File))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b2, err := os.ReadFile(filepath.Join(dir2, f.File))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b1, b2) {
+			t.Fatalf("expected the same content for %s", f.File)
+		}
+	}
+
+	r, err := os.Open(filepath.Join(dir1, "tree.car"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	cr, err := car.NewCarReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cr.Header.Roots) != 1 || cr.Header.Roots[0].String() != m1.Fixtures[0].Root {
+		t.Fatalf("unexpected roots %v", cr.Header.Roots)
+	}
+}
+
+func TestGenerateIPNS(t *testing.T) {
+	m, dir := generate(t, testSpec)
+	rec := m.Fixtures[2]
+	if rec.Value != "/ipfs/"+m.Fixtures[0].Root {
+		t.Fatalf("expected the record to point to the tree, got %s", rec.Value)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, rec.File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry pb.IpnsEntry
+	if err := proto.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.Decode(rec.IPNSName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := id.ExtractPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ipns.Validate(pk, &entry); err != nil {
+		t.Fatalf("expected a valid record: %s", err)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, spec := range []string{
+		`{"Fixtures": [{"Name": "x", "Type": "car"}]}`,
+		`{"Fixtures": [{"Name": "x", "Type": "unixfs"}]}`,
+		`{"Fixtures": [{"Name": "x/y", "Type": "unixfs", "Root": {}}]}`,
+		`{"Fixtures": [{"Name": "x", "Type": "unixfs", "Layout": "flat", "Root": {}}]}`,
+		`{"Fixtures": [{"Name": "x", "Type": "unixfs", "Root": {"ShardWidth": 3}}]}`,
+		`{"Fixtures": [{"Name": "x", "Type": "ipns", "Target": "missing", "Validity": "2030-01-01T00:00:00Z"}]}`,
+		`{"Fixtures": [{"Name": "x", "Type": "ipns", "Value": "/ipfs/bafkqaaa"}]}`,
+	} {
+		var s Spec
+		if err := json.Unmarshal([]byte(spec), &s); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Generate(context.Background(), s, t.TempDir()); err == nil {
+			t.Errorf("expected an error for %s", spec)
+		}
+	}
+}
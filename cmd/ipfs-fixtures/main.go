@@ -0,0 +1,60 @@
+// Command ipfs-fixtures generates the canonical test fixtures described in a
+// spec file: UnixFS trees written as CARs, and signed IPNS records. The same
+// spec always generates the same fixtures, byte for byte, so that the interop
+// tests of go-ipfs and of other implementations can share them.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+var outDir = flag.String("o", ".", "the directory to write the fixtures to")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-o dir] <spec.json>\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), flag.Arg(0), *outDir); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, specPath, outDir string) error {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("parsing %s: %w", specPath, err)
+	}
+
+	manifest, err := Generate(ctx, spec, outDir)
+	if err != nil {
+		return err
+	}
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "fixtures.json"), append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	for _, f := range manifest.Fixtures {
+		log.Printf("%s: %s", f.File, f.Root+f.IPNSName)
+	}
+	return nil
+}
@@ -9,8 +9,11 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,6 +31,7 @@ import (
 	corerepo "github.com/ipfs/go-ipfs/core/corerepo"
 	libp2p "github.com/ipfs/go-ipfs/core/node/libp2p"
 	nodeMount "github.com/ipfs/go-ipfs/fuse/node"
+	repo "github.com/ipfs/go-ipfs/repo"
 	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
 	"github.com/ipfs/go-ipfs/repo/fsrepo/migrations"
 	"github.com/ipfs/go-ipfs/repo/fsrepo/migrations/ipfsfetcher"
@@ -46,6 +50,8 @@ import (
 const (
 	adjustFDLimitKwd          = "manage-fdlimit"
 	enableGCKwd               = "enable-gc"
+	ephemeralKwd              = "ephemeral"
+	ephemeralMaxSizeKwd       = "ephemeral-max-size"
 	initOptionKwd             = "init"
 	initConfigOptionKwd       = "init-config"
 	initProfileOptionKwd      = "init-profile"
@@ -67,6 +73,7 @@ const (
 	enablePubSubKwd           = "enable-pubsub-experiment"
 	enableIPNSPubSubKwd       = "enable-namesys-pubsub"
 	enableMultiplexKwd        = "enable-mplex-experiment"
+	readOnlyKwd               = "read-only"
 	agentVersionSuffix        = "agent-version-suffix"
 	// apiAddrKwd    = "address-api"
 	// swarmAddrKwd  = "address-swarm"
@@ -141,6 +148,37 @@ environment variable:
 
   export IPFS_PATH=/path/to/ipfsrepo
 
+Ephemeral nodes
+
+For CI, tests and throwaway gateway caches, the daemon can run without an
+on-disk repo at all:
+
+  ipfs daemon --ephemeral
+
+A fresh identity and default config are generated on every start (use
+--init-config and --init-profile to customize them), and all data is kept
+in an in-memory datastore that is lost on shutdown. Use
+--ephemeral-max-size to cap how much memory the datastore may use. Since no
+api file is written, clients have to be pointed at the daemon with --api.
+
+Read-only repo
+
+To serve a repo that must not change, such as an immutable snapshot or a
+volume shared with other nodes, run the daemon as:
+
+  ipfs daemon --read-only
+
+or set Datastore.ReadOnly in the config to also apply it to the commands run
+without a daemon. Adding content, changing the pins, writing to MFS and
+changing the keys or IPNS names fail with "the repo is read-only", while the
+content of the repo is still read, and served by the gateway and bitswap.
+Content missing from the repo can't be fetched, as it can't be stored.
+
+The repo is neither locked nor written to, so it may be on a read-only
+filesystem. What the node records while running, such as the routing records,
+is kept in memory, up to 64MiB past which these writes fail. As no api file is
+written, clients have to be pointed at the daemon with --api.
+
 Routing
 
 IPFS by default will use a DHT for content routing. There is a highly
@@ -168,6 +206,8 @@ Headers.
 		cmds.BoolOption(initOptionKwd, "Initialize ipfs with default settings if not already initialized"),
 		cmds.StringOption(initConfigOptionKwd, "Path to existing configuration file to be loaded during --init"),
 		cmds.StringOption(initProfileOptionKwd, "Configuration profiles to apply for --init. See ipfs init --help for more"),
+		cmds.BoolOption(ephemeralKwd, "Run from an in-memory repo with a new identity, keeping nothing on disk. Uses --init-config and --init-profile if given."),
+		cmds.StringOption(ephemeralMaxSizeKwd, "Maximum size of the in-memory datastore used with --ephemeral, e.g. '2GiB'. Unlimited by default."),
 		cmds.StringOption(routingOptionKwd, "Overrides the routing option").WithDefault(routingOptionDefaultKwd),
 		cmds.BoolOption(mountKwd, "Mounts IPFS to the filesystem"),
 		cmds.BoolOption(writableKwd, "Enable writing objects (with POST, PUT and DELETE)"),
@@ -181,6 +221,7 @@ Headers.
 		cmds.BoolOption(enablePubSubKwd, "Enable experimental pubsub feature. Overrides Pubsub.Enabled config."),
 		cmds.BoolOption(enableIPNSPubSubKwd, "Enable IPNS over pubsub. Implicitly enables pubsub, overrides Ipns.UsePubsub config."),
 		cmds.BoolOption(enableMultiplexKwd, "DEPRECATED"),
+		cmds.BoolOption(readOnlyKwd, "Refuse the operations changing the blocks, pins and keys of the repo, while still serving its content. Implied by Datastore.ReadOnly."),
 		cmds.StringOption(agentVersionSuffix, "Optional suffix to the AgentVersion presented by `ipfs id` and also advertised through BitSwap."),
 
 		// TODO: add way to override addresses. tricky part: updating the config if also --init.
@@ -242,10 +283,12 @@ func daemonFunc(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment
 		You will not be able to connect to regular encrypted networks.`, unencryptTransportKwd)
 	}
 
+	ephemeral, _ := req.Options[ephemeralKwd].(bool)
+
 	// first, whether user has provided the initialization flag. we may be
 	// running in an uninitialized state.
 	initialize, _ := req.Options[initOptionKwd].(bool)
-	if initialize && !fsrepo.IsInitialized(cctx.ConfigRoot) {
+	if initialize && !ephemeral && !fsrepo.IsInitialized(cctx.ConfigRoot) {
 		cfgLocation, _ := req.Options[initConfigOptionKwd].(string)
 		profiles, _ := req.Options[initProfileOptionKwd].(string)
 		var conf *config.Config
@@ -277,13 +320,27 @@ func daemonFunc(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment
 	var cacheMigrations, pinMigrations bool
 	var fetcher migrations.Fetcher
 
-	// acquire the repo lock _before_ constructing a node. we need to make
-	// sure we are permitted to access the resources (datastore, etc.)
-	repo, err := fsrepo.Open(cctx.ConfigRoot)
+	readOnly, _ := req.Options[readOnlyKwd].(bool)
+
+	var repo repo.Repo
+	switch {
+	case ephemeral:
+		repo, err = openEphemeralRepo(req)
+	case readOnly:
+		// a read-only repo is not locked, nothing is written to it
+		repo, err = fsrepo.OpenReadOnly(cctx.ConfigRoot)
+	default:
+		// acquire the repo lock _before_ constructing a node. we need to make
+		// sure we are permitted to access the resources (datastore, etc.)
+		repo, err = fsrepo.Open(cctx.ConfigRoot)
+	}
 	switch err {
 	default:
 		return err
 	case fsrepo.ErrNeedMigration:
+		if readOnly {
+			return fmt.Errorf("fs-repo requires migration, which can't run on a read-only repo")
+		}
 		domigrate, found := req.Options[migrateKwd].(bool)
 		fmt.Println("Found outdated fs-repo, migrations need to be run.")
 
@@ -393,8 +450,9 @@ func daemonFunc(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment
 		Online:                      !offline,
 		DisableEncryptedConnections: unencrypted,
 		ExtraOpts: map[string]bool{
-			"pubsub": pubsub,
-			"ipnsps": ipnsps,
+			"pubsub":   pubsub,
+			"ipnsps":   ipnsps,
+			"readonly": readOnly,
 		},
 		//TODO(Kubuxu): refactor Online vs Offline by adding Permanent vs Ephemeral
 	}
@@ -530,9 +588,20 @@ func daemonFunc(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment
 	// start MFS pinning thread
 	startPinMFS(daemonConfigPollInterval, cctx, &ipfsPinMFSNode{node})
 
-	// The daemon is *finally* ready.
-	fmt.Printf("Daemon is ready\n")
-	notifyReady()
+	// The daemon is *finally* ready, once the routers in
+	// Routing.HealthCheck.ReadyRouters are healthy.
+	if node.RoutingHealth != nil && len(node.RoutingHealth.ReadyRouters()) > 0 {
+		fmt.Printf("Waiting for the routers to be healthy: %s\n", strings.Join(node.RoutingHealth.ReadyRouters(), ", "))
+		go func() {
+			if node.RoutingHealth.WaitReady(req.Context) == nil {
+				fmt.Printf("Daemon is ready\n")
+				notifyReady()
+			}
+		}()
+	} else {
+		fmt.Printf("Daemon is ready\n")
+		notifyReady()
+	}
 
 	// Give the user some immediate feedback when they hit C-c
 	go func() {
@@ -587,6 +656,60 @@ func daemonFunc(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment
 	return errs
 }
 
+// openEphemeralRepo builds an in-memory repo for --ephemeral. It never
+// touches the on-disk repo: the config is generated (or loaded from
+// --init-config) and all data lives in a "mem" datastore.
+func openEphemeralRepo(req *cmds.Request) (repo.Repo, error) {
+	cfgLocation, _ := req.Options[initConfigOptionKwd].(string)
+	profiles, _ := req.Options[initProfileOptionKwd].(string)
+	maxSize, _ := req.Options[ephemeralMaxSizeKwd].(string)
+
+	var conf *config.Config
+	var err error
+	if cfgLocation != "" {
+		if conf, err = cserial.Load(cfgLocation); err != nil {
+			return nil, err
+		}
+	} else {
+		identity, err := config.CreateIdentity(ioutil.Discard, []options.KeyGenerateOption{
+			options.Key.Type(algorithmDefault),
+		})
+		if err != nil {
+			return nil, err
+		}
+		conf, err = config.InitWithIdentity(identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyProfiles(conf, profiles); err != nil {
+		return nil, err
+	}
+
+	if conf.Experimental.FilestoreEnabled || conf.Experimental.UrlstoreEnabled {
+		return nil, fmt.Errorf("the filestore and urlstore cannot be used with --%s", ephemeralKwd)
+	}
+
+	spec := map[string]interface{}{"type": "mem"}
+	if maxSize != "" {
+		spec["maxSize"] = maxSize
+	}
+	conf.Datastore.Spec = spec
+
+	dsc, err := fsrepo.AnyDatastoreConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+	d, err := dsc.Create("")
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Running ephemeral node with peer identity %s, nothing will be persisted\n", conf.Identity.PeerID)
+	return repo.NewMemRepo(conf, d), nil
+}
+
 // serveHTTPApi collects options, creates listener, prints status message and starts serving requests
 func serveHTTPApi(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, error) {
 	cfg, err := cctx.GetConfig()
@@ -608,8 +731,12 @@ func serveHTTPApi(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, error
 	}
 
 	listenerAddrs := make(map[string]bool, len(listeners))
+	listenerCfgs := make(map[manet.Listener]config.HTTPListener, len(listeners))
 	for _, listener := range listeners {
 		listenerAddrs[string(listener.Multiaddr().Bytes())] = true
+		if listenerCfgs[listener], err = listenerConfig(cfg, listener.Multiaddr()); err != nil {
+			return nil, fmt.Errorf("serveHTTPApi: %w", err)
+		}
 	}
 
 	for _, addr := range apiAddrs {
@@ -621,22 +748,41 @@ func serveHTTPApi(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, error
 			continue
 		}
 
-		apiLis, err := manet.Listen(apiMaddr)
+		lcfg, err := listenerConfig(cfg, apiMaddr)
+		if err != nil {
+			return nil, fmt.Errorf("serveHTTPApi: %w", err)
+		}
+		apiLis, err := listenHTTP(apiMaddr, lcfg)
 		if err != nil {
 			return nil, fmt.Errorf("serveHTTPApi: manet.Listen(%s) failed: %s", apiMaddr, err)
 		}
+		// the settings may be keyed by the address listened on, like the
+		// port picked for /tcp/0
+		if lcfg, err = listenerConfig(cfg, apiMaddr, apiLis.Multiaddr()); err != nil {
+			apiLis.Close()
+			return nil, fmt.Errorf("serveHTTPApi: %w", err)
+		}
 
-		listenerAddrs[string(apiMaddr.Bytes())] = true
+		listenerAddrs[string(apiLis.Multiaddr().Bytes())] = true
 		listeners = append(listeners, apiLis)
+		listenerCfgs[apiLis] = lcfg
 	}
 
 	for _, listener := range listeners {
 		// we might have listened to /tcp/0 - let's see what we are listing on
+		if listenerCfgs[listener].ReadOnly.WithDefault(false) {
+			fmt.Printf("API server (read-only) listening on %s\n", listener.Multiaddr())
+			continue
+		}
 		fmt.Printf("API server listening on %s\n", listener.Multiaddr())
 		// Browsers require TCP.
 		switch listener.Addr().Network() {
 		case "tcp", "tcp4", "tcp6":
-			fmt.Printf("WebUI: http://%s/webui\n", listener.Addr())
+			scheme := "http"
+			if !listenerCfgs[listener].TLSCertFile.IsDefault() {
+				scheme = "https"
+			}
+			fmt.Printf("WebUI: %s://%s/webui\n", scheme, listener.Addr())
 		}
 	}
 
@@ -650,9 +796,13 @@ func serveHTTPApi(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, error
 		gatewayOpt = corehttp.GatewayOption(true, "/ipfs", "/ipns")
 	}
 
+	// a client has the same limit whatever listeners it sends its requests to
+	rateLimitOpt := corehttp.APIRateLimitOption(cfg.API.RateLimit)
+
 	var opts = []corehttp.ServeOption{
 		corehttp.MetricsCollectionOption("api"),
 		corehttp.MetricsOpenCensusCollectionOption(),
+		rateLimitOpt,
 		corehttp.CheckVersionOption(),
 		corehttp.CommandsOption(*cctx),
 		corehttp.WebUIOption,
@@ -671,12 +821,30 @@ func serveHTTPApi(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, error
 		opts = append(opts, corehttp.RedirectOption("", cfg.Gateway.RootRedirect))
 	}
 
+	// read-only listeners only serve the commands inspecting the node.
+	var roOpts = []corehttp.ServeOption{
+		corehttp.MetricsCollectionOption("api"),
+		rateLimitOpt,
+		corehttp.CheckVersionOption(),
+		corehttp.CommandsInspectOption(*cctx),
+		corehttp.VersionOption(),
+	}
+
 	node, err := cctx.ConstructNode()
 	if err != nil {
 		return nil, fmt.Errorf("serveHTTPApi: ConstructNode() failed: %s", err)
 	}
 
-	if err := node.Repo.SetAPIAddr(listeners[0].Multiaddr()); err != nil {
+	// the CLI talks to the daemon through the address in the api file, it
+	// needs a listener serving all the commands.
+	apiFileLis := listeners[0]
+	for _, lis := range listeners {
+		if !listenerCfgs[lis].ReadOnly.WithDefault(false) {
+			apiFileLis = lis
+			break
+		}
+	}
+	if err := node.Repo.SetAPIAddr(apiFileLis.Multiaddr()); err != nil {
 		return nil, fmt.Errorf("serveHTTPApi: SetAPIAddr() failed: %s", err)
 	}
 
@@ -686,7 +854,11 @@ func serveHTTPApi(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, error
 		wg.Add(1)
 		go func(lis manet.Listener) {
 			defer wg.Done()
-			errc <- corehttp.Serve(node, manet.NetListener(lis), opts...)
+			lisOpts := opts
+			if listenerCfgs[lis].ReadOnly.WithDefault(false) {
+				lisOpts = roOpts
+			}
+			errc <- corehttp.ServeWithListenerConfig(node, manet.NetListener(lis), listenerCfgs[lis], lisOpts...)
 		}(apiLis)
 	}
 
@@ -698,6 +870,109 @@ func serveHTTPApi(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, error
 	return errc, nil
 }
 
+// listenerConfig returns the Addresses.Listeners entry matching the first
+// of addrs that has one, or the zero value if there is none. addrs are the
+// address of a listener as configured, and as listened on once resolved.
+func listenerConfig(cfg *config.Config, addrs ...ma.Multiaddr) (config.HTTPListener, error) {
+	keys := make(map[string]config.HTTPListener, len(cfg.Addresses.Listeners))
+	for k, lcfg := range cfg.Addresses.Listeners {
+		kaddr, err := ma.NewMultiaddr(k)
+		if err != nil {
+			return config.HTTPListener{}, fmt.Errorf("invalid Addresses.Listeners key %q: %s", k, err)
+		}
+		keys[string(kaddr.Bytes())] = lcfg
+	}
+	for _, addr := range addrs {
+		if lcfg, ok := keys[string(addr.Bytes())]; ok {
+			return lcfg, nil
+		}
+	}
+	return config.HTTPListener{}, nil
+}
+
+// listenHTTP listens on an API or Gateway address. Unix sockets left over
+// by a previous daemon are replaced, and get the permissions set in
+// SocketMode.
+func listenHTTP(addr ma.Multiaddr, lcfg config.HTTPListener) (manet.Listener, error) {
+	path, err := addr.ValueForProtocol(ma.P_UNIX)
+	if err != nil {
+		return manet.Listen(addr)
+	}
+
+	// Only remove sockets nobody accepts connections on anymore.
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+		} else if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if lcfg.SocketMode.IsDefault() {
+		return manet.Listen(addr)
+	}
+	m, err := strconv.ParseUint(lcfg.SocketMode.WithDefault(""), 8, 32)
+	if err != nil || m > 0777 {
+		return nil, fmt.Errorf("invalid SocketMode %q: must be octal permissions, like 0660", lcfg.SocketMode.WithDefault(""))
+	}
+	return listenUnixWithMode(addr, path, os.FileMode(m))
+}
+
+// listenUnixWithMode listens on the unix socket at path with the given
+// permissions. The socket is created in a private directory, where nobody
+// else can connect to it before its permissions are set, then moved to path.
+func listenUnixWithMode(addr ma.Multiaddr, path string, mode os.FileMode) (manet.Listener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".ipfs")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "s")
+	tmpAddr, err := ma.NewComponent("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	lis, err := manet.Listen(tmpAddr)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmp, mode); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	return &movedUnixListener{Listener: lis, addr: addr, path: path}, nil
+}
+
+// movedUnixListener is a listener on a unix socket moved to path.
+type movedUnixListener struct {
+	manet.Listener
+	addr ma.Multiaddr
+	path string
+}
+
+func (l *movedUnixListener) Multiaddr() ma.Multiaddr {
+	return l.addr
+}
+
+func (l *movedUnixListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: l.path, Net: "unix"}
+}
+
+// Close closes the listener and removes its socket, like the listeners on
+// the socket they created.
+func (l *movedUnixListener) Close() error {
+	err := l.Listener.Close()
+	if rerr := os.Remove(l.path); rerr != nil && !os.IsNotExist(rerr) && err == nil {
+		err = rerr
+	}
+	return err
+}
+
 // printSwarmAddrs prints the addresses of the host
 func printSwarmAddrs(node *core.IpfsNode) {
 	if !node.IsOnline {
@@ -747,8 +1022,12 @@ func serveHTTPGateway(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, e
 	}
 
 	listenerAddrs := make(map[string]bool, len(listeners))
+	listenerCfgs := make(map[manet.Listener]config.HTTPListener, len(listeners))
 	for _, listener := range listeners {
 		listenerAddrs[string(listener.Multiaddr().Bytes())] = true
+		if listenerCfgs[listener], err = listenerConfig(cfg, listener.Multiaddr()); err != nil {
+			return nil, fmt.Errorf("serveHTTPGateway: %w", err)
+		}
 	}
 
 	gatewayAddrs := cfg.Addresses.Gateway
@@ -762,12 +1041,23 @@ func serveHTTPGateway(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, e
 			continue
 		}
 
-		gwLis, err := manet.Listen(gatewayMaddr)
+		lcfg, err := listenerConfig(cfg, gatewayMaddr)
+		if err != nil {
+			return nil, fmt.Errorf("serveHTTPGateway: %w", err)
+		}
+		gwLis, err := listenHTTP(gatewayMaddr, lcfg)
 		if err != nil {
 			return nil, fmt.Errorf("serveHTTPGateway: manet.Listen(%s) failed: %s", gatewayMaddr, err)
 		}
-		listenerAddrs[string(gatewayMaddr.Bytes())] = true
+		// the settings may be keyed by the address listened on, like the
+		// port picked for /tcp/0
+		if lcfg, err = listenerConfig(cfg, gatewayMaddr, gwLis.Multiaddr()); err != nil {
+			gwLis.Close()
+			return nil, fmt.Errorf("serveHTTPGateway: %w", err)
+		}
+		listenerAddrs[string(gwLis.Multiaddr().Bytes())] = true
 		listeners = append(listeners, gwLis)
+		listenerCfgs[gwLis] = lcfg
 	}
 
 	// we might have listened to /tcp/0 - let's see what we are listing on
@@ -800,6 +1090,10 @@ func serveHTTPGateway(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, e
 		opts = append(opts, corehttp.RedirectOption("", cfg.Gateway.RootRedirect))
 	}
 
+	if cfg.Petnames.GatewayPrefix != "" {
+		opts = append(opts, corehttp.PetnameOption(cfg.Petnames.GatewayPrefix))
+	}
+
 	if len(cfg.Gateway.PathPrefixes) > 0 {
 		log.Error("Support for X-Ipfs-Gateway-Prefix and Gateway.PathPrefixes is deprecated and will be removed in the next release. Please comment on the issue if you're using this feature: https://github.com/ipfs/go-ipfs/issues/7702")
 	}
@@ -815,7 +1109,7 @@ func serveHTTPGateway(req *cmds.Request, cctx *oldcmds.Context) (<-chan error, e
 		wg.Add(1)
 		go func(lis manet.Listener) {
 			defer wg.Done()
-			errc <- corehttp.Serve(node, manet.NetListener(lis), opts...)
+			errc <- corehttp.ServeWithListenerConfig(node, manet.NetListener(lis), listenerCfgs[lis], opts...)
 		}(lis)
 	}
 
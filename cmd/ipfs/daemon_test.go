@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/ipfs/go-ipfs/config"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+func TestListenHTTPSocketMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api.sock")
+	addr, err := ma.NewComponent("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []os.FileMode{0600, 0660, 0} {
+		lcfg := config.HTTPListener{SocketMode: socketMode(t, mode)}
+
+		lis, err := listenHTTP(addr, lcfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != mode {
+			t.Errorf("expected a socket with mode %o, got %s", mode, fi.Mode())
+		}
+		if !lis.Multiaddr().Equal(addr) {
+			t.Errorf("expected the listener on %s, got %s", addr, lis.Multiaddr())
+		}
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("expected only the socket to be left, got %d files", len(entries))
+		}
+
+		if mode != 0 {
+			go func() {
+				if conn, err := lis.Accept(); err == nil {
+					conn.Close()
+				}
+			}()
+			conn, err := net.Dial("unix", path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+		}
+
+		if err := lis.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected the socket to be removed on close, got %v", err)
+		}
+	}
+
+	if _, err := listenHTTP(addr, config.HTTPListener{SocketMode: socketMode(t, 01777)}); err == nil {
+		t.Fatal("expected an invalid mode to be refused")
+	}
+}
+
+// socketMode returns the SocketMode setting mode.
+func socketMode(t *testing.T, mode os.FileMode) *config.OptionalString {
+	t.Helper()
+	var s config.OptionalString
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`"%04o"`, uint32(mode))), &s); err != nil {
+		t.Fatal(err)
+	}
+	return &s
+}
+
+func TestListenerConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Addresses.Listeners = map[string]config.HTTPListener{
+		"/ip4/127.0.0.1/tcp/0":    {ReadOnly: config.True},
+		"/ip4/127.0.0.1/tcp/5001": {ProxyProtocol: config.True},
+	}
+
+	for _, tc := range []struct {
+		addrs    []string
+		readOnly bool
+		proxy    bool
+	}{
+		{addrs: []string{"/ip4/127.0.0.1/tcp/0"}, readOnly: true},
+		{addrs: []string{"/ip4/127.0.0.1/tcp/0", "/ip4/127.0.0.1/tcp/5001"}, readOnly: true},
+		{addrs: []string{"/ip4/127.0.0.1/tcp/5002", "/ip4/127.0.0.1/tcp/5001"}, proxy: true},
+		{addrs: []string{"/ip4/0.0.0.0/tcp/0", "/ip4/0.0.0.0/tcp/5001"}},
+	} {
+		var addrs []ma.Multiaddr
+		for _, s := range tc.addrs {
+			addrs = append(addrs, ma.StringCast(s))
+		}
+		lcfg, err := listenerConfig(cfg, addrs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lcfg.ReadOnly.WithDefault(false) != tc.readOnly || lcfg.ProxyProtocol.WithDefault(false) != tc.proxy {
+			t.Errorf("%v: expected read-only %t and proxy protocol %t, got %+v", tc.addrs, tc.readOnly, tc.proxy, lcfg)
+		}
+	}
+
+	cfg.Addresses.Listeners["/ip4/nope"] = config.HTTPListener{}
+	if _, err := listenerConfig(cfg, ma.StringCast("/ip4/127.0.0.1/tcp/0")); err == nil {
+		t.Fatal("expected an invalid key to be refused")
+	}
+}
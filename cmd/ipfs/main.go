@@ -10,10 +10,13 @@ import (
 	"net/http"
 	"os"
 	"runtime/pprof"
+	"strings"
 	"time"
 
 	util "github.com/ipfs/go-ipfs/cmd/ipfs/util"
 	oldcmds "github.com/ipfs/go-ipfs/commands"
+	config "github.com/ipfs/go-ipfs/config"
+	serialize "github.com/ipfs/go-ipfs/config/serialize"
 	core "github.com/ipfs/go-ipfs/core"
 	corecmds "github.com/ipfs/go-ipfs/core/commands"
 	corehttp "github.com/ipfs/go-ipfs/core/corehttp"
@@ -131,6 +134,11 @@ func mainRet() (exitCode int) {
 	// so we need to make sure it's stable
 	os.Args[0] = "ipfs"
 
+	// the defaults in the Commands config must be set before the command
+	// line is parsed. The daemon also serves the defaults set when it
+	// started.
+	setCommandDefaults(os.Args[1:])
+
 	buildEnv := func(ctx context.Context, req *cmds.Request) (cmds.Environment, error) {
 		checkDebug(req)
 		repoPath, err := getRepoPath(req)
@@ -315,6 +323,96 @@ func getRepoPath(req *cmds.Request) (string, error) {
 	return repoPath, nil
 }
 
+// setCommandDefaults sets the option defaults in the Commands config of the
+// repo the command line is run with: those of the command run, or all of them
+// for the daemon, which serves every command. The config is not read for the
+// commands not using the repo, and the invalid defaults are reported and left
+// out.
+func setCommandDefaults(args []string) {
+	var repoPath, configFile string
+	var path []string
+	cmd, resolving := Root, true
+	for i := 0; i < len(args) && args[i] != "--"; i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			// the command is named by the first arguments
+			if sub := cmd.Subcommands[args[i]]; resolving && sub != nil {
+				cmd = sub
+				path = append(path, args[i])
+			} else {
+				resolving = false
+			}
+			continue
+		}
+
+		name := strings.TrimLeft(args[i], "-")
+		var value string
+		if j := strings.IndexByte(name, '='); j >= 0 {
+			name, value = name[:j], name[j+1:]
+		} else if optionTakesValue(cmd, name) && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch name {
+		case corecmds.RepoDirOption:
+			repoPath = value
+		case corecmds.ConfigFileOption:
+			configFile = value
+		}
+	}
+
+	if cmd == Root {
+		return
+	}
+	if doesNotUseRepo, ok := corecmds.GetDoesNotUseRepo(cmd.Extra); doesNotUseRepo && ok {
+		return
+	}
+
+	if repoPath == "" {
+		var err error
+		repoPath, err = fsrepo.BestKnownPath()
+		if err != nil {
+			return
+		}
+	}
+	filename, err := config.Filename(repoPath, configFile)
+	if err != nil {
+		return
+	}
+	// a missing or invalid config is reported by the commands using it
+	cfg, err := serialize.Load(filename)
+	if err != nil || len(cfg.Commands) == 0 {
+		return
+	}
+
+	defaults := cfg.Commands
+	if name := strings.Join(path, " "); name != "daemon" {
+		defaults = make(config.Commands)
+		for p, values := range cfg.Commands {
+			if strings.Join(strings.Fields(p), " ") == name {
+				defaults[p] = values
+			}
+		}
+	}
+	for _, err := range corecmds.SetOptionDefaults(Root, defaults) {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring the default in %s: %s\n", filename, err)
+	}
+}
+
+// optionTakesValue returns whether the option name of cmd, or a global one,
+// is followed by its value on the command line.
+func optionTakesValue(cmd *cmds.Command, name string) bool {
+	for _, c := range []*cmds.Command{cmd, Root} {
+		for _, opt := range c.Options {
+			for _, n := range opt.Names() {
+				if n == name {
+					return opt.Type() != cmds.Bool
+				}
+			}
+		}
+	}
+	return false
+}
+
 // startProfiling begins CPU profiling and returns a `stop` function to be
 // executed as late as possible. The stop function captures the memprofile.
 func startProfiling() (func(), error) {
@@ -8,4 +8,52 @@ type Addresses struct {
 	NoAnnounce     []string // swarm addresses not to announce to the network
 	API            Strings  // address for the local API (RPC)
 	Gateway        Strings  // address to listen on for IPFS HTTP object gateway
+
+	// Listeners holds per-listener settings for the API and Gateway
+	// addresses, keyed by the multiaddr as it appears in Addresses.API or
+	// Addresses.Gateway.
+	Listeners map[string]HTTPListener `json:",omitempty"`
+}
+
+// HTTPListener configures the HTTP server behind a single API or Gateway
+// address.
+type HTTPListener struct {
+	// ReadTimeout is the maximum duration for reading an entire request,
+	// including the body.
+	ReadTimeout *OptionalDuration `json:",omitempty"`
+	// ReadHeaderTimeout is the maximum duration for reading request headers.
+	ReadHeaderTimeout *OptionalDuration `json:",omitempty"`
+	// WriteTimeout is the maximum duration before timing out writes of the
+	// response.
+	WriteTimeout *OptionalDuration `json:",omitempty"`
+	// IdleTimeout is the maximum amount of time to wait for the next request
+	// on a keep-alive connection.
+	IdleTimeout *OptionalDuration `json:",omitempty"`
+	// MaxHeaderBytes limits the size of request headers.
+	MaxHeaderBytes *OptionalInteger `json:",omitempty"`
+
+	// ProxyProtocol expects every connection to start with a PROXY protocol
+	// (v1 or v2) header, as sent by L4 load balancers such as HAProxy or
+	// AWS NLB, and uses the client address it carries.
+	ProxyProtocol Flag `json:",omitempty"`
+
+	// TrustedProxies lists the IP addresses and CIDR ranges of reverse
+	// proxies in front of this listener. Only connections from these
+	// addresses may send a PROXY protocol header, and only their
+	// X-Forwarded-For and X-Real-IP headers are used to determine the
+	// client address.
+	TrustedProxies []string `json:",omitempty"`
+
+	// ReadOnly limits an API listener to the commands that inspect the
+	// node without modifying it, for monitoring agents. It has no effect
+	// on Gateway listeners, whose API is always read-only.
+	ReadOnly Flag `json:",omitempty"`
+
+	// SocketMode sets the file permissions of a unix socket listener, in
+	// octal, like "0660".
+	SocketMode *OptionalString `json:",omitempty"`
+
+	// TLSCertFile and TLSKeyFile enable HTTPS on this listener.
+	TLSCertFile *OptionalString `json:",omitempty"`
+	TLSKeyFile  *OptionalString `json:",omitempty"`
 }
@@ -2,4 +2,21 @@ package config
 
 type API struct {
 	HTTPHeaders map[string][]string // HTTP headers to return with the API.
+
+	// RateLimit limits how many RPC requests each client may make.
+	RateLimit APIRateLimit
+}
+
+// APIRateLimit configures a token bucket per API client. Clients are told
+// apart by IP address.
+type APIRateLimit struct {
+	// Enabled turns rate limiting on. Off by default.
+	Enabled Flag `json:",omitempty"`
+
+	// Rate is the number of requests per second each client may sustain.
+	Rate *OptionalInteger `json:",omitempty"`
+
+	// Burst is the number of requests a client may make at once before it
+	// is held to Rate. Defaults to Rate.
+	Burst *OptionalInteger `json:",omitempty"`
 }
@@ -0,0 +1,17 @@
+package config
+
+// Caretaker keeps the content and IPNS names of other publishers resolvable
+// while they are offline: the node periodically republishes their standing
+// IPNS records, and keeps their content pinned and provided.
+type Caretaker struct {
+	// Names are the IPNS names whose records are republished, as peer IDs
+	// or /ipns/ paths. The records are republished as they were signed by
+	// their publisher, until they expire.
+	Names []string `json:",omitempty"`
+
+	// Cids are the CIDs pinned and provided by this node.
+	Cids []string `json:",omitempty"`
+
+	// Interval is the time between two republishes. Default: 4h.
+	Interval *OptionalDuration `json:",omitempty"`
+}
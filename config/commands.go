@@ -0,0 +1,10 @@
+package config
+
+// Commands are the default values of the command options, by command, as
+// typed on the command line ("add", "pin add"), then by option name:
+//
+//	{"add": {"pin": false}, "pin add": {"progress": true}}
+//
+// The options given on the command line, or in an API request, still
+// override these.
+type Commands map[string]map[string]interface{}
@@ -30,14 +30,26 @@ type Config struct {
 	Peering   Peering
 	DNS       DNS
 	Migration Migration
+	Import    Import
+	Journal   Journal
 
 	Provider     Provider
 	Reprovider   Reprovider
 	Experimental Experiments
 	Plugins      Plugins
 	Pinning      Pinning
+	Standby      Standby
+	Caretaker    Caretaker
+	Commands     Commands `json:",omitempty"`
+	Petnames     Petnames
+	Retrieval    Retrieval
+	IPLD         IPLD
 
 	Internal Internal // experimental/unstable options
+
+	// Include lists config fragments, files or /ipfs/ paths, merged under
+	// this config.
+	Include []string `json:",omitempty"`
 }
 
 const (
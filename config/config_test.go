@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -27,3 +28,19 @@ func TestClone(t *testing.T) {
 		t.Fatal("HTTP headers not preserved")
 	}
 }
+
+func TestChunkersByContentType(t *testing.T) {
+	var imp Import
+	if err := json.Unmarshal([]byte(`{"ChunkerByContentType":{"video/*":"size-1048576","text/*":"buzhash"}}`), &imp); err != nil {
+		t.Fatal(err)
+	}
+	if imp.ChunkerByContentType["video/*"] != "size-1048576" || imp.ChunkerByContentType["text/*"] != "buzhash" {
+		t.Fatalf("unexpected chunkers %v", imp.ChunkerByContentType)
+	}
+
+	for _, c := range []string{"size-0", "size-x", "rabin-1-2", "fast"} {
+		if err := json.Unmarshal([]byte(`{"ChunkerByContentType":{"video/*":"`+c+`"}}`), &imp); err == nil {
+			t.Errorf("expected the chunker %q to be refused", c)
+		}
+	}
+}
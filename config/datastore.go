@@ -23,6 +23,26 @@ type Datastore struct {
 
 	HashOnRead      bool
 	BloomFilterSize int
+
+	// GCKeepCodecs lists IPLD codecs whose blocks garbage collection keeps
+	// even when they are not pinned.
+	GCKeepCodecs []string `json:",omitempty"`
+
+	// GCMarkWorkers is the number of pins garbage collection walks at once
+	// when marking the blocks to keep.
+	GCMarkWorkers *OptionalInteger `json:",omitempty"`
+
+	// GCMarkMaxInMemory is the number of marked blocks garbage collection
+	// keeps in memory, past which they are written to the datastore.
+	GCMarkMaxInMemory *OptionalInteger `json:",omitempty"`
+
+	// Quarantine moves the blocks that fail validation when read out of
+	// the blockstore, and fetches them again.
+	Quarantine Flag `json:",omitempty"`
+
+	// ReadOnly refuses the operations changing the blocks, the pins and the
+	// keys of the repo, while the content is still read and served.
+	ReadOnly Flag `json:",omitempty"`
 }
 
 // DataStorePath returns the default data store path given a configuration root
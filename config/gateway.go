@@ -76,4 +76,29 @@ type Gateway struct {
 	// PublicGateways configures behavior of known public gateways.
 	// Each key is a fully qualified domain name (FQDN).
 	PublicGateways map[string]*GatewaySpec
+
+	// Aliases maps content paths that have moved (e.g. "/ipfs/<old-cid>")
+	// to their new location. Requests for a key, or for anything below it,
+	// are permanently redirected to the same place under the new path.
+	Aliases map[string]string `json:",omitempty"`
+
+	// ContentTypeSniffing enables detecting the Content-Type of files from
+	// their first bytes when it can't be told from their extension. When
+	// disabled, such files are served as application/octet-stream.
+	ContentTypeSniffing Flag `json:",omitempty"`
+
+	// NoSniff sets "X-Content-Type-Options: nosniff" on file responses, so
+	// browsers render them only as the Content-Type set by the gateway.
+	NoSniff Flag `json:",omitempty"`
+
+	// ContentSecurityPolicy is the Content-Security-Policy header set on
+	// file responses.
+	ContentSecurityPolicy *OptionalString `json:",omitempty"`
+
+	// ForceDownloadTypes is the list of content types that path gateways
+	// serve as attachments instead of rendering them, e.g. "text/html" or
+	// "image/svg+xml". A type ending with "/*" matches a whole family.
+	// Subdomain and DNSLink gateways give each site its own origin and
+	// are not affected.
+	ForceDownloadTypes []string `json:",omitempty"`
 }
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	chunker "github.com/ipfs/go-ipfs-chunker"
+)
+
+// Import configures how files are imported into the repo.
+type Import struct {
+	// ChunkerByContentType selects the chunker used for a file from its
+	// detected content type, when no chunker was explicitly requested.
+	// Keys are MIME types, like "video/mp4", or "video/*" to match all the
+	// subtypes; values are chunker strings as accepted by 'ipfs add -s'.
+	ChunkerByContentType ChunkersByContentType `json:",omitempty"`
+	// Scanner passes the imported files to an external scanner, which may
+	// reject them.
+	Scanner ContentScanner
+}
+
+// ContentScanner configures the scanning of the imported files.
+type ContentScanner struct {
+	// URL of the HTTP endpoint the content of each file is posted to.
+	// Scanning is disabled when empty.
+	URL string `json:",omitempty"`
+
+	// Timeout is the time the scanner has to give its verdict on a file.
+	Timeout *OptionalDuration `json:",omitempty"`
+
+	// FailOpen accepts the files the scanner fails to scan, rather than
+	// failing their import.
+	FailOpen Flag `json:",omitempty"`
+
+	// Async completes imports without waiting for the verdicts, and only
+	// announces the content to the network once it was accepted.
+	Async Flag `json:",omitempty"`
+}
+
+// ChunkersByContentType maps MIME types to the chunker strings of the files
+// of that type.
+type ChunkersByContentType map[string]string
+
+// UnmarshalJSON refuses the chunker strings 'ipfs add -s' doesn't accept, so
+// they are reported when the config is loaded rather than on the first add.
+func (c *ChunkersByContentType) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	for contentType, s := range m {
+		if _, err := chunker.FromString(bytes.NewReader(nil), s); err != nil {
+			return fmt.Errorf("invalid chunker %q for %q: %w", s, contentType, err)
+		}
+	}
+	*c = m
+	return nil
+}
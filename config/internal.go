@@ -5,6 +5,7 @@ type Internal struct {
 	Bitswap                     *InternalBitswap `json:",omitempty"`
 	UnixFSShardingSizeThreshold *OptionalString  `json:",omitempty"`
 	Libp2pForceReachability     *OptionalString  `json:",omitempty"`
+	DAGWalkWorkers              *OptionalInteger `json:",omitempty"`
 }
 
 type InternalBitswap struct {
@@ -12,4 +13,5 @@ type InternalBitswap struct {
 	EngineBlockstoreWorkerCount OptionalInteger
 	EngineTaskWorkerCount       OptionalInteger
 	MaxOutstandingBytesPerPeer  OptionalInteger
+	PresenceIndexSize           OptionalInteger
 }
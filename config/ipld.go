@@ -0,0 +1,16 @@
+package config
+
+// IPLD limits the size and shape of the DAGs the node decodes and traverses,
+// to protect it from DAGs crafted to exhaust its resources. Nothing is
+// limited by default.
+type IPLD struct {
+	// MaxBlockSize is the size of the largest block decoded, like "2MiB".
+	MaxBlockSize *OptionalString `json:",omitempty"`
+
+	// MaxLinks is the largest number of links of a decoded node.
+	MaxLinks *OptionalInteger `json:",omitempty"`
+
+	// MaxDepth is the largest number of links followed from a root, when
+	// resolving a path or pinning a DAG.
+	MaxDepth *OptionalInteger `json:",omitempty"`
+}
@@ -6,6 +6,10 @@ type Ipns struct {
 
 	ResolveCacheSize int
 
+	// MaxDelegations bounds the names a name is resolved through when it
+	// points at another name.
+	MaxDelegations *OptionalInteger `json:",omitempty"`
+
 	// Enable namesys pubsub (--enable-namesys-pubsub)
 	UsePubsub Flag `json:",omitempty"`
 }
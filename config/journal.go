@@ -0,0 +1,15 @@
+package config
+
+// Journal configures the event journal, kept in the repo datastore.
+type Journal struct {
+	// Enabled turns on recording events (pins, publishes, GC runs and
+	// config changes) in the journal.
+	Enabled Flag `json:",omitempty"`
+
+	// MaxEntries is the number of entries kept in the journal, the oldest
+	// ones are removed first.
+	MaxEntries *OptionalInteger `json:",omitempty"`
+
+	// MaxAge is how long entries are kept in the journal.
+	MaxAge *OptionalDuration `json:",omitempty"`
+}
@@ -0,0 +1,29 @@
+package config
+
+import "strings"
+
+// Petnames are local names for content paths, e.g. "docs" for /ipfs/<cid>,
+// accepted by the commands wherever they take a path.
+type Petnames struct {
+	// Paths maps the petnames to the content paths they stand for.
+	Paths map[string]string `json:",omitempty"`
+
+	// GatewayPrefix is the URL path under which the gateway redirects
+	// requests for a petname to its content path, e.g. "/petname". The
+	// petnames are not served by the gateway when empty.
+	GatewayPrefix string `json:",omitempty"`
+}
+
+// Resolve returns p with its first segment, a petname, replaced by the
+// content path of the petname.
+func (pn Petnames) Resolve(p string) (string, bool) {
+	name, rest := p, ""
+	if i := strings.IndexByte(p, '/'); i >= 0 {
+		name, rest = p[:i], p[i:]
+	}
+	target, ok := pn.Paths[name]
+	if !ok {
+		return "", false
+	}
+	return strings.TrimRight(target, "/") + rest, true
+}
@@ -2,4 +2,18 @@ package config
 
 type Provider struct {
 	Strategy string // Which keys to announce
+
+	// DeferIncomplete stops bitswap from announcing blocks as they arrive.
+	// Pinned roots are announced once their DAG is fully local, everything
+	// else is left to the reprovider.
+	DeferIncomplete Flag `json:",omitempty"`
+
+	// Announcer is a peer, given by ID or by multiaddr ending with
+	// /p2p/<ID>, that announces the provider records of this node in its
+	// place. The node then announces nothing to the DHT itself.
+	Announcer *OptionalString `json:",omitempty"`
+
+	// AnnounceFor lists the IDs of the peers whose provider records this
+	// node announces when they ask it to.
+	AnnounceFor []string `json:",omitempty"`
 }
@@ -11,4 +11,23 @@ type PubsubConfig struct {
 
 	// Enable pubsub (--enable-pubsub-experiment)
 	Enabled Flag `json:",omitempty"`
+
+	// TopicLimits limits the traffic of the topics, by topic name.
+	TopicLimits map[string]PubsubTopicLimits `json:",omitempty"`
+}
+
+// PubsubTopicLimits limits the traffic of a pubsub topic. Unset limits are
+// not enforced.
+type PubsubTopicLimits struct {
+	// MaxMessageRate is the number of messages per second relayed for the
+	// topic.
+	MaxMessageRate *OptionalInteger `json:",omitempty"`
+
+	// MaxMessageSize is the size of the largest message relayed for the
+	// topic, e.g. "64KiB".
+	MaxMessageSize *OptionalString `json:",omitempty"`
+
+	// MaxPeers is the number of peers the topic is exchanged with, which
+	// bounds its mesh degree. Only supported by gossipsub.
+	MaxPeers *OptionalInteger `json:",omitempty"`
 }
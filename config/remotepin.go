@@ -7,6 +7,40 @@ var (
 
 type Pinning struct {
 	RemoteServices map[string]RemotePinningService
+
+	// Gossip replicates the recursive pins of a set of trusted peers.
+	Gossip PinGossip
+
+	// MFS pins the content of MFS paths.
+	MFS PinMFS
+}
+
+// PinMFS configures the pinning of the content written to MFS paths: the
+// content under each path is recursively pinned while it is there, and
+// unpinned a grace period after it was replaced or removed.
+type PinMFS struct {
+	// Paths are the MFS paths whose content is pinned, like "/published".
+	Paths []string `json:",omitempty"`
+
+	// UnpinGracePeriod is how long the content replaced or removed from the
+	// paths stays pinned. Default: 1h.
+	UnpinGracePeriod *OptionalDuration `json:",omitempty"`
+}
+
+// PinGossip configures the exchange of pinsets with trusted peers: each
+// node periodically asks the others for their recursive pins, and fetches
+// and pins the roots it is missing.
+type PinGossip struct {
+	// Peers are the IDs of the trusted peers. Pins are only exchanged when
+	// both peers list each other.
+	Peers []string `json:",omitempty"`
+
+	// Interval is the time between two exchanges with each peer.
+	Interval *OptionalDuration `json:",omitempty"`
+
+	// MaxPinSize is the largest DAG, in bytes, fetched and pinned on behalf
+	// of a peer. Larger DAGs are skipped.
+	MaxPinSize *OptionalString `json:",omitempty"`
 }
 
 type RemotePinningService struct {
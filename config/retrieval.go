@@ -0,0 +1,24 @@
+package config
+
+// Retrieval configures where the blocks missing from the repo are fetched
+// from.
+type Retrieval struct {
+	// Sources are the sources of the blocks, in the order they are tried.
+	// Each source is tried alone for its Timeout before the next one is
+	// tried too. When empty, blocks are fetched with bitswap from the
+	// connected peers, then from the providers found by the router.
+	Sources []RetrievalSource `json:",omitempty"`
+}
+
+// RetrievalSource is a source of blocks.
+type RetrievalSource struct {
+	// Type is "peers" for the connected peers, "routing" for the providers
+	// found by the router, or "gateway" for an HTTP gateway.
+	Type string
+
+	// Timeout is the time the source is tried alone before the next one.
+	Timeout *OptionalDuration `json:",omitempty"`
+
+	// URL is the URL of the gateway, for the "gateway" sources.
+	URL string `json:",omitempty"`
+}
@@ -6,4 +6,58 @@ type Routing struct {
 	//
 	// Can be one of "dht", "dhtclient", "dhtserver", "none", or unset.
 	Type string
+
+	// ProtocolCache remembers the protocols of the peers across restarts.
+	ProtocolCache RoutingProtocolCache
+
+	// HealthCheck probes the routers periodically.
+	HealthCheck RoutingHealthCheck
+
+	// LookupCache remembers the providers found across restarts.
+	LookupCache RoutingLookupCache
+}
+
+// RoutingProtocolCache persists the protocols the peers announced with
+// identify, so that the providers known not to speak bitswap are skipped
+// without being dialed.
+type RoutingProtocolCache struct {
+	// Enabled enables the cache. Default: false.
+	Enabled Flag `json:",omitempty"`
+
+	// MaxAge is how long the protocols of a peer are remembered after it was
+	// last identified. Default: 72h.
+	MaxAge *OptionalDuration `json:",omitempty"`
+}
+
+// RoutingLookupCache persists the providers found for the blocks fetched
+// with bitswap, along with their addresses, so that a restarted node asks
+// them for the blocks while the routing warms up.
+type RoutingLookupCache struct {
+	// Enabled enables the cache. Default: false.
+	Enabled Flag `json:",omitempty"`
+
+	// TTL is how long the providers of a CID are remembered after they were
+	// last found. Default: 24h.
+	TTL *OptionalDuration `json:",omitempty"`
+
+	// MaxEntries is the number of CIDs whose providers are remembered, the
+	// ones found most recently. Default: 1024.
+	MaxEntries *OptionalInteger `json:",omitempty"`
+}
+
+// RoutingHealthCheck configures the probes of the health of the routers.
+type RoutingHealthCheck struct {
+	// Enabled enables the probes. Default: true when ReadyRouters is set.
+	Enabled Flag `json:",omitempty"`
+
+	// Interval is the time between two probes of a healthy router. Unhealthy
+	// routers are probed more often. Default: 5m.
+	Interval *OptionalDuration `json:",omitempty"`
+
+	// Timeout bounds each probe. Default: 1m.
+	Timeout *OptionalDuration `json:",omitempty"`
+
+	// ReadyRouters are the routers that must be healthy before the daemon
+	// reports it is ready. Default: none.
+	ReadyRouters []string `json:",omitempty"`
 }
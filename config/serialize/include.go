@@ -0,0 +1,164 @@
+package fsrepo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/repo/common"
+)
+
+// maxIncludeDepth bounds the nesting of the config fragments including
+// other fragments.
+const maxIncludeDepth = 8
+
+// ipfsIncludeForbidden are the config sections a fragment read from an
+// /ipfs/ path can't set: they're used before the repo is opened.
+var ipfsIncludeForbidden = []string{"Datastore", "Plugins"}
+
+// IncludeReader reads the config fragments included from /ipfs/ paths.
+type IncludeReader func(p string) ([]byte, error)
+
+// ReadConfigMap reads the config from filename as a map, merged with the
+// config fragments it includes. The fragments included from /ipfs/ paths are
+// read with readIPFS, or skipped when it's nil.
+//
+// The fragments listed in Include are merged in order, each one overriding
+// the previous ones, then the including config overrides them all, except
+// for its null values. Objects are merged key by key, other values are
+// replaced. Relative paths are relative to the directory of the including
+// file.
+func ReadConfigMap(filename string, readIPFS IncludeReader) (map[string]interface{}, error) {
+	return readFragment(filename, readIPFS, nil)
+}
+
+// MergeIncludes merges the config fragments included by m, the config of
+// filename, as ReadConfigMap.
+func MergeIncludes(m map[string]interface{}, filename string, readIPFS IncludeReader) (map[string]interface{}, error) {
+	return mergeIncludes(m, filepath.Dir(filename), readIPFS, []string{filename})
+}
+
+// LoadWithIncludes reads the config from filename, merged with the config
+// fragments it includes, as ReadConfigMap.
+func LoadWithIncludes(filename string, readIPFS IncludeReader) (*config.Config, error) {
+	m, err := ReadConfigMap(filename, readIPFS)
+	if err != nil {
+		return nil, err
+	}
+	return config.FromMap(m)
+}
+
+// mergeIncludes merges the fragments included by m, read from dir, under m.
+// including are the fragments being read, to detect the cycles.
+func mergeIncludes(m map[string]interface{}, dir string, readIPFS IncludeReader, including []string) (map[string]interface{}, error) {
+	v, ok := m["Include"]
+	if !ok || v == nil {
+		return m, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.New("config Include must be a list of paths")
+	}
+	if len(list) == 0 {
+		return m, nil
+	}
+	if len(including) > maxIncludeDepth {
+		return nil, fmt.Errorf("config includes nested more than %d deep", maxIncludeDepth)
+	}
+
+	merged := make(map[string]interface{})
+	for _, v := range list {
+		inc, ok := v.(string)
+		if !ok || inc == "" {
+			return nil, errors.New("config Include must be a list of paths")
+		}
+
+		var frag map[string]interface{}
+		var err error
+		if strings.HasPrefix(inc, "/ipfs/") {
+			if readIPFS == nil {
+				continue
+			}
+			frag, err = readIPFSFragment(inc, readIPFS, including)
+		} else {
+			if !filepath.IsAbs(inc) {
+				if dir == "" {
+					return nil, fmt.Errorf("config include %s: a fragment read from IPFS can only include absolute paths", inc)
+				}
+				inc = filepath.Join(dir, inc)
+			}
+			if err = checkIncludeCycle(inc, including); err == nil {
+				frag, err = readFragment(inc, readIPFS, including)
+			}
+			if errors.Is(err, ErrNotInitialized) {
+				err = os.ErrNotExist
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config include %s: %w", inc, err)
+		}
+		merged = common.MapMergeDeep(merged, frag)
+	}
+	return common.MapMergeDeep(merged, withoutNulls(m)), nil
+}
+
+// withoutNulls returns m without its null values, which leave the included
+// values unchanged.
+func withoutNulls(m map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch v := v.(type) {
+		case nil:
+		case map[string]interface{}:
+			res[k] = withoutNulls(v)
+		default:
+			res[k] = v
+		}
+	}
+	return res
+}
+
+func readFragment(filename string, readIPFS IncludeReader, including []string) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := ReadConfigFile(filename, &m); err != nil {
+		return nil, err
+	}
+	return mergeIncludes(m, filepath.Dir(filename), readIPFS, append(including, filename))
+}
+
+func readIPFSFragment(p string, readIPFS IncludeReader, including []string) (map[string]interface{}, error) {
+	if err := checkIncludeCycle(p, including); err != nil {
+		return nil, err
+	}
+	data, err := readIPFS(p)
+	if err != nil {
+		return nil, err
+	}
+	var frag map[string]interface{}
+	if err := json.Unmarshal(data, &frag); err != nil {
+		return nil, fmt.Errorf("failure to decode config: %s", err)
+	}
+	frag, err = mergeIncludes(frag, "", readIPFS, append(including, p))
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range ipfsIncludeForbidden {
+		if _, ok := frag[section]; ok {
+			return nil, fmt.Errorf("%s can't be set by a fragment read from IPFS", section)
+		}
+	}
+	return frag, nil
+}
+
+func checkIncludeCycle(p string, including []string) error {
+	for _, inc := range including {
+		if inc == p {
+			return errors.New("includes itself")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package fsrepo
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFragment(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(p, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "base.json", `{"Bootstrap": ["/base"], "Gateway": {"Writable": true, "RootRedirect": "/base"}}`)
+	writeFragment(t, dir, "region.json", `{"Gateway": {"RootRedirect": "/region"}}`)
+	main := writeFragment(t, dir, "config", `{
+		"Include": ["base.json", "/ipfs/bafkqaaa", "region.json"],
+		"Gateway": {"NoFetch": true}
+	}`)
+
+	cfg, err := Load(main)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Bootstrap) != 1 || cfg.Bootstrap[0] != "/base" || !cfg.Gateway.Writable || !cfg.Gateway.NoFetch {
+		t.Fatalf("expected the fragments to be merged, got %+v, %+v", cfg.Bootstrap, cfg.Gateway)
+	}
+	if cfg.Gateway.RootRedirect != "/region" {
+		t.Fatalf("expected the later fragments to override the earlier ones, got %s", cfg.Gateway.RootRedirect)
+	}
+
+	readIPFS := func(p string) ([]byte, error) {
+		if p != "/ipfs/bafkqaaa" {
+			return nil, errors.New("not found")
+		}
+		return []byte(`{"Bootstrap": ["/ipfs"]}`), nil
+	}
+	cfg, err = LoadWithIncludes(main, readIPFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Bootstrap) != 1 || cfg.Bootstrap[0] != "/ipfs" {
+		t.Fatalf("expected the fragment read from IPFS to be merged, got %v", cfg.Bootstrap)
+	}
+
+	forbidden := func(p string) ([]byte, error) {
+		return []byte(`{"Datastore": {"StorageMax": "1GB"}}`), nil
+	}
+	if _, err := LoadWithIncludes(main, forbidden); err == nil || !strings.Contains(err.Error(), "Datastore can't be set") {
+		t.Fatalf("expected the Datastore to be refused from IPFS, got %v", err)
+	}
+
+	writeFragment(t, dir, "loop.json", `{"Include": ["config"]}`)
+	loop := writeFragment(t, dir, "config", `{"Include": ["loop.json"]}`)
+	if _, err := Load(loop); err == nil || !strings.Contains(err.Error(), "includes itself") {
+		t.Fatalf("expected the cycle to be detected, got %v", err)
+	}
+}
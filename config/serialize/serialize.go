@@ -60,13 +60,18 @@ func encode(w io.Writer, value interface{}) error {
 	return err
 }
 
-// Load reads given file and returns the read config, or error.
+// Load reads given file and returns the read config, or error. The config
+// fragments it includes from files are merged, the ones from /ipfs/ paths
+// are skipped.
 func Load(filename string) (*config.Config, error) {
 	var cfg config.Config
 	err := ReadConfigFile(filename, &cfg)
 	if err != nil {
 		return nil, err
 	}
+	if len(cfg.Include) > 0 {
+		return LoadWithIncludes(filename, nil)
+	}
 
 	return &cfg, err
 }
@@ -0,0 +1,20 @@
+package config
+
+// Standby configures warm standby pairs: a standby node mirrors the pins,
+// MFS root, IPNS records and optionally the keys of its primary, and takes
+// over from it when promoted with 'ipfs standby promote'.
+type Standby struct {
+	// Primary is the peer ID of the node this node is the standby of.
+	Primary string `json:",omitempty"`
+
+	// Interval is the time between two syncs with the primary.
+	Interval *OptionalDuration `json:",omitempty"`
+
+	// Standbys are the peer IDs of the standbys of this node, the only
+	// peers allowed to mirror it.
+	Standbys []string `json:",omitempty"`
+
+	// Keys sends the private keys of this node to its standbys, so they
+	// can publish its IPNS names once promoted.
+	Keys Flag `json:",omitempty"`
+}
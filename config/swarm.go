@@ -54,6 +54,53 @@ type SwarmConfig struct {
 
 	// ResourceMgr configures the libp2p Network Resource Manager
 	ResourceMgr ResourceMgr
+
+	// Services restricts which peers may use the libp2p services this node
+	// exposes.
+	Services SwarmServices
+
+	// PrivateNetwork restricts the peers listed in Bootstrap and
+	// Peering.Peers when the node is part of a private network (a swarm.key
+	// is present in the repo).
+	PrivateNetwork PrivateNetwork
+
+	// InboundAllowlist restricts the peers this node accepts inbound
+	// connections from.
+	InboundAllowlist InboundAllowlist
+}
+
+// InboundAllowlist configures the peers inbound connections are accepted
+// from, when enabled: the peers listed, and the peers presenting a
+// certificate signed by one of the certificate authorities.
+type InboundAllowlist struct {
+	Enabled Flag `json:",omitempty"`
+
+	// Peers lists the peer IDs inbound connections are accepted from.
+	Peers []string `json:",omitempty"`
+
+	// CertificateAuthorities lists the peer IDs of the keys whose
+	// certificates admit a peer, issued with `ipfs swarm allowlist certify`.
+	CertificateAuthorities []string `json:",omitempty"`
+
+	// ProbationTimeout is how long a peer that is not listed has to present
+	// its certificate before it is disconnected.
+	ProbationTimeout *OptionalDuration `json:",omitempty"`
+
+	// Certificate is the certificate of this node, presented to the peers
+	// it connects to. It is presented even when the allowlist is disabled.
+	Certificate *OptionalString `json:",omitempty"`
+}
+
+// PrivateNetwork configures the checks of the bootstrap and peering peers of
+// a node in a private network.
+type PrivateNetwork struct {
+	// AllowedPeers lists the peer IDs Bootstrap and Peering.Peers may refer
+	// to. When empty, any peer but the public bootstrap peers is allowed.
+	AllowedPeers []string `json:",omitempty"`
+
+	// AllowPublicBootstrap allows Bootstrap and Peering.Peers to refer to
+	// the public IPFS bootstrap peers.
+	AllowPublicBootstrap Flag `json:",omitempty"`
 }
 
 type RelayClient struct {
@@ -127,6 +174,34 @@ type Transports struct {
 	}
 }
 
+// SwarmServices configures who may open inbound streams to the built-in
+// libp2p services. Each field takes one of the ServiceExposure* values and
+// defaults to ServiceExposureAll.
+type SwarmServices struct {
+	// Ping is the libp2p ping protocol.
+	Ping *OptionalString `json:",omitempty"`
+	// IdentifyPush covers the identify push and delta protocols used by peers
+	// to announce changes to their addresses and protocols.
+	IdentifyPush *OptionalString `json:",omitempty"`
+	// AutoNAT is the AutoNAT dial-back service.
+	AutoNAT *OptionalString `json:",omitempty"`
+	// Relay is the circuit v2 relay service (hop protocol).
+	Relay *OptionalString `json:",omitempty"`
+}
+
+const (
+	// ServiceExposureAll lets any peer use the service.
+	ServiceExposureAll = "all"
+	// ServiceExposurePrivate only lets peers connected over private or
+	// loopback addresses use the service.
+	ServiceExposurePrivate = "private"
+	// ServiceExposurePublic only lets peers connected over public addresses
+	// use the service.
+	ServiceExposurePublic = "public"
+	// ServiceExposureDisabled rejects every inbound stream for the service.
+	ServiceExposureDisabled = "disabled"
+)
+
 // ConnMgr defines configuration options for the libp2p connection manager
 type ConnMgr struct {
 	Type        string
@@ -141,6 +216,19 @@ type ResourceMgr struct {
 	// Enables the Network Resource Manager feature
 	Enabled Flag                      `json:",omitempty"`
 	Limits  *rcmgr.BasicLimiterConfig `json:",omitempty"`
+
+	// ServiceAlert warns about services that stay close to their limits.
+	ServiceAlert ServiceAlert
+}
+
+// ServiceAlert configures the sampling of the usage of the service scopes of
+// the resource manager, and the alert raised when a service stays above
+// Threshold percent of one of its limits for longer than Duration.
+type ServiceAlert struct {
+	Enabled   Flag              `json:",omitempty"`
+	Threshold *OptionalInteger  `json:",omitempty"`
+	Duration  *OptionalDuration `json:",omitempty"`
+	Interval  *OptionalDuration `json:",omitempty"`
 }
 
 const (
@@ -218,6 +218,11 @@ type OptionalDuration struct {
 	value *time.Duration
 }
 
+// NewOptionalDuration returns an OptionalDuration set to d.
+func NewOptionalDuration(d time.Duration) *OptionalDuration {
+	return &OptionalDuration{value: &d}
+}
+
 func (d *OptionalDuration) UnmarshalJSON(input []byte) error {
 	switch string(input) {
 	case "null", "undefined", "\"null\"", "", "default", "\"\"", "\"default\"":
@@ -269,6 +274,11 @@ type OptionalInteger struct {
 	value *int64
 }
 
+// NewOptionalInteger returns an OptionalInteger set to v.
+func NewOptionalInteger(v int64) *OptionalInteger {
+	return &OptionalInteger{value: &v}
+}
+
 // WithDefault resolves the integer with the given default.
 func (p *OptionalInteger) WithDefault(defaultValue int64) (value int64) {
 	if p == nil || p.value == nil {
@@ -321,6 +331,11 @@ type OptionalString struct {
 	value *string
 }
 
+// NewOptionalString returns an OptionalString set to s.
+func NewOptionalString(s string) *OptionalString {
+	return &OptionalString{value: &s}
+}
+
 // WithDefault resolves the integer with the given default.
 func (p *OptionalString) WithDefault(defaultValue string) (value string) {
 	if p == nil || p.value == nil {
@@ -0,0 +1,286 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	version "github.com/ipfs/go-ipfs"
+	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
+
+	humanize "github.com/dustin/go-humanize"
+	cid "github.com/ipfs/go-cid"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	files "github.com/ipfs/go-ipfs-files"
+	coreiface "github.com/ipfs/interface-go-ipfs-core"
+	options "github.com/ipfs/interface-go-ipfs-core/options"
+	path "github.com/ipfs/interface-go-ipfs-core/path"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	pstore "github.com/libp2p/go-libp2p-core/peerstore"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// BenchmarkThroughput is the result of a benchmark moving data around.
+type BenchmarkThroughput struct {
+	Bytes          uint64
+	Duration       time.Duration
+	BytesPerSecond float64
+}
+
+// BenchmarkLatency is the result of a benchmark timing a set of lookups.
+type BenchmarkLatency struct {
+	Lookups int
+	Failed  int
+	Min     time.Duration
+	Mean    time.Duration
+	Max     time.Duration
+}
+
+// BenchmarkReport is the output of 'ipfs benchmark'. Benchmarks that were
+// not run are left out.
+type BenchmarkReport struct {
+	Time    time.Time
+	Version string
+	Add     *BenchmarkThroughput `json:",omitempty"`
+	Read    *BenchmarkThroughput `json:",omitempty"`
+	Fetch   *BenchmarkThroughput `json:",omitempty"`
+	DHT     *BenchmarkLatency    `json:",omitempty"`
+}
+
+const (
+	benchmarkSizeOptionName       = "size"
+	benchmarkPeerOptionName       = "peer"
+	benchmarkCidOptionName        = "cid"
+	benchmarkDhtLookupsOptionName = "dht-lookups"
+)
+
+const (
+	benchmarkDhtLookupTimeout = time.Minute
+
+	// benchmarkMaxSize bounds the data added, streamed to the node.
+	benchmarkMaxSize = 1 << 30
+)
+
+type benchmarkOptions struct {
+	size      uint64
+	lookups   int
+	peer, cid string
+}
+
+func parseBenchmarkOptions(opts cmds.OptMap) (*benchmarkOptions, error) {
+	sizeStr, _ := opts[benchmarkSizeOptionName].(string)
+	size, err := humanize.ParseBytes(sizeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size: %w", err)
+	}
+	if size == 0 {
+		return nil, errors.New("size must be positive")
+	}
+	if size > benchmarkMaxSize {
+		return nil, fmt.Errorf("size must be at most %s", humanize.IBytes(benchmarkMaxSize))
+	}
+	lookups, _ := opts[benchmarkDhtLookupsOptionName].(int)
+	if lookups < 0 {
+		return nil, fmt.Errorf("DHT lookups must be positive, was %d", lookups)
+	}
+	peerStr, _ := opts[benchmarkPeerOptionName].(string)
+	cidStr, _ := opts[benchmarkCidOptionName].(string)
+	if (peerStr == "") != (cidStr == "") {
+		return nil, errors.New("--peer and --cid must be used together")
+	}
+	return &benchmarkOptions{size: size, lookups: lookups, peer: peerStr, cid: cidStr}, nil
+}
+
+var BenchmarkCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Measure the performance of the node.",
+		ShortDescription: `
+'ipfs benchmark' measures the throughput of adding data to the repo and of
+reading it back, and, when online, the latency of DHT lookups. Use
+--enc=json to get a report that can be compared across configurations.
+`,
+		LongDescription: `
+'ipfs benchmark' measures the throughput of adding data to the repo and of
+reading it back, and, when online, the latency of DHT lookups. Use
+--enc=json to get a report that can be compared across configurations.
+
+The add benchmark imports --size bytes of random data, up to 1GiB, with the
+default settings of 'ipfs add', and the read benchmark reads it back. The data is not
+pinned and is removed by the next garbage collection.
+
+The DHT benchmark looks up the peers closest to --dht-lookups random keys
+on the public DHT. Lookups that fail or find no peers are counted as failed.
+
+To measure fetching data over bitswap, pass the CID of a file that --peer
+has and this node does not. The node connects to the peer before fetching
+the file, but blocks may also come from other connected peers that have
+them. Fetched blocks are kept until the next garbage collection, which has to
+run before fetching the same file again.
+`,
+	},
+	Options: []cmds.Option{
+		cmds.StringOption(benchmarkSizeOptionName, "s", "Size of the data added and read.").WithDefault("16MiB"),
+		cmds.StringOption(benchmarkPeerOptionName, "Peer ID or multiaddr of the peer to fetch --cid from."),
+		cmds.StringOption(benchmarkCidOptionName, "CID of a file to fetch from --peer."),
+		cmds.IntOption(benchmarkDhtLookupsOptionName, "Number of DHT lookups, 0 to skip the DHT benchmark.").WithDefault(3),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		api, err := cmdenv.GetApi(env, req)
+		if err != nil {
+			return err
+		}
+
+		opts, err := parseBenchmarkOptions(req.Options)
+		if err != nil {
+			return err
+		}
+		size, lookups, peerStr, cidStr := opts.size, opts.lookups, opts.peer, opts.cid
+
+		report := &BenchmarkReport{
+			Time:    time.Now(),
+			Version: version.CurrentVersionNumber,
+		}
+
+		// add
+		data := files.NewReaderFile(io.LimitReader(rand.Reader, int64(size)))
+		start := time.Now()
+		added, err := api.Unixfs().Add(req.Context, data, options.Unixfs.Pin(false))
+		if err != nil {
+			return fmt.Errorf("add benchmark: %w", err)
+		}
+		report.Add = newBenchmarkThroughput(size, time.Since(start))
+
+		// read
+		start = time.Now()
+		read, err := benchmarkRead(req.Context, api.Unixfs(), added)
+		if err != nil {
+			return fmt.Errorf("read benchmark: %w", err)
+		}
+		report.Read = newBenchmarkThroughput(read, time.Since(start))
+
+		// fetch
+		if peerStr != "" {
+			if !n.IsOnline {
+				return ErrNotOnline
+			}
+			addr, pid, err := ParsePeerParam(peerStr)
+			if err != nil {
+				return fmt.Errorf("failed to parse peer address '%s': %s", peerStr, err)
+			}
+			root, err := cid.Decode(cidStr)
+			if err != nil {
+				return fmt.Errorf("invalid CID: %w", err)
+			}
+			if has, err := n.Blockstore.Has(req.Context, root); err != nil {
+				return err
+			} else if has {
+				return fmt.Errorf("%s is already in the local blockstore, run 'ipfs repo gc' before fetching it again", root)
+			}
+
+			if addr != nil {
+				n.Peerstore.AddAddr(pid, addr, pstore.TempAddrTTL)
+			}
+			if err := api.Swarm().Connect(req.Context, peer.AddrInfo{ID: pid}); err != nil {
+				return fmt.Errorf("fetch benchmark: %w", err)
+			}
+
+			start = time.Now()
+			fetched, err := benchmarkRead(req.Context, api.Unixfs(), path.IpfsPath(root))
+			if err != nil {
+				return fmt.Errorf("fetch benchmark: %w", err)
+			}
+			report.Fetch = newBenchmarkThroughput(fetched, time.Since(start))
+		}
+
+		// DHT
+		if lookups > 0 && n.IsOnline && n.DHT != nil {
+			lat := &BenchmarkLatency{Lookups: lookups}
+			var total time.Duration
+			for i := 0; i < lookups; i++ {
+				key := make([]byte, 32)
+				if _, err := rand.Read(key); err != nil {
+					return err
+				}
+				target, err := mh.Sum(key, mh.SHA2_256, -1)
+				if err != nil {
+					return err
+				}
+
+				ctx, cancel := context.WithTimeout(req.Context, benchmarkDhtLookupTimeout)
+				start = time.Now()
+				closest, err := n.DHT.WAN.GetClosestPeers(ctx, string(target))
+				took := time.Since(start)
+				cancel()
+				if req.Context.Err() != nil {
+					return req.Context.Err()
+				}
+				if err != nil || len(closest) == 0 {
+					lat.Failed++
+					continue
+				}
+
+				total += took
+				if lat.Min == 0 || took < lat.Min {
+					lat.Min = took
+				}
+				if took > lat.Max {
+					lat.Max = took
+				}
+			}
+			if ok := lookups - lat.Failed; ok > 0 {
+				lat.Mean = total / time.Duration(ok)
+			}
+			report.DHT = lat
+		}
+
+		return cmds.EmitOnce(res, report)
+	},
+	Type: BenchmarkReport{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *BenchmarkReport) error {
+			printThroughput := func(name string, t *BenchmarkThroughput) {
+				if t != nil {
+					fmt.Fprintf(w, "%-6s %s in %s (%s/s)\n", name, humanize.IBytes(t.Bytes), t.Duration.Round(time.Millisecond), humanize.IBytes(uint64(t.BytesPerSecond)))
+				}
+			}
+			printThroughput("add", out.Add)
+			printThroughput("read", out.Read)
+			printThroughput("fetch", out.Fetch)
+			if d := out.DHT; d != nil {
+				fmt.Fprintf(w, "%-6s %d lookups, %d failed, min %s, mean %s, max %s\n", "dht", d.Lookups, d.Failed, d.Min.Round(time.Millisecond), d.Mean.Round(time.Millisecond), d.Max.Round(time.Millisecond))
+			}
+			return nil
+		}),
+	},
+}
+
+func newBenchmarkThroughput(n uint64, d time.Duration) *BenchmarkThroughput {
+	t := &BenchmarkThroughput{Bytes: n, Duration: d}
+	if d > 0 {
+		t.BytesPerSecond = float64(n) / d.Seconds()
+	}
+	return t
+}
+
+// benchmarkRead reads the whole file at p, and returns its size.
+func benchmarkRead(ctx context.Context, api coreiface.UnixfsAPI, p path.Path) (uint64, error) {
+	nd, err := api.Get(ctx, p)
+	if err != nil {
+		return 0, err
+	}
+	defer nd.Close()
+
+	f, ok := nd.(files.File)
+	if !ok {
+		return 0, fmt.Errorf("%s is not a file", p)
+	}
+	read, err := io.Copy(io.Discard, f)
+	return uint64(read), err
+}
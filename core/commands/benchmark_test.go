@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+func TestParseBenchmarkOptions(t *testing.T) {
+	opts, err := parseBenchmarkOptions(cmds.OptMap{benchmarkSizeOptionName: "1MiB", benchmarkDhtLookupsOptionName: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.size != 1<<20 || opts.lookups != 2 {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+
+	for _, c := range []struct {
+		name string
+		opts cmds.OptMap
+	}{
+		{"an invalid size", cmds.OptMap{benchmarkSizeOptionName: "lots"}},
+		{"a size of 0", cmds.OptMap{benchmarkSizeOptionName: "0"}},
+		{"a size above the cap", cmds.OptMap{benchmarkSizeOptionName: "2GiB"}},
+		{"negative DHT lookups", cmds.OptMap{benchmarkSizeOptionName: "1MiB", benchmarkDhtLookupsOptionName: -1}},
+		{"--peer without --cid", cmds.OptMap{benchmarkSizeOptionName: "1MiB", benchmarkPeerOptionName: "12D3KooWFhHST3YBVWMrRZ2ANhQUCbKJg9a1MwDYSWbHV85baUMv"}},
+	} {
+		if _, err := parseBenchmarkOptions(c.opts); err == nil {
+			t.Errorf("expected %s to be refused", c.name)
+		}
+	}
+}
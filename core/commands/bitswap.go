@@ -12,9 +12,18 @@ import (
 	decision "github.com/ipfs/go-bitswap/decision"
 	cidutil "github.com/ipfs/go-cidutil"
 	cmds "github.com/ipfs/go-ipfs-cmds"
+	exchange "github.com/ipfs/go-ipfs-exchange-interface"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 )
 
+// unwrapExchange returns the exchange behind the retrieval policy, if any.
+func unwrapExchange(exch exchange.Interface) exchange.Interface {
+	if w, ok := exch.(interface{ Unwrap() exchange.Interface }); ok {
+		return w.Unwrap()
+	}
+	return exch
+}
+
 var BitswapCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
 		Tagline:          "Interact with the bitswap agent.",
@@ -53,7 +62,7 @@ Print out all blocks currently on the bitswap wantlist for the local peer.`,
 			return ErrNotOnline
 		}
 
-		bs, ok := nd.Exchange.(*bitswap.Bitswap)
+		bs, ok := unwrapExchange(nd.Exchange).(*bitswap.Bitswap)
 		if !ok {
 			return e.TypeErr(bs, nd.Exchange)
 		}
@@ -112,7 +121,7 @@ var bitswapStatCmd = &cmds.Command{
 			return cmds.Errorf(cmds.ErrClient, ErrNotOnline.Error())
 		}
 
-		bs, ok := nd.Exchange.(*bitswap.Bitswap)
+		bs, ok := unwrapExchange(nd.Exchange).(*bitswap.Bitswap)
 		if !ok {
 			return e.TypeErr(bs, nd.Exchange)
 		}
@@ -190,7 +199,7 @@ prints the ledger associated with a given peer.
 			return ErrNotOnline
 		}
 
-		bs, ok := nd.Exchange.(*bitswap.Bitswap)
+		bs, ok := unwrapExchange(nd.Exchange).(*bitswap.Bitswap)
 		if !ok {
 			return e.TypeErr(bs, nd.Exchange)
 		}
@@ -28,6 +28,7 @@ func TestROCommands(t *testing.T) {
 		"/dag/get",
 		"/dag/resolve",
 		"/dag/stat",
+		"/dag/walk",
 		"/dag/export",
 		"/dns",
 		"/get",
@@ -70,9 +71,80 @@ func TestROCommands(t *testing.T) {
 		}
 	}
 }
+
+func TestInspectCommands(t *testing.T) {
+	list := []string{
+		"/bitswap",
+		"/bitswap/ledger",
+		"/bitswap/stat",
+		"/bitswap/wantlist",
+		"/block",
+		"/block/get",
+		"/block/stat",
+		"/cat",
+		"/commands",
+		"/commands/completion",
+		"/commands/completion/bash",
+		"/dag",
+		"/dag/export",
+		"/dag/get",
+		"/dag/resolve",
+		"/dag/stat",
+		"/dag/walk",
+		"/dns",
+		"/get",
+		"/id",
+		"/ls",
+		"/name",
+		"/name/resolve",
+		"/object",
+		"/object/data",
+		"/object/get",
+		"/object/links",
+		"/object/stat",
+		"/pin",
+		"/pin/ls",
+		"/refs",
+		"/repo",
+		"/repo/stat",
+		"/repo/version",
+		"/resolve",
+		"/stats",
+		"/stats/bitswap",
+		"/stats/bw",
+		"/stats/dht",
+		"/stats/provide",
+		"/stats/repo",
+		"/swarm",
+		"/swarm/addrs",
+		"/swarm/addrs/listen",
+		"/swarm/addrs/local",
+		"/swarm/peers",
+		"/swarm/stats",
+		"/version",
+		"/version/deps",
+	}
+
+	cmdSet := make(map[string]struct{})
+	collectPaths("", RootInspect, cmdSet)
+
+	for _, path := range list {
+		if _, ok := cmdSet[path]; !ok {
+			t.Errorf("%q not in result", path)
+		} else {
+			delete(cmdSet, path)
+		}
+	}
+
+	for path := range cmdSet {
+		t.Errorf("%q in result but shouldn't be", path)
+	}
+}
+
 func TestCommands(t *testing.T) {
 	list := []string{
 		"/add",
+		"/benchmark",
 		"/bitswap",
 		"/bitswap/ledger",
 		"/bitswap/reprovide",
@@ -112,6 +184,7 @@ func TestCommands(t *testing.T) {
 		"/dag/put",
 		"/dag/resolve",
 		"/dag/stat",
+		"/dag/walk",
 		"/dht",
 		"/dht/findpeer",
 		"/dht/findprovs",
@@ -123,7 +196,11 @@ func TestCommands(t *testing.T) {
 		"/diag/cmds",
 		"/diag/cmds/clear",
 		"/diag/cmds/set-time",
+		"/diag/content",
+		"/diag/peer",
 		"/diag/profile",
+		"/diag/rcmgr",
+		"/diag/rcmgr/dump",
 		"/diag/sys",
 		"/dns",
 		"/file",
@@ -143,8 +220,15 @@ func TestCommands(t *testing.T) {
 		"/filestore/dups",
 		"/filestore/ls",
 		"/filestore/verify",
+		"/gateway",
+		"/gateway/alias",
+		"/gateway/alias/add",
+		"/gateway/alias/ls",
+		"/gateway/alias/rm",
 		"/get",
 		"/id",
+		"/journal",
+		"/journal/ls",
 		"/key",
 		"/key/export",
 		"/key/gen",
@@ -165,6 +249,7 @@ func TestCommands(t *testing.T) {
 		"/multibase/transcode",
 		"/multibase/list",
 		"/name",
+		"/name/inspect",
 		"/name/publish",
 		"/name/pubsub",
 		"/name/pubsub/cancel",
@@ -192,6 +277,10 @@ func TestCommands(t *testing.T) {
 		"/p2p/stream",
 		"/p2p/stream/close",
 		"/p2p/stream/ls",
+		"/petname",
+		"/petname/ls",
+		"/petname/rm",
+		"/petname/set",
 		"/pin",
 		"/pin/add",
 		"/pin/ls",
@@ -217,11 +306,21 @@ func TestCommands(t *testing.T) {
 		"/repo",
 		"/repo/fsck",
 		"/repo/gc",
+		"/repo/quarantine",
+		"/repo/quarantine/ls",
+		"/repo/quarantine/rm",
 		"/repo/stat",
 		"/repo/verify",
 		"/repo/version",
 		"/resolve",
+		"/routing",
+		"/routing/findprovs",
+		"/routing/stat",
+		"/routing/trace",
 		"/shutdown",
+		"/standby",
+		"/standby/promote",
+		"/standby/status",
 		"/stats",
 		"/stats/bitswap",
 		"/stats/bw",
@@ -232,6 +331,8 @@ func TestCommands(t *testing.T) {
 		"/swarm/addrs",
 		"/swarm/addrs/listen",
 		"/swarm/addrs/local",
+		"/swarm/allowlist",
+		"/swarm/allowlist/certify",
 		"/swarm/connect",
 		"/swarm/disconnect",
 		"/swarm/filters",
@@ -239,10 +340,12 @@ func TestCommands(t *testing.T) {
 		"/swarm/filters/rm",
 		"/swarm/limit",
 		"/swarm/peers",
+		"/swarm/pause",
 		"/swarm/peering",
 		"/swarm/peering/add",
 		"/swarm/peering/ls",
 		"/swarm/peering/rm",
+		"/swarm/resume",
 		"/swarm/stats",
 		"/tar",
 		"/tar/add",
@@ -513,6 +513,15 @@ func replaceConfig(r repo.Repo, file io.Reader) error {
 		return errors.New("failed to decode file as config")
 	}
 
+	// the fields missing from the file would override the included ones
+	oldCfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	if len(oldCfg.Include) > 0 || len(newCfg.Include) > 0 {
+		return errors.New("can't replace a config including config fragments, edit the config file instead")
+	}
+
 	// Handle Identity.PrivKey (secret)
 
 	if len(newCfg.Identity.PrivKey) != 0 {
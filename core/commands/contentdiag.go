@@ -0,0 +1,318 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+
+	bitswap "github.com/ipfs/go-bitswap"
+	bsnet "github.com/ipfs/go-bitswap/network"
+	cid "github.com/ipfs/go-cid"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	contentDiagGatewayOptionName      = "gateway"
+	contentDiagSkipGatewaysOptionName = "skip-gateways"
+	contentDiagNumProvidersOptionName = "num-providers"
+	contentDiagProbeTimeoutOptionName = "probe-timeout"
+)
+
+var defaultContentDiagGateways = []string{"https://ipfs.io", "https://dweb.link"}
+
+// ContentDiagReport is the output of 'ipfs diag content'.
+type ContentDiagReport struct {
+	Cid string
+	// Local is whether the block is in the local blockstore.
+	Local     bool
+	Routing   []ContentDiagRouter
+	Providers []ContentDiagProvider
+	// Retrieved is whether the block was retrieved with bitswap. It is not
+	// tried when the block is local, bitswap would not ask the providers.
+	Retrieved     bool
+	RetrieveError string `json:",omitempty"`
+	Gateways      []ContentDiagGateway
+}
+
+// ContentDiagRouter is the result of a router looking for the providers.
+type ContentDiagRouter struct {
+	Router    string
+	Providers int
+	Duration  time.Duration
+	Error     string `json:",omitempty"`
+}
+
+// ContentDiagProvider is the result of the checks of a provider.
+type ContentDiagProvider struct {
+	ID        peer.ID
+	Connected bool
+	// Bitswap is whether the provider speaks bitswap.
+	Bitswap bool
+	// Sent is whether the provider sent the block when it was retrieved.
+	Sent  bool
+	Error string `json:",omitempty"`
+}
+
+// ContentDiagGateway is the result of the check of a gateway.
+type ContentDiagGateway struct {
+	URL    string
+	Cached bool
+	Status int    `json:",omitempty"`
+	Error  string `json:",omitempty"`
+}
+
+var contentDiagCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Check whether content is findable and retrievable by others.",
+		ShortDescription: `
+'ipfs diag content' reports how healthy the publication of a CID is:
+
+  - whether the router of the node finds providers for it,
+  - whether the providers found can be dialed and speak bitswap,
+  - whether the block is retrieved with bitswap, and which of the providers
+    sent it,
+  - whether public gateways have a copy of it in their cache.
+
+Only the root block of the CID is checked. When the block is in the local
+blockstore, it is not retrieved with bitswap, which would not ask the
+providers. Otherwise the one retrieved is stored, like with 'ipfs block get'.
+The node itself is never counted as a provider.
+
+The gateways are asked with a 'Cache-Control: only-if-cached' HEAD request,
+which doesn't make them fetch the content. Gateways not supporting it report
+their response status. Use --gateway to check other gateways, and
+--skip-gateways not to check any.
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("cid", true, false, "CID of the content to check."),
+	},
+	Options: []cmds.Option{
+		cmds.StringsOption(contentDiagGatewayOptionName, "URL of a gateway to check, can be repeated.").WithDefault(defaultContentDiagGateways),
+		cmds.BoolOption(contentDiagSkipGatewaysOptionName, "Do not check the gateways."),
+		cmds.IntOption(contentDiagNumProvidersOptionName, "Number of providers to check.").WithDefault(5),
+		cmds.StringOption(contentDiagProbeTimeoutOptionName, "Timeout of each check.").WithDefault("30s"),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+
+		if !n.IsOnline {
+			return ErrNotOnline
+		}
+
+		c, err := cid.Decode(req.Arguments[0])
+		if err != nil {
+			return fmt.Errorf("invalid CID: %w", err)
+		}
+
+		timeoutStr, _ := req.Options[contentDiagProbeTimeoutOptionName].(string)
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return err
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("probe timeout must be positive, was %s", timeout)
+		}
+		numProviders, _ := req.Options[contentDiagNumProvidersOptionName].(int)
+		if numProviders <= 0 {
+			return fmt.Errorf("number of providers must be positive, was %d", numProviders)
+		}
+		var gateways []string
+		if skip, _ := req.Options[contentDiagSkipGatewaysOptionName].(bool); !skip {
+			gateways, _ = req.Options[contentDiagGatewayOptionName].([]string)
+			for _, gw := range gateways {
+				if u, err := url.Parse(gw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("invalid gateway URL %q", gw)
+				}
+			}
+		}
+
+		cfg, err := n.Repo.Config()
+		if err != nil {
+			return err
+		}
+		routerName := cfg.Routing.Type
+		if routerName == "" {
+			routerName = "dht"
+		}
+
+		report := &ContentDiagReport{Cid: c.String()}
+		report.Local, err = n.Blockstore.Has(req.Context, c)
+		if err != nil {
+			return err
+		}
+
+		// routing
+		start := time.Now()
+		findCtx, cancel := context.WithTimeout(req.Context, timeout)
+		var providers []peer.AddrInfo
+		found := 0
+		for ai := range n.Routing.FindProvidersAsync(findCtx, c, numProviders+1) {
+			found++
+			if ai.ID != n.Identity && len(providers) < numProviders {
+				providers = append(providers, ai)
+			}
+		}
+		cancel()
+		router := ContentDiagRouter{Router: routerName, Providers: found, Duration: time.Since(start)}
+		if found == 0 && findCtx.Err() == context.DeadlineExceeded {
+			router.Error = "no provider found before the timeout"
+		}
+		report.Routing = append(report.Routing, router)
+
+		// providers
+		for _, ai := range providers {
+			p := ContentDiagProvider{ID: ai.ID}
+			connCtx, cancel := context.WithTimeout(req.Context, timeout)
+			err := n.PeerHost.Connect(connCtx, ai)
+			cancel()
+			if err != nil {
+				p.Error = err.Error()
+			} else {
+				p.Connected = true
+				supported, err := n.Peerstore.SupportsProtocols(ai.ID,
+					string(bsnet.ProtocolBitswap), string(bsnet.ProtocolBitswapOneOne),
+					string(bsnet.ProtocolBitswapOneZero), string(bsnet.ProtocolBitswapNoVers))
+				p.Bitswap = err == nil && len(supported) > 0
+			}
+			report.Providers = append(report.Providers, p)
+		}
+
+		// bitswap, which would return the local block without asking the
+		// providers
+		bs, ok := unwrapExchange(n.Exchange).(*bitswap.Bitswap)
+		switch {
+		case report.Local:
+		case !ok:
+			report.RetrieveError = "the exchange is not bitswap"
+		default:
+			recv := make([]uint64, len(report.Providers))
+			for i, p := range report.Providers {
+				recv[i] = bs.LedgerForPeer(p.ID).Recv
+			}
+			getCtx, cancel := context.WithTimeout(req.Context, timeout)
+			_, err := bs.GetBlock(getCtx, c)
+			cancel()
+			if err != nil {
+				report.RetrieveError = err.Error()
+			} else {
+				report.Retrieved = true
+			}
+			for i := range report.Providers {
+				report.Providers[i].Sent = bs.LedgerForPeer(report.Providers[i].ID).Recv > recv[i]
+			}
+		}
+
+		// gateways
+		client := &http.Client{Timeout: timeout}
+		for _, gw := range gateways {
+			report.Gateways = append(report.Gateways, checkGatewayCache(req.Context, client, gw, c))
+		}
+
+		if err := req.Context.Err(); err != nil {
+			return err
+		}
+		return cmds.EmitOnce(res, report)
+	},
+	Type: ContentDiagReport{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, r *ContentDiagReport) error {
+			fmt.Fprintf(w, "CID %s\n", r.Cid)
+			fmt.Fprintf(w, "Local: %t\n", r.Local)
+			fmt.Fprintln(w, "Routing:")
+			for _, rt := range r.Routing {
+				fmt.Fprintf(w, "  %s: %d providers (%s)", rt.Router, rt.Providers, rt.Duration.Round(time.Millisecond))
+				if rt.Error != "" {
+					fmt.Fprintf(w, ", %s", rt.Error)
+				}
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, "Providers:")
+			if len(r.Providers) == 0 {
+				fmt.Fprintln(w, "  none")
+			}
+			for _, p := range r.Providers {
+				var status []string
+				if !p.Connected {
+					status = append(status, "failed to connect: "+p.Error)
+				} else {
+					status = append(status, "connected")
+					if p.Bitswap {
+						status = append(status, "speaks bitswap")
+					} else {
+						status = append(status, "does not speak bitswap")
+					}
+					if p.Sent {
+						status = append(status, "sent the block")
+					}
+				}
+				fmt.Fprintf(w, "  %s: %s\n", p.ID, strings.Join(status, ", "))
+			}
+			fmt.Fprintf(w, "Bitswap: %s\n", bitswapStatus(r))
+			if len(r.Gateways) > 0 {
+				fmt.Fprintln(w, "Gateways:")
+			}
+			for _, gw := range r.Gateways {
+				switch {
+				case gw.Error != "":
+					fmt.Fprintf(w, "  %s: %s\n", gw.URL, gw.Error)
+				case gw.Cached:
+					fmt.Fprintf(w, "  %s: cached\n", gw.URL)
+				default:
+					fmt.Fprintf(w, "  %s: not cached\n", gw.URL)
+				}
+			}
+			return nil
+		}),
+	},
+}
+
+// bitswapStatus describes the retrieval of the block of r with bitswap.
+func bitswapStatus(r *ContentDiagReport) string {
+	switch {
+	case r.Local:
+		return "n/a, the block is local"
+	case r.Retrieved:
+		return "retrieved"
+	default:
+		return "not retrieved: " + r.RetrieveError
+	}
+}
+
+// checkGatewayCache asks the gateway at gw whether it has c in its cache,
+// without making it fetch c.
+func checkGatewayCache(ctx context.Context, client *http.Client, gw string, c cid.Cid) ContentDiagGateway {
+	result := ContentDiagGateway{URL: gw}
+	r, err := http.NewRequestWithContext(ctx, http.MethodHead, strings.TrimSuffix(gw, "/")+"/ipfs/"+c.String(), nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	r.Header.Set("Cache-Control", "only-if-cached")
+	resp, err := client.Do(r)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	switch resp.StatusCode {
+	case http.StatusOK:
+		result.Cached = true
+	case http.StatusPreconditionFailed:
+	default:
+		result.Error = "unexpected status " + resp.Status
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+)
+
+func TestCheckGatewayCache(t *testing.T) {
+	c, err := cid.Decode("bafkqaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.Header.Get("Cache-Control") != "only-if-cached" {
+			t.Errorf("unexpected request %s %v", r.Method, r.Header)
+		}
+		switch r.URL.Path {
+		case "/cached/ipfs/" + c.String():
+			w.WriteHeader(http.StatusOK)
+		case "/missing/ipfs/" + c.String():
+			w.WriteHeader(http.StatusPreconditionFailed)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		gw      string
+		cached  bool
+		wantErr bool
+	}{
+		{gw: srv.URL + "/cached/", cached: true},
+		{gw: srv.URL + "/missing"},
+		{gw: srv.URL + "/broken", wantErr: true},
+	} {
+		res := checkGatewayCache(context.Background(), srv.Client(), tc.gw, c)
+		if res.Cached != tc.cached || (res.Error != "") != tc.wantErr {
+			t.Errorf("unexpected result for %s: %+v", tc.gw, res)
+		}
+	}
+}
+
+func TestBitswapStatus(t *testing.T) {
+	for _, tc := range []struct {
+		report   ContentDiagReport
+		expected string
+	}{
+		{report: ContentDiagReport{Local: true}, expected: "n/a, the block is local"},
+		{report: ContentDiagReport{Retrieved: true}, expected: "retrieved"},
+		{report: ContentDiagReport{RetrieveError: "context deadline exceeded"}, expected: "not retrieved: context deadline exceeded"},
+	} {
+		if status := bitswapStatus(&tc.report); status != tc.expected {
+			t.Errorf("expected %q for %+v, got %q", tc.expected, tc.report, status)
+		}
+	}
+}
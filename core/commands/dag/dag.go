@@ -21,6 +21,12 @@ const (
 	progressOptionName = "progress"
 	silentOptionName   = "silent"
 	statsOptionName    = "stats"
+	maxDepthOptionName = "max-depth"
+	linkNameOptionName = "link-name"
+	codecOptionName    = "codec"
+	minSizeOptionName  = "min-size"
+	maxSizeOptionName  = "max-size"
+	metaOptionName     = "meta"
 )
 
 // DagCmd provides a subset of commands for interacting with ipld dag objects
@@ -41,6 +47,7 @@ the existing 'ipfs object' command moving forward.
 		"import":  DagImportCmd,
 		"export":  DagExportCmd,
 		"stat":    DagStatCmd,
+		"walk":    DagWalkCmd,
 	},
 }
 
@@ -322,3 +329,68 @@ Note: This command skips duplicate blocks in reporting both size and the number
 		}),
 	},
 }
+
+// DagWalkOutput is a node visited by 'dag walk'.
+type DagWalkOutput struct {
+	Cid  cid.Cid
+	Meta *DagWalkMeta `json:",omitempty"`
+}
+
+// DagWalkMeta describes a node visited by 'dag walk'.
+type DagWalkMeta struct {
+	Codec string
+	Size  int
+	Links int
+	Depth int
+	// Path are the names of the links followed from the root, skipping the
+	// unnamed links.
+	Path string
+}
+
+// DagWalkCmd is a command streaming the nodes of a DAG
+var DagWalkCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Stream the CIDs of the nodes of a DAG.",
+		ShortDescription: `
+'ipfs dag walk' walks a DAG depth first, from its root, and outputs the CID of
+each node visited as it is fetched. Each node is visited once.
+
+The walk follows the links up to --max-depth links from the root, and only the
+links whose name matches the --link-name glob, as matched by Go's path.Match.
+Of the nodes walked, only the ones with a --codec, or with a size within
+--min-size and --max-size, are output.
+
+With --meta, each node is output with its codec, the size of its block, its
+number of links, its depth and the names of the links followed from the root:
+
+  <cid>	<codec>	<size>	<links>	<depth>	<path>
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("root", true, false, "CID of a DAG root to walk").EnableStdin(),
+	},
+	Options: []cmds.Option{
+		cmds.IntOption(maxDepthOptionName, "The depth of the nodes whose links are not followed, -1 for none.").WithDefault(-1),
+		cmds.StringOption(linkNameOptionName, "Only follow the links whose name matches this glob."),
+		cmds.StringsOption(codecOptionName, "Only output the nodes with this codec, e.g. dag-pb. May be repeated."),
+		cmds.IntOption(minSizeOptionName, "Only output the nodes of at least this size, in bytes."),
+		cmds.IntOption(maxSizeOptionName, "Only output the nodes of at most this size, in bytes."),
+		cmds.BoolOption(metaOptionName, "Output the metadata of each node."),
+	},
+	Run:  dagWalk,
+	Type: DagWalkOutput{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *DagWalkOutput) error {
+			enc, err := cmdenv.GetLowLevelCidEncoder(req)
+			if err != nil {
+				return err
+			}
+			if out.Meta == nil {
+				_, err = fmt.Fprintln(w, enc.Encode(out.Cid))
+				return err
+			}
+			_, err = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n", enc.Encode(out.Cid), out.Meta.Codec, out.Meta.Size, out.Meta.Links, out.Meta.Depth, out.Meta.Path)
+			return err
+		}),
+	},
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
 	"github.com/ipfs/go-ipfs/core/commands/e"
+	"github.com/ipfs/go-ipfs/walkpool"
 	"github.com/ipfs/go-merkledag/traverse"
 	"github.com/ipfs/interface-go-ipfs-core/path"
 
@@ -31,7 +32,11 @@ func dagStat(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment)
 		return fmt.Errorf("cannot return size for anything other than a DAG with a root CID")
 	}
 
-	nodeGetter := mdag.NewSession(req.Context, api.Dag())
+	nd, err := cmdenv.GetNode(env)
+	if err != nil {
+		return err
+	}
+	nodeGetter := nd.WalkPool.NodeGetter(walkpool.OpDagStat, mdag.NewSession(req.Context, api.Dag()))
 	obj, err := nodeGetter.Get(req.Context, rp.Cid())
 	if err != nil {
 		return err
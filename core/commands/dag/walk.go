@@ -0,0 +1,167 @@
+package dagcmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/walkpool"
+	ipath "github.com/ipfs/interface-go-ipfs-core/path"
+
+	cid "github.com/ipfs/go-cid"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	ipld "github.com/ipfs/go-ipld-format"
+	mdag "github.com/ipfs/go-merkledag"
+	mc "github.com/multiformats/go-multicodec"
+)
+
+// dagWalker walks a DAG depth first, visiting each node once.
+type dagWalker struct {
+	getter ipld.NodeGetter
+
+	// maxDepth is the depth of the nodes whose links are not followed, or
+	// -1 to follow all the links.
+	maxDepth int
+	// linkName is the glob the names of the links followed must match, or
+	// "" to follow all the links.
+	linkName string
+
+	// codecs are the codecs of the nodes visited, or nil for all of them.
+	codecs map[uint64]struct{}
+	// minSize and maxSize bound the size of the blocks visited, 0 for no
+	// bound.
+	minSize, maxSize int
+
+	// seen are the depths the nodes were seen at. A node seen again higher
+	// in the DAG is walked again, as its links may have been cut by the max
+	// depth, but not visited again.
+	seen map[string]int
+}
+
+// walk calls visit with the nodes of the DAG of root passing the filters,
+// in depth first order. The path of a node are the names of the links
+// followed from root, the unnamed links, like the chunks of a file, keeping
+// the path of their parent.
+func (w *dagWalker) walk(ctx context.Context, root cid.Cid, visit func(nd ipld.Node, depth int, p string) error) error {
+	if w.linkName != "" {
+		if _, err := path.Match(w.linkName, ""); err != nil {
+			return fmt.Errorf("invalid link name glob %q: %w", w.linkName, err)
+		}
+	}
+	w.seen = make(map[string]int)
+	return w.walkNode(ctx, root, 0, "", visit)
+}
+
+func (w *dagWalker) walkNode(ctx context.Context, c cid.Cid, depth int, p string, visit func(nd ipld.Node, depth int, p string) error) error {
+	key := string(c.Bytes())
+	oldDepth, seen := w.seen[key]
+	if seen && (w.maxDepth < 0 || oldDepth <= depth) {
+		return nil
+	}
+	w.seen[key] = depth
+	nd, err := w.getter.Get(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if !seen && w.visits(nd) {
+		if err := visit(nd, depth, p); err != nil {
+			return err
+		}
+	}
+
+	if w.maxDepth >= 0 && depth >= w.maxDepth {
+		return nil
+	}
+	for _, l := range nd.Links() {
+		if w.linkName != "" {
+			if ok, _ := path.Match(w.linkName, l.Name); !ok {
+				continue
+			}
+		}
+		lp := p
+		switch {
+		case l.Name == "":
+		case p == "":
+			lp = l.Name
+		default:
+			lp = p + "/" + l.Name
+		}
+		if err := w.walkNode(ctx, l.Cid, depth+1, lp, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visits returns whether nd passes the filters of the nodes visited.
+func (w *dagWalker) visits(nd ipld.Node) bool {
+	if w.codecs != nil {
+		if _, ok := w.codecs[nd.Cid().Type()]; !ok {
+			return false
+		}
+	}
+	size := len(nd.RawData())
+	return size >= w.minSize && (w.maxSize == 0 || size <= w.maxSize)
+}
+
+func dagWalk(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+	api, err := cmdenv.GetApi(env, req)
+	if err != nil {
+		return err
+	}
+	nd, err := cmdenv.GetNode(env)
+	if err != nil {
+		return err
+	}
+
+	rp, err := api.ResolvePath(req.Context, ipath.New(req.Arguments[0]))
+	if err != nil {
+		return err
+	}
+	if len(rp.Remainder()) > 0 {
+		return fmt.Errorf("cannot walk anything other than a DAG with a root CID")
+	}
+
+	maxDepth, _ := req.Options[maxDepthOptionName].(int)
+	linkName, _ := req.Options[linkNameOptionName].(string)
+	minSize, _ := req.Options[minSizeOptionName].(int)
+	maxSize, _ := req.Options[maxSizeOptionName].(int)
+	meta, _ := req.Options[metaOptionName].(bool)
+	if minSize < 0 || maxSize < 0 {
+		return fmt.Errorf("the size bounds must be positive")
+	}
+
+	w := &dagWalker{
+		getter:   nd.WalkPool.NodeGetter(walkpool.OpDagWalk, mdag.NewSession(req.Context, api.Dag())),
+		maxDepth: maxDepth,
+		linkName: linkName,
+		minSize:  minSize,
+		maxSize:  maxSize,
+	}
+	if names, _ := req.Options[codecOptionName].([]string); len(names) > 0 {
+		w.codecs = make(map[uint64]struct{}, len(names))
+		for _, name := range names {
+			var codec mc.Code
+			if err := codec.Set(name); err != nil {
+				return err
+			}
+			w.codecs[uint64(codec)] = struct{}{}
+		}
+	}
+
+	return w.walk(req.Context, rp.Cid(), func(n ipld.Node, depth int, p string) error {
+		out := &DagWalkOutput{Cid: n.Cid()}
+		if meta {
+			out.Meta = &DagWalkMeta{
+				Codec: mc.Code(n.Cid().Type()).String(),
+				Size:  len(n.RawData()),
+				Links: len(n.Links()),
+				Depth: depth,
+				Path:  p,
+			}
+		}
+		return res.Emit(out)
+	})
+}
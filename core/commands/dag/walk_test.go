@@ -0,0 +1,101 @@
+package dagcmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	mdag "github.com/ipfs/go-merkledag"
+	dstest "github.com/ipfs/go-merkledag/test"
+)
+
+func TestDagWalker(t *testing.T) {
+	ctx := context.Background()
+	dag := dstest.Mock()
+
+	leaf := mdag.NewRawNode([]byte("leaf"))
+	big := mdag.NewRawNode([]byte("a bigger leaf"))
+	dir := mdag.NodeWithData([]byte("dir"))
+	if err := dir.AddNodeLink("x", leaf); err != nil {
+		t.Fatal(err)
+	}
+	root := mdag.NodeWithData([]byte("root"))
+	for name, nd := range map[string]ipld.Node{"a": dir, "b": big, "c": leaf} {
+		if err := root.AddNodeLink(name, nd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := dag.AddMany(ctx, []ipld.Node{leaf, big, dir, root}); err != nil {
+		t.Fatal(err)
+	}
+
+	walk := func(w *dagWalker) (visited []string) {
+		t.Helper()
+		w.getter = dag
+		err := w.walk(ctx, root.Cid(), func(nd ipld.Node, depth int, p string) error {
+			visited = append(visited, p)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return visited
+	}
+
+	if got := walk(&dagWalker{maxDepth: -1}); !reflect.DeepEqual(got, []string{"", "a", "a/x", "b"}) {
+		t.Fatalf("expected each node visited once, depth first, got %v", got)
+	}
+	if got := walk(&dagWalker{maxDepth: 1}); !reflect.DeepEqual(got, []string{"", "a", "b", "c"}) {
+		t.Fatalf("expected the links below the max depth not to be followed, got %v", got)
+	}
+	if got := walk(&dagWalker{maxDepth: -1, linkName: "[ac]"}); !reflect.DeepEqual(got, []string{"", "a", "c"}) {
+		t.Fatalf("expected only the links matching the glob to be followed, got %v", got)
+	}
+	raw := map[uint64]struct{}{cid.Raw: {}}
+	if got := walk(&dagWalker{maxDepth: -1, codecs: raw}); !reflect.DeepEqual(got, []string{"a/x", "b"}) {
+		t.Fatalf("expected only the nodes with the codecs to be visited, got %v", got)
+	}
+	if got := walk(&dagWalker{maxDepth: -1, codecs: raw, minSize: 5}); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Fatalf("expected only the nodes within the sizes to be visited, got %v", got)
+	}
+
+	// sub is first seen at the max depth, then higher in the DAG where its
+	// links are followed
+	sub := mdag.NodeWithData([]byte("sub"))
+	if err := sub.AddNodeLink("z", leaf); err != nil {
+		t.Fatal(err)
+	}
+	mid := mdag.NodeWithData([]byte("mid"))
+	if err := mid.AddNodeLink("s", sub); err != nil {
+		t.Fatal(err)
+	}
+	top := mdag.NodeWithData([]byte("top"))
+	if err := top.AddNodeLink("p", mid); err != nil {
+		t.Fatal(err)
+	}
+	if err := top.AddNodeLink("q", sub); err != nil {
+		t.Fatal(err)
+	}
+	if err := dag.AddMany(ctx, []ipld.Node{sub, mid, top}); err != nil {
+		t.Fatal(err)
+	}
+	var visited []string
+	w := &dagWalker{maxDepth: 2, getter: dag}
+	err := w.walk(ctx, top.Cid(), func(nd ipld.Node, depth int, p string) error {
+		visited = append(visited, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(visited, []string{"", "p", "p/s", "q/z"}) {
+		t.Fatalf("expected the links of a node seen again higher in the DAG to be followed, got %v", visited)
+	}
+
+	w = &dagWalker{maxDepth: -1, linkName: "[", getter: dag}
+	if err := w.walk(ctx, root.Cid(), func(ipld.Node, int, string) error { return nil }); err == nil {
+		t.Fatal("expected an invalid glob to be refused")
+	}
+}
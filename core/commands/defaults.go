@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+// SetOptionDefaults sets the defaults of the options of the commands under
+// root to the values in the Commands config. The options given in a request
+// still override them, as they override the built-in defaults. The invalid
+// defaults are left out, and returned as errors.
+func SetOptionDefaults(root *cmds.Command, defaults config.Commands) []error {
+	var errs []error
+	for path, values := range defaults {
+		cmd := root
+		for _, name := range strings.Fields(path) {
+			if cmd = cmd.Subcommands[name]; cmd == nil {
+				break
+			}
+		}
+		if cmd == nil {
+			errs = append(errs, fmt.Errorf("unknown command %q in Commands", path))
+			continue
+		}
+		if cmd == root {
+			errs = append(errs, fmt.Errorf("missing command name in Commands"))
+			continue
+		}
+
+		for name, value := range values {
+			i := optionIndex(cmd, name)
+			if i < 0 {
+				errs = append(errs, fmt.Errorf("unknown option %q of command %q in Commands", name, path))
+				continue
+			}
+			v, err := optionValue(cmd.Options[i], value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid default of option %q of command %q in Commands: %w", name, path, err))
+				continue
+			}
+			cmd.Options[i] = cmd.Options[i].WithDefault(v)
+		}
+	}
+	return errs
+}
+
+func optionIndex(cmd *cmds.Command, name string) int {
+	for i, opt := range cmd.Options {
+		for _, n := range opt.Names() {
+			if n == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// optionValue converts a value decoded from the JSON config to the type of
+// opt.
+func optionValue(opt cmds.Option, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if opt.Type() == cmds.Strings {
+			return []string{v}, nil
+		}
+		return opt.Parse(v)
+	case bool:
+		if opt.Type() != cmds.Bool {
+			return nil, fmt.Errorf("expected a %s, got a boolean", opt.Type())
+		}
+		return v, nil
+	case float64:
+		switch opt.Type() {
+		case cmds.Bool, cmds.String, cmds.Strings:
+			return nil, fmt.Errorf("expected a %s, got a number", opt.Type())
+		}
+		return opt.Parse(strconv.FormatFloat(v, 'f', -1, 64))
+	case []interface{}:
+		if opt.Type() != cmds.Strings {
+			return nil, fmt.Errorf("expected a %s, got a list", opt.Type())
+		}
+		strs := make([]string, len(v))
+		for i, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a list of strings")
+			}
+			strs[i] = s
+		}
+		return strs, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %v", value)
+	}
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+func TestSetOptionDefaults(t *testing.T) {
+	newRoot := func() *cmds.Command {
+		return &cmds.Command{
+			Subcommands: map[string]*cmds.Command{
+				"add": {Options: []cmds.Option{
+					cmds.BoolOption("pin", "").WithDefault(true),
+					cmds.IntOption("chunk-size", "s", ""),
+				}},
+				"pin": {Subcommands: map[string]*cmds.Command{
+					"add": {Options: []cmds.Option{
+						cmds.BoolOption("progress", ""),
+						cmds.StringsOption("name", ""),
+					}},
+				}},
+			},
+		}
+	}
+	parse := func(s string) config.Commands {
+		var defaults config.Commands
+		if err := json.Unmarshal([]byte(s), &defaults); err != nil {
+			t.Fatal(err)
+		}
+		return defaults
+	}
+
+	root := newRoot()
+	errs := SetOptionDefaults(root, parse(`{"add": {"pin": false, "s": 1024}, "pin add": {"progress": true, "name": ["a", "b"]}}`))
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	for _, tc := range []struct {
+		opt      cmds.Option
+		expected interface{}
+	}{
+		{root.Subcommands["add"].Options[0], false},
+		{root.Subcommands["add"].Options[1], 1024},
+		{root.Subcommands["pin"].Subcommands["add"].Options[0], true},
+		{root.Subcommands["pin"].Subcommands["add"].Options[1], []string{"a", "b"}},
+	} {
+		if !reflect.DeepEqual(tc.opt.Default(), tc.expected) {
+			t.Errorf("expected the default of %s to be %v, got %v", tc.opt.Name(), tc.expected, tc.opt.Default())
+		}
+	}
+
+	for _, invalid := range []string{
+		`{"cat": {"pin": false}}`,
+		`{"": {"pin": false}}`,
+		`{"add": {"offline": true}}`,
+		`{"add": {"pin": "maybe"}}`,
+		`{"add": {"chunk-size": 1.5}}`,
+		`{"pin add": {"progress": 1}}`,
+		`{"pin add": {"name": [1]}}`,
+	} {
+		if errs := SetOptionDefaults(newRoot(), parse(invalid)); len(errs) != 1 {
+			t.Errorf("expected an error for %s, got %v", invalid, errs)
+		}
+	}
+
+	root = newRoot()
+	errs = SetOptionDefaults(root, parse(`{"cat": {"pin": false}, "add": {"pin": "maybe", "s": 1024}}`))
+	if len(errs) != 2 {
+		t.Fatalf("expected the two invalid defaults to be reported, got %v", errs)
+	}
+	if root.Subcommands["add"].Options[1].Default() != 1024 {
+		t.Fatal("expected the valid defaults to be set next to the invalid ones")
+	}
+}
@@ -13,5 +13,8 @@ var DiagCmd = &cmds.Command{
 		"sys":     sysDiagCmd,
 		"cmds":    ActiveReqsCmd,
 		"profile": sysProfileCmd,
+		"peer":    peerDiagCmd,
+		"rcmgr":   rcmgrDiagCmd,
+		"content": contentDiagCmd,
 	},
 }
@@ -13,10 +13,14 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	"github.com/ipfs/go-ipfs/core"
 	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/core/coreunix"
+	"github.com/ipfs/go-ipfs/repo"
+	"github.com/ipfs/go-ipfs/scanner"
 
 	bservice "github.com/ipfs/go-blockservice"
 	cid "github.com/ipfs/go-cid"
 	cidenc "github.com/ipfs/go-cidutil/cidenc"
+	chunker "github.com/ipfs/go-ipfs-chunker"
 	cmds "github.com/ipfs/go-ipfs-cmds"
 	offline "github.com/ipfs/go-ipfs-exchange-offline"
 	ipld "github.com/ipfs/go-ipld-format"
@@ -24,6 +28,8 @@ import (
 	dag "github.com/ipfs/go-merkledag"
 	mfs "github.com/ipfs/go-mfs"
 	ft "github.com/ipfs/go-unixfs"
+	"github.com/ipfs/go-unixfs/importer/balanced"
+	ihelper "github.com/ipfs/go-unixfs/importer/helpers"
 	iface "github.com/ipfs/interface-go-ipfs-core"
 	path "github.com/ipfs/interface-go-ipfs-core/path"
 	mh "github.com/multiformats/go-multihash"
@@ -365,6 +371,9 @@ being GC'ed.
 		if err != nil {
 			return err
 		}
+		if repo.IsReadOnly(nd.Repo) {
+			return repo.ErrReadOnly
+		}
 
 		prefix, err := getPrefixNew(req)
 		if err != nil {
@@ -694,6 +703,9 @@ Example:
 		if err != nil {
 			return err
 		}
+		if repo.IsReadOnly(nd.Repo) {
+			return repo.ErrReadOnly
+		}
 
 		flush, _ := req.Options[filesFlushOptionName].(bool)
 
@@ -741,6 +753,10 @@ Newly created leaves will be in the legacy format (Protobuf) if the
 CID version is 0, or raw if the CID version is non-zero.  Use of the
 '--raw-leaves' option will override this behavior.
 
+A file written whole, that is a new or empty file or one written with
+'--truncate', from offset 0, is chunked with the chunker set for its content
+type in Import.ChunkerByContentType, as with 'ipfs add'.
+
 If the '--flush' option is set to false, changes will not be propagated to the
 merkledag root. This can make operations much faster when doing a large number
 of writes to a deeper directory structure.
@@ -792,6 +808,9 @@ stat' on the file or any of its ancestors.
 		if err != nil {
 			return err
 		}
+		if repo.IsReadOnly(nd.Repo) {
+			return repo.ErrReadOnly
+		}
 
 		offset, _ := req.Options[filesOffsetOptionName].(int64)
 		if offset < 0 {
@@ -805,6 +824,22 @@ stat' on the file or any of its ancestors.
 			}
 		}
 
+		cfg, err := nd.Repo.Config()
+		if err != nil {
+			return err
+		}
+		scan := scanner.FromConfig(cfg.Import.Scanner)
+		// the previous version of the file, restored if the scanner
+		// rejects the write
+		var prev ipld.Node
+		if scan != nil {
+			if prevFile, err := getFileHandle(nd.FilesRoot, path, false, prefix); err == nil {
+				if prev, err = prevFile.GetNode(); err != nil {
+					return err
+				}
+			}
+		}
+
 		fi, err := getFileHandle(nd.FilesRoot, path, create, prefix)
 		if err != nil {
 			return err
@@ -813,6 +848,53 @@ stat' on the file or any of its ancestors.
 			fi.RawLeaves = rawLeaves
 		}
 
+		if scan != nil {
+			defer func() {
+				if !errors.Is(retErr, scanner.ErrRejected) {
+					return
+				}
+				if err := restoreFile(nd.FilesRoot, path, prev); err != nil {
+					flog.Errorf("files: error restoring %s after a rejected write: %s", path, err)
+				}
+			}()
+		}
+
+		count, countfound := req.Options[filesCountOptionName].(int64)
+		if countfound && count < 0 {
+			return fmt.Errorf("cannot have negative byte count")
+		}
+
+		var r io.Reader
+		r, err = cmdenv.GetFileArg(req.Files.Entries())
+		if err != nil {
+			return err
+		}
+		if countfound {
+			r = io.LimitReader(r, int64(count))
+		}
+		if scan != nil {
+			r = scanner.NewBatch(req.Context, scan, false).Reader(path, r)
+		}
+
+		// a file written whole is chunked as by 'ipfs add', with the
+		// chunker of its content type in Import.ChunkerByContentType
+		if byType := cfg.Import.ChunkerByContentType; len(byType) > 0 && offset == 0 {
+			size, err := fi.Size()
+			if err != nil {
+				return err
+			}
+			if trunc || size == 0 {
+				var chunkerStr string
+				chunkerStr, r, err = coreunix.ChunkerForContent(r, byType)
+				if err != nil {
+					return err
+				}
+				if chunkerStr != "" {
+					return writeChunked(nd.FilesRoot, nd.DAG, path, fi, r, chunkerStr)
+				}
+			}
+		}
+
 		wfd, err := fi.Open(mfs.Flags{Write: true, Sync: flush})
 		if err != nil {
 			return err
@@ -835,31 +917,65 @@ stat' on the file or any of its ancestors.
 			}
 		}
 
-		count, countfound := req.Options[filesCountOptionName].(int64)
-		if countfound && count < 0 {
-			return fmt.Errorf("cannot have negative byte count")
-		}
-
 		_, err = wfd.Seek(int64(offset), io.SeekStart)
 		if err != nil {
 			flog.Error("seekfail: ", err)
 			return err
 		}
 
-		var r io.Reader
-		r, err = cmdenv.GetFileArg(req.Files.Entries())
-		if err != nil {
-			return err
-		}
-		if countfound {
-			r = io.LimitReader(r, int64(count))
-		}
-
 		_, err = io.Copy(wfd, r)
 		return err
 	},
 }
 
+// writeChunked replaces the content of the file fi at path with data,
+// chunked with chunkerStr. The file keeps its CID version, hash function and
+// leaves format.
+func writeChunked(r *mfs.Root, dserv ipld.DAGService, path string, fi *mfs.File, data io.Reader, chunkerStr string) error {
+	prev, err := fi.GetNode()
+	if err != nil {
+		return err
+	}
+	spl, err := chunker.FromString(data, chunkerStr)
+	if err != nil {
+		return err
+	}
+	params := ihelper.DagBuilderParams{
+		Dagserv:    dserv,
+		RawLeaves:  fi.RawLeaves,
+		Maxlinks:   ihelper.DefaultLinksPerBlock,
+		CidBuilder: prev.Cid().Prefix(),
+	}
+	db, err := params.New(spl)
+	if err != nil {
+		return err
+	}
+	nd, err := balanced.Layout(db)
+	if err != nil {
+		return err
+	}
+	return restoreFile(r, path, nd)
+}
+
+// restoreFile puts back prev at path, or removes the file at path when prev
+// is nil.
+func restoreFile(r *mfs.Root, path string, prev ipld.Node) error {
+	dirname, fname := gopath.Split(path)
+	pdir, err := getParentDir(r, dirname)
+	if err != nil {
+		return err
+	}
+	if err := pdir.Unlink(fname); err != nil {
+		return err
+	}
+	if prev != nil {
+		if err := pdir.AddChild(fname, prev); err != nil {
+			return err
+		}
+	}
+	return pdir.Flush()
+}
+
 var filesMkdirCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
 		Tagline: "Make directories.",
@@ -891,6 +1007,9 @@ Examples:
 		if err != nil {
 			return err
 		}
+		if repo.IsReadOnly(n.Repo) {
+			return repo.ErrReadOnly
+		}
 
 		dashp, _ := req.Options[filesParentsOptionName].(bool)
 		dirtomake, err := checkPath(req.Arguments[0])
@@ -976,6 +1095,9 @@ Change the CID version or hash function of the root node of a given path.
 		if err != nil {
 			return err
 		}
+		if repo.IsReadOnly(nd.Repo) {
+			return repo.ErrReadOnly
+		}
 
 		path := "/"
 		if len(req.Arguments) > 0 {
@@ -1044,6 +1166,9 @@ Remove files or directories.
 		if err != nil {
 			return err
 		}
+		if repo.IsReadOnly(nd.Repo) {
+			return repo.ErrReadOnly
+		}
 		// if '--force' specified, it will remove anything else,
 		// including file, directory, corrupted node, etc
 		force, _ := req.Options[forceOptionName].(bool)
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	path "github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+var GatewayCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Manage the HTTP gateway.",
+		ShortDescription: `
+'ipfs gateway' is a set of commands to manage how the HTTP gateway serves
+content.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"alias": gatewayAliasCmd,
+	},
+}
+
+var gatewayAliasCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Manage redirects for moved content.",
+		ShortDescription: `
+Aliases permanently redirect (HTTP 301) gateway requests for a content path
+that has moved to its new location, so that published links keep working
+after a content migration. A request for the alias, or for any path below
+it, is redirected to the same place under the target:
+
+  > ipfs gateway alias add /ipfs/QmOldSite /ipfs/bafyNewSite
+  > curl -sI http://127.0.0.1:8080/ipfs/QmOldSite/about.html | grep Location
+  Location: /ipfs/bafyNewSite/about.html
+
+/ipfs/ aliases match the content whatever CID version the request uses.
+Aliases are kept in the Gateway.Aliases config field, and the gateway serves
+the changes once the daemon is restarted. To give content paths local names,
+see 'ipfs petname'.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"add": gatewayAliasAddCmd,
+		"rm":  gatewayAliasRmCmd,
+		"ls":  gatewayAliasLsCmd,
+	},
+}
+
+// GatewayAlias is a single Gateway.Aliases entry.
+type GatewayAlias struct {
+	From string
+	To   string
+}
+
+// GatewayAliasList is the output of 'ipfs gateway alias ls'.
+type GatewayAliasList struct {
+	Aliases []GatewayAlias
+}
+
+func normalizeGatewayAlias(p string) (string, error) {
+	ip := path.New(strings.TrimRight(p, "/"))
+	if err := ip.IsValid(); err != nil {
+		return "", fmt.Errorf("invalid content path %q: %w", p, err)
+	}
+	return ip.String(), nil
+}
+
+var gatewayAliasAddCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Redirect a moved content path to its new location.",
+		ShortDescription: "Adds or replaces the gateway alias for <from>.",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("from", true, false, "Content path that has moved."),
+		cmds.StringArg("to", true, false, "Content path to redirect to."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		from, err := normalizeGatewayAlias(req.Arguments[0])
+		if err != nil {
+			return err
+		}
+		to, err := normalizeGatewayAlias(req.Arguments[1])
+		if err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("alias would redirect %s to itself", from)
+		}
+
+		cfgRoot, err := cmdenv.GetConfigRoot(env)
+		if err != nil {
+			return err
+		}
+		r, err := fsrepo.Open(cfgRoot)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		cfg, err := r.Config()
+		if err != nil {
+			return err
+		}
+
+		if cfg.Gateway.Aliases == nil {
+			cfg.Gateway.Aliases = map[string]string{}
+		}
+		cfg.Gateway.Aliases[from] = to
+		if err := r.SetConfig(cfg); err != nil {
+			return err
+		}
+
+		return cmds.EmitOnce(res, &GatewayAlias{From: from, To: to})
+	},
+	Type: GatewayAlias{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *GatewayAlias) error {
+			_, err := fmt.Fprintf(w, "added %s -> %s\n", out.From, out.To)
+			return err
+		}),
+	},
+}
+
+var gatewayAliasRmCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Remove a gateway alias.",
+		ShortDescription: "Stops redirecting requests for <from>.",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("from", true, false, "Content path of the alias to remove."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		from, err := normalizeGatewayAlias(req.Arguments[0])
+		if err != nil {
+			return err
+		}
+
+		cfgRoot, err := cmdenv.GetConfigRoot(env)
+		if err != nil {
+			return err
+		}
+		r, err := fsrepo.Open(cfgRoot)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		cfg, err := r.Config()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := cfg.Gateway.Aliases[from]; !ok {
+			return fmt.Errorf("no alias for %s", from)
+		}
+		delete(cfg.Gateway.Aliases, from)
+		return r.SetConfig(cfg)
+	},
+}
+
+var gatewayAliasLsCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "List gateway aliases.",
+		ShortDescription: "Lists the content paths redirected by the gateway.",
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		cfgRoot, err := cmdenv.GetConfigRoot(env)
+		if err != nil {
+			return err
+		}
+		r, err := fsrepo.Open(cfgRoot)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		cfg, err := r.Config()
+		if err != nil {
+			return err
+		}
+
+		out := &GatewayAliasList{Aliases: make([]GatewayAlias, 0, len(cfg.Gateway.Aliases))}
+		for from, to := range cfg.Gateway.Aliases {
+			out.Aliases = append(out.Aliases, GatewayAlias{From: from, To: to})
+		}
+		sort.Slice(out.Aliases, func(i, j int) bool {
+			return out.Aliases[i].From < out.Aliases[j].From
+		})
+		return cmds.EmitOnce(res, out)
+	},
+	Type: GatewayAliasList{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *GatewayAliasList) error {
+			tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+			for _, a := range out.Aliases {
+				fmt.Fprintf(tw, "%s\t-> %s\n", a.From, a.To)
+			}
+			return tw.Flush()
+		}),
+	},
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/journal"
+)
+
+var JournalCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Inspect the event journal.",
+		ShortDescription: `
+The event journal records significant events of the node in the repo: pins
+(type "pin"), IPNS publishes ("publish"), garbage collection runs ("gc")
+and config changes ("config").
+
+Recording is disabled by default, enable it with:
+
+  ipfs config --json Journal.Enabled true
+
+The number and age of the entries kept is limited by Journal.MaxEntries and
+Journal.MaxAge.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"ls": journalLsCmd,
+	},
+}
+
+const (
+	journalSinceOptionName = "since"
+	journalTypeOptionName  = "type"
+)
+
+var journalLsCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "List the entries of the event journal.",
+		ShortDescription: `
+'ipfs journal ls' lists the entries of the event journal, oldest first.
+
+--since takes either a time in RFC 3339 format, like 2022-05-01T12:00:00Z,
+or a duration, like 1h30m, to list the entries recorded since that long ago.
+--type takes a comma-separated list of event types to list.
+`,
+	},
+	Options: []cmds.Option{
+		cmds.StringOption(journalSinceOptionName, "List the entries recorded since this time or duration ago."),
+		cmds.DelimitedStringsOption(",", journalTypeOptionName, "List the entries of these types (pin,publish,gc,config,quarantine,scan)."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+
+		var since time.Time
+		if s, _ := req.Options[journalSinceOptionName].(string); s != "" {
+			since, err = parseJournalSince(s, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+		types, _ := req.Options[journalTypeOptionName].([]string)
+
+		entries, err := journal.Query(req.Context, n.Repo.Datastore(), since, types)
+		if err != nil {
+			return err
+		}
+		for i := range entries {
+			if err := res.Emit(&entries[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Type: journal.Entry{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, e *journal.Entry) error {
+			keys := make([]string, 0, len(e.Fields))
+			for k := range e.Fields {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "%s %-8s", e.Time.Format(time.RFC3339), e.Type)
+			for _, k := range keys {
+				fmt.Fprintf(&b, " %s=%s", k, e.Fields[k])
+			}
+			b.WriteByte('\n')
+			_, err := io.WriteString(w, b.String())
+			return err
+		}),
+	},
+}
+
+// parseJournalSince parses the --since option of 'ipfs journal ls': an
+// RFC 3339 time, or a duration before now.
+func parseJournalSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q: must be an RFC 3339 time or a duration", journalSinceOptionName, s)
+	}
+	return now.Add(-d), nil
+}
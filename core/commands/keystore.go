@@ -20,6 +20,7 @@ import (
 	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
 	"github.com/ipfs/go-ipfs/core/commands/e"
 	ke "github.com/ipfs/go-ipfs/core/commands/keyencode"
+	"github.com/ipfs/go-ipfs/repo"
 	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
 	migrations "github.com/ipfs/go-ipfs/repo/fsrepo/migrations"
 	options "github.com/ipfs/interface-go-ipfs-core/options"
@@ -627,17 +628,20 @@ environment variable:
 
 func doRotate(out io.Writer, repoRoot string, oldKey string, algorithm string, nBitsForKeypair int, nBitsGiven bool) error {
 	// Open repo
-	repo, err := fsrepo.Open(repoRoot)
+	r, err := fsrepo.Open(repoRoot)
 	if err != nil {
 		return fmt.Errorf("opening repo (%v)", err)
 	}
-	defer repo.Close()
+	defer r.Close()
 
 	// Read config file from repo
-	cfg, err := repo.Config()
+	cfg, err := r.Config()
 	if err != nil {
 		return fmt.Errorf("reading config from repo (%v)", err)
 	}
+	if cfg.Datastore.ReadOnly.WithDefault(false) {
+		return fmt.Errorf("rotating the identity: %w", repo.ErrReadOnly)
+	}
 
 	// Generate new identity
 	var identity config.Identity
@@ -660,7 +664,7 @@ func doRotate(out io.Writer, repoRoot string, oldKey string, algorithm string, n
 	if err != nil {
 		return fmt.Errorf("decoding old private key (%v)", err)
 	}
-	keystore := repo.Keystore()
+	keystore := r.Keystore()
 	if err := keystore.Put(oldKey, oldPrivKey); err != nil {
 		return fmt.Errorf("saving old key in keystore (%v)", err)
 	}
@@ -669,7 +673,7 @@ func doRotate(out io.Writer, repoRoot string, oldKey string, algorithm string, n
 	cfg.Identity = identity
 
 	// Write config file to repo
-	if err = repo.SetConfig(cfg); err != nil {
+	if err = r.SetConfig(cfg); err != nil {
 		return fmt.Errorf("saving new key to config (%v)", err)
 	}
 	return nil
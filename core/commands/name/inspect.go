@@ -0,0 +1,159 @@
+package name
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	ke "github.com/ipfs/go-ipfs/core/commands/keyencode"
+	"github.com/ipfs/go-ipfs/core/node"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	ipns "github.com/ipfs/go-ipns"
+	namesys "github.com/ipfs/go-namesys"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	checkNetworkOptionName = "check-network"
+)
+
+// IpnsInspectEntry describes the publishing state of one of our IPNS names.
+type IpnsInspectEntry struct {
+	Name            string
+	Value           string
+	Sequence        uint64
+	Validity        *time.Time `json:",omitempty"`
+	NetworkSequence uint64
+	Conflict        bool
+	LastPublished   *time.Time `json:",omitempty"`
+	LastChecked     *time.Time `json:",omitempty"`
+}
+
+var IpnsInspectCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Inspect the publishing state of an IPNS name.",
+		ShortDescription: `
+Shows the IPNS record this node last published for one of its keys, the
+highest sequence number ever used for that key and the sequence number last
+seen in the routing system.
+
+A conflict is reported when the routing system holds a record under our key
+with a higher sequence number, or with the same sequence number but a
+different value. This usually means the key is also in use on another node,
+or has been compromised.
+
+The routing system is looked up when publishing a key without a local record,
+e.g. in a re-created repo. Pass --check-network to look the record up now
+instead of reporting what was observed then.
+`,
+	},
+
+	Options: []cmds.Option{
+		cmds.StringOption(keyOptionName, "k", "Name of the key to inspect or a valid PeerID, as listed by 'ipfs key list -l'.").WithDefault("self"),
+		cmds.BoolOption(checkNetworkOptionName, "Look up the current record in the routing system."),
+		ke.OptionIPNSBase,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		api, err := cmdenv.GetApi(env, req)
+		if err != nil {
+			return err
+		}
+		keyEnc, err := ke.KeyEncoderFromString(req.Options[ke.OptionIPNSBase.Name()].(string))
+		if err != nil {
+			return err
+		}
+
+		kname, _ := req.Options[keyOptionName].(string)
+		keys, err := api.Key().List(req.Context)
+		if err != nil {
+			return err
+		}
+		var id peer.ID
+		for _, k := range keys {
+			if k.Name() == kname || k.ID().String() == kname {
+				id = k.ID()
+				break
+			}
+		}
+		if id == "" {
+			return fmt.Errorf("no key by the given name or PeerID was found")
+		}
+
+		store := node.NewIpnsSequenceStore(n.Repo.Datastore())
+		info, err := store.Get(req.Context, id)
+		if err != nil {
+			return err
+		}
+
+		local, err := namesys.NewIpnsPublisher(n.Routing, n.Repo.Datastore()).GetPublished(req.Context, id, false)
+		if err != nil {
+			return err
+		}
+
+		if check, _ := req.Options[checkNetworkOptionName].(bool); check {
+			if !n.IsOnline {
+				return cmds.Errorf(cmds.ErrClient, "--%s requires a running daemon", checkNetworkOptionName)
+			}
+			network, err := node.FetchIpnsRecord(req.Context, n.Routing, id)
+			if err != nil {
+				return err
+			}
+			info.Observe(local, network)
+			if err := store.Put(req.Context, id, info); err != nil {
+				return err
+			}
+		}
+
+		out := &IpnsInspectEntry{
+			Name:            keyEnc.FormatID(id),
+			Sequence:        info.Sequence,
+			NetworkSequence: info.NetworkSequence,
+			Conflict:        info.Conflict,
+		}
+		if local != nil {
+			out.Value = string(local.GetValue())
+			if local.GetSequence() > out.Sequence {
+				out.Sequence = local.GetSequence()
+			}
+			if eol, err := ipns.GetEOL(local); err == nil {
+				out.Validity = &eol
+			}
+		}
+		if !info.LastPublished.IsZero() {
+			out.LastPublished = &info.LastPublished
+		}
+		if !info.LastChecked.IsZero() {
+			out.LastChecked = &info.LastChecked
+		}
+
+		return cmds.EmitOnce(res, out)
+	},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *IpnsInspectEntry) error {
+			tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+			fmt.Fprintf(tw, "Name:\t%s\n", cmdenv.EscNonPrint(out.Name))
+			fmt.Fprintf(tw, "Value:\t%s\n", cmdenv.EscNonPrint(out.Value))
+			fmt.Fprintf(tw, "Sequence:\t%d\n", out.Sequence)
+			if out.Validity != nil {
+				fmt.Fprintf(tw, "Validity:\t%s\n", out.Validity.Format(time.RFC3339))
+			}
+			fmt.Fprintf(tw, "Network Sequence:\t%d\n", out.NetworkSequence)
+			if out.LastPublished != nil {
+				fmt.Fprintf(tw, "Last Published:\t%s\n", out.LastPublished.Format(time.RFC3339))
+			}
+			if out.LastChecked != nil {
+				fmt.Fprintf(tw, "Last Checked:\t%s\n", out.LastChecked.Format(time.RFC3339))
+			}
+			fmt.Fprintf(tw, "Conflict:\t%t\n", out.Conflict)
+			return tw.Flush()
+		}),
+	},
+	Type: IpnsInspectEntry{},
+}
@@ -1,17 +1,23 @@
 package name
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
 
 	cmds "github.com/ipfs/go-ipfs-cmds"
 	ke "github.com/ipfs/go-ipfs/core/commands/keyencode"
+	"github.com/ipfs/go-merkledag/dagutils"
+	namesys "github.com/ipfs/go-namesys"
+	ft "github.com/ipfs/go-unixfs"
 	iface "github.com/ipfs/interface-go-ipfs-core"
 	options "github.com/ipfs/interface-go-ipfs-core/options"
+	nsopts "github.com/ipfs/interface-go-ipfs-core/options/namesys"
 	path "github.com/ipfs/interface-go-ipfs-core/path"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 )
@@ -28,6 +34,8 @@ const (
 	ttlOptionName          = "ttl"
 	keyOptionName          = "key"
 	quieterOptionName      = "quieter"
+	pathsOptionName        = "paths"
+	pinOptionName          = "pin"
 )
 
 var PublishCmd = &cmds.Command{
@@ -67,11 +75,32 @@ Alternatively, publish an <ipfs-path> using a valid PeerID (as listed by
  > ipfs name publish --key=QmbCMUZw6JFeZ7Wp9jkzbye3Fzp2GGcPgC3nmeUjfVF87n /ipfs/QmatmE9msSfkKxoffpHwNLNKgwZG8eT9Bud6YoPab52vpy
   Published to QmbCMUZw6JFeZ7Wp9jkzbye3Fzp2GGcPgC3nmeUjfVF87n: /ipfs/QmatmE9msSfkKxoffpHwNLNKgwZG8eT9Bud6YoPab52vpy
 
+Publish a new directory assembled from several roots, in one step:
+
+  > ipfs name publish --paths index.html=/ipfs/QmZULkCELmmk5XNfCgTnCyFgAVxBRBXyDHGGMVoLFLiXEN docs/v1=/ipns/docs.example.com
+  Published to QmbCMUZw6JFeZ7Wp9jkzbye3Fzp2GGcPgC3nmeUjfVF87n: /ipfs/QmUA7aDm3x6Bm9EpPEoAndwBQPgZGGdBGVpaNTKSMrnbPN
+
+With --paths, every argument is a <name>=<ipfs-path> pair. The directory is
+built on the node, with intermediate directories created for names
+containing '/', and pinned unless --pin=false is passed. The name is only
+published once the whole directory has been built. Every path is resolved to
+build the directory, so --resolve=false can't be passed with --paths.
+
+Delegate a name to another name, e.g. a team name to the name of the current
+publisher, so that the publisher key can be rotated without changing the name
+others use:
+
+  > ipfs name publish --key=team /ipns/k51qzi5uqu5dlvj2baxnqndepeb86cbk3ng7n3i46uzyxzyqj2xjonzllnv0v8
+
+The name resolves to what the other name resolves to. Resolving follows at
+most Ipns.MaxDelegations names; with --resolve, publishing fails when the
+names delegated to lead back to the published name.
+
 `,
 	},
 
 	Arguments: []cmds.Argument{
-		cmds.StringArg(ipfsPathOptionName, true, false, "ipfs path of the object to be published.").EnableStdin(),
+		cmds.StringArg(ipfsPathOptionName, true, true, "ipfs path of the object to be published, or <name>=<ipfs-path> pairs with --paths.").EnableStdin(),
 	},
 	Options: []cmds.Option{
 		cmds.BoolOption(resolveOptionName, "Check if the given path can be resolved before publishing.").WithDefault(true),
@@ -83,6 +112,8 @@ Alternatively, publish an <ipfs-path> using a valid PeerID (as listed by
 		cmds.StringOption(ttlOptionName, "Time duration this record should be cached for. Uses the same syntax as the lifetime option. (caution: experimental)"),
 		cmds.StringOption(keyOptionName, "k", "Name of the key to be used or a valid PeerID, as listed by 'ipfs key list -l'.").WithDefault("self"),
 		cmds.BoolOption(quieterOptionName, "Q", "Write only final hash."),
+		cmds.BoolOption(pathsOptionName, "Publish a new directory built from <name>=<ipfs-path> arguments."),
+		cmds.BoolOption(pinOptionName, "Pin the directory built with --paths.").WithDefault(true),
 		ke.OptionIPNSBase,
 	},
 	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
@@ -119,13 +150,36 @@ Alternatively, publish an <ipfs-path> using a valid PeerID (as listed by
 			opts = append(opts, options.Name.TTL(d))
 		}
 
-		p := path.New(req.Arguments[0])
-
-		if verifyExists, _ := req.Options[resolveOptionName].(bool); verifyExists {
-			_, err := api.ResolveNode(req.Context, p)
+		var p path.Path
+		if usePaths, _ := req.Options[pathsOptionName].(bool); usePaths {
+			if verifyExists, _ := req.Options[resolveOptionName].(bool); !verifyExists {
+				return fmt.Errorf("--%s=false can't be used with --%s: the paths are resolved to build the directory", resolveOptionName, pathsOptionName)
+			}
+			dir, err := buildPathsDir(req.Context, api, req.Arguments)
 			if err != nil {
 				return err
 			}
+			if doPin, _ := req.Options[pinOptionName].(bool); doPin {
+				if err := api.Pin().Add(req.Context, dir); err != nil {
+					return err
+				}
+			}
+			p = dir
+		} else {
+			if len(req.Arguments) != 1 {
+				return fmt.Errorf("expected a single path to publish, use --%s to publish several", pathsOptionName)
+			}
+			p = path.New(req.Arguments[0])
+
+			if verifyExists, _ := req.Options[resolveOptionName].(bool); verifyExists {
+				if err := checkDelegation(req.Context, api, kname, p); err != nil {
+					return err
+				}
+				_, err := api.ResolveNode(req.Context, p)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		out, err := api.Name().Publish(req.Context, p, opts...)
@@ -161,3 +215,117 @@ Alternatively, publish an <ipfs-path> using a valid PeerID (as listed by
 	},
 	Type: IpnsEntry{},
 }
+
+// buildPathsDir builds a UnixFS directory from <name>=<ipfs-path> pairs.
+// Every path is resolved while the directory is built.
+func buildPathsDir(ctx context.Context, api iface.CoreAPI, args []string) (path.Resolved, error) {
+	e := dagutils.NewDagEditor(ft.EmptyDirNode(), api.Dag())
+
+	names := make(map[string]struct{}, len(args))
+	dirs := make(map[string]struct{})
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid argument %q: expected <name>=<ipfs-path>", arg)
+		}
+		name, target := parts[0], parts[1]
+		segs := strings.Split(name, "/")
+		for _, seg := range segs {
+			if seg == "" || seg == "." || seg == ".." {
+				return nil, fmt.Errorf("invalid name %q", name)
+			}
+		}
+		if _, dup := names[name]; dup {
+			return nil, fmt.Errorf("name %q given more than once", name)
+		}
+		// a name can't be both a path and a directory of other names
+		if _, ok := dirs[name]; ok {
+			return nil, fmt.Errorf("name %q is a directory of other names", name)
+		}
+		for i := 1; i < len(segs); i++ {
+			dir := strings.Join(segs[:i], "/")
+			if _, ok := names[dir]; ok {
+				return nil, fmt.Errorf("name %q is inside %q, which is not a directory", name, dir)
+			}
+			dirs[dir] = struct{}{}
+		}
+		names[name] = struct{}{}
+
+		rp, err := api.ResolvePath(ctx, path.New(target))
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", name, err)
+		}
+		nd, err := api.Dag().Get(ctx, rp.Cid())
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", name, err)
+		}
+		if err := e.InsertNodeAtPath(ctx, name, nd, ft.EmptyDirNode); err != nil {
+			return nil, fmt.Errorf("adding %s: %w", name, err)
+		}
+	}
+
+	dir, err := e.Finalize(ctx, api.Dag())
+	if err != nil {
+		return nil, err
+	}
+	return path.IpfsPath(dir.Cid()), nil
+}
+
+// checkDelegation follows the names p delegates to one at a time, when it's
+// an /ipns/ path, and fails when they delegate back to the name of the key
+// kname or to a loop of names, which would never resolve.
+func checkDelegation(ctx context.Context, api iface.CoreAPI, kname string, p path.Path) error {
+	if p.Namespace() != "ipns" {
+		return nil
+	}
+	self, err := publishKeyID(ctx, api, kname)
+	if err != nil {
+		// reported by publishing
+		return nil
+	}
+
+	value := p
+	seen := make(map[string]bool)
+	for i := 0; i < nsopts.DefaultDepthLimit && p.Namespace() == "ipns"; i++ {
+		name := strings.SplitN(strings.TrimPrefix(p.String(), "/ipns/"), "/", 2)[0]
+		if id, err := peer.Decode(name); err == nil {
+			if id == self {
+				return fmt.Errorf("%s delegates back to the name published, %s", value, iface.FormatKeyID(self))
+			}
+			name = id.String()
+		}
+		if seen[name] {
+			return fmt.Errorf("%s delegates to a loop of names, through %s", value, name)
+		}
+		seen[name] = true
+
+		p, err = api.Name().Resolve(ctx, name, options.Name.ResolveOption(nsopts.Depth(1)))
+		if err != nil && err != namesys.ErrResolveRecursion {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishKeyID returns the ID of the key kname, by name or ID.
+func publishKeyID(ctx context.Context, api iface.CoreAPI, kname string) (peer.ID, error) {
+	if kname == "self" {
+		key, err := api.Key().Self(ctx)
+		if err != nil {
+			return "", err
+		}
+		return key.ID(), nil
+	}
+
+	keys, err := api.Key().List(ctx)
+	if err != nil {
+		return "", err
+	}
+	id, _ := peer.Decode(kname)
+	for _, key := range keys {
+		if key.Name() == kname || key.ID() == id {
+			return key.ID(), nil
+		}
+	}
+	return "", fmt.Errorf("no key named %s", kname)
+}
@@ -0,0 +1,92 @@
+package name
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	datastore "github.com/ipfs/go-datastore"
+	syncds "github.com/ipfs/go-datastore/sync"
+	files "github.com/ipfs/go-ipfs-files"
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/core/coreapi"
+	"github.com/ipfs/go-ipfs/repo"
+	path "github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+func TestBuildPathsDir(t *testing.T) {
+	ctx := context.Background()
+	r := &repo.Mock{
+		C: config.Config{
+			Identity: config.Identity{
+				PeerID: "QmTFauExutTsy4XP6JbMFcw2Wa9645HJt2bTqL6qYDCKfe", // required by offline node
+			},
+		},
+		D: syncds.MutexWrap(datastore.NewMapDatastore()),
+	}
+	node, err := core.NewNode(ctx, &core.BuildCfg{Repo: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+	api, err := coreapi.NewCoreAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := api.Unixfs().Add(ctx, files.NewBytesFile([]byte("index")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1, err := api.Unixfs().Add(ctx, files.NewBytesFile([]byte("v1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := buildPathsDir(ctx, api, []string{"v1=" + v1.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := buildPathsDir(ctx, api, []string{
+		"index.html=" + index.String(),
+		"docs/v1=" + v1.String(),
+		"docs/old=" + docs.String() + "/v1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range map[string]path.Resolved{"index.html": index, "docs/v1": v1, "docs/old": v1} {
+		rp, err := api.ResolvePath(ctx, path.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rp.Cid().Equals(expected.Cid()) {
+			t.Errorf("expected %s to be %s, got %s", name, expected.Cid(), rp.Cid())
+		}
+	}
+	nd, err := api.Dag().Get(ctx, dir.Cid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nd.Links()) != 2 {
+		t.Errorf("expected index.html and docs in the directory, got %d links", len(nd.Links()))
+	}
+
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{index.String()}, "expected <name>=<ipfs-path>"},
+		{[]string{"a//b=" + index.String()}, "invalid name"},
+		{[]string{"../a=" + index.String()}, "invalid name"},
+		{[]string{"a=" + index.String(), "a=" + v1.String()}, "more than once"},
+		{[]string{"a=" + index.String(), "a/b=" + v1.String()}, "not a directory"},
+		{[]string{"a/b=" + index.String(), "a=" + v1.String()}, "directory of other names"},
+		{[]string{"a=/ipfs/" + index.Cid().String() + "/missing"}, "resolving a"},
+	} {
+		if _, err := buildPathsDir(ctx, api, tc.args); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("expected %v to fail with %q, got %v", tc.args, tc.err, err)
+		}
+	}
+}
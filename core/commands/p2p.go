@@ -30,6 +30,10 @@ type P2PListenerInfoOutput struct {
 	Protocol      string
 	ListenAddress string
 	TargetAddress string
+	AllowedPeers  []string `json:",omitempty"`
+	MaxStreams    int      `json:",omitempty"`
+	IdleTimeout   string   `json:",omitempty"`
+	Streams       int
 }
 
 // P2PStreamInfoOutput is output type of streams command
@@ -38,6 +42,8 @@ type P2PStreamInfoOutput struct {
 	Protocol      string
 	OriginAddress string
 	TargetAddress string
+	BytesIn       int64
+	BytesOut      int64
 }
 
 // P2PLsOutput is output type of ls command
@@ -53,6 +59,9 @@ type P2PStreamsOutput struct {
 const (
 	allowCustomProtocolOptionName = "allow-custom-protocol"
 	reportPeerIDOptionName        = "report-peer-id"
+	allowPeerOptionName           = "allow-peer"
+	maxStreamsOptionName          = "max-streams"
+	idleTimeoutOptionName         = "idle-timeout"
 )
 
 var resolveTimeout = 10 * time.Second
@@ -88,6 +97,10 @@ Forward connections made to <listen-address> to <target-address>.
 <protocol> specifies the libp2p protocol name to use for libp2p
 connections and/or handlers. It must be prefixed with '` + P2PProtoPrefix + `'.
 
+--max-streams limits the connections forwarded at once; the connections over
+the limit are closed. --idle-timeout closes the connections with no data
+forwarded either way for that long.
+
 Example:
   ipfs p2p forward ` + P2PProtoPrefix + `myproto /ip4/127.0.0.1/tcp/4567 /p2p/QmPeer
     - Forward connections to 127.0.0.1:4567 to '` + P2PProtoPrefix + `myproto' service on /p2p/QmPeer
@@ -101,6 +114,8 @@ Example:
 	},
 	Options: []cmds.Option{
 		cmds.BoolOption(allowCustomProtocolOptionName, "Don't require /x/ prefix"),
+		cmds.IntOption(maxStreamsOptionName, "Maximum number of connections forwarded at once, 0 for no limit."),
+		cmds.StringOption(idleTimeoutOptionName, "Close the connections idle for this long, like 10m."),
 	},
 	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
 		n, err := p2pGetNode(env)
@@ -130,7 +145,12 @@ Example:
 			return errors.New("protocol name must be within '" + P2PProtoPrefix + "' namespace")
 		}
 
-		return forwardLocal(n.Context(), n.P2P, n.Peerstore, proto, listen, targets)
+		limits, err := p2pLimits(req)
+		if err != nil {
+			return err
+		}
+
+		return forwardLocal(n.Context(), n.P2P, n.Peerstore, proto, listen, targets, limits)
 	},
 }
 
@@ -189,10 +209,18 @@ Create libp2p service and forward connections made to <target-address>.
 
 <protocol> specifies the libp2p handler name. It must be prefixed with '` + P2PProtoPrefix + `'.
 
+--allow-peer restricts the peers allowed to open streams to the service,
+any peer being allowed by default. --max-streams limits the streams forwarded
+at once; the streams over the limit are reset. --idle-timeout closes the
+streams with no data forwarded either way for that long.
+
 Example:
   ipfs p2p listen ` + P2PProtoPrefix + `myproto /ip4/127.0.0.1/tcp/1234
     - Forward connections to 'myproto' libp2p service to 127.0.0.1:1234
 
+  ipfs p2p listen --allow-peer QmPeer --max-streams 10 ` + P2PProtoPrefix + `myproto /ip4/127.0.0.1/tcp/1234
+    - Only let QmPeer open up to 10 streams at once to 'myproto'
+
 `,
 	},
 	Arguments: []cmds.Argument{
@@ -202,6 +230,9 @@ Example:
 	Options: []cmds.Option{
 		cmds.BoolOption(allowCustomProtocolOptionName, "Don't require /x/ prefix"),
 		cmds.BoolOption(reportPeerIDOptionName, "r", "Send remote base58 peerid to target when a new connection is established"),
+		cmds.StringsOption(allowPeerOptionName, "Only allow this peer to open streams. Can be given multiple times."),
+		cmds.IntOption(maxStreamsOptionName, "Maximum number of streams forwarded at once, 0 for no limit."),
+		cmds.StringOption(idleTimeoutOptionName, "Close the streams idle for this long, like 10m."),
 	},
 	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
 		n, err := p2pGetNode(env)
@@ -231,11 +262,46 @@ Example:
 			return errors.New("protocol name must be within '" + P2PProtoPrefix + "' namespace")
 		}
 
-		_, err = n.P2P.ForwardRemote(n.Context(), proto, target, reportPeerID)
+		limits, err := p2pLimits(req)
+		if err != nil {
+			return err
+		}
+		allowed, _ := req.Options[allowPeerOptionName].([]string)
+		for _, s := range allowed {
+			p, err := peer.Decode(s)
+			if err != nil {
+				return fmt.Errorf("invalid peer ID %q: %s", s, err)
+			}
+			limits.AllowedPeers = append(limits.AllowedPeers, p)
+		}
+
+		_, err = n.P2P.ForwardRemote(n.Context(), proto, target, reportPeerID, limits)
 		return err
 	},
 }
 
+// p2pLimits returns the stream limits set in the options of req.
+func p2pLimits(req *cmds.Request) (p2p.Limits, error) {
+	var limits p2p.Limits
+	maxStreams, _ := req.Options[maxStreamsOptionName].(int)
+	if maxStreams < 0 {
+		return limits, fmt.Errorf("--%s must be positive", maxStreamsOptionName)
+	}
+	limits.MaxStreams = maxStreams
+
+	if s, ok := req.Options[idleTimeoutOptionName].(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return limits, fmt.Errorf("invalid --%s: %s", idleTimeoutOptionName, err)
+		}
+		if d < 0 {
+			return limits, fmt.Errorf("--%s must be positive", idleTimeoutOptionName)
+		}
+		limits.IdleTimeout = d
+	}
+	return limits, nil
+}
+
 // checkPort checks whether target multiaddr contains tcp or udp protocol
 // and whether the port is equal to 0
 func checkPort(target ma.Multiaddr) error {
@@ -271,10 +337,10 @@ func checkPort(target ma.Multiaddr) error {
 }
 
 // forwardLocal forwards local connections to a libp2p service
-func forwardLocal(ctx context.Context, p *p2p.P2P, ps pstore.Peerstore, proto protocol.ID, bindAddr ma.Multiaddr, addr *peer.AddrInfo) error {
+func forwardLocal(ctx context.Context, p *p2p.P2P, ps pstore.Peerstore, proto protocol.ID, bindAddr ma.Multiaddr, addr *peer.AddrInfo, limits p2p.Limits) error {
 	ps.AddAddrs(addr.ID, addr.Addrs, pstore.TempAddrTTL)
 	// TODO: return some info
-	_, err := p.ForwardLocal(ctx, addr.ID, proto, bindAddr)
+	_, err := p.ForwardLocal(ctx, addr.ID, proto, bindAddr, limits)
 	return err
 }
 
@@ -300,21 +366,13 @@ var p2pLsCmd = &cmds.Command{
 
 		n.P2P.ListenersLocal.Lock()
 		for _, listener := range n.P2P.ListenersLocal.Listeners {
-			output.Listeners = append(output.Listeners, P2PListenerInfoOutput{
-				Protocol:      string(listener.Protocol()),
-				ListenAddress: listener.ListenAddress().String(),
-				TargetAddress: listener.TargetAddress().String(),
-			})
+			output.Listeners = append(output.Listeners, p2pListenerInfo(n.P2P, listener))
 		}
 		n.P2P.ListenersLocal.Unlock()
 
 		n.P2P.ListenersP2P.Lock()
 		for _, listener := range n.P2P.ListenersP2P.Listeners {
-			output.Listeners = append(output.Listeners, P2PListenerInfoOutput{
-				Protocol:      string(listener.Protocol()),
-				ListenAddress: listener.ListenAddress().String(),
-				TargetAddress: listener.TargetAddress().String(),
-			})
+			output.Listeners = append(output.Listeners, p2pListenerInfo(n.P2P, listener))
 		}
 		n.P2P.ListenersP2P.Unlock()
 
@@ -339,6 +397,25 @@ var p2pLsCmd = &cmds.Command{
 	},
 }
 
+// p2pListenerInfo returns the output of ls for listener.
+func p2pListenerInfo(p *p2p.P2P, listener p2p.Listener) P2PListenerInfoOutput {
+	limits := listener.Limits()
+	info := P2PListenerInfoOutput{
+		Protocol:      string(listener.Protocol()),
+		ListenAddress: listener.ListenAddress().String(),
+		TargetAddress: listener.TargetAddress().String(),
+		MaxStreams:    limits.MaxStreams,
+		Streams:       p.Streams.Active(listener),
+	}
+	for _, allowed := range limits.AllowedPeers {
+		info.AllowedPeers = append(info.AllowedPeers, allowed.String())
+	}
+	if limits.IdleTimeout > 0 {
+		info.IdleTimeout = limits.IdleTimeout.String()
+	}
+	return info
+}
+
 const (
 	p2pAllOptionName           = "all"
 	p2pProtocolOptionName      = "protocol"
@@ -462,6 +539,7 @@ var p2pStreamLsCmd = &cmds.Command{
 
 		n.P2P.Streams.Lock()
 		for id, s := range n.P2P.Streams.Streams {
+			in, out := s.Traffic()
 			output.Streams = append(output.Streams, P2PStreamInfoOutput{
 				HandlerID: strconv.FormatUint(id, 10),
 
@@ -469,6 +547,9 @@ var p2pStreamLsCmd = &cmds.Command{
 
 				OriginAddress: s.OriginAddr.String(),
 				TargetAddress: s.TargetAddr.String(),
+
+				BytesIn:  in,
+				BytesOut: out,
 			})
 		}
 		n.P2P.Streams.Unlock()
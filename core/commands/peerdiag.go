@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	network "github.com/libp2p/go-libp2p-core/network"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	peerDiagCaptureOptionName = "capture"
+)
+
+// PeerDiagEvent is a single entry of the timeline output by 'ipfs diag peer'.
+type PeerDiagEvent struct {
+	Time      time.Time
+	Event     string
+	Direction string        `json:",omitempty"`
+	Addr      string        `json:",omitempty"`
+	Stream    string        `json:",omitempty"`
+	Protocol  string        `json:",omitempty"`
+	Duration  time.Duration `json:",omitempty"`
+
+	// Set on the final "summary" event.
+	BytesIn  int64 `json:",omitempty"`
+	BytesOut int64 `json:",omitempty"`
+	Dropped  int64 `json:",omitempty"`
+}
+
+const (
+	peerDiagConnected    = "connected"
+	peerDiagDisconnected = "disconnected"
+	peerDiagStreamOpen   = "stream-open"
+	peerDiagStreamClose  = "stream-close"
+	peerDiagSummary      = "summary"
+)
+
+// peerDiagBuffer is the number of events that may queue up while being
+// sent to the client; further events are dropped and counted.
+const peerDiagBuffer = 1024
+
+var peerDiagCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Capture a timeline of the activity with a peer.",
+		ShortDescription: `
+'ipfs diag peer' records the connections and streams with a peer for a time
+window, and prints them as they happen. It is meant to debug interoperability
+problems with a specific peer or implementation.
+
+Stream opens are reported before protocol negotiation has completed, so their
+protocol is usually empty; match them with their close by stream ID. Streams
+open when the capture ends are not reported as closed.
+
+The final summary has the bytes exchanged with the peer during the capture,
+and the number of events dropped if the client could not keep up. Bytes are
+only counted when Swarm.DisableBandwidthMetrics is false.
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("peer", true, false, "ID of the peer to watch."),
+	},
+	Options: []cmds.Option{
+		cmds.StringOption(peerDiagCaptureOptionName, "Duration of the capture.").WithDefault("30s"),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+
+		if !n.IsOnline {
+			return ErrNotOnline
+		}
+
+		pid, err := peer.Decode(req.Arguments[0])
+		if err != nil {
+			return fmt.Errorf("invalid peer ID: %w", err)
+		}
+
+		captureStr, _ := req.Options[peerDiagCaptureOptionName].(string)
+		capture, err := time.ParseDuration(captureStr)
+		if err != nil {
+			return err
+		}
+		if capture <= 0 {
+			return fmt.Errorf("capture duration must be positive, was %s", capture)
+		}
+
+		events := make(chan *PeerDiagEvent, peerDiagBuffer)
+		var dropped int64
+		emit := func(ev *PeerDiagEvent) {
+			ev.Time = time.Now()
+			select {
+			case events <- ev:
+			default:
+				atomic.AddInt64(&dropped, 1)
+			}
+		}
+
+		notifiee := &network.NotifyBundle{
+			ConnectedF: func(_ network.Network, c network.Conn) {
+				if c.RemotePeer() == pid {
+					emit(&PeerDiagEvent{Event: peerDiagConnected, Direction: c.Stat().Direction.String(), Addr: c.RemoteMultiaddr().String()})
+				}
+			},
+			DisconnectedF: func(_ network.Network, c network.Conn) {
+				if c.RemotePeer() == pid {
+					emit(&PeerDiagEvent{Event: peerDiagDisconnected, Direction: c.Stat().Direction.String(), Addr: c.RemoteMultiaddr().String()})
+				}
+			},
+			OpenedStreamF: func(_ network.Network, s network.Stream) {
+				if s.Conn().RemotePeer() == pid {
+					emit(&PeerDiagEvent{Event: peerDiagStreamOpen, Direction: s.Stat().Direction.String(), Stream: s.ID(), Protocol: string(s.Protocol())})
+				}
+			},
+			ClosedStreamF: func(_ network.Network, s network.Stream) {
+				if s.Conn().RemotePeer() == pid {
+					emit(&PeerDiagEvent{Event: peerDiagStreamClose, Direction: s.Stat().Direction.String(), Stream: s.ID(), Protocol: string(s.Protocol()), Duration: time.Since(s.Stat().Opened)})
+				}
+			},
+		}
+
+		var startIn, startOut int64
+		if n.Reporter != nil {
+			stats := n.Reporter.GetBandwidthForPeer(pid)
+			startIn, startOut = stats.TotalIn, stats.TotalOut
+		}
+
+		n.PeerHost.Network().Notify(notifiee)
+		timer := time.NewTimer(capture)
+		defer timer.Stop()
+
+	loop:
+		for {
+			select {
+			case ev := <-events:
+				if err := res.Emit(ev); err != nil {
+					n.PeerHost.Network().StopNotify(notifiee)
+					return err
+				}
+			case <-timer.C:
+				break loop
+			case <-req.Context.Done():
+				n.PeerHost.Network().StopNotify(notifiee)
+				return req.Context.Err()
+			}
+		}
+		n.PeerHost.Network().StopNotify(notifiee)
+
+		// StopNotify waits for the notifications in flight, so nothing is
+		// sent on events anymore.
+		close(events)
+		for ev := range events {
+			if err := res.Emit(ev); err != nil {
+				return err
+			}
+		}
+
+		summary := &PeerDiagEvent{Time: time.Now(), Event: peerDiagSummary, Dropped: atomic.LoadInt64(&dropped)}
+		if n.Reporter != nil {
+			stats := n.Reporter.GetBandwidthForPeer(pid)
+			summary.BytesIn = stats.TotalIn - startIn
+			summary.BytesOut = stats.TotalOut - startOut
+		}
+		return res.Emit(summary)
+	},
+	Type: PeerDiagEvent{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, ev *PeerDiagEvent) error {
+			ts := ev.Time.Format("15:04:05.000")
+			var err error
+			switch ev.Event {
+			case peerDiagConnected, peerDiagDisconnected:
+				_, err = fmt.Fprintf(w, "%s %-12s %-8s %s\n", ts, ev.Event, ev.Direction, ev.Addr)
+			case peerDiagStreamOpen:
+				_, err = fmt.Fprintf(w, "%s %-12s %-8s stream=%s %s\n", ts, ev.Event, ev.Direction, ev.Stream, ev.Protocol)
+			case peerDiagStreamClose:
+				_, err = fmt.Fprintf(w, "%s %-12s %-8s stream=%s %s (%s)\n", ts, ev.Event, ev.Direction, ev.Stream, ev.Protocol, ev.Duration.Round(time.Millisecond))
+			case peerDiagSummary:
+				_, err = fmt.Fprintf(w, "%s %-12s in=%d out=%d dropped=%d\n", ts, ev.Event, ev.BytesIn, ev.BytesOut, ev.Dropped)
+			}
+			return err
+		}),
+	},
+}
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
+
+	cid "github.com/ipfs/go-cid"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	path "github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+var PetnameCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Manage local names for content paths.",
+		ShortDescription: `
+Petnames are local names for content paths, accepted by the commands wherever
+they take a path. The petname can be followed by a path below the content:
+
+  > ipfs petname set docs /ipfs/bafybeihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku
+  > ipfs cat docs/index.html
+
+Petnames are kept in the Petnames.Paths config field and take effect
+immediately, without restarting the daemon. They are only resolved by this
+node: they are not published to the network.
+
+Set Petnames.GatewayPrefix to make the gateway redirect the requests for a
+petname under that prefix, e.g. /petname/docs/index.html, to its content
+path. To redirect the gateway requests for content that has moved, see
+'ipfs gateway alias'.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"set": petnameSetCmd,
+		"rm":  petnameRmCmd,
+		"ls":  petnameLsCmd,
+	},
+}
+
+// Petname is a single Petnames.Paths entry.
+type Petname struct {
+	Name string
+	Path string
+}
+
+// PetnameList is the output of 'ipfs petname ls'.
+type PetnameList struct {
+	Petnames []Petname
+}
+
+func checkPetname(name string) error {
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid petname %q: expected a non-empty name without slashes", name)
+	}
+	if _, err := cid.Decode(name); err == nil {
+		return fmt.Errorf("invalid petname %q: a CID can't be used as a name", name)
+	}
+	return nil
+}
+
+var petnameSetCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Set the content path of a petname.",
+		ShortDescription: "Adds the petname <name>, or points it to another content path.",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("name", true, false, "The petname."),
+		cmds.StringArg("path", true, false, "Content path the petname stands for, /ipfs/ or /ipns/."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		name := req.Arguments[0]
+		if err := checkPetname(name); err != nil {
+			return err
+		}
+		p := path.New(strings.TrimRight(req.Arguments[1], "/"))
+		if err := p.IsValid(); err != nil {
+			return fmt.Errorf("invalid content path %q: %w", req.Arguments[1], err)
+		}
+
+		cfgRoot, err := cmdenv.GetConfigRoot(env)
+		if err != nil {
+			return err
+		}
+		r, err := fsrepo.Open(cfgRoot)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		cfg, err := r.Config()
+		if err != nil {
+			return err
+		}
+
+		if cfg.Petnames.Paths == nil {
+			cfg.Petnames.Paths = map[string]string{}
+		}
+		cfg.Petnames.Paths[name] = p.String()
+		if err := r.SetConfig(cfg); err != nil {
+			return err
+		}
+
+		return cmds.EmitOnce(res, &Petname{Name: name, Path: p.String()})
+	},
+	Type: Petname{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *Petname) error {
+			_, err := fmt.Fprintf(w, "set %s -> %s\n", out.Name, out.Path)
+			return err
+		}),
+	},
+}
+
+var petnameRmCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Remove a petname.",
+		ShortDescription: "Removes the petname <name>.",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("name", true, false, "The petname to remove."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		name := req.Arguments[0]
+
+		cfgRoot, err := cmdenv.GetConfigRoot(env)
+		if err != nil {
+			return err
+		}
+		r, err := fsrepo.Open(cfgRoot)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		cfg, err := r.Config()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := cfg.Petnames.Paths[name]; !ok {
+			return fmt.Errorf("no petname %s", name)
+		}
+		delete(cfg.Petnames.Paths, name)
+		return r.SetConfig(cfg)
+	},
+}
+
+var petnameLsCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "List petnames.",
+		ShortDescription: "Lists the petnames and the content paths they stand for.",
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		cfgRoot, err := cmdenv.GetConfigRoot(env)
+		if err != nil {
+			return err
+		}
+		r, err := fsrepo.Open(cfgRoot)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		cfg, err := r.Config()
+		if err != nil {
+			return err
+		}
+
+		out := &PetnameList{Petnames: make([]Petname, 0, len(cfg.Petnames.Paths))}
+		for name, p := range cfg.Petnames.Paths {
+			out.Petnames = append(out.Petnames, Petname{Name: name, Path: p})
+		}
+		sort.Slice(out.Petnames, func(i, j int) bool {
+			return out.Petnames[i].Name < out.Petnames[j].Name
+		})
+		return cmds.EmitOnce(res, out)
+	},
+	Type: PetnameList{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *PetnameList) error {
+			tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+			for _, a := range out.Petnames {
+				fmt.Fprintf(tw, "%s\t-> %s\n", a.Name, a.Path)
+			}
+			return tw.Flush()
+		}),
+	},
+}
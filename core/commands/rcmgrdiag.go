@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/core/node/libp2p"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+// rcmgrDumpCBOR is the encoding of 'ipfs diag rcmgr dump --enc=cbor'.
+const rcmgrDumpCBOR = cmds.EncodingType("cbor")
+
+var rcmgrDiagCmd = &cmds.Command{
+	Status: cmds.Experimental,
+	Helptext: cmds.HelpText{
+		Tagline: "Inspect the libp2p resource manager.",
+	},
+	Subcommands: map[string]*cmds.Command{
+		"dump": rcmgrDumpCmd,
+	},
+}
+
+var rcmgrDumpCmd = &cmds.Command{
+	Status: cmds.Experimental,
+	Helptext: cmds.HelpText{
+		Tagline: "Dump the usage of every resource manager scope.",
+		ShortDescription: `
+'ipfs diag rcmgr dump' takes a snapshot of the resource usage of the system
+and transient scopes, and of every service, protocol and peer scope the
+resource manager currently tracks. Attach it to bug reports about resource
+exhaustion.
+
+The output is JSON, or DAG-CBOR with --enc=cbor:
+
+  ipfs diag rcmgr dump --enc=cbor > rcmgr.cbor
+`,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		node, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+
+		if node.ResourceManager == nil {
+			return libp2p.NoResourceMgrError
+		}
+
+		result, err := libp2p.NetDump(node.ResourceManager)
+		if err != nil {
+			return err
+		}
+		return cmds.EmitOnce(res, &result)
+	},
+	Type: libp2p.NetDumpOut{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *libp2p.NetDumpOut) error {
+			buf, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return err
+			}
+			buf = append(buf, '\n')
+			_, err = w.Write(buf)
+			return err
+		}),
+		rcmgrDumpCBOR: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *libp2p.NetDumpOut) error {
+			buf, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			nb := basicnode.Prototype.Any.NewBuilder()
+			if err := dagjson.Decode(nb, bytes.NewReader(buf)); err != nil {
+				return err
+			}
+			return dagcbor.Encode(nb.Build(), w)
+		}),
+	},
+}
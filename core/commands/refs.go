@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/walkpool"
 
 	cid "github.com/ipfs/go-cid"
 	cidenc "github.com/ipfs/go-cidutil/cidenc"
@@ -79,6 +80,10 @@ NOTE: List all references recursively by using the flag '-r'.
 		if err != nil {
 			return err
 		}
+		nd, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
 
 		enc, err := cmdenv.GetCidEncoder(req)
 		if err != nil {
@@ -111,7 +116,7 @@ NOTE: List all references recursively by using the flag '-r'.
 
 		rw := RefWriter{
 			res:      res,
-			DAG:      merkledag.NewSession(ctx, api.Dag()),
+			DAG:      nd.WalkPool.NodeGetter(walkpool.OpRefs, merkledag.NewSession(ctx, api.Dag())),
 			Ctx:      ctx,
 			Unique:   unique,
 			PrintFmt: format,
@@ -14,6 +14,7 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
 	corerepo "github.com/ipfs/go-ipfs/core/corerepo"
+	gc "github.com/ipfs/go-ipfs/gc"
 	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
 
 	cid "github.com/ipfs/go-cid"
@@ -34,17 +35,19 @@ var RepoCmd = &cmds.Command{
 	},
 
 	Subcommands: map[string]*cmds.Command{
-		"stat":    repoStatCmd,
-		"gc":      repoGcCmd,
-		"fsck":    repoFsckCmd,
-		"version": repoVersionCmd,
-		"verify":  repoVerifyCmd,
+		"stat":       repoStatCmd,
+		"gc":         repoGcCmd,
+		"fsck":       repoFsckCmd,
+		"version":    repoVersionCmd,
+		"verify":     repoVerifyCmd,
+		"quarantine": repoQuarantineCmd,
 	},
 }
 
 // GcResult is the result returned by "repo gc" command.
 type GcResult struct {
 	Key   cid.Cid
+	Kept  bool   `json:",omitempty"`
 	Error string `json:",omitempty"`
 }
 
@@ -61,6 +64,9 @@ var repoGcCmd = &cmds.Command{
 'ipfs repo gc' is a plumbing command that will sweep the local
 set of stored objects and remove ones that are not pinned in
 order to reclaim hard disk space.
+
+Unpinned blocks of the codecs listed in Datastore.GCKeepCodecs are kept,
+and reported as such.
 `,
 	},
 	Options: []cmds.Option{
@@ -87,6 +93,10 @@ order to reclaim hard disk space.
 						return err
 					}
 					errs = true
+				} else if res.KeyKept.Defined() {
+					if err := re.Emit(&GcResult{Key: res.KeyKept, Kept: true}); err != nil {
+						return err
+					}
 				} else {
 					if err := re.Emit(&GcResult{Key: res.KeyRemoved}); err != nil {
 						return err
@@ -97,7 +107,26 @@ order to reclaim hard disk space.
 				return errors.New("encountered errors during gc run")
 			}
 		} else {
-			err := corerepo.CollectResult(req.Context, gcOutChan, func(k cid.Cid) {
+			// CollectResult only reports removed blocks, the kept ones
+			// are reported once it is done.
+			results := make(chan gc.Result)
+			var kept []cid.Cid
+			go func() {
+				defer close(results)
+				for res := range gcOutChan {
+					if res.KeyKept.Defined() {
+						kept = append(kept, res.KeyKept)
+						continue
+					}
+					select {
+					case results <- res:
+					case <-req.Context.Done():
+						return
+					}
+				}
+			}()
+
+			err := corerepo.CollectResult(req.Context, results, func(k cid.Cid) {
 				if silent {
 					return
 				}
@@ -109,6 +138,13 @@ order to reclaim hard disk space.
 			if err != nil {
 				return err
 			}
+			if !silent {
+				for _, k := range kept {
+					if err := re.Emit(&GcResult{Key: k, Kept: true}); err != nil {
+						return err
+					}
+				}
+			}
 		}
 
 		return nil
@@ -129,7 +165,12 @@ order to reclaim hard disk space.
 			}
 
 			prefix := "removed "
-			if quiet {
+			if gcr.Kept {
+				if quiet {
+					return nil
+				}
+				prefix = "kept "
+			} else if quiet {
 				prefix = ""
 			}
 
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/quarantine"
+
+	cid "github.com/ipfs/go-cid"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+var repoQuarantineCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Inspect the blocks quarantined for failing validation.",
+		ShortDescription: `
+When a block read from the repo doesn't match its hash or can't be decoded,
+it is moved to the quarantine and fetched again from the network. This can
+be disabled with Datastore.Quarantine. Each incident is also recorded in the
+event journal, see 'ipfs journal ls --type=quarantine'.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"ls": repoQuarantineLsCmd,
+		"rm": repoQuarantineRmCmd,
+	},
+}
+
+var repoQuarantineLsCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "List the quarantined blocks.",
+		ShortDescription: `
+'ipfs repo quarantine ls' lists the blocks moved to the quarantine, with the
+reason they failed validation and whether a valid copy was fetched again.
+`,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+
+		entries, err := quarantine.List(req.Context, n.Repo.Datastore())
+		if err != nil {
+			return err
+		}
+		for i := range entries {
+			if err := res.Emit(&entries[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Type: quarantine.Entry{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, e *quarantine.Entry) error {
+			refetched := "not refetched"
+			if e.Refetched {
+				refetched = "refetched"
+			}
+			_, err := fmt.Fprintf(w, "%s %s %s: %s\n", e.Time.Format(time.RFC3339), e.Cid, refetched, e.Reason)
+			return err
+		}),
+	},
+}
+
+var repoQuarantineRmCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Remove blocks from the quarantine.",
+		ShortDescription: `
+'ipfs repo quarantine rm' deletes the quarantined copies of the given blocks.
+It doesn't affect the blocks in the blockstore.
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("cid", true, true, "CIDs of the quarantined blocks to remove."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range req.Arguments {
+			c, err := cid.Decode(arg)
+			if err != nil {
+				return fmt.Errorf("invalid CID %q: %w", arg, err)
+			}
+			if err := quarantine.Remove(req.Context, n.Repo.Datastore(), c); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -61,6 +61,9 @@ ADVANCED COMMANDS
   stats         Various operational stats
   p2p           Libp2p stream mounting (experimental)
   filestore     Manage the filestore (experimental)
+  gateway       Manage the HTTP gateway
+  petname       Manage local names for content paths
+  standby       Manage the warm standby of a primary node
 
 NETWORK COMMANDS
   id            Show info about IPFS peers
@@ -70,11 +73,14 @@ NETWORK COMMANDS
   ping          Measure the latency of a connection
   bitswap       Inspect bitswap state
   pubsub        Send and receive messages via pubsub
+  routing       Inspect the routing system
 
 TOOL COMMANDS
   config        Manage configuration
   version       Show IPFS version information
   diag          Generate diagnostic reports
+  journal       Inspect the event journal
+  benchmark     Measure the performance of the node
   update        Download and apply go-ipfs updates
   commands      List all available commands
   log           Manage and show logs of running daemon
@@ -121,12 +127,14 @@ var CommandsDaemonCmd = CommandsCmd(Root)
 
 var rootSubcommands = map[string]*cmds.Command{
 	"add":       AddCmd,
+	"benchmark": BenchmarkCmd,
 	"bitswap":   BitswapCmd,
 	"block":     BlockCmd,
 	"cat":       CatCmd,
 	"commands":  CommandsDaemonCmd,
 	"files":     FilesCmd,
 	"filestore": FileStoreCmd,
+	"gateway":   GatewayCmd,
 	"get":       GetCmd,
 	"pubsub":    PubsubCmd,
 	"repo":      RepoCmd,
@@ -138,6 +146,7 @@ var rootSubcommands = map[string]*cmds.Command{
 	"diag":      DiagCmd,
 	"dns":       DNSCmd,
 	"id":        IDCmd,
+	"journal":   JournalCmd,
 	"key":       KeyCmd,
 	"log":       LogCmd,
 	"ls":        LsCmd,
@@ -147,8 +156,11 @@ var rootSubcommands = map[string]*cmds.Command{
 	"pin":       pin.PinCmd,
 	"ping":      PingCmd,
 	"p2p":       P2PCmd,
+	"petname":   PetnameCmd,
 	"refs":      RefsCmd,
 	"resolve":   ResolveCmd,
+	"routing":   RoutingCmd,
+	"standby":   StandbyCmd,
 	"swarm":     SwarmCmd,
 	"tar":       TarCmd,
 	"file":      unixfs.UnixFSCmd,
@@ -171,6 +183,12 @@ var RefsROCmd = &cmds.Command{}
 // VersionROCmd is `ipfs version` command (without deps).
 var VersionROCmd = &cmds.Command{}
 
+// RootInspect is the command set of the read-only API listeners: the
+// commands of RootRO, plus the commands inspecting the state of the node.
+var RootInspect = &cmds.Command{}
+
+var CommandsDaemonInspectCmd = CommandsCmd(RootInspect)
+
 var rootROSubcommands = map[string]*cmds.Command{
 	"commands": CommandsDaemonROCmd,
 	"cat":      CatCmd,
@@ -201,6 +219,7 @@ var rootROSubcommands = map[string]*cmds.Command{
 			"get":     dag.DagGetCmd,
 			"resolve": dag.DagResolveCmd,
 			"stat":    dag.DagStatCmd,
+			"walk":    dag.DagWalkCmd,
 			"export":  dag.DagExportCmd,
 		},
 	},
@@ -227,6 +246,45 @@ func init() {
 
 	Root.Subcommands = rootSubcommands
 	RootRO.Subcommands = rootROSubcommands
+
+	*RootInspect = *Root
+	RootInspect.Subcommands = map[string]*cmds.Command{}
+	for name, cmd := range rootROSubcommands {
+		RootInspect.Subcommands[name] = cmd
+	}
+	for name, cmd := range map[string]*cmds.Command{
+		"commands": CommandsDaemonInspectCmd,
+		"id":       IDCmd,
+		"stats":    StatsCmd,
+		"version":  VersionCmd,
+		"bitswap": {
+			Subcommands: map[string]*cmds.Command{
+				"stat":     bitswapStatCmd,
+				"wantlist": showWantlistCmd,
+				"ledger":   ledgerCmd,
+			},
+		},
+		"pin": {
+			Subcommands: map[string]*cmds.Command{
+				"ls": pin.PinCmd.Subcommands["ls"],
+			},
+		},
+		"repo": {
+			Subcommands: map[string]*cmds.Command{
+				"stat":    repoStatCmd,
+				"version": repoVersionCmd,
+			},
+		},
+		"swarm": {
+			Subcommands: map[string]*cmds.Command{
+				"addrs": swarmAddrsCmd,
+				"peers": swarmPeersCmd,
+				"stats": swarmStatsCmd,
+			},
+		},
+	} {
+		RootInspect.Subcommands[name] = cmd
+	}
 }
 
 type MessageOutput struct {
@@ -0,0 +1,441 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/core/node/libp2p"
+	"github.com/ipfs/go-ipfs/routingtrace"
+
+	cid "github.com/ipfs/go-cid"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	routing "github.com/libp2p/go-libp2p-core/routing"
+)
+
+var RoutingCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Inspect the routing system.",
+		ShortDescription: `
+'ipfs routing' inspects how content and peers are found: it traces DHT
+lookups, finds providers with all the routers of the node and reports the
+health of the routers.
+`,
+	},
+
+	Subcommands: map[string]*cmds.Command{
+		"trace":     traceRoutingCmd,
+		"findprovs": findProvidersRoutingCmd,
+		"stat":      statRoutingCmd,
+	},
+}
+
+// Results of the peers contacted during a traced lookup.
+const (
+	routingTraceResponse   = "response"
+	routingTraceError      = "error"
+	routingTraceNoResponse = "no-response"
+	routingTraceNotQueried = "not-queried"
+)
+
+// RoutingTraceHop is a peer contacted during a traced lookup.
+type RoutingTraceHop struct {
+	Peer peer.ID
+	// ReferredBy is the peer that returned Peer as one of its closer peers.
+	// It is empty for the peers taken from the routing table.
+	ReferredBy  peer.ID `json:",omitempty"`
+	Result      string
+	RTT         time.Duration `json:",omitempty"`
+	CloserPeers []peer.ID     `json:",omitempty"`
+	Error       string        `json:",omitempty"`
+}
+
+// RoutingTrace is the output of 'ipfs routing trace'.
+type RoutingTrace struct {
+	Key       string
+	Duration  time.Duration
+	Hops      []*RoutingTraceHop
+	Closest   []peer.ID `json:",omitempty"`
+	Providers []peer.ID `json:",omitempty"`
+	Error     string    `json:",omitempty"`
+}
+
+const (
+	routingTraceProvidersOptionName = "providers"
+)
+
+var traceRoutingCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Trace a DHT lookup, hop by hop.",
+		ShortDescription: `
+'ipfs routing trace' looks up the peers closest to a peer ID on the DHT, and
+reports every peer contacted along the way: the round trip time of its
+answer, or the error it failed with, and the closer peers it returned. Peers
+are shown under the peer that referred them, the first ones are taken from
+the routing table.
+
+With --providers, the key is a CID and its providers are looked up instead.
+
+Peers the lookup dialed but did not get to query before it ended are
+reported as not-queried, and peers queried without an answer as
+no-response.
+`,
+	},
+
+	Arguments: []cmds.Argument{
+		cmds.StringArg("key", true, false, "The peer ID, or the CID with --providers, to look up."),
+	},
+	Options: []cmds.Option{
+		cmds.BoolOption(routingTraceProvidersOptionName, "p", "Look up the providers of a CID."),
+		cmds.IntOption(numProvidersOptionName, "n", "The number of providers to find.").WithDefault(20),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		nd, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+
+		if nd.DHTClient == nil {
+			return ErrNotDHT
+		}
+
+		client := nd.DHTClient
+		if client == nd.DHT {
+			client = nd.DHT.WAN
+			if !nd.DHT.WANActive() {
+				client = nd.DHT.LAN
+			}
+		}
+
+		providers, _ := req.Options[routingTraceProvidersOptionName].(bool)
+		numProviders, _ := req.Options[numProvidersOptionName].(int)
+
+		var lookup func(context.Context, *RoutingTrace) error
+		if providers {
+			c, err := cid.Parse(req.Arguments[0])
+			if err != nil {
+				return cmds.ClientError("invalid CID")
+			}
+			if numProviders < 1 {
+				return fmt.Errorf("number of providers must be greater than 0")
+			}
+			lookup = func(ctx context.Context, trace *RoutingTrace) error {
+				for p := range client.FindProvidersAsync(ctx, c, numProviders) {
+					trace.Providers = append(trace.Providers, p.ID)
+				}
+				return nil
+			}
+		} else {
+			id, err := peer.Decode(req.Arguments[0])
+			if err != nil {
+				return cmds.ClientError("invalid peer ID")
+			}
+			d, ok := client.(kademlia)
+			if !ok {
+				return fmt.Errorf("dht client does not support GetClosestPeers")
+			}
+			lookup = func(ctx context.Context, trace *RoutingTrace) error {
+				closest, err := d.GetClosestPeers(ctx, string(id))
+				trace.Closest = closest
+				return err
+			}
+		}
+
+		trace := &RoutingTrace{Key: req.Arguments[0]}
+
+		ctx, cancel := context.WithCancel(req.Context)
+		defer cancel()
+		ctx, events := routing.RegisterForQueryEvents(ctx)
+
+		start := time.Now()
+		errCh := make(chan error, 1)
+		go func() {
+			defer cancel()
+			errCh <- lookup(ctx, trace)
+		}()
+
+		collectRoutingTrace(trace, events)
+
+		if err := <-errCh; err != nil {
+			trace.Error = err.Error()
+		}
+		trace.Duration = time.Since(start)
+		return cmds.EmitOnce(res, trace)
+	},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *RoutingTrace) error {
+			writeRoutingTrace(w, out)
+			return nil
+		}),
+	},
+	Type: RoutingTrace{},
+}
+
+// collectRoutingTrace adds the peers contacted in the query events of a
+// lookup to trace, until events is closed.
+func collectRoutingTrace(trace *RoutingTrace, events <-chan *routing.QueryEvent) {
+	hops := make(map[peer.ID]*RoutingTraceHop)
+	referrers := make(map[peer.ID]peer.ID)
+	sent := make(map[peer.ID]time.Time)
+	hop := func(p peer.ID) *RoutingTraceHop {
+		h, ok := hops[p]
+		if !ok {
+			h = &RoutingTraceHop{Peer: p, Result: routingTraceNotQueried, ReferredBy: referrers[p]}
+			hops[p] = h
+			trace.Hops = append(trace.Hops, h)
+		}
+		return h
+	}
+	for e := range events {
+		if e.ID == "" {
+			continue
+		}
+		switch e.Type {
+		case routing.DialingPeer:
+			hop(e.ID)
+		case routing.SendingQuery:
+			hop(e.ID).Result = routingTraceNoResponse
+			sent[e.ID] = time.Now()
+		case routing.PeerResponse:
+			h := hop(e.ID)
+			h.Result = routingTraceResponse
+			if t, ok := sent[e.ID]; ok {
+				h.RTT = time.Since(t)
+			}
+			for _, ai := range e.Responses {
+				h.CloserPeers = append(h.CloserPeers, ai.ID)
+				if _, ok := referrers[ai.ID]; !ok && ai.ID != e.ID {
+					referrers[ai.ID] = e.ID
+				}
+			}
+		case routing.QueryError:
+			h := hop(e.ID)
+			h.Result = routingTraceError
+			h.Error = e.Extra
+		}
+	}
+}
+
+// writeRoutingTrace writes the hops of out as a tree, each peer under the
+// peer that referred it.
+func writeRoutingTrace(w io.Writer, out *RoutingTrace) {
+	children := make(map[peer.ID][]*RoutingTraceHop)
+	contacted := make(map[peer.ID]bool, len(out.Hops))
+	for _, h := range out.Hops {
+		contacted[h.Peer] = true
+	}
+	var roots []*RoutingTraceHop
+	for _, h := range out.Hops {
+		if h.ReferredBy == "" || !contacted[h.ReferredBy] {
+			roots = append(roots, h)
+		} else {
+			children[h.ReferredBy] = append(children[h.ReferredBy], h)
+		}
+	}
+
+	var printHop func(h *RoutingTraceHop, depth int)
+	printHop = func(h *RoutingTraceHop, depth int) {
+		fmt.Fprintf(w, "%s%s %s", strings.Repeat("  ", depth), h.Peer, h.Result)
+		switch h.Result {
+		case routingTraceResponse:
+			fmt.Fprintf(w, " in %s, %d closer peers", h.RTT.Round(time.Millisecond), len(h.CloserPeers))
+		case routingTraceError:
+			fmt.Fprintf(w, ": %s", h.Error)
+		}
+		fmt.Fprintln(w)
+		for _, c := range children[h.Peer] {
+			printHop(c, depth+1)
+		}
+	}
+	for _, h := range roots {
+		printHop(h, 0)
+	}
+
+	fmt.Fprintf(w, "\n%d peers contacted in %s\n", len(out.Hops), out.Duration.Round(time.Millisecond))
+	if len(out.Closest) > 0 {
+		fmt.Fprintln(w, "closest peers:")
+		for _, p := range out.Closest {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+	}
+	if len(out.Providers) > 0 {
+		fmt.Fprintln(w, "providers:")
+		for _, p := range out.Providers {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+	}
+	if out.Error != "" {
+		fmt.Fprintf(w, "error: %s\n", out.Error)
+	}
+}
+
+// RoutingFindProvsOutput is an output of 'ipfs routing findprovs': a provider,
+// and last the routers queried with --trace.
+type RoutingFindProvsOutput struct {
+	Provider *peer.AddrInfo       `json:",omitempty"`
+	Trace    []routingtrace.Query `json:",omitempty"`
+}
+
+const (
+	routingTraceOptionName = "trace"
+)
+
+var findProvidersRoutingCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Find the providers of a CID with all the routers.",
+		ShortDescription: `
+'ipfs routing findprovs' looks up the providers of a CID with all the routers
+of the node, as content is looked up, and outputs them as they are found.
+
+With --trace, it then reports the routers queried, in the order they were
+queried, how long each took to answer, and the providers each found, to debug
+how the routers are combined.
+`,
+	},
+
+	Arguments: []cmds.Argument{
+		cmds.StringArg("cid", true, false, "The CID to find the providers of."),
+	},
+	Options: []cmds.Option{
+		cmds.IntOption(numProvidersOptionName, "n", "The number of providers to find.").WithDefault(20),
+		cmds.BoolOption(routingTraceOptionName, "Report the routers queried."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		nd, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		if !nd.IsOnline {
+			return ErrNotOnline
+		}
+
+		numProviders, _ := req.Options[numProvidersOptionName].(int)
+		if numProviders < 1 {
+			return fmt.Errorf("number of providers must be greater than 0")
+		}
+		c, err := cid.Parse(req.Arguments[0])
+		if err != nil {
+			return cmds.ClientError("invalid CID")
+		}
+
+		ctx := req.Context
+		var trace *routingtrace.Trace
+		if traced, _ := req.Options[routingTraceOptionName].(bool); traced {
+			ctx, trace = routingtrace.WithTrace(ctx)
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		for p := range nd.Routing.FindProvidersAsync(ctx, c, numProviders) {
+			p := p
+			if err := res.Emit(&RoutingFindProvsOutput{Provider: &p}); err != nil {
+				return err
+			}
+		}
+		if trace == nil {
+			return nil
+		}
+		return res.Emit(&RoutingFindProvsOutput{Trace: trace.Queries()})
+	},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *RoutingFindProvsOutput) error {
+			if out.Provider != nil {
+				fmt.Fprintln(w, out.Provider.ID)
+				return nil
+			}
+
+			fmt.Fprintln(w, "\nrouters queried:")
+			for _, q := range out.Trace {
+				fmt.Fprintf(w, "  +%s %s %s: %d providers in %s", q.Start.Round(time.Millisecond), q.Router, q.Method, len(q.Peers), q.Latency.Round(time.Millisecond))
+				if q.Error != "" {
+					fmt.Fprintf(w, " (%s)", q.Error)
+				}
+				fmt.Fprintln(w)
+				for _, p := range q.Peers {
+					fmt.Fprintf(w, "    %s\n", p)
+				}
+			}
+			return nil
+		}),
+	},
+	Type: RoutingFindProvsOutput{},
+}
+
+// RoutingStatOutput is the health of the routers.
+type RoutingStatOutput struct {
+	Routers []libp2p.RouterHealth
+	// ReadyRouters are the routers that must be healthy for the node to be
+	// ready, and Ready whether they are.
+	ReadyRouters []string `json:",omitempty"`
+	Ready        bool
+}
+
+var statRoutingCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Show the health of the routers.",
+		ShortDescription: `
+'ipfs routing stat' shows the health of each router of the node, in the order
+they are queried, as found by its last probe.
+
+The routers are probed periodically with a lookup of the node itself, as
+configured in Routing.HealthCheck. A router answering it is healthy, even
+without finding the node. The routers that can't find peers are unsupported,
+and never probed. The probes are only enabled by default when
+Routing.HealthCheck.ReadyRouters is set.
+`,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		nd, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		if !nd.IsOnline {
+			return ErrNotOnline
+		}
+		if nd.RoutingHealth == nil {
+			return fmt.Errorf("the routers are not probed, set Routing.HealthCheck.Enabled to true")
+		}
+
+		return cmds.EmitOnce(res, &RoutingStatOutput{
+			Routers:      nd.RoutingHealth.Status(),
+			ReadyRouters: nd.RoutingHealth.ReadyRouters(),
+			Ready:        nd.RoutingHealth.Ready(),
+		})
+	},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *RoutingStatOutput) error {
+			width := 0
+			for _, r := range out.Routers {
+				if len(r.Name) > width {
+					width = len(r.Name)
+				}
+			}
+			for _, r := range out.Routers {
+				fmt.Fprintf(w, "%-*s  %s", width, r.Name, r.Status)
+				if !r.LastProbe.IsZero() {
+					fmt.Fprintf(w, ", probed %s ago in %s", time.Since(r.LastProbe).Round(time.Second), r.Latency.Round(time.Millisecond))
+				}
+				if r.Status == libp2p.RouterUnhealthy {
+					if r.LastHealthy.IsZero() {
+						fmt.Fprint(w, ", never healthy")
+					} else {
+						fmt.Fprintf(w, ", healthy %s ago", time.Since(r.LastHealthy).Round(time.Second))
+					}
+				}
+				if r.Error != "" {
+					fmt.Fprintf(w, ": %s", r.Error)
+				}
+				fmt.Fprintln(w)
+			}
+			if len(out.ReadyRouters) > 0 {
+				fmt.Fprintf(w, "ready: %t (needs %s)\n", out.Ready, strings.Join(out.ReadyRouters, ", "))
+			}
+			return nil
+		}),
+	},
+	Type: RoutingStatOutput{},
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	"github.com/libp2p/go-libp2p-core/test"
+)
+
+func TestRoutingTrace(t *testing.T) {
+	first, second, closer, failing, dialed := test.RandPeerIDFatal(t), test.RandPeerIDFatal(t), test.RandPeerIDFatal(t), test.RandPeerIDFatal(t), test.RandPeerIDFatal(t)
+
+	events := make(chan *routing.QueryEvent, 16)
+	for _, e := range []*routing.QueryEvent{
+		{Type: routing.DialingPeer, ID: first},
+		{Type: routing.SendingQuery, ID: first},
+		{Type: routing.SendingQuery, ID: second},
+		{Type: routing.PeerResponse, ID: first, Responses: []*peer.AddrInfo{{ID: closer}, {ID: failing}, {ID: first}}},
+		{Type: routing.SendingQuery, ID: closer},
+		{Type: routing.PeerResponse, ID: closer, Responses: []*peer.AddrInfo{{ID: dialed}}},
+		{Type: routing.QueryError, ID: failing, Extra: "connection refused"},
+		{Type: routing.DialingPeer, ID: dialed},
+		{Type: routing.Value},
+	} {
+		events <- e
+	}
+	close(events)
+
+	trace := &RoutingTrace{Key: first.String()}
+	collectRoutingTrace(trace, events)
+
+	expected := []RoutingTraceHop{
+		{Peer: first, Result: routingTraceResponse, CloserPeers: []peer.ID{closer, failing, first}},
+		{Peer: second, Result: routingTraceNoResponse},
+		{Peer: closer, ReferredBy: first, Result: routingTraceResponse, CloserPeers: []peer.ID{dialed}},
+		{Peer: failing, ReferredBy: first, Result: routingTraceError, Error: "connection refused"},
+		{Peer: dialed, ReferredBy: closer, Result: routingTraceNotQueried},
+	}
+	if len(trace.Hops) != len(expected) {
+		t.Fatalf("expected %d hops, got %d", len(expected), len(trace.Hops))
+	}
+	for i, h := range trace.Hops {
+		got := *h
+		got.RTT = 0
+		if fmt.Sprint(got) != fmt.Sprint(expected[i]) {
+			t.Errorf("expected hop %d to be %v, got %v", i, expected[i], got)
+		}
+	}
+	if trace.Hops[2].RTT == 0 && trace.Hops[0].RTT == 0 {
+		t.Error("expected the round trip times of the answers to be set")
+	}
+
+	var buf bytes.Buffer
+	writeRoutingTrace(&buf, trace)
+	lines := strings.Split(buf.String(), "\n")
+	for i, prefix := range []string{
+		first.String() + " response",
+		"  " + closer.String() + " response",
+		"    " + dialed.String() + " not-queried",
+		"  " + failing.String() + " error: connection refused",
+		second.String() + " no-response",
+		"",
+		"5 peers contacted",
+	} {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("expected line %d to start with %q, got %q", i, prefix, lines[i])
+		}
+	}
+}
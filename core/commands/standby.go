@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	cmdenv "github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	"github.com/ipfs/go-ipfs/core/node"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+var StandbyCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Manage the warm standby of a primary node.",
+		ShortDescription: `
+A standby continuously mirrors the pins, MFS root, IPNS records and,
+optionally, the keys of its primary, set in Standby.Primary. The primary only
+lets the peers in its Standby.Standbys mirror it, and sends its keys when
+Standby.Keys is set. 'ipfs standby promote' makes the standby take over.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"status":  standbyStatusCmd,
+		"promote": standbyPromoteCmd,
+	},
+}
+
+var standbyStatusCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Show the state of the mirroring of the primary.",
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		nd, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		if !nd.IsOnline {
+			return ErrNotOnline
+		}
+
+		st, err := nd.Standby.Status()
+		if err != nil {
+			return err
+		}
+		return cmds.EmitOnce(res, st)
+	},
+	Type: node.StandbyStatus{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, st *node.StandbyStatus) error {
+			fmt.Fprintf(w, "Primary: %s\n", st.Primary)
+			if st.Promoted {
+				fmt.Fprintln(w, "Promoted: took over from the primary")
+			}
+			if st.LastSync.IsZero() {
+				fmt.Fprintln(w, "Last sync: never")
+			} else {
+				fmt.Fprintf(w, "Last sync: %s\n", st.LastSync.Format(time.RFC3339))
+			}
+			if st.LastError != "" {
+				fmt.Fprintf(w, "Last error: %s\n", st.LastError)
+			}
+			fmt.Fprintf(w, "Pins: %d recursive, %d direct\n", st.Recursive, st.Direct)
+			if st.FilesRoot != "" {
+				fmt.Fprintf(w, "MFS root: %s\n", st.FilesRoot)
+			}
+			_, err := fmt.Fprintf(w, "IPNS records: %d\nKeys: %d\n", st.Records, st.Keys)
+			return err
+		}),
+	},
+}
+
+type StandbyPromoteOutput struct {
+	Published []string
+}
+
+var standbyPromoteCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Promote this standby to take over from its primary.",
+		ShortDescription: `
+'ipfs standby promote' stops mirroring the primary, imports its keys into the
+keystore, its identity as the key named "primary", and republishes its IPNS
+names from this node. Standby.Primary is removed from the config, so the node
+stays promoted across restarts.
+
+Stop the primary first, or remove this node from its Standby.Standbys: both
+nodes publishing the same names would compete for them.
+`,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		nd, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		if !nd.IsOnline {
+			return ErrNotOnline
+		}
+
+		published, err := nd.Standby.Promote(req.Context)
+		if err != nil {
+			return err
+		}
+		return cmds.EmitOnce(res, &StandbyPromoteOutput{Published: published})
+	},
+	Type: StandbyPromoteOutput{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *StandbyPromoteOutput) error {
+			for _, name := range out.Published {
+				fmt.Fprintf(w, "republished %s\n", name)
+			}
+			_, err := fmt.Fprintln(w, "promoted")
+			return err
+		}),
+	},
+}
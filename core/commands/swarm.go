@@ -15,6 +15,7 @@ import (
 	files "github.com/ipfs/go-ipfs-files"
 	"github.com/ipfs/go-ipfs/commands"
 	"github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core"
 	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
 	"github.com/ipfs/go-ipfs/core/node/libp2p"
 	"github.com/ipfs/go-ipfs/repo"
@@ -52,11 +53,14 @@ ipfs peers in the internet.
 	},
 	Subcommands: map[string]*cmds.Command{
 		"addrs":      swarmAddrsCmd,
+		"allowlist":  swarmAllowlistCmd,
 		"connect":    swarmConnectCmd,
 		"disconnect": swarmDisconnectCmd,
 		"filters":    swarmFiltersCmd,
 		"peers":      swarmPeersCmd,
+		"pause":      swarmPauseCmd,
 		"peering":    swarmPeeringCmd,
+		"resume":     swarmResumeCmd,
 		"stats":      swarmStatsCmd, // libp2p Network Resource Manager
 		"limit":      swarmLimitCmd, // libp2p Network Resource Manager
 	},
@@ -67,6 +71,13 @@ const (
 	swarmStreamsOptionName   = "streams"
 	swarmLatencyOptionName   = "latency"
 	swarmDirectionOptionName = "direction"
+
+	swarmAllOptionName             = "all"
+	swarmTransportOptionName       = "transport"
+	swarmTagOptionName             = "tag"
+	swarmExceptPeeringOptionName   = "except-peering"
+	swarmExceptProtectedOptionName = "except-protected"
+	swarmDryRunOptionName          = "dry-run"
 )
 
 type peeringResult struct {
@@ -684,10 +695,35 @@ ipfs swarm disconnect /ip4/104.131.131.82/tcp/4001/p2p/QmaCpDMGvV2BGHeYERUEnRQAw
 
 The disconnect is not permanent; if ipfs needs to talk to that address later,
 it will reconnect.
+
+With --all, the connections to every peer are closed instead, for recovering
+a node from a connection storm. They can be narrowed down with:
+
+  --transport    the connections over a transport, like quic, tcp or ws
+  --direction    the inbound or the outbound connections
+  --tag          the connections to the peers tagged so in the connection
+                 manager
+  --except-peering    keeps the connections to the peers of the peering
+                      subsystem
+  --except-protected  keeps the connections to the peers protected in the
+                      connection manager, including the peering ones
+
+--dry-run lists the connections that would be closed, without closing them:
+
+ipfs swarm disconnect --all --direction=inbound --except-protected --dry-run
 `,
 	},
 	Arguments: []cmds.Argument{
-		cmds.StringArg("address", true, true, "Address of peer to disconnect from.").EnableStdin(),
+		cmds.StringArg("address", false, true, "Address of peer to disconnect from.").EnableStdin(),
+	},
+	Options: []cmds.Option{
+		cmds.BoolOption(swarmAllOptionName, "Close the connections to every peer."),
+		cmds.StringOption(swarmTransportOptionName, "With --all, only close the connections over this transport (e.g. quic, tcp, ws)."),
+		cmds.StringOption(swarmDirectionOptionName, "With --all, only close the connections in this direction: inbound or outbound."),
+		cmds.StringOption(swarmTagOptionName, "With --all, only close the connections to the peers with this connection manager tag."),
+		cmds.BoolOption(swarmExceptPeeringOptionName, "With --all, keep the connections to the peers of the peering subsystem."),
+		cmds.BoolOption(swarmExceptProtectedOptionName, "With --all, keep the connections to the peers protected in the connection manager."),
+		cmds.BoolOption(swarmDryRunOptionName, "List the connections that would be closed, without closing them."),
 	},
 	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
 		node, err := cmdenv.GetNode(env)
@@ -695,6 +731,28 @@ it will reconnect.
 			return err
 		}
 
+		if all, _ := req.Options[swarmAllOptionName].(bool); all {
+			if len(req.Arguments) > 0 {
+				return errors.New("--all does not take addresses")
+			}
+			if !node.IsOnline {
+				return ErrNotOnline
+			}
+			output, err := swarmDisconnectAll(node, req.Options)
+			if err != nil {
+				return err
+			}
+			return cmds.EmitOnce(res, &stringList{output})
+		}
+		for _, opt := range []string{swarmTransportOptionName, swarmDirectionOptionName, swarmTagOptionName, swarmExceptPeeringOptionName, swarmExceptProtectedOptionName, swarmDryRunOptionName} {
+			if _, ok := req.Options[opt]; ok {
+				return fmt.Errorf("--%s requires --all", opt)
+			}
+		}
+		if len(req.Arguments) == 0 {
+			return errors.New("an address or --all is required")
+		}
+
 		api, err := cmdenv.GetApi(env, req)
 		if err != nil {
 			return err
@@ -738,6 +796,274 @@ it will reconnect.
 	Type: stringList{},
 }
 
+// swarmDisconnectAll closes the connections selected by the options of
+// 'ipfs swarm disconnect --all', returning a line per connection.
+func swarmDisconnectAll(node *core.IpfsNode, opts cmds.OptMap) ([]string, error) {
+	transport, _ := opts[swarmTransportOptionName].(string)
+	tag, _ := opts[swarmTagOptionName].(string)
+	exceptPeering, _ := opts[swarmExceptPeeringOptionName].(bool)
+	exceptProtected, _ := opts[swarmExceptProtectedOptionName].(bool)
+	dryRun, _ := opts[swarmDryRunOptionName].(bool)
+
+	var transportCode int
+	if transport != "" {
+		p := ma.ProtocolWithName(transport)
+		if p.Code == 0 {
+			return nil, fmt.Errorf("unknown transport %q", transport)
+		}
+		transportCode = p.Code
+	}
+	direction := inet.DirUnknown
+	switch d, _ := opts[swarmDirectionOptionName].(string); d {
+	case "":
+	case "inbound":
+		direction = inet.DirInbound
+	case "outbound":
+		direction = inet.DirOutbound
+	default:
+		return nil, fmt.Errorf("unknown direction %q, expected inbound or outbound", d)
+	}
+
+	peering := make(map[peer.ID]bool)
+	if exceptPeering {
+		for _, ai := range node.Peering.ListPeers() {
+			peering[ai.ID] = true
+		}
+	}
+	cm := node.PeerHost.ConnManager()
+
+	var output []string
+	for _, c := range node.PeerHost.Network().Conns() {
+		p := c.RemotePeer()
+		if peering[p] || (exceptProtected && cm.IsProtected(p, "")) {
+			continue
+		}
+		if direction != inet.DirUnknown && c.Stat().Direction != direction {
+			continue
+		}
+		if transportCode != 0 {
+			if _, err := c.RemoteMultiaddr().ValueForProtocol(transportCode); err != nil {
+				continue
+			}
+		}
+		if tag != "" {
+			info := cm.GetTagInfo(p)
+			if info == nil {
+				continue
+			}
+			if _, ok := info.Tags[tag]; !ok {
+				continue
+			}
+		}
+
+		msg := "disconnect " + p.Pretty() + " " + c.RemoteMultiaddr().String()
+		if dryRun {
+			output = append(output, msg+" (dry run)")
+			continue
+		}
+		if err := c.Close(); err != nil {
+			msg += " failure: " + err.Error()
+		} else {
+			msg += " success"
+		}
+		output = append(output, msg)
+	}
+	return output, nil
+}
+
+var swarmPauseCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Stop accepting and dialing new connections.",
+		ShortDescription: `
+'ipfs swarm pause' is an emergency brake for overloaded nodes: until 'ipfs
+swarm resume' or a restart, the new connections are denied, both inbound
+and outbound, except with the peers protected in the connection manager,
+like the ones of the peering subsystem. The existing connections are kept,
+close them with 'ipfs swarm disconnect --all --except-protected'.
+
+Inbound connections are denied once their peer is known, after the security
+handshake. Pausing a paused swarm does nothing.
+`,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		if !n.IsOnline || n.SwarmBrake == nil {
+			return ErrNotOnline
+		}
+
+		cm := n.PeerHost.ConnManager()
+		st := n.SwarmBrake.Pause(func(p peer.ID) bool {
+			return cm.IsProtected(p, "")
+		})
+		return cmds.EmitOnce(res, &st)
+	},
+	Type: libp2p.SwarmBrakeStatus{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(swarmBrakeEncoder),
+	},
+}
+
+var swarmResumeCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Accept and dial new connections again after 'ipfs swarm pause'.",
+		ShortDescription: `
+'ipfs swarm resume' undoes 'ipfs swarm pause', and prints the number of
+connections denied during the pause.
+`,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		if !n.IsOnline || n.SwarmBrake == nil {
+			return ErrNotOnline
+		}
+
+		st := n.SwarmBrake.Resume()
+		return cmds.EmitOnce(res, &st)
+	},
+	Type: libp2p.SwarmBrakeStatus{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, st *libp2p.SwarmBrakeStatus) error {
+			if !st.Paused {
+				_, err := fmt.Fprintln(w, "swarm not paused")
+				return err
+			}
+			_, err := fmt.Fprintf(w, "swarm resumed, denied %d inbound and %d outbound connections while paused\n", st.DeniedInbound, st.DeniedOutbound)
+			return err
+		}),
+	},
+}
+
+const (
+	allowlistKeyOptionName      = "key"
+	allowlistLifetimeOptionName = "lifetime"
+)
+
+var swarmAllowlistCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Show the peers admitted by the inbound allowlist.",
+		ShortDescription: `
+'ipfs swarm allowlist' lists the peers admitted with their certificate by
+Swarm.InboundAllowlist, the peers on probation that have yet to present
+theirs, and the number of inbound connections denied.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"certify": swarmAllowlistCertifyCmd,
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		if !n.IsOnline {
+			return ErrNotOnline
+		}
+		if n.Allowlist == nil {
+			return fmt.Errorf("the inbound allowlist is disabled, see Swarm.InboundAllowlist.Enabled")
+		}
+
+		st := n.Allowlist.Status()
+		return cmds.EmitOnce(res, &st)
+	},
+	Type: libp2p.InboundAllowlistStatus{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, st *libp2p.InboundAllowlistStatus) error {
+			for _, a := range st.Admitted {
+				fmt.Fprintf(w, "%s\tadmitted until %s\n", a.Peer, a.Expires.Format(time.RFC3339))
+			}
+			for _, p := range st.Probation {
+				fmt.Fprintf(w, "%s\ton probation\n", p)
+			}
+			_, err := fmt.Fprintf(w, "denied %d inbound connections\n", st.Denied)
+			return err
+		}),
+	},
+}
+
+// AllowlistCertificate is a certificate admitting a peer in the inbound
+// allowlist of the nodes trusting its signer.
+type AllowlistCertificate struct {
+	Certificate string
+	Expires     time.Time
+}
+
+var swarmAllowlistCertifyCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Issue the certificate of a peer for the inbound allowlists.",
+		ShortDescription: `
+'ipfs swarm allowlist certify' signs a certificate admitting the given peer
+in the inbound allowlist of the nodes listing the peer ID of the signing key
+in Swarm.InboundAllowlist.CertificateAuthorities. The peer presents the
+certificate set in its Swarm.InboundAllowlist.Certificate when connecting.
+
+The certificate is signed with the key of this node, or the key named with
+--key, as listed by 'ipfs key list -l'.
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("peer-id", true, false, "The peer to certify."),
+	},
+	Options: []cmds.Option{
+		cmds.StringOption(allowlistKeyOptionName, "k", "The name of the key to sign the certificate with.").WithDefault("self"),
+		cmds.StringOption(allowlistLifetimeOptionName, "How long the certificate is valid for.").WithDefault("8760h"),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		n, err := cmdenv.GetNode(env)
+		if err != nil {
+			return err
+		}
+		p, err := peer.Decode(req.Arguments[0])
+		if err != nil {
+			return fmt.Errorf("invalid peer ID: %w", err)
+		}
+		lifetime, err := time.ParseDuration(req.Options[allowlistLifetimeOptionName].(string))
+		if err != nil {
+			return fmt.Errorf("invalid lifetime: %w", err)
+		}
+		if lifetime <= 0 {
+			return fmt.Errorf("the lifetime must be positive")
+		}
+
+		name := req.Options[allowlistKeyOptionName].(string)
+		sk := n.PrivateKey
+		if name != "self" {
+			if sk, err = n.Repo.Keystore().Get(name); err != nil {
+				return fmt.Errorf("key with name '%s' doesn't exist", name)
+			}
+		}
+
+		expires := time.Now().Add(lifetime)
+		cert, err := libp2p.IssuePeerCertificate(sk, p, expires)
+		if err != nil {
+			return err
+		}
+		return cmds.EmitOnce(res, &AllowlistCertificate{Certificate: cert, Expires: expires.UTC().Truncate(time.Second)})
+	},
+	Type: AllowlistCertificate{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *AllowlistCertificate) error {
+			_, err := fmt.Fprintln(w, out.Certificate)
+			return err
+		}),
+	},
+}
+
+func swarmBrakeEncoder(req *cmds.Request, w io.Writer, st *libp2p.SwarmBrakeStatus) error {
+	if !st.Paused {
+		_, err := fmt.Fprintln(w, "swarm not paused")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "swarm paused since %s, denied %d inbound and %d outbound connections\n",
+		st.Since.Format(time.RFC3339), st.DeniedInbound, st.DeniedOutbound)
+	return err
+}
+
 // parseAddresses is a function that takes in a slice of string peer addresses
 // (multiaddr + peerid) and returns a slice of properly constructed peers
 func parseAddresses(ctx context.Context, addrs []string, rslv *madns.Resolver) ([]peer.AddrInfo, error) {
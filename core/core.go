@@ -5,7 +5,7 @@ Packages underneath core/ provide a (relatively) stable, low-level API
 to carry out most IPFS-related tasks.  For more details on the other
 interfaces and how core/... fits into the bigger IPFS picture, see:
 
-  $ godoc github.com/ipfs/go-ipfs
+	$ godoc github.com/ipfs/go-ipfs
 */
 package core
 
@@ -47,9 +47,13 @@ import (
 	"github.com/ipfs/go-ipfs/core/node"
 	"github.com/ipfs/go-ipfs/core/node/libp2p"
 	"github.com/ipfs/go-ipfs/fuse/mount"
+	"github.com/ipfs/go-ipfs/ipldlimits"
+	"github.com/ipfs/go-ipfs/journal"
 	"github.com/ipfs/go-ipfs/p2p"
 	"github.com/ipfs/go-ipfs/peering"
+	"github.com/ipfs/go-ipfs/quarantine"
 	"github.com/ipfs/go-ipfs/repo"
+	"github.com/ipfs/go-ipfs/walkpool"
 	"github.com/ipfs/go-namesys"
 	ipnsrp "github.com/ipfs/go-namesys/republisher"
 )
@@ -84,20 +88,28 @@ type IpfsNode struct {
 	Discovery            mdns.Service              `optional:"true"`
 	FilesRoot            *mfs.Root
 	RecordValidator      record.Validator
+	Journal              *journal.Journal       // the event journal, nil when disabled
+	Quarantine           *quarantine.Quarantine // the quarantine of invalid blocks, nil when disabled
+	IPLDLimits           *ipldlimits.Limits     // the limits of the DAGs, nil when unlimited
+	WalkPool             *walkpool.Pool         // the pool of the DAG walks, nil when unbounded
 
 	// Online
-	PeerHost        p2phost.Host            `optional:"true"` // the network host (server+client)
-	Peering         *peering.PeeringService `optional:"true"`
-	Filters         *ma.Filters             `optional:"true"`
-	Bootstrapper    io.Closer               `optional:"true"` // the periodic bootstrapper
-	Routing         routing.Routing         `optional:"true"` // the routing system. recommend ipfs-dht
-	DNSResolver     *madns.Resolver         // the DNS resolver
-	Exchange        exchange.Interface      // the block exchange + strategy (bitswap)
-	Namesys         namesys.NameSystem      // the name system, resolves paths to hashes
-	Provider        provider.System         // the value provider system
-	IpnsRepub       *ipnsrp.Republisher     `optional:"true"`
-	GraphExchange   graphsync.GraphExchange `optional:"true"`
-	ResourceManager network.ResourceManager `optional:"true"`
+	PeerHost        p2phost.Host             `optional:"true"` // the network host (server+client)
+	Peering         *peering.PeeringService  `optional:"true"`
+	Filters         *ma.Filters              `optional:"true"`
+	SwarmBrake      *libp2p.SwarmBrake       `optional:"true"` // pauses the new connections
+	Allowlist       *libp2p.InboundAllowlist `optional:"true"` // the peers inbound connections are accepted from, nil when disabled
+	Bootstrapper    io.Closer                `optional:"true"` // the periodic bootstrapper
+	Routing         routing.Routing          `optional:"true"` // the routing system. recommend ipfs-dht
+	RoutingHealth   *libp2p.RoutingHealth    `optional:"true"` // the probes of the routers
+	DNSResolver     *madns.Resolver          // the DNS resolver
+	Exchange        exchange.Interface       // the block exchange + strategy (bitswap)
+	Namesys         namesys.NameSystem       // the name system, resolves paths to hashes
+	Provider        provider.System          // the value provider system
+	IpnsRepub       *ipnsrp.Republisher      `optional:"true"`
+	GraphExchange   graphsync.GraphExchange  `optional:"true"`
+	ResourceManager network.ResourceManager  `optional:"true"`
+	Standby         *node.Standby            `optional:"true"`
 
 	PubSub   *pubsub.PubSub             `optional:"true"`
 	PSRouter *psrouter.PubsubValueStore `optional:"true"`
@@ -173,7 +185,11 @@ func (n *IpfsNode) loadBootstrapPeers() ([]peer.AddrInfo, error) {
 		return nil, err
 	}
 
-	return cfg.BootstrapPeers()
+	peers, err := cfg.BootstrapPeers()
+	if err != nil {
+		return nil, err
+	}
+	return libp2p.FilterPNetPeers(n.PNetFingerprint, peers, cfg.Swarm.PrivateNetwork), nil
 }
 
 type ConstructPeerHostOpts struct {
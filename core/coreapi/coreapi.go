@@ -41,7 +41,10 @@ import (
 
 	"github.com/ipfs/go-ipfs/core"
 	"github.com/ipfs/go-ipfs/core/node"
+	"github.com/ipfs/go-ipfs/ipldlimits"
+	"github.com/ipfs/go-ipfs/journal"
 	"github.com/ipfs/go-ipfs/repo"
+	"github.com/ipfs/go-ipfs/walkpool"
 	"github.com/ipfs/go-namesys"
 )
 
@@ -70,9 +73,15 @@ type CoreAPI struct {
 	dnsResolver *madns.Resolver
 
 	provider provider.System
+	// bitswapProvides is set when bitswap announces the fetched blocks
+	bitswapProvides bool
 
 	pubSub *pubsub.PubSub
 
+	journal *journal.Journal
+	limits  *ipldlimits.Limits
+	walkers *walkpool.Pool
+
 	checkPublishAllowed func() error
 	checkOnline         func(allowOffline bool) error
 
@@ -144,6 +153,14 @@ func (api *CoreAPI) PubSub() coreiface.PubSubAPI {
 	return (*PubSubAPI)(api)
 }
 
+// WithWalkPool returns api fetching the nodes of its DAG through the walk
+// pool of the node, as op.
+func (api *CoreAPI) WithWalkPool(op string) *CoreAPI {
+	subApi := *api
+	subApi.dag = api.walkers.DAGService(op, api.dag)
+	return &subApi
+}
+
 // WithOptions returns api with global options applied
 func (api *CoreAPI) WithOptions(opts ...options.ApiOption) (coreiface.CoreAPI, error) {
 	settings := api.parentOpts // make sure to copy
@@ -158,6 +175,11 @@ func (api *CoreAPI) WithOptions(opts ...options.ApiOption) (coreiface.CoreAPI, e
 
 	n := api.nd
 
+	cfg, err := n.Repo.Config()
+	if err != nil {
+		return nil, err
+	}
+
 	subApi := &CoreAPI{
 		nctx: n.Context(),
 
@@ -182,10 +204,15 @@ func (api *CoreAPI) WithOptions(opts ...options.ApiOption) (coreiface.CoreAPI, e
 		routing:         n.Routing,
 		dnsResolver:     n.DNSResolver,
 
-		provider: n.Provider,
+		provider:        n.Provider,
+		bitswapProvides: node.BitswapProvides(cfg),
 
 		pubSub: n.PubSub,
 
+		journal: n.Journal,
+		limits:  n.IPLDLimits,
+		walkers: n.WalkPool,
+
 		nd:         n,
 		parentOpts: settings,
 	}
@@ -201,15 +228,13 @@ func (api *CoreAPI) WithOptions(opts ...options.ApiOption) (coreiface.CoreAPI, e
 		if n.Mounts.Ipns != nil && n.Mounts.Ipns.IsActive() {
 			return errors.New("cannot manually publish while IPNS is mounted")
 		}
+		if repo.IsReadOnly(n.Repo) {
+			return repo.ErrReadOnly
+		}
 		return nil
 	}
 
 	if settings.Offline {
-		cfg, err := n.Repo.Config()
-		if err != nil {
-			return nil, err
-		}
-
 		cs := cfg.Ipns.ResolveCacheSize
 		if cs == 0 {
 			cs = node.DefaultIpnsCacheSize
@@ -238,7 +263,7 @@ func (api *CoreAPI) WithOptions(opts ...options.ApiOption) (coreiface.CoreAPI, e
 	if settings.Offline || !settings.FetchBlocks {
 		subApi.exchange = offlinexch.Exchange(subApi.blockstore)
 		subApi.blocks = bserv.New(subApi.blockstore, subApi.exchange)
-		subApi.dag = dag.NewDAGService(subApi.blocks)
+		subApi.dag = n.IPLDLimits.Wrap(n.Quarantine.Wrap(dag.NewDAGService(n.IPLDLimits.WrapBlocks(subApi.blocks))))
 	}
 
 	return subApi, nil
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	keystore "github.com/ipfs/go-ipfs-keystore"
+	"github.com/ipfs/go-ipfs/core/node"
+	"github.com/ipfs/go-ipfs/journal"
 	"github.com/ipfs/go-ipfs/tracing"
 	"github.com/ipfs/go-namesys"
 	"go.opentelemetry.io/otel/attribute"
@@ -74,6 +76,15 @@ func (api *NameAPI) Publish(ctx context.Context, p path.Path, opts ...caopts.Nam
 		return nil, err
 	}
 
+	pid, err := peer.IDFromPrivateKey(k)
+	if err != nil {
+		return nil, err
+	}
+	name := coreiface.FormatKeyID(pid)
+	if delegatedName(pth) == pid {
+		return nil, fmt.Errorf("can't delegate %s to itself", name)
+	}
+
 	if options.TTL != nil {
 		ctx = context.WithValue(ctx, "ipns-publish-ttl", *options.TTL)
 	}
@@ -84,13 +95,13 @@ func (api *NameAPI) Publish(ctx context.Context, p path.Path, opts ...caopts.Nam
 		return nil, err
 	}
 
-	pid, err := peer.IDFromPrivateKey(k)
-	if err != nil {
-		return nil, err
-	}
+	api.journal.Record(ctx, journal.TypePublish, map[string]string{
+		"name":  name,
+		"value": p.String(),
+	})
 
 	return &ipnsEntry{
-		name:  coreiface.FormatKeyID(pid),
+		name:  name,
 		value: p,
 	}, nil
 }
@@ -113,12 +124,17 @@ func (api *NameAPI) Search(ctx context.Context, name string, opts ...caopts.Name
 
 	var resolver namesys.Resolver = api.namesys
 	if !options.Cache {
-		resolver, err = namesys.NewNameSystem(api.routing,
+		cfg, err := api.repo.Config()
+		if err != nil {
+			return nil, err
+		}
+		ns, err := namesys.NewNameSystem(api.routing,
 			namesys.WithDatastore(api.repo.Datastore()),
 			namesys.WithDNSResolver(api.dnsResolver))
 		if err != nil {
 			return nil, err
 		}
+		resolver = node.LimitDelegations(ns, int(cfg.Ipns.MaxDelegations.WithDefault(node.DefaultIpnsMaxDelegations)))
 	}
 
 	if !strings.HasPrefix(name, "/ipns/") {
@@ -167,6 +183,20 @@ func (api *NameAPI) Resolve(ctx context.Context, name string, opts ...caopts.Nam
 	return p, err
 }
 
+// delegatedName returns the name p delegates to, when it's the /ipns/ path of
+// a key.
+func delegatedName(p ipath.Path) peer.ID {
+	segments := p.Segments()
+	if len(segments) < 2 || segments[0] != "ipns" {
+		return ""
+	}
+	id, err := peer.Decode(segments[1])
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
 func keylookup(self ci.PrivKey, kstore keystore.Keystore, k string) (ci.PrivKey, error) {
 	////////////////////
 	// Lookup by name //
@@ -48,7 +48,11 @@ func (api *CoreAPI) ResolvePath(ctx context.Context, p path.Path) (path.Resolved
 		return p.(path.Resolved), nil
 	}
 	if err := p.IsValid(); err != nil {
-		return nil, err
+		named, ok := api.resolvePetname(p.String())
+		if !ok {
+			return nil, err
+		}
+		p = named
 	}
 
 	ipath := ipfspath.Path(p.String())
@@ -62,6 +66,10 @@ func (api *CoreAPI) ResolvePath(ctx context.Context, p path.Path) (path.Resolved
 	if ipath.Segments()[0] != "ipfs" && ipath.Segments()[0] != "ipld" {
 		return nil, fmt.Errorf("unsupported path namespace: %s", p.Namespace())
 	}
+	// the links followed are the segments after the namespace and root
+	if err := api.limits.CheckPathDepth(len(ipath.Segments()) - 2); err != nil {
+		return nil, err
+	}
 
 	var dataFetcher fetcher.Factory
 	if ipath.Segments()[0] == "ipld" {
@@ -83,3 +91,21 @@ func (api *CoreAPI) ResolvePath(ctx context.Context, p path.Path) (path.Resolved
 
 	return path.NewResolvedPath(ipath, node, root, gopath.Join(rest...)), nil
 }
+
+// resolvePetname returns the content path p stands for when it starts with
+// one of the Petnames.
+func (api *CoreAPI) resolvePetname(p string) (path.Path, bool) {
+	cfg, err := api.repo.Config()
+	if err != nil {
+		return nil, false
+	}
+	named, ok := cfg.Petnames.Resolve(p)
+	if !ok {
+		return nil, false
+	}
+	resolved := path.New(named)
+	if resolved.IsValid() != nil {
+		return nil, false
+	}
+	return resolved, true
+}
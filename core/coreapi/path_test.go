@@ -0,0 +1,65 @@
+package coreapi
+
+import (
+	"context"
+	"testing"
+
+	datastore "github.com/ipfs/go-datastore"
+	syncds "github.com/ipfs/go-datastore/sync"
+	files "github.com/ipfs/go-ipfs-files"
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/repo"
+	"github.com/ipfs/interface-go-ipfs-core/options"
+	"github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+func TestResolvePetname(t *testing.T) {
+	ctx := context.Background()
+	r := &repo.Mock{
+		C: config.Config{
+			Identity: config.Identity{
+				PeerID: testPeerID, // required by offline node
+			},
+		},
+		D: syncds.MutexWrap(datastore.NewMapDatastore()),
+	}
+	node, err := core.NewNode(ctx, &core.BuildCfg{Repo: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+	api, err := NewCoreAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := api.Unixfs().Add(ctx, files.NewMapDirectory(map[string]files.Node{
+		"index.html": files.NewBytesFile([]byte("index")),
+	}), options.Unixfs.Pin(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := api.ResolvePath(ctx, path.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the petnames are read from the config at each resolution
+	r.C.Petnames.Paths = map[string]string{"docs": dir.String() + "/"}
+
+	for p, expected := range map[string]path.Resolved{"docs": dir, "docs/index.html": index} {
+		rp, err := api.ResolvePath(ctx, path.New(p))
+		if err != nil {
+			t.Fatalf("resolving %s: %s", p, err)
+		}
+		if !rp.Cid().Equals(expected.Cid()) {
+			t.Errorf("expected %s to resolve to %s, got %s", p, expected.Cid(), rp.Cid())
+		}
+	}
+	for _, p := range []string{"other", "other/index.html", "docs/missing"} {
+		if _, err := api.ResolvePath(ctx, path.New(p)); err == nil {
+			t.Errorf("expected %s not to resolve", p)
+		}
+	}
+}
@@ -3,12 +3,15 @@ package coreapi
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	bserv "github.com/ipfs/go-blockservice"
 	"github.com/ipfs/go-cid"
 	offline "github.com/ipfs/go-ipfs-exchange-offline"
 	pin "github.com/ipfs/go-ipfs-pinner"
+	"github.com/ipfs/go-ipfs/journal"
 	"github.com/ipfs/go-ipfs/tracing"
+	"github.com/ipfs/go-ipfs/walkpool"
 	"github.com/ipfs/go-merkledag"
 	coreiface "github.com/ipfs/interface-go-ipfs-core"
 	caopts "github.com/ipfs/interface-go-ipfs-core/options"
@@ -37,7 +40,8 @@ func (api *PinAPI) Add(ctx context.Context, p path.Path, opts ...caopts.PinAddOp
 
 	defer api.blockstore.PinLock(ctx).Unlock(ctx)
 
-	err = api.pinning.Pin(ctx, dagNode, settings.Recursive)
+	// the depth of the DAG is checked as the pinner fetches it
+	err = api.pinning.Pin(api.limits.WithDepthLimit(ctx, dagNode.Cid()), dagNode, settings.Recursive)
 	if err != nil {
 		return fmt.Errorf("pin: %s", err)
 	}
@@ -46,7 +50,16 @@ func (api *PinAPI) Add(ctx context.Context, p path.Path, opts ...caopts.PinAddOp
 		return err
 	}
 
-	return api.pinning.Flush(ctx)
+	if err := api.pinning.Flush(ctx); err != nil {
+		return err
+	}
+
+	api.journal.Record(ctx, journal.TypePin, map[string]string{
+		"op":        "add",
+		"cid":       dagNode.Cid().String(),
+		"recursive": strconv.FormatBool(settings.Recursive),
+	})
+	return nil
 }
 
 func (api *PinAPI) Ls(ctx context.Context, opts ...caopts.PinLsOption) (<-chan coreiface.Pin, error) {
@@ -118,7 +131,16 @@ func (api *PinAPI) Rm(ctx context.Context, p path.Path, opts ...caopts.PinRmOpti
 		return err
 	}
 
-	return api.pinning.Flush(ctx)
+	if err := api.pinning.Flush(ctx); err != nil {
+		return err
+	}
+
+	api.journal.Record(ctx, journal.TypePin, map[string]string{
+		"op":        "rm",
+		"cid":       rp.Cid().String(),
+		"recursive": strconv.FormatBool(settings.Recursive),
+	})
+	return nil
 }
 
 func (api *PinAPI) Update(ctx context.Context, from path.Path, to path.Path, opts ...caopts.PinUpdateOption) error {
@@ -152,7 +174,24 @@ func (api *PinAPI) Update(ctx context.Context, from path.Path, to path.Path, opt
 		return err
 	}
 
-	return api.pinning.Flush(ctx)
+	// bitswap announced the blocks of the new DAG as it fetched them
+	if !api.bitswapProvides {
+		if err := api.provider.Provide(tp.Cid()); err != nil {
+			return err
+		}
+	}
+
+	if err := api.pinning.Flush(ctx); err != nil {
+		return err
+	}
+
+	api.journal.Record(ctx, journal.TypePin, map[string]string{
+		"op":    "update",
+		"from":  fp.Cid().String(),
+		"cid":   tp.Cid().String(),
+		"unpin": strconv.FormatBool(settings.Unpin),
+	})
+	return nil
 }
 
 type pinStatus struct {
@@ -317,7 +356,7 @@ func (api *PinAPI) pinLsAll(ctx context.Context, typeStr string) <-chan coreifac
 			set := cid.NewSet()
 			for _, k := range rkeys {
 				err = merkledag.Walk(
-					ctx, merkledag.GetLinksWithDAG(api.dag), k,
+					ctx, api.walkers.GetLinks(walkpool.OpPin, merkledag.GetLinksWithDAG(api.dag)), k,
 					set.Visit,
 					merkledag.SkipRoot(), merkledag.Concurrent(),
 				)
@@ -352,7 +391,7 @@ func (api *PinAPI) pinLsAll(ctx context.Context, typeStr string) <-chan coreifac
 			set := cid.NewSet()
 			for _, k := range rkeys {
 				err = merkledag.Walk(
-					ctx, merkledag.GetLinksWithDAG(api.dag), k,
+					ctx, api.walkers.GetLinks(walkpool.OpPin, merkledag.GetLinksWithDAG(api.dag)), k,
 					set.Visit,
 					merkledag.SkipRoot(), merkledag.Concurrent(),
 				)
@@ -0,0 +1,94 @@
+package coreapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	syncds "github.com/ipfs/go-datastore/sync"
+	provider "github.com/ipfs/go-ipfs-provider"
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/repo"
+	ipld "github.com/ipfs/go-ipld-format"
+	dag "github.com/ipfs/go-merkledag"
+	"github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+const testPeerID = "QmTFauExutTsy4XP6JbMFcw2Wa9645HJt2bTqL6qYDCKfe"
+
+// recordingProvider records the CIDs it is asked to provide.
+type recordingProvider struct {
+	provider.System
+	provided []cid.Cid
+}
+
+func (p *recordingProvider) Provide(c cid.Cid) error {
+	p.provided = append(p.provided, c)
+	return nil
+}
+
+func TestPinUpdateProvide(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		provider string
+		expected bool
+	}{
+		{name: "bitswap provides", provider: `{}`},
+		{name: "deferred", provider: `{"DeferIncomplete": true}`, expected: true},
+		{name: "announcer", provider: `{"Announcer": "/ip4/127.0.0.1/tcp/4001/p2p/` + testPeerID + `"}`, expected: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			var pcfg config.Provider
+			if err := json.Unmarshal([]byte(tc.provider), &pcfg); err != nil {
+				t.Fatal(err)
+			}
+			r := &repo.Mock{
+				C: config.Config{
+					Identity: config.Identity{
+						PeerID: testPeerID, // required by offline node
+					},
+					Provider: pcfg,
+				},
+				D: syncds.MutexWrap(datastore.NewMapDatastore()),
+			}
+			node, err := core.NewNode(ctx, &core.BuildCfg{Repo: r})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer node.Close()
+			iapi, err := NewCoreAPI(node)
+			if err != nil {
+				t.Fatal(err)
+			}
+			api := iapi.(*CoreAPI)
+			prov := &recordingProvider{System: api.provider}
+			api.provider = prov
+
+			from := dag.NodeWithData([]byte("from"))
+			to := dag.NodeWithData([]byte("to"))
+			if err := api.Dag().AddMany(ctx, []ipld.Node{from, to}); err != nil {
+				t.Fatal(err)
+			}
+			if err := api.Pin().Add(ctx, path.IpfsPath(from.Cid())); err != nil {
+				t.Fatal(err)
+			}
+			prov.provided = nil
+
+			if err := api.Pin().Update(ctx, path.IpfsPath(from.Cid()), path.IpfsPath(to.Cid())); err != nil {
+				t.Fatal(err)
+			}
+			var expected []cid.Cid
+			if tc.expected {
+				expected = []cid.Cid{to.Cid()}
+			}
+			if fmt.Sprint(prov.provided) != fmt.Sprint(expected) {
+				t.Fatalf("expected %v to be provided, got %v", expected, prov.provided)
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ipfs/go-ipfs/core/coreunix"
+	"github.com/ipfs/go-ipfs/journal"
+	"github.com/ipfs/go-ipfs/scanner"
 
 	blockservice "github.com/ipfs/go-blockservice"
 	cid "github.com/ipfs/go-cid"
@@ -19,6 +21,7 @@ import (
 	bstore "github.com/ipfs/go-ipfs-blockstore"
 	files "github.com/ipfs/go-ipfs-files"
 	ipld "github.com/ipfs/go-ipld-format"
+	logging "github.com/ipfs/go-log"
 	dag "github.com/ipfs/go-merkledag"
 	merkledag "github.com/ipfs/go-merkledag"
 	dagtest "github.com/ipfs/go-merkledag/test"
@@ -31,6 +34,8 @@ import (
 	path "github.com/ipfs/interface-go-ipfs-core/path"
 )
 
+var log = logging.Logger("coreapi")
+
 type UnixfsAPI CoreAPI
 
 var nilNode *core.IpfsNode
@@ -147,6 +152,9 @@ func (api *UnixfsAPI) Add(ctx context.Context, files files.Node, opts ...options
 	}
 
 	fileAdder.Chunker = settings.Chunker
+	if settings.Chunker == "" {
+		fileAdder.ChunkerByContentType = cfg.Import.ChunkerByContentType
+	}
 	if settings.Events != nil {
 		fileAdder.Out = settings.Events
 		fileAdder.Progress = settings.Progress
@@ -186,12 +194,26 @@ func (api *UnixfsAPI) Add(ctx context.Context, files files.Node, opts ...options
 		fileAdder.SetMfsRoot(mr)
 	}
 
+	// the files are scanned while they are imported, the content of the
+	// rejected files is left unpinned for the GC to remove
+	var scan *scanner.Batch
+	if s := scanner.FromConfig(cfg.Import.Scanner); s != nil && !settings.OnlyHash {
+		if cfg.Import.Scanner.Async.WithDefault(false) {
+			scan = scanner.NewBatch(context.Background(), s, true)
+		} else {
+			scan = scanner.NewBatch(ctx, s, false)
+		}
+		files = scan.Node("", files)
+	}
+
 	nd, err := fileAdder.AddAllAndPin(ctx, files)
 	if err != nil {
 		return nil, err
 	}
 
-	if !settings.OnlyHash {
+	if scan != nil && cfg.Import.Scanner.Async.WithDefault(false) {
+		go api.provideScanned(scan, nd.Cid(), fileAdder.Pin && !fileAdder.RootPinned)
+	} else if !settings.OnlyHash {
 		if err := api.provider.Provide(nd.Cid()); err != nil {
 			return nil, err
 		}
@@ -200,6 +222,29 @@ func (api *UnixfsAPI) Add(ctx context.Context, files files.Node, opts ...options
 	return path.IpfsPath(nd.Cid()), nil
 }
 
+// provideScanned announces the content of an import once the scanner
+// accepted all of its files. The content that was rejected is unpinned
+// instead, when the import pinned it.
+func (api *UnixfsAPI) provideScanned(scan *scanner.Batch, c cid.Cid, pinned bool) {
+	ctx := context.Background()
+	err := scan.Wait()
+	if err == nil {
+		if err := api.provider.Provide(c); err != nil {
+			log.Errorf("providing %s: %s", c, err)
+		}
+		return
+	}
+
+	log.Errorf("not providing %s: %s", c, err)
+	api.journal.Record(ctx, journal.TypeScan, map[string]string{"cid": c.String(), "error": err.Error()})
+	if !pinned {
+		return
+	}
+	if err := api.core().Pin().Rm(ctx, path.IpfsPath(c)); err != nil {
+		log.Errorf("unpinning %s: %s", c, err)
+	}
+}
+
 func (api *UnixfsAPI) Get(ctx context.Context, p path.Path) (files.Node, error) {
 	ctx, span := tracing.Span(ctx, "CoreAPI.UnixfsAPI", "Get", trace.WithAttributes(attribute.String("path", p.String())))
 	defer span.End()
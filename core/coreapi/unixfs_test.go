@@ -0,0 +1,71 @@
+package coreapi
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	datastore "github.com/ipfs/go-datastore"
+	syncds "github.com/ipfs/go-datastore/sync"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/repo"
+	"github.com/ipfs/go-ipfs/scanner"
+	dag "github.com/ipfs/go-merkledag"
+	"github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+type rejectingScanner struct{}
+
+func (rejectingScanner) Scan(context.Context, string, io.Reader) error {
+	return scanner.ErrRejected
+}
+
+func TestProvideScannedRejected(t *testing.T) {
+	ctx := context.Background()
+	r := &repo.Mock{
+		C: config.Config{
+			Identity: config.Identity{
+				PeerID: testPeerID, // required by offline node
+			},
+		},
+		D: syncds.MutexWrap(datastore.NewMapDatastore()),
+	}
+	node, err := core.NewNode(ctx, &core.BuildCfg{Repo: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+	iapi, err := NewCoreAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := iapi.(*CoreAPI)
+
+	nd := dag.NodeWithData([]byte("rejected"))
+	if err := api.Dag().Add(ctx, nd); err != nil {
+		t.Fatal(err)
+	}
+	scan := scanner.NewBatch(ctx, rejectingScanner{}, true)
+	if _, err := ioutil.ReadAll(scan.Reader("rejected", strings.NewReader("rejected"))); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pin was there before the import, or created by it
+	for _, created := range []bool{false, true} {
+		if err := api.Pin().Add(ctx, path.IpfsPath(nd.Cid())); err != nil {
+			t.Fatal(err)
+		}
+		api.Unixfs().(*UnixfsAPI).provideScanned(scan, nd.Cid(), created)
+		_, pinned, err := api.pinning.IsPinnedWithType(ctx, nd.Cid(), pin.Recursive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pinned == created {
+			t.Fatalf("expected the rejected content to be unpinned only when the import pinned it, created: %t, pinned: %t", created, pinned)
+		}
+	}
+}
@@ -163,6 +163,13 @@ func CommandsROOption(cctx oldcmds.Context) ServeOption {
 	return commandsOption(cctx, corecommands.RootRO, true)
 }
 
+// CommandsInspectOption constructs a ServerOption for hooking the commands
+// that inspect the node without modifying it into the HTTP server. It will
+// NOT allow GET requests.
+func CommandsInspectOption(cctx oldcmds.Context) ServeOption {
+	return commandsOption(cctx, corecommands.RootInspect, false)
+}
+
 // CheckVersionOption returns a ServeOption that checks whether the client ipfs version matches. Does nothing when the user agent string does not contain `/go-ipfs/`
 func CheckVersionOption() ServeOption {
 	daemonVersion := version.ApiVersion
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"time"
 
+	config "github.com/ipfs/go-ipfs/config"
 	core "github.com/ipfs/go-ipfs/core"
 	logging "github.com/ipfs/go-log"
 	"github.com/jbenet/goprocess"
@@ -83,6 +84,32 @@ func ListenAndServe(n *core.IpfsNode, listeningMultiAddr string, options ...Serv
 // Serve accepts incoming HTTP connections on the listener and pass them
 // to ServeOption handlers.
 func Serve(node *core.IpfsNode, lis net.Listener, options ...ServeOption) error {
+	return serve(node, lis, &http.Server{}, options...)
+}
+
+// ServeWithListenerConfig is like Serve, but applies the per-listener
+// settings from Addresses.Listeners to the listener and the HTTP server.
+func ServeWithListenerConfig(node *core.IpfsNode, lis net.Listener, lcfg config.HTTPListener, options ...ServeOption) error {
+	wrapped, err := wrapListener(lis, lcfg)
+	if err != nil {
+		lis.Close()
+		return err
+	}
+
+	server := &http.Server{
+		ReadTimeout:       lcfg.ReadTimeout.WithDefault(0),
+		ReadHeaderTimeout: lcfg.ReadHeaderTimeout.WithDefault(0),
+		WriteTimeout:      lcfg.WriteTimeout.WithDefault(0),
+		IdleTimeout:       lcfg.IdleTimeout.WithDefault(0),
+		MaxHeaderBytes:    int(lcfg.MaxHeaderBytes.WithDefault(0)),
+	}
+	if len(lcfg.TrustedProxies) > 0 {
+		options = append([]ServeOption{TrustedProxiesOption(lcfg.TrustedProxies)}, options...)
+	}
+	return serve(node, wrapped, server, options...)
+}
+
+func serve(node *core.IpfsNode, lis net.Listener, server *http.Server, options ...ServeOption) error {
 	// make sure we close this no matter what.
 	defer lis.Close()
 
@@ -90,6 +117,7 @@ func Serve(node *core.IpfsNode, lis net.Listener, options ...ServeOption) error
 	if err != nil {
 		return err
 	}
+	server.Handler = handler
 
 	addr, err := manet.FromNetAddr(lis.Addr())
 	if err != nil {
@@ -102,10 +130,6 @@ func Serve(node *core.IpfsNode, lis net.Listener, options ...ServeOption) error
 	default:
 	}
 
-	server := &http.Server{
-		Handler: handler,
-	}
-
 	var serverError error
 	serverProc := node.Process.Go(func(p goprocess.Process) {
 		serverError = server.Serve(lis)
@@ -9,6 +9,7 @@ import (
 	version "github.com/ipfs/go-ipfs"
 	core "github.com/ipfs/go-ipfs/core"
 	coreapi "github.com/ipfs/go-ipfs/core/coreapi"
+	"github.com/ipfs/go-ipfs/walkpool"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	options "github.com/ipfs/interface-go-ipfs-core/options"
@@ -20,6 +21,16 @@ type GatewayConfig struct {
 	Writable              bool
 	PathPrefixes          []string
 	FastDirIndexThreshold int
+
+	// Aliases is the Gateway.Aliases table, normalized by normalizeAliases.
+	Aliases map[string]string
+
+	// Content type policy of file responses, see the Gateway section of the
+	// config.
+	ContentTypeSniffing   bool
+	NoSniff               bool
+	ContentSecurityPolicy string
+	ForceDownloadTypes    []string
 }
 
 // A helper function to clean up a set of headers:
@@ -53,6 +64,13 @@ func GatewayOption(writable bool, paths ...string) ServeOption {
 		if err != nil {
 			return nil, err
 		}
+		// the gateway shares the walk pool with the other walks
+		api = api.(*coreapi.CoreAPI).WithWalkPool(walkpool.OpGateway)
+
+		aliases, err := normalizeAliases(cfg.Gateway.Aliases)
+		if err != nil {
+			return nil, err
+		}
 
 		headers := make(map[string][]string, len(cfg.Gateway.HTTPHeaders))
 		for h, v := range cfg.Gateway.HTTPHeaders {
@@ -94,6 +112,11 @@ func GatewayOption(writable bool, paths ...string) ServeOption {
 			Writable:              writable,
 			PathPrefixes:          cfg.Gateway.PathPrefixes,
 			FastDirIndexThreshold: int(cfg.Gateway.FastDirIndexThreshold.WithDefault(100)),
+			Aliases:               aliases,
+			ContentTypeSniffing:   cfg.Gateway.ContentTypeSniffing.WithDefault(true),
+			NoSniff:               cfg.Gateway.NoSniff.WithDefault(false),
+			ContentSecurityPolicy: cfg.Gateway.ContentSecurityPolicy.WithDefault(""),
+			ForceDownloadTypes:    cfg.Gateway.ForceDownloadTypes,
 		}, api)
 
 		gateway = otelhttp.NewHandler(gateway, "Gateway.Request")
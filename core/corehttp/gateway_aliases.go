@@ -0,0 +1,86 @@
+package corehttp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	cid "github.com/ipfs/go-cid"
+	ipath "github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+// normalizeAliasPath returns the canonical form of a content path used as
+// Gateway.Aliases key: no trailing slash, and an /ipfs/ root in CIDv1 so
+// that the same content matches whatever CID version the request used.
+func normalizeAliasPath(p string) (string, bool) {
+	ip := ipath.New(strings.TrimRight(p, "/"))
+	if ip.IsValid() != nil {
+		return "", false
+	}
+	// with the /ipfs/ root of the paths that are a CID
+	p = ip.String()
+
+	segments := strings.SplitN(strings.TrimPrefix(p, "/"), "/", 3)
+	if segments[0] == "ipfs" {
+		c, err := cid.Decode(segments[1])
+		if err != nil {
+			return "", false
+		}
+		segments[1] = cid.NewCidV1(c.Type(), c.Hash()).String()
+	}
+	return "/" + strings.Join(segments, "/"), true
+}
+
+// normalizeAliases returns the Gateway.Aliases table with its keys and
+// targets in the form lookupAlias expects.
+func normalizeAliases(aliases map[string]string) (map[string]string, error) {
+	table := make(map[string]string, len(aliases))
+	for from, to := range aliases {
+		k, ok := normalizeAliasPath(from)
+		if !ok {
+			return nil, fmt.Errorf("invalid Gateway.Aliases key %q: expected an /ipfs/ or /ipns/ path", from)
+		}
+		table[k] = strings.TrimRight(to, "/")
+	}
+	return table, nil
+}
+
+// lookupAlias finds the longest key of table, normalized by
+// normalizeAliases, that is p or one of its parents, and returns p with that
+// prefix replaced by the alias target.
+func lookupAlias(table map[string]string, p string) (string, bool) {
+	if len(table) == 0 {
+		return "", false
+	}
+	normalized, ok := normalizeAliasPath(p)
+	if !ok {
+		return "", false
+	}
+
+	// Walk up the path, never past the /ipfs/<cid> or /ipns/<name> root.
+	prefix := normalized
+	for strings.Count(prefix, "/") >= 2 {
+		if to, ok := table[prefix]; ok {
+			return to + strings.TrimPrefix(normalized, prefix), true
+		}
+		prefix = prefix[:strings.LastIndex(prefix, "/")]
+	}
+	return "", false
+}
+
+// handleAliasRedirect permanently redirects requests for content that has
+// been moved, as configured in Gateway.Aliases.
+func (i *gatewayHandler) handleAliasRedirect(w http.ResponseWriter, r *http.Request, contentPath ipath.Path) (requestHandled bool) {
+	target, ok := lookupAlias(i.config.Aliases, contentPath.String())
+	if !ok {
+		return false
+	}
+	if strings.HasSuffix(r.URL.Path, "/") && !strings.HasSuffix(target, "/") {
+		target += "/"
+	}
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+	return true
+}
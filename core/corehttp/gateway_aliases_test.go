@@ -0,0 +1,49 @@
+package corehttp
+
+import "testing"
+
+func TestLookupAlias(t *testing.T) {
+	aliases, err := normalizeAliases(map[string]string{
+		"/ipfs/QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn":          "/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi/",
+		"/ipfs/QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn/old/docs": "/ipns/docs.example.com",
+		"/ipns/example.com/": "/ipns/example.net",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path, target string
+		found        bool
+	}{
+		{"/ipfs/QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn", "/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi", true},
+		{"/ipfs/QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn/a/b.txt", "/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi/a/b.txt", true},
+		// the same content addressed with a CIDv1
+		{"/ipfs/bafybeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354/a", "/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi/a", true},
+		// the longest matching alias wins
+		{"/ipfs/QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn/old/docs/index.html", "/ipns/docs.example.com/index.html", true},
+		{"/ipns/example.com/blog/", "/ipns/example.net/blog", true},
+		{"/ipns/example.org/blog", "", false},
+		{"/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi", "", false},
+	} {
+		target, found := lookupAlias(aliases, tc.path)
+		if found != tc.found || target != tc.target {
+			t.Errorf("lookupAlias(%q) = %q, %t; want %q, %t", tc.path, target, found, tc.target, tc.found)
+		}
+	}
+
+	// a CID is the /ipfs/ path of the content
+	aliases, err = normalizeAliases(map[string]string{"QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn": "/ipns/example.net"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target, _ := lookupAlias(aliases, "/ipfs/QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn/a"); target != "/ipns/example.net/a" {
+		t.Errorf("expected the alias of a CID to redirect to /ipns/example.net/a, got %q", target)
+	}
+
+	for _, from := range []string{"/ipfs/not-a-cid", "/http/example.com", ""} {
+		if _, err := normalizeAliases(map[string]string{from: "/ipns/example.net"}); err == nil {
+			t.Errorf("expected the key %q to be refused", from)
+		}
+	}
+}
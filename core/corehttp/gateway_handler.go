@@ -24,6 +24,7 @@ import (
 	path "github.com/ipfs/go-path"
 	"github.com/ipfs/go-path/resolver"
 	coreiface "github.com/ipfs/interface-go-ipfs-core"
+	options "github.com/ipfs/interface-go-ipfs-core/options"
 	ipath "github.com/ipfs/interface-go-ipfs-core/path"
 	routing "github.com/libp2p/go-libp2p-core/routing"
 	prometheus "github.com/prometheus/client_golang/prometheus"
@@ -364,6 +365,10 @@ func (i *gatewayHandler) getOrHeadHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if requestHandled := i.handleAliasRedirect(w, r, contentPath); requestHandled {
+		return
+	}
+
 	// Resolve path to the final DAG node for the ETag
 	resolvedPath, err := i.api.ResolvePath(r.Context(), contentPath)
 	switch err {
@@ -468,7 +473,8 @@ func (i *gatewayHandler) servePretty404IfPresent(w http.ResponseWriter, r *http.
 }
 
 func (i *gatewayHandler) postHandler(w http.ResponseWriter, r *http.Request) {
-	p, err := i.api.Unixfs().Add(r.Context(), files.NewReaderFile(r.Body))
+	// An empty chunker picks it from Import.ChunkerByContentType.
+	p, err := i.api.Unixfs().Add(r.Context(), files.NewReaderFile(r.Body), options.Unixfs.Chunker(""))
 	if err != nil {
 		internalWebError(w, err)
 		return
@@ -511,7 +517,7 @@ func (i *gatewayHandler) putHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create the new file.
-	newFilePath, err := i.api.Unixfs().Add(ctx, files.NewReaderFile(r.Body))
+	newFilePath, err := i.api.Unixfs().Add(ctx, files.NewReaderFile(r.Body), options.Unixfs.Chunker(""))
 	if err != nil {
 		webError(w, "WritableGateway: could not create DAG from request", err, http.StatusInternalServerError)
 		return
@@ -52,7 +52,9 @@ func (i *gatewayHandler) serveFile(ctx context.Context, w http.ResponseWriter, r
 		ctype = "inode/symlink"
 	} else {
 		ctype = mime.TypeByExtension(gopath.Ext(name))
-		if ctype == "" {
+		if ctype == "" && !i.config.ContentTypeSniffing {
+			ctype = "application/octet-stream"
+		} else if ctype == "" {
 			// uses https://github.com/gabriel-vasile/mimetype library to determine the content type.
 			// Fixes https://github.com/ipfs/go-ipfs/issues/7252
 			mimeType, err := mimetype.DetectReader(content)
@@ -80,6 +82,19 @@ func (i *gatewayHandler) serveFile(ctx context.Context, w http.ResponseWriter, r
 	// (unifies behavior across gateways and web browsers)
 	w.Header().Set("Content-Type", ctype)
 
+	if i.config.NoSniff {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}
+	if i.config.ContentSecurityPolicy != "" {
+		w.Header().Set("Content-Security-Policy", i.config.ContentSecurityPolicy)
+	}
+
+	// Path gateways serve every site from the same origin: rendering
+	// user content there would let it script the others.
+	if _, ownOrigin := r.Context().Value("gw-hostname").(string); !ownOrigin && matchContentType(i.config.ForceDownloadTypes, ctype) {
+		setContentDispositionHeader(w, name, "attachment")
+	}
+
 	// special fixup around redirects
 	w = &statusResponseWriter{w}
 
@@ -93,3 +108,19 @@ func (i *gatewayHandler) serveFile(ctx context.Context, w http.ResponseWriter, r
 		i.unixfsFileGetMetric.WithLabelValues(contentPath.Namespace()).Observe(time.Since(begin).Seconds())
 	}
 }
+
+// matchContentType returns whether ctype is one of types, ignoring its
+// parameters. Types ending with "/*" match any subtype.
+func matchContentType(types []string, ctype string) bool {
+	mediatype, _, err := mime.ParseMediaType(ctype)
+	if err != nil {
+		mediatype = strings.ToLower(ctype)
+	}
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == mediatype || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediatype, t[:len(t)-1])) {
+			return true
+		}
+	}
+	return false
+}
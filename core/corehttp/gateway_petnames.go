@@ -0,0 +1,46 @@
+package corehttp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	gopath "path"
+	"strings"
+
+	ipath "github.com/ipfs/interface-go-ipfs-core/path"
+
+	core "github.com/ipfs/go-ipfs/core"
+)
+
+// PetnameOption redirects the gateway requests for prefix/<name>/<path> to
+// <path> under the content path of the petname <name>, as configured in
+// Petnames. The redirects are temporary (HTTP 302) as petnames can be
+// changed at any time.
+func PetnameOption(prefix string) ServeOption {
+	return func(n *core.IpfsNode, _ net.Listener, mux *http.ServeMux) (*http.ServeMux, error) {
+		switch {
+		case !strings.HasPrefix(prefix, "/") || prefix == "/" || gopath.Clean(prefix) != prefix:
+			return nil, fmt.Errorf("invalid Petnames.GatewayPrefix %q: expected an absolute URL path such as /petname", prefix)
+		case prefix == "/ipfs" || prefix == "/ipns" || prefix == "/api" || prefix == "/p2p":
+			return nil, fmt.Errorf("invalid Petnames.GatewayPrefix %q: the path is used by the gateway", prefix)
+		}
+
+		mux.HandleFunc(prefix+"/", func(w http.ResponseWriter, r *http.Request) {
+			cfg, err := n.Repo.Config()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			target, ok := cfg.Petnames.Resolve(strings.TrimPrefix(r.URL.Path, prefix+"/"))
+			if !ok || ipath.New(target).IsValid() != nil {
+				http.NotFound(w, r)
+				return
+			}
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusFound)
+		})
+		return mux, nil
+	}
+}
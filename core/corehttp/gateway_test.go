@@ -681,3 +681,23 @@ func TestEtagMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchContentType(t *testing.T) {
+	types := []string{"text/html", "image/svg+xml", "Application/*"}
+	for _, tc := range []struct {
+		ctype string
+		match bool
+	}{
+		{"text/html", true},
+		{"text/html; charset=utf-8", true},
+		{"image/svg+xml", true},
+		{"application/xhtml+xml", true},
+		{"text/plain", false},
+		{"image/png", false},
+		{"applications/x", false},
+	} {
+		if match := matchContentType(types, tc.ctype); match != tc.match {
+			t.Errorf("matchContentType(%q) = %t; want %t", tc.ctype, match, tc.match)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package corehttp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	config "github.com/ipfs/go-ipfs/config"
+	core "github.com/ipfs/go-ipfs/core"
+	proxyproto "github.com/pires/go-proxyproto"
+)
+
+// wrapListener applies the connection level settings of lcfg to lis. The
+// PROXY protocol header is read before anything else, so it has to wrap
+// the raw listener before TLS does.
+func wrapListener(lis net.Listener, lcfg config.HTTPListener) (net.Listener, error) {
+	if lcfg.ProxyProtocol.WithDefault(false) {
+		policy := func(net.Addr) (proxyproto.Policy, error) {
+			return proxyproto.REQUIRE, nil
+		}
+		if len(lcfg.TrustedProxies) > 0 {
+			trusted, err := parseTrustedProxies(lcfg.TrustedProxies)
+			if err != nil {
+				return nil, err
+			}
+			// Trusted proxies may send the header, anyone else connecting
+			// directly may not.
+			policy = func(upstream net.Addr) (proxyproto.Policy, error) {
+				if ip := addrIP(upstream); ip != nil && trusted.contains(ip) {
+					return proxyproto.USE, nil
+				}
+				return proxyproto.REJECT, nil
+			}
+		}
+		lis = &proxyproto.Listener{
+			Listener: lis,
+			Policy:   policy,
+		}
+	}
+
+	certFile := lcfg.TLSCertFile.WithDefault("")
+	keyFile := lcfg.TLSKeyFile.WithDefault("")
+	switch {
+	case certFile == "" && keyFile == "":
+	case certFile == "" || keyFile == "":
+		return nil, fmt.Errorf("both TLSCertFile and TLSKeyFile must be set to enable TLS")
+	default:
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading TLS certificate: %w", err)
+		}
+		lis = tls.NewListener(lis, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+	}
+
+	return lis, nil
+}
+
+type ipNets []*net.IPNet
+
+func (nets ipNets) contains(ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies parses a list of IP addresses and CIDR ranges.
+func parseTrustedProxies(proxies []string) (ipNets, error) {
+	nets := make(ipNets, 0, len(proxies))
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy address %q", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %w", p, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP
+	case *net.UDPAddr:
+		return a.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+// clientIP returns the address of the client that originated r. Forwarding
+// headers are only honored when the request came from a trusted proxy, and
+// X-Forwarded-For is walked from the right so a client can't spoof its
+// address by sending the header itself.
+func clientIP(r *http.Request, trusted ipNets) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote := net.ParseIP(host)
+	if remote == nil || !trusted.contains(remote) {
+		return remote
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		client := remote
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			client = ip
+			if !trusted.contains(ip) {
+				break
+			}
+		}
+		return client
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip
+	}
+	return remote
+}
+
+// TrustedProxiesOption rewrites the remote address of requests relayed by
+// one of the trusted proxies to the address of the original client, so
+// everything further down the handler chain sees the real client.
+func TrustedProxiesOption(proxies []string) ServeOption {
+	return func(_ *core.IpfsNode, _ net.Listener, mux *http.ServeMux) (*http.ServeMux, error) {
+		trusted, err := parseTrustedProxies(proxies)
+		if err != nil {
+			return nil, err
+		}
+
+		childMux := http.NewServeMux()
+		mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := clientIP(r, trusted); ip != nil {
+				r.RemoteAddr = net.JoinHostPort(ip.String(), "0")
+			}
+			childMux.ServeHTTP(w, r)
+		}))
+		return childMux, nil
+	}
+}
@@ -0,0 +1,58 @@
+package corehttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := parseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		remoteAddr string
+		xff        string
+		xRealIP    string
+		expected   string
+	}{
+		// direct connection, headers must be ignored
+		{"203.0.113.5:1234", "198.51.100.7", "", "203.0.113.5"},
+		{"203.0.113.5:1234", "", "198.51.100.7", "203.0.113.5"},
+		// trusted proxy without forwarding headers
+		{"10.1.2.3:1234", "", "", "10.1.2.3"},
+		// trusted proxy, spoofed leftmost entry is skipped
+		{"192.0.2.1:1234", "1.1.1.1, 198.51.100.7", "", "198.51.100.7"},
+		// chain of trusted proxies
+		{"10.1.2.3:1234", "198.51.100.7, 10.9.9.9", "", "198.51.100.7"},
+		// every hop trusted
+		{"10.1.2.3:1234", "10.2.2.2, 10.9.9.9", "", "10.2.2.2"},
+		// X-Real-IP fallback
+		{"10.1.2.3:1234", "", "198.51.100.7", "198.51.100.7"},
+	}
+
+	for _, tc := range tcs {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tc.remoteAddr
+		if tc.xff != "" {
+			r.Header.Set("X-Forwarded-For", tc.xff)
+		}
+		if tc.xRealIP != "" {
+			r.Header.Set("X-Real-IP", tc.xRealIP)
+		}
+		if ip := clientIP(r, trusted); ip.String() != tc.expected {
+			t.Errorf("%+v: expected %s, got %s", tc, tc.expected, ip)
+		}
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	if _, err := parseTrustedProxies([]string{"not-an-ip"}); err == nil {
+		t.Error("expected error for invalid address")
+	}
+	if _, err := parseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected error for invalid range")
+	}
+}
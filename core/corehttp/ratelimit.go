@@ -0,0 +1,163 @@
+package corehttp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	core "github.com/ipfs/go-ipfs/core"
+
+	config "github.com/ipfs/go-ipfs/config"
+	prometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultAPIRateLimit is the number of requests per second each client may
+// make when API.RateLimit is enabled without an explicit Rate.
+const DefaultAPIRateLimit = 100
+
+// rateLimitIdle is how long a client has to stay away before its bucket and
+// metrics are dropped.
+const rateLimitIdle = 10 * time.Minute
+
+var apiRateLimitRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ipfs",
+		Subsystem: "http",
+		Name:      "api_ratelimit_requests_total",
+		Help:      "Number of RPC API requests per client, by whether they were allowed or rate limited.",
+	},
+	[]string{"client", "result"},
+)
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	lk        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(rate, burst int64) *rateLimiter {
+	return &rateLimiter{
+		rate:    float64(rate),
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes a token from the client's bucket. When the bucket is empty it
+// returns false and how long until the next token is available.
+func (rl *rateLimiter) allow(client string) (bool, time.Duration) {
+	rl.lk.Lock()
+	defer rl.lk.Unlock()
+
+	now := rl.now()
+	if now.Sub(rl.lastSweep) > rateLimitIdle {
+		rl.sweep(now)
+	}
+
+	b, ok := rl.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: rl.burst, last: now}
+		rl.buckets[client] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * rl.rate
+	if b.tokens > rl.burst {
+		b.tokens = rl.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep forgets clients that have been idle for a while, so neither the
+// bucket table nor the metric labels grow without bound.
+func (rl *rateLimiter) sweep(now time.Time) {
+	for client, b := range rl.buckets {
+		if now.Sub(b.last) > rateLimitIdle {
+			delete(rl.buckets, client)
+			apiRateLimitRequests.DeleteLabelValues(client, "allowed")
+			apiRateLimitRequests.DeleteLabelValues(client, "limited")
+		}
+	}
+	rl.lastSweep = now
+}
+
+// rateLimitClient identifies the client of an API request by its IP address.
+// Nothing the client sends is trusted, as a client could get a new bucket by
+// changing it. IPv6 clients are identified by their /64, which they can pick
+// addresses from at will.
+func rateLimitClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.To4() != nil {
+		return host
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+// APIRateLimitOption limits the rate of RPC API requests per client, as
+// configured in API.RateLimit. Requests over the limit are answered with
+// 429 Too Many Requests. The listeners served with the same option share the
+// limit of each client.
+func APIRateLimitOption(cfg config.APIRateLimit) ServeOption {
+	if !cfg.Enabled.WithDefault(false) {
+		return func(_ *core.IpfsNode, _ net.Listener, mux *http.ServeMux) (*http.ServeMux, error) {
+			return mux, nil
+		}
+	}
+
+	rate := cfg.Rate.WithDefault(DefaultAPIRateLimit)
+	burst := cfg.Burst.WithDefault(rate)
+	rl := newRateLimiter(rate, burst)
+
+	return func(_ *core.IpfsNode, _ net.Listener, mux *http.ServeMux) (*http.ServeMux, error) {
+		if rate <= 0 || burst <= 0 {
+			return nil, fmt.Errorf("API.RateLimit: Rate and Burst must be positive")
+		}
+		if err := prometheus.Register(apiRateLimitRequests); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				return nil, err
+			}
+		}
+
+		childMux := http.NewServeMux()
+		mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, APIPath+"/") {
+				childMux.ServeHTTP(w, r)
+				return
+			}
+
+			client := rateLimitClient(r)
+			ok, wait := rl.allow(client)
+			if !ok {
+				apiRateLimitRequests.WithLabelValues(client, "limited").Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(wait/time.Second)+1))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			apiRateLimitRequests.WithLabelValues(client, "allowed").Inc()
+			childMux.ServeHTTP(w, r)
+		}))
+		return childMux, nil
+	}
+}
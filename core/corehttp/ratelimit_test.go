@@ -0,0 +1,95 @@
+package corehttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	rl := newRateLimiter(2, 3)
+	rl.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := rl.allow("a"); !ok {
+			t.Fatalf("request %d within the burst was limited", i)
+		}
+	}
+	ok, wait := rl.allow("a")
+	if ok {
+		t.Fatal("request past the burst was allowed")
+	}
+	if wait != 500*time.Millisecond {
+		t.Fatalf("expected to wait 500ms, got %s", wait)
+	}
+
+	// other clients have their own bucket
+	if ok, _ := rl.allow("b"); !ok {
+		t.Fatal("request from another client was limited")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := rl.allow("a"); !ok {
+		t.Fatal("request after refill was limited")
+	}
+	if ok, _ := rl.allow("a"); ok {
+		t.Fatal("bucket refilled faster than the rate")
+	}
+
+	now = now.Add(time.Hour)
+	rl.allow("c")
+	if _, ok := rl.buckets["a"]; ok {
+		t.Fatal("idle client was not swept")
+	}
+}
+
+func TestRateLimitClient(t *testing.T) {
+	for _, c := range []struct {
+		remote, auth, client string
+	}{
+		{"1.2.3.4:5001", "", "1.2.3.4"},
+		{"1.2.3.4:5002", "Bearer random", "1.2.3.4"},
+		{"[2001:db8:1:2:3:4:5:6]:5001", "", "2001:db8:1:2::/64"},
+		{"[2001:db8:1:2:ffff::1]:5001", "", "2001:db8:1:2::/64"},
+	} {
+		r := &http.Request{RemoteAddr: c.remote, Header: http.Header{}}
+		if c.auth != "" {
+			r.Header.Set("Authorization", c.auth)
+		}
+		if got := rateLimitClient(r); got != c.client {
+			t.Errorf("expected %s to be identified as %s, got %s", c.remote, c.client, got)
+		}
+	}
+}
+
+func TestAPIRateLimitOption(t *testing.T) {
+	var cfg config.APIRateLimit
+	if err := json.Unmarshal([]byte(`{"Enabled": true, "Rate": 1, "Burst": 2}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	opt := APIRateLimitOption(cfg)
+	// two listeners, like the API and a read-only one
+	var handlers []http.Handler
+	for i := 0; i < 2; i++ {
+		h, err := makeHandler(nil, nil, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handlers = append(handlers, h)
+	}
+
+	for i, expected := range []int{http.StatusNotFound, http.StatusNotFound, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		r := httptest.NewRequest(http.MethodPost, APIPath+"/id", nil)
+		r.RemoteAddr = "1.2.3.4:5001"
+		w := httptest.NewRecorder()
+		handlers[i%2].ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatalf("expected request %d to be answered with %d, got %d", i, expected, w.Code)
+		}
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/ipfs/go-ipfs/core"
 	"github.com/ipfs/go-ipfs/gc"
+	"github.com/ipfs/go-ipfs/journal"
 	"github.com/ipfs/go-ipfs/repo"
 
 	"github.com/dustin/go-humanize"
@@ -83,14 +85,77 @@ func BestEffortRoots(filesRoot *mfs.Root) ([]cid.Cid, error) {
 	return []cid.Cid{rootDag.Cid()}, nil
 }
 
+// gcOptions returns the GC keep rules set in the node config, or nil, and
+// the options of the mark phase.
+func gcOptions(n *core.IpfsNode) (*gc.KeepCodecs, gc.MarkOptions, error) {
+	cfg, err := n.Repo.Config()
+	if err != nil {
+		return nil, gc.MarkOptions{}, err
+	}
+	mark := gc.MarkOptions{
+		Workers:     int(cfg.Datastore.GCMarkWorkers.WithDefault(gc.DefaultMarkWorkers)),
+		MaxInMemory: int(cfg.Datastore.GCMarkMaxInMemory.WithDefault(0)),
+	}
+	if len(cfg.Datastore.GCKeepCodecs) == 0 {
+		return nil, mark, nil
+	}
+	keep, err := gc.NewKeepCodecs(cfg.Datastore.GCKeepCodecs)
+	return keep, mark, err
+}
+
 func GarbageCollect(n *core.IpfsNode, ctx context.Context) error {
+	if repo.IsReadOnly(n.Repo) {
+		return repo.ErrReadOnly
+	}
 	roots, err := BestEffortRoots(n.FilesRoot)
 	if err != nil {
 		return err
 	}
-	rmed := gc.GC(ctx, n.Blockstore, n.Repo.Datastore(), n.Pinning, roots)
+	keep, mark, err := gcOptions(n)
+	if err != nil {
+		return err
+	}
+	rmed := gc.GCKeep(ctx, n.Blockstore, n.Repo.Datastore(), n.Pinning, roots, keep, n.WalkPool, mark)
 
-	return CollectResult(ctx, rmed, nil)
+	return CollectResult(ctx, journalGC(ctx, n, rmed), nil)
+}
+
+// journalGC records the garbage collection run outputting gcOut in the
+// journal of the node, once it has completed.
+func journalGC(ctx context.Context, n *core.IpfsNode, gcOut <-chan gc.Result) <-chan gc.Result {
+	if n.Journal == nil {
+		return gcOut
+	}
+
+	out := make(chan gc.Result, cap(gcOut))
+	go func() {
+		defer close(out)
+
+		start := time.Now()
+		var removed, kept, errs int
+		for res := range gcOut {
+			switch {
+			case res.Error != nil:
+				errs++
+			case res.KeyRemoved.Defined():
+				removed++
+			case res.KeyKept.Defined():
+				kept++
+			}
+			select {
+			case out <- res:
+			case <-ctx.Done():
+			}
+		}
+
+		n.Journal.Record(n.Context(), journal.TypeGC, map[string]string{
+			"removed":  strconv.Itoa(removed),
+			"kept":     strconv.Itoa(kept),
+			"errors":   strconv.Itoa(errs),
+			"duration": time.Since(start).String(),
+		})
+	}()
+	return out
 }
 
 // CollectResult collects the output of a garbage collection run and calls the
@@ -149,14 +214,21 @@ func (e *MultiError) Error() string {
 
 func GarbageCollectAsync(n *core.IpfsNode, ctx context.Context) <-chan gc.Result {
 	roots, err := BestEffortRoots(n.FilesRoot)
-	if err != nil {
-		out := make(chan gc.Result)
-		out <- gc.Result{Error: err}
-		close(out)
-		return out
+	if err == nil && repo.IsReadOnly(n.Repo) {
+		err = repo.ErrReadOnly
+	}
+	if err == nil {
+		var keep *gc.KeepCodecs
+		var mark gc.MarkOptions
+		if keep, mark, err = gcOptions(n); err == nil {
+			return journalGC(ctx, n, gc.GCKeep(ctx, n.Blockstore, n.Repo.Datastore(), n.Pinning, roots, keep, n.WalkPool, mark))
+		}
 	}
 
-	return gc.GC(ctx, n.Blockstore, n.Repo.Datastore(), n.Pinning, roots)
+	out := make(chan gc.Result, 1)
+	out <- gc.Result{Error: err}
+	close(out)
+	return out
 }
 
 func PeriodicGC(ctx context.Context, node *core.IpfsNode) error {
@@ -177,6 +249,10 @@ func PeriodicGC(ctx context.Context, node *core.IpfsNode) error {
 		// if duration is 0, it means GC is disabled.
 		return nil
 	}
+	if repo.IsReadOnly(node.Repo) {
+		log.Warn("the repo is read-only, periodic garbage collection is disabled")
+		return nil
+	}
 
 	gc, err := NewGC(node)
 	if err != nil {
@@ -1,13 +1,16 @@
 package coreunix
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	gopath "path"
 	"strconv"
+	"strings"
 
+	"github.com/gabriel-vasile/mimetype"
 	"github.com/ipfs/go-cid"
 	bstore "github.com/ipfs/go-ipfs-blockstore"
 	chunker "github.com/ipfs/go-ipfs-chunker"
@@ -80,6 +83,14 @@ type Adder struct {
 	tempRoot   cid.Cid
 	CidBuilder cid.Builder
 	liveNodes  uint64
+
+	// ChunkerByContentType picks the chunker from the content type of
+	// each file when Chunker is empty. See config.Import.
+	ChunkerByContentType map[string]string
+
+	// RootPinned is set by PinRoot when the root was already pinned
+	// recursively, before this import pinned it.
+	RootPinned bool
 }
 
 func (adder *Adder) mfsRoot() (*mfs.Root, error) {
@@ -101,9 +112,52 @@ func (adder *Adder) SetMfsRoot(r *mfs.Root) {
 	adder.mroot = r
 }
 
+// contentTypeSniffLen is the number of bytes read from the start of a file
+// to detect its content type.
+const contentTypeSniffLen = 3072
+
+// ChunkerForContent detects the content type of the data in reader and
+// returns the matching chunker from byType, or "" if none matches. The
+// returned reader yields the whole data, including the bytes read to detect
+// the content type.
+func ChunkerForContent(reader io.Reader, byType map[string]string) (string, io.Reader, error) {
+	head := make([]byte, contentTypeSniffLen)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+	reader = io.MultiReader(bytes.NewReader(head), reader)
+
+	// Try the detected type and then the more generic ones it derives
+	// from (e.g. application/json, then text/plain), each first by exact
+	// match and then by "type/*".
+	for mt := mimetype.Detect(head); mt != nil; mt = mt.Parent() {
+		mediaType := strings.TrimSpace(strings.SplitN(mt.String(), ";", 2)[0])
+		if c, ok := byType[mediaType]; ok {
+			return c, reader, nil
+		}
+		if c, ok := byType[strings.SplitN(mediaType, "/", 2)[0]+"/*"]; ok {
+			return c, reader, nil
+		}
+	}
+	return "", reader, nil
+}
+
 // Constructs a node from reader's data, and adds it. Doesn't pin.
 func (adder *Adder) add(reader io.Reader) (ipld.Node, error) {
-	chnk, err := chunker.FromString(reader, adder.Chunker)
+	chunkerStr := adder.Chunker
+	// NoCopy needs the file itself to reach the DAG builder, so it can't
+	// be sniffed.
+	if chunkerStr == "" && len(adder.ChunkerByContentType) > 0 && !adder.NoCopy {
+		var err error
+		chunkerStr, reader, err = ChunkerForContent(reader, adder.ChunkerByContentType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chnk, err := chunker.FromString(reader, chunkerStr)
 	if err != nil {
 		return nil, err
 	}
@@ -182,6 +236,10 @@ func (adder *Adder) PinRoot(ctx context.Context, root ipld.Node) error {
 		adder.tempRoot = rnk
 	}
 
+	_, adder.RootPinned, err = adder.pinning.IsPinnedWithType(ctx, rnk, pin.Recursive)
+	if err != nil {
+		return err
+	}
 	adder.pinning.PinWithMode(rnk, pin.Recursive)
 	return adder.pinning.Flush(ctx)
 }
@@ -319,6 +319,44 @@ func TestAddWPosInfoAndRawLeafs(t *testing.T) {
 	testAddWPosInfo(t, true)
 }
 
+func TestChunkerForContent(t *testing.T) {
+	byType := map[string]string{
+		"image/png":  "size-1024",
+		"text/*":     "buzhash",
+		"text/plain": "size-2048",
+	}
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 5000)...)
+
+	for _, tc := range []struct {
+		name    string
+		data    []byte
+		chunker string
+	}{
+		{"exact", png, "size-1024"},
+		{"exact before wildcard", []byte("just some text"), "size-2048"},
+		{"wildcard", []byte("<html><body>hi</body></html>"), "buzhash"},
+		{"no match", []byte{0xca, 0xfe, 0xba, 0xbe, 0, 0, 0, 2, 0xff}, ""},
+		{"empty", nil, "size-2048"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, r, err := ChunkerForContent(bytes.NewReader(tc.data), byType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c != tc.chunker {
+				t.Errorf("expected chunker %q, got %q", tc.chunker, c)
+			}
+			out, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out, tc.data) {
+				t.Error("reader does not return the original data")
+			}
+		})
+	}
+}
+
 type testBlockstore struct {
 	blockstore.GCBlockstore
 	expectedPath         string
@@ -366,3 +404,32 @@ func (fi *dummyFileInfo) Mode() os.FileMode  { return 0 }
 func (fi *dummyFileInfo) ModTime() time.Time { return fi.modTime }
 func (fi *dummyFileInfo) IsDir() bool        { return false }
 func (fi *dummyFileInfo) Sys() interface{}   { return nil }
+
+func TestAddRootPinned(t *testing.T) {
+	r := &repo.Mock{
+		C: config.Config{
+			Identity: config.Identity{
+				PeerID: testPeerID, // required by offline node
+			},
+		},
+		D: syncds.MutexWrap(datastore.NewMapDatastore()),
+	}
+	node, err := core.NewNode(context.Background(), &core.BuildCfg{Repo: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []bool{false, true} {
+		adder, err := NewAdder(context.Background(), node.Pinning, node.Blockstore, node.DAG)
+		if err != nil {
+			t.Fatal(err)
+		}
+		adder.Pin = true
+		if _, err := adder.AddAllAndPin(context.Background(), files.NewBytesFile([]byte("pinned twice"))); err != nil {
+			t.Fatal(err)
+		}
+		if adder.RootPinned != expected {
+			t.Fatalf("expected the root to have been pinned before: %t", expected)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package node
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	provider "github.com/ipfs/go-ipfs-provider"
+	q "github.com/ipfs/go-ipfs-provider/queue"
+	"github.com/ipfs/go-ipfs-provider/simple"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/multiformats/go-multihash"
+	"go.uber.org/fx"
+
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+// AnnounceProtocol is the protocol nodes use to have their provider records
+// announced by the peer set in Provider.Announcer.
+const AnnounceProtocol = protocol.ID("/ipfs/announce/0.1.0")
+
+const (
+	// announceMaxCidLen bounds the size of the records read by the
+	// announcer.
+	announceMaxCidLen      = 256
+	announceMaxResponseLen = 1024
+	announceTimeout        = time.Minute
+
+	announceOK = 0
+)
+
+// ParseAnnouncer parses Provider.Announcer: a peer ID, or a multiaddr
+// ending with /p2p/<peer ID>.
+func ParseAnnouncer(s string) (peer.AddrInfo, error) {
+	if strings.HasPrefix(s, "/") {
+		addr, err := ma.NewMultiaddr(s)
+		if err != nil {
+			return peer.AddrInfo{}, fmt.Errorf("invalid Provider.Announcer: %w", err)
+		}
+		ai, err := peer.AddrInfoFromP2pAddr(addr)
+		if err != nil {
+			return peer.AddrInfo{}, fmt.Errorf("invalid Provider.Announcer: %w", err)
+		}
+		return *ai, nil
+	}
+	id, err := peer.Decode(s)
+	if err != nil {
+		return peer.AddrInfo{}, fmt.Errorf("invalid Provider.Announcer: %w", err)
+	}
+	return peer.AddrInfo{ID: id}, nil
+}
+
+// announcerRouter sends the records of a node delegating its announcements
+// to the announcer. Each batch of records is sent on a single stream, as the
+// multihashes prefixed by their varint length.
+type announcerRouter struct {
+	h         host.Host
+	announcer peer.ID
+}
+
+func (r *announcerRouter) ProvideMany(ctx context.Context, keys []multihash.Multihash) error {
+	s, err := r.h.NewStream(ctx, r.announcer, AnnounceProtocol)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Reset()
+		case <-done:
+		}
+	}()
+
+	w := bufio.NewWriter(s)
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, k := range keys {
+		_ = s.SetWriteDeadline(time.Now().Add(announceTimeout))
+		n := binary.PutUvarint(buf, uint64(len(k)))
+		if _, err := w.Write(buf[:n]); err != nil {
+			s.Reset()
+			return err
+		}
+		if _, err := w.Write(k); err != nil {
+			s.Reset()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		s.Reset()
+		return err
+	}
+	if err := s.CloseWrite(); err != nil {
+		s.Reset()
+		return err
+	}
+
+	_ = s.SetReadDeadline(time.Now().Add(announceTimeout))
+	resp, err := ioutil.ReadAll(io.LimitReader(s, announceMaxResponseLen))
+	if err != nil {
+		s.Reset()
+		return err
+	}
+	if len(resp) == 0 {
+		return errors.New("announcer closed the stream")
+	}
+	if resp[0] != announceOK {
+		return fmt.Errorf("announcer refused the records: %s", resp[1:])
+	}
+	return nil
+}
+
+// Ready returns true, the announcer is dialed when records are sent.
+func (r *announcerRouter) Ready() bool {
+	return true
+}
+
+func newAnnouncerRouter(h host.Host, announcer peer.AddrInfo) *announcerRouter {
+	if len(announcer.Addrs) > 0 {
+		h.Peerstore().AddAddrs(announcer.ID, announcer.Addrs, peerstore.PermanentAddrTTL)
+	}
+	return &announcerRouter{h: h, announcer: announcer.ID}
+}
+
+// AnnouncerProviderSys creates a provider system sending its records to the
+// announcer, in batches.
+func AnnouncerProviderSys(announcer peer.AddrInfo, reprovideInterval string) interface{} {
+	return func(lc fx.Lifecycle, h host.Host, q *q.Queue, keyProvider simple.KeyChanFunc, repo repo.Repo) (provider.System, error) {
+		return newBatchedProviderSys(lc, newAnnouncerRouter(h, announcer), q, keyProvider, repo, true, reprovideInterval)
+	}
+}
+
+// AnnouncerProviders groups the units of a node that has its provider
+// records announced by another peer instead of announcing them itself.
+func AnnouncerProviders(announcer peer.AddrInfo, reprovideStrategy string, reprovideInterval string) fx.Option {
+	return fx.Options(
+		fx.Provide(ProviderQueue),
+		keyProviderStrategy(reprovideStrategy),
+		fx.Provide(AnnouncerProviderSys(announcer, reprovideInterval)),
+	)
+}
+
+// AnnounceService announces the provider records sent by the peers in
+// Provider.AnnounceFor. The records are queued in the provider system of
+// this node, and announced as provided by this node.
+func AnnounceService(announceFor []string) interface{} {
+	return func(h host.Host, sys provider.System) error {
+		allowed := make(map[peer.ID]struct{}, len(announceFor))
+		for _, s := range announceFor {
+			id, err := peer.Decode(s)
+			if err != nil {
+				return fmt.Errorf("invalid peer in Provider.AnnounceFor: %w", err)
+			}
+			allowed[id] = struct{}{}
+		}
+
+		h.SetStreamHandler(AnnounceProtocol, announceHandler(allowed, sys))
+		return nil
+	}
+}
+
+// announceHandler queues the records sent by the allowed peers in sys.
+func announceHandler(allowed map[peer.ID]struct{}, sys provider.System) network.StreamHandler {
+	return func(s network.Stream) {
+		if _, ok := allowed[s.Conn().RemotePeer()]; !ok {
+			s.Reset()
+			return
+		}
+		err := provideRecords(s, sys)
+		if err != nil {
+			// let the peer finish sending, to read the error
+			_ = s.SetReadDeadline(time.Now().Add(announceTimeout))
+			_, _ = io.Copy(ioutil.Discard, s)
+		}
+		_ = s.SetWriteDeadline(time.Now().Add(announceTimeout))
+		if err != nil {
+			_, _ = s.Write(append([]byte{1}, err.Error()...))
+		} else {
+			_, _ = s.Write([]byte{announceOK})
+		}
+		s.Close()
+	}
+}
+
+// provideRecords queues the records read from s in sys, until the peer
+// closes its side of the stream.
+func provideRecords(s network.Stream, sys provider.System) error {
+	r := bufio.NewReader(s)
+	for {
+		_ = s.SetReadDeadline(time.Now().Add(announceTimeout))
+		l, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if l > announceMaxCidLen {
+			return fmt.Errorf("record of %d bytes", l)
+		}
+		buf := make([]byte, l)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		mh, err := multihash.Cast(buf)
+		if err != nil {
+			return err
+		}
+		if err := sys.Provide(cid.NewCidV1(cid.Raw, mh)); err != nil {
+			return err
+		}
+	}
+}
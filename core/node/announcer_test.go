@@ -0,0 +1,101 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	provider "github.com/ipfs/go-ipfs-provider"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/multiformats/go-multihash"
+)
+
+// announcedRecords is the provider system of an announcer, recording the
+// CIDs it is asked to provide.
+type announcedRecords struct {
+	provider.System
+
+	lk       sync.Mutex
+	provided []cid.Cid
+	err      error
+}
+
+func (r *announcedRecords) Provide(c cid.Cid) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.provided = append(r.provided, c)
+	return nil
+}
+
+func TestAnnouncerRouter(t *testing.T) {
+	ctx := context.Background()
+	mn := mocknet.New()
+	announcer, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	var streams int32
+	sys := &announcedRecords{}
+	handler := announceHandler(map[peer.ID]struct{}{client.ID(): {}}, sys)
+	announcer.SetStreamHandler(AnnounceProtocol, func(s network.Stream) {
+		atomic.AddInt32(&streams, 1)
+		handler(s)
+	})
+
+	var keys []multihash.Multihash
+	for _, s := range []string{"a", "b", "c"} {
+		mh, err := multihash.Sum([]byte(s), multihash.SHA2_256, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, mh)
+	}
+
+	r := newAnnouncerRouter(client, announcer.Peerstore().PeerInfo(announcer.ID()))
+	if err := r.ProvideMany(ctx, keys); err != nil {
+		t.Fatal(err)
+	}
+	if streams := atomic.LoadInt32(&streams); streams != 1 {
+		t.Fatalf("expected the records on a single stream, got %d streams", streams)
+	}
+	if len(sys.provided) != len(keys) {
+		t.Fatalf("expected %d records to be provided, got %d", len(keys), len(sys.provided))
+	}
+	for i, c := range sys.provided {
+		if !bytes.Equal(c.Hash(), keys[i]) {
+			t.Fatalf("expected %s to be provided, got %s", keys[i], c.Hash())
+		}
+	}
+
+	sys.err = errors.New("queue closed")
+	if err := r.ProvideMany(ctx, keys); err == nil || !strings.Contains(err.Error(), "queue closed") {
+		t.Fatalf("expected the announcer to refuse the records, got %v", err)
+	}
+
+	r = newAnnouncerRouter(other, announcer.Peerstore().PeerInfo(announcer.ID()))
+	if err := r.ProvideMany(ctx, keys); err == nil {
+		t.Fatal("expected the records of a peer not in Provider.AnnounceFor to be refused")
+	}
+}
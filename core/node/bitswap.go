@@ -25,8 +25,18 @@ const (
 
 // OnlineExchange creates new LibP2P backed block exchange (BitSwap)
 func OnlineExchange(cfg *config.Config, provide bool) interface{} {
-	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, host host.Host, rt routing.Routing, bs blockstore.GCBlockstore) exchange.Interface {
-		bitswapNetwork := network.NewFromIpfsHost(host, rt)
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, host host.Host, rt routing.Routing, bs blockstore.GCBlockstore, index *PresenceIndex, pc *ProtocolCache, lookups *LookupCache) (exchange.Interface, error) {
+		policy, err := NewRetrievalPolicy(cfg.Retrieval)
+		if err != nil {
+			return nil, err
+		}
+
+		var cr routing.ContentRouting = lookups.ContentRouting(rt)
+		if policy != nil && !policy.has(RetrievalRouting) {
+			cr = noProviderSearch{rt}
+		}
+		cr = pc.ContentRouting(cr, bitswapProtocols...)
+		bitswapNetwork := network.NewFromIpfsHost(host, cr)
 
 		var internalBsCfg config.InternalBitswap
 		if cfg.Internal.Bitswap != nil {
@@ -40,13 +50,21 @@ func OnlineExchange(cfg *config.Config, provide bool) interface{} {
 			bitswap.EngineTaskWorkerCount(int(internalBsCfg.EngineTaskWorkerCount.WithDefault(DefaultEngineTaskWorkerCount))),
 			bitswap.MaxOutstandingBytesPerPeer(int(internalBsCfg.MaxOutstandingBytesPerPeer.WithDefault(DefaultMaxOutstandingBytesPerPeer))),
 		}
+		if policy != nil {
+			opts = append(opts, bitswap.ProviderSearchDelay(policy.providerSearchDelay()))
+		}
+		if index != nil {
+			bs = &presenceBlockstore{GCBlockstore: bs, index: index}
+		}
 		exch := bitswap.New(helpers.LifecycleCtx(mctx, lc), bitswapNetwork, bs, opts...)
 		lc.Append(fx.Hook{
 			OnStop: func(ctx context.Context) error {
 				return exch.Close()
 			},
 		})
-		return exch
-
+		if policy != nil {
+			return policy.Exchange(exch.(exchange.SessionExchange)), nil
+		}
+		return exch, nil
 	}
 }
@@ -114,6 +114,10 @@ func (cfg *BuildCfg) options(ctx context.Context) (fx.Option, *cfg.Config) {
 		return fx.Error(err), nil
 	}
 
+	if cfg.getOpt("readonly") || conf.Datastore.ReadOnly.WithDefault(false) {
+		cfg.Repo = repo.ReadOnly(cfg.Repo)
+	}
+
 	return fx.Options(
 		repoOption,
 		hostOption,
@@ -0,0 +1,252 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	proto "github.com/gogo/protobuf/proto"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	provider "github.com/ipfs/go-ipfs-provider"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-ipns"
+	pb "github.com/ipfs/go-ipns/pb"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/journal"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+const defaultCaretakerInterval = 4 * time.Hour
+
+var caretakerKey = datastore.NewKey("/local/caretaker")
+
+// caretaker republishes the IPNS records of other publishers, and keeps
+// their content pinned and provided, as configured in Caretaker.
+type caretaker struct {
+	routing  routing.Routing
+	ds       datastore.Datastore
+	pinning  pin.Pinner
+	dag      format.DAGService
+	gcLocker blockstore.GCLocker
+	provider provider.System
+	journal  *journal.Journal
+
+	names []peer.ID
+	cids  []cid.Cid
+
+	// records are the last records of the names, republished while the
+	// routing doesn't find them anymore.
+	records map[peer.ID]*pb.IpnsEntry
+}
+
+// republishName republishes the newest record of id, the one found in the
+// routing or the last one kept here.
+func (c *caretaker) republishName(ctx context.Context, id peer.ID) error {
+	entry := c.records[id]
+	found, err := FetchIpnsRecord(ctx, c.routing, id)
+	if err != nil {
+		return err
+	}
+	if found != nil {
+		if entry == nil {
+			entry = found
+		} else if cmp, err := ipns.Compare(found, entry); err == nil && cmp > 0 {
+			entry = found
+		}
+	}
+	if entry == nil {
+		return fmt.Errorf("no record found")
+	}
+
+	eol, err := ipns.GetEOL(entry)
+	if err != nil {
+		return err
+	}
+	if time.Now().After(eol) {
+		delete(c.records, id)
+		return fmt.Errorf("the record expired on %s, its publisher must publish it again", eol.Format(time.RFC3339))
+	}
+
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := c.routing.PutValue(ctx, ipns.RecordKey(id), data); err != nil {
+		return err
+	}
+	c.records[id] = entry
+	return nil
+}
+
+// keepContent pins root if it is not yet, and provides it.
+func (c *caretaker) keepContent(ctx context.Context, root cid.Cid) error {
+	_, pinned, err := c.pinning.IsPinnedWithType(ctx, root, pin.Recursive)
+	if err != nil {
+		return err
+	}
+	if !pinned {
+		nd, err := c.dag.Get(ctx, root)
+		if err != nil {
+			return err
+		}
+		unlock := c.gcLocker.PinLock(ctx)
+		err = c.pinning.Pin(ctx, nd, true)
+		if err == nil {
+			err = c.pinning.Flush(ctx)
+		}
+		unlock.Unlock(ctx)
+		if err != nil {
+			return err
+		}
+		c.journal.Record(ctx, journal.TypePin, map[string]string{
+			"op":        "add",
+			"cid":       root.String(),
+			"recursive": "true",
+			"caretaker": "true",
+		})
+	}
+	return c.provider.Provide(root)
+}
+
+func (c *caretaker) republish(ctx context.Context) {
+	for _, id := range c.names {
+		if err := c.republishName(ctx, id); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Errorf("caretaker: republishing /ipns/%s: %s", id, err)
+			continue
+		}
+		logger.Debugf("caretaker: republished /ipns/%s", id)
+	}
+	if err := c.save(ctx); err != nil {
+		logger.Errorf("caretaker: saving the records: %s", err)
+	}
+
+	for _, root := range c.cids {
+		if err := c.keepContent(ctx, root); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Errorf("caretaker: keeping %s: %s", root, err)
+		}
+	}
+}
+
+func (c *caretaker) run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		c.republish(ctx)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// load restores the records kept before a restart.
+func (c *caretaker) load(ctx context.Context) error {
+	data, err := c.ds.Get(ctx, caretakerKey)
+	if err == datastore.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var saved map[string][]byte
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	for s, rec := range saved {
+		id, err := peer.Decode(s)
+		if err != nil {
+			return err
+		}
+		entry := new(pb.IpnsEntry)
+		if err := proto.Unmarshal(rec, entry); err != nil {
+			return fmt.Errorf("invalid IPNS record for %s: %w", id, err)
+		}
+		c.records[id] = entry
+	}
+	return nil
+}
+
+func (c *caretaker) save(ctx context.Context) error {
+	saved := make(map[string][]byte, len(c.records))
+	for id, entry := range c.records {
+		rec, err := proto.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		saved[id.String()] = rec
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	return c.ds.Put(ctx, caretakerKey, data)
+}
+
+// Caretaker republishes the names and provides the CIDs in Caretaker.
+func Caretaker(cfg config.Caretaker) interface{} {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, rt routing.Routing, r repo.Repo, pinning pin.Pinner, dag format.DAGService, gcLocker blockstore.GCLocker, sys provider.System, j *journal.Journal) error {
+		interval := cfg.Interval.WithDefault(defaultCaretakerInterval)
+		if interval <= 0 {
+			return fmt.Errorf("Caretaker.Interval must be positive")
+		}
+
+		c := &caretaker{
+			routing:  rt,
+			ds:       r.Datastore(),
+			pinning:  pinning,
+			dag:      dag,
+			gcLocker: gcLocker,
+			provider: sys,
+			journal:  j,
+			records:  make(map[peer.ID]*pb.IpnsEntry),
+		}
+		for _, name := range cfg.Names {
+			id, err := peer.Decode(strings.TrimPrefix(name, "/ipns/"))
+			if err != nil {
+				return fmt.Errorf("invalid name in Caretaker.Names: %w", err)
+			}
+			c.names = append(c.names, id)
+		}
+		for _, s := range cfg.Cids {
+			root, err := cid.Decode(s)
+			if err != nil {
+				return fmt.Errorf("invalid CID in Caretaker.Cids: %w", err)
+			}
+			c.cids = append(c.cids, root)
+		}
+
+		ctx, cancel := context.WithCancel(helpers.LifecycleCtx(mctx, lc))
+		if err := c.load(ctx); err != nil {
+			cancel()
+			return fmt.Errorf("loading the caretaker records: %w", err)
+		}
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				go c.run(ctx, interval)
+				return nil
+			},
+			OnStop: func(_ context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	offroute "github.com/ipfs/go-ipfs-routing/offline"
+	"github.com/ipfs/go-ipns"
+	pb "github.com/ipfs/go-ipns/pb"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	"github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	record "github.com/libp2p/go-libp2p-record"
+)
+
+func newTestRouting(t *testing.T) routing.Routing {
+	t.Helper()
+	ps, err := pstoremem.NewPeerstore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return offroute.NewOfflineRouter(dssync.MutexWrap(datastore.NewMapDatastore()), record.NamespacedValidator{
+		"ipns": ipns.Validator{KeyBook: ps},
+	})
+}
+
+func TestCaretakerRepublish(t *testing.T) {
+	ctx := context.Background()
+	sk, _, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publish := func(rt routing.Routing, seq uint64, eol time.Time) *pb.IpnsEntry {
+		t.Helper()
+		entry, err := ipns.Create(sk, []byte("/ipfs/bafkqaaa"), seq, eol, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := entry.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rt.PutValue(ctx, ipns.RecordKey(id), data); err != nil {
+			t.Fatal(err)
+		}
+		return entry
+	}
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	c := &caretaker{routing: newTestRouting(t), ds: ds, records: make(map[peer.ID]*pb.IpnsEntry)}
+	if err := c.republishName(ctx, id); err == nil {
+		t.Fatal("expected an error without any record")
+	}
+	publish(c.routing, 3, time.Now().Add(time.Hour))
+	if err := c.republishName(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.save(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the publisher is gone, a restarted caretaker republishes the record
+	// it kept
+	restarted := &caretaker{routing: newTestRouting(t), ds: ds, records: make(map[peer.ID]*pb.IpnsEntry)}
+	if err := restarted.load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.republishName(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	found, err := FetchIpnsRecord(ctx, restarted.routing, id)
+	if err != nil || found.GetSequence() != 3 {
+		t.Fatalf("expected the kept record to be republished, got %v, %v", found, err)
+	}
+
+	// an older record in the routing doesn't replace the kept one
+	stale := &caretaker{routing: newTestRouting(t), ds: ds, records: restarted.records}
+	publish(stale.routing, 1, time.Now().Add(time.Hour))
+	if err := stale.republishName(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if stale.records[id].GetSequence() != 3 {
+		t.Fatalf("expected the newest record to be kept, got sequence %d", stale.records[id].GetSequence())
+	}
+
+	// expired records are dropped
+	expired, err := ipns.Create(sk, []byte("/ipfs/bafkqaaa"), 4, time.Now().Add(-time.Minute), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted.records[id] = expired
+	if err := restarted.republishName(ctx, id); err == nil {
+		t.Fatal("expected an error for an expired record")
+	}
+	if _, ok := restarted.records[id]; ok {
+		t.Fatal("expected the expired record to be dropped")
+	}
+}
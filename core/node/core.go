@@ -26,7 +26,10 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/ipldlimits"
+	"github.com/ipfs/go-ipfs/quarantine"
 	"github.com/ipfs/go-ipfs/repo"
+	"github.com/ipfs/go-ipfs/walkpool"
 )
 
 // BlockService creates new blockservice which provides an interface to fetch content-addressable blocks
@@ -42,9 +45,10 @@ func BlockService(lc fx.Lifecycle, bs blockstore.Blockstore, rem exchange.Interf
 	return bsvc
 }
 
-// Pinning creates new pinner which tells GC which blocks should be kept
-func Pinning(bstore blockstore.Blockstore, ds format.DAGService, repo repo.Repo) (pin.Pinner, error) {
-	rootDS := repo.Datastore()
+// Pinning creates new pinner which tells GC which blocks should be kept. The
+// DAGs it pins are fetched through the walk pool.
+func Pinning(bstore blockstore.Blockstore, ds format.DAGService, r repo.Repo, walkers *walkpool.Pool) (pin.Pinner, error) {
+	rootDS := r.Datastore()
 
 	syncFn := func(ctx context.Context) error {
 		if err := rootDS.Sync(ctx, blockstore.BlockPrefix); err != nil {
@@ -52,7 +56,7 @@ func Pinning(bstore blockstore.Blockstore, ds format.DAGService, repo repo.Repo)
 		}
 		return rootDS.Sync(ctx, filestore.FilestorePrefix)
 	}
-	syncDs := &syncDagService{ds, syncFn}
+	syncDs := &syncDagService{walkers.DAGService(walkpool.OpPin, ds), syncFn}
 
 	ctx := context.TODO()
 
@@ -61,6 +65,9 @@ func Pinning(bstore blockstore.Blockstore, ds format.DAGService, repo repo.Repo)
 		return nil, err
 	}
 
+	if repo.IsReadOnly(r) {
+		return &readOnlyPinner{Pinner: pinning}, nil
+	}
 	return pinning, nil
 }
 
@@ -104,12 +111,23 @@ func FetcherConfig(bs blockservice.BlockService) fetchersOut {
 }
 
 // Dag creates new DAGService
-func Dag(bs blockservice.BlockService) format.DAGService {
-	return merkledag.NewDAGService(bs)
+func Dag(bs blockservice.BlockService, q *quarantine.Quarantine, l *ipldlimits.Limits) format.DAGService {
+	return l.Wrap(q.Wrap(merkledag.NewDAGService(l.WrapBlocks(bs))))
+}
+
+// WalkPool creates the pool of the nodes fetched at once by the DAG walks. It
+// is nil when size is not positive, leaving the walks unbounded.
+func WalkPool(size int64) func() *walkpool.Pool {
+	return func() *walkpool.Pool {
+		if size <= 0 {
+			return nil
+		}
+		return walkpool.New(int(size))
+	}
 }
 
 // Files loads persisted MFS root
-func Files(mctx helpers.MetricsCtx, lc fx.Lifecycle, repo repo.Repo, dag format.DAGService) (*mfs.Root, error) {
+func Files(mctx helpers.MetricsCtx, lc fx.Lifecycle, repo repo.Repo, dag format.DAGService, mfsPins *MFSPins) (*mfs.Root, error) {
 	dsk := datastore.NewKey("/local/filesroot")
 	pf := func(ctx context.Context, c cid.Cid) error {
 		rootDS := repo.Datastore()
@@ -123,7 +141,11 @@ func Files(mctx helpers.MetricsCtx, lc fx.Lifecycle, repo repo.Repo, dag format.
 		if err := rootDS.Put(ctx, dsk, c.Bytes()); err != nil {
 			return err
 		}
-		return rootDS.Sync(ctx, dsk)
+		if err := rootDS.Sync(ctx, dsk); err != nil {
+			return err
+		}
+		mfsPins.Published(c)
+		return nil
 	}
 
 	var nd *merkledag.ProtoNode
@@ -158,6 +180,7 @@ func Files(mctx helpers.MetricsCtx, lc fx.Lifecycle, repo repo.Repo, dag format.
 		return nil, err
 	}
 
+	mfsPins.Published(nd.Cid())
 	root, err := mfs.NewRoot(ctx, dag, nd, pf)
 
 	lc.Append(fx.Hook{
@@ -80,6 +80,14 @@ func LibP2P(bcfg *BuildCfg, cfg *config.Config) fx.Option {
 			pubsub.WithMessageSigning(!cfg.Pubsub.DisableSigning),
 		)
 
+		limiter, err := libp2p.NewTopicLimiter(cfg.Pubsub.TopicLimits)
+		if err != nil {
+			return fx.Error(err)
+		}
+		if limiter != nil {
+			pubsubOptions = append(pubsubOptions, limiter.Options()...)
+		}
+
 		switch cfg.Pubsub.Router {
 		case "":
 			fallthrough
@@ -90,6 +98,9 @@ func LibP2P(bcfg *BuildCfg, cfg *config.Config) fx.Option {
 		default:
 			return fx.Error(fmt.Errorf("unknown pubsub router %s", cfg.Pubsub.Router))
 		}
+		if limiter != nil {
+			ps = fx.Options(ps, fx.Invoke(limiter.Start))
+		}
 	}
 
 	autonat := fx.Options()
@@ -143,6 +154,13 @@ func LibP2P(bcfg *BuildCfg, cfg *config.Config) fx.Option {
 			"If you want to continue running a circuit v1 relay, please use the standalone relay daemon: https://dist.ipfs.io/#libp2p-relay-daemon (with RelayV1.Enabled: true)")
 	}
 
+	// the probes are lookups of the node itself, only run by default for
+	// the nodes waiting on them to be ready
+	enableHealthCheck := cfg.Routing.HealthCheck.Enabled.WithDefault(len(cfg.Routing.HealthCheck.ReadyRouters) > 0)
+	if !enableHealthCheck && len(cfg.Routing.HealthCheck.ReadyRouters) > 0 {
+		return fx.Error(errors.New("Routing.HealthCheck.ReadyRouters requires Routing.HealthCheck.Enabled"))
+	}
+
 	peerChan := make(libp2p.AddrInfoChan)
 	// Gather all the options
 	opts := fx.Options(
@@ -152,12 +170,16 @@ func LibP2P(bcfg *BuildCfg, cfg *config.Config) fx.Option {
 
 		// Services (resource management)
 		fx.Provide(libp2p.ResourceManager(cfg.Swarm)),
+		fx.Provide(libp2p.InboundAllowlistService(cfg.Swarm.InboundAllowlist)),
 		fx.Provide(libp2p.AddrFilters(cfg.Swarm.AddrFilters)),
 		fx.Provide(libp2p.AddrsFactory(cfg.Addresses.Announce, cfg.Addresses.AppendAnnounce, cfg.Addresses.NoAnnounce)),
 		fx.Provide(libp2p.SmuxTransport(cfg.Swarm.Transports)),
 		fx.Provide(libp2p.RelayTransport(enableRelayTransport)),
 		fx.Provide(libp2p.RelayService(enableRelayService, cfg.Swarm.RelayService)),
 		fx.Provide(libp2p.Transports(cfg.Swarm.Transports)),
+		fx.Invoke(libp2p.RestrictServices(cfg.Swarm.Services)),
+		fx.Invoke(libp2p.PeerCertificates(cfg.Swarm.InboundAllowlist)),
+		fx.Invoke(libp2p.PNetPeersChecker(cfg)),
 		fx.Invoke(libp2p.StartListening(cfg.Addresses.Swarm)),
 		fx.Invoke(libp2p.SetupDiscovery(cfg.Discovery.MDNS.Enabled, cfg.Discovery.MDNS.Interval)),
 		fx.Provide(libp2p.ForceReachability(cfg.Internal.Libp2pForceReachability)),
@@ -167,12 +189,13 @@ func LibP2P(bcfg *BuildCfg, cfg *config.Config) fx.Option {
 
 		fx.Provide(libp2p.Routing),
 		fx.Provide(libp2p.BaseRouting(cfg.Experimental.AcceleratedDHTClient)),
+		maybeProvide(libp2p.RoutingHealthService(cfg.Routing.HealthCheck), enableHealthCheck),
 		maybeProvide(libp2p.PubsubRouter, bcfg.getOpt("ipnsps")),
 
 		maybeProvide(libp2p.BandwidthCounter, !cfg.Swarm.DisableBandwidthMetrics),
 		maybeProvide(libp2p.NatPortMap, !cfg.Swarm.DisableNatPortMap),
 		maybeProvide(libp2p.AutoRelay(cfg.Swarm.RelayClient.StaticRelays, peerChan), enableRelayClient),
-		maybeInvoke(libp2p.AutoRelayFeeder(cfg.Peering), enableRelayClient),
+		maybeInvoke(libp2p.AutoRelayFeeder(cfg.Peering, cfg.Swarm.PrivateNetwork), enableRelayClient),
 		autonat,
 		connmgr,
 		ps,
@@ -190,16 +213,27 @@ func Storage(bcfg *BuildCfg, cfg *config.Config) fx.Option {
 		cacheOpts.HasBloomFilterSize = 0
 	}
 
+	// The presence index only sees blocks written through the base
+	// blockstore, not those kept by the filestore.
+	var presenceIndexSize int64
+	if cfg.Internal.Bitswap != nil && bcfg.Online && bcfg.Permanent && !bcfg.NilRepo {
+		presenceIndexSize = cfg.Internal.Bitswap.PresenceIndexSize.WithDefault(0)
+	}
+
 	finalBstore := fx.Provide(GcBlockstoreCtor)
 	if cfg.Experimental.FilestoreEnabled || cfg.Experimental.UrlstoreEnabled {
 		finalBstore = fx.Provide(FilestoreBlockstoreCtor)
+		presenceIndexSize = 0
 	}
 
 	return fx.Options(
 		fx.Provide(RepoConfig),
 		fx.Provide(Datastore),
-		fx.Provide(BaseBlockstoreCtor(cacheOpts, bcfg.NilRepo, cfg.Datastore.HashOnRead)),
+		fx.Provide(EventJournal),
+		fx.Provide(PresenceIndexCtor(BaseBlockstoreCtor(cacheOpts, bcfg.NilRepo, cfg.Datastore.HashOnRead), int(presenceIndexSize))),
 		finalBstore,
+		fx.Provide(BlockQuarantine(cfg.Datastore.Quarantine.WithDefault(true) && !bcfg.NilRepo)),
+		fx.Provide(IPLDLimits(cfg.IPLD)),
 	)
 }
 
@@ -287,23 +321,36 @@ func Online(bcfg *BuildCfg, cfg *config.Config) fx.Option {
 		recordLifetime = d
 	}
 
-	/* don't provide from bitswap when the strategic provider service is active */
-	shouldBitswapProvide := !cfg.Experimental.StrategicProviding
+	providers := OnlineProviders(cfg.Experimental.StrategicProviding, cfg.Experimental.AcceleratedDHTClient, cfg.Reprovider.Strategy, cfg.Reprovider.Interval)
+	announcer := cfg.Provider.Announcer.WithDefault("")
+	if announcer != "" && !cfg.Experimental.StrategicProviding {
+		ai, err := ParseAnnouncer(announcer)
+		if err != nil {
+			return fx.Error(err)
+		}
+		providers = AnnouncerProviders(ai, cfg.Reprovider.Strategy, cfg.Reprovider.Interval)
+	}
 
 	return fx.Options(
-		fx.Provide(OnlineExchange(cfg, shouldBitswapProvide)),
+		fx.Provide(OnlineExchange(cfg, BitswapProvides(cfg))),
+		fx.Provide(ProtocolCacheService(cfg.Routing.ProtocolCache)),
+		fx.Provide(LookupCacheService(cfg.Routing.LookupCache)),
 		maybeProvide(Graphsync, cfg.Experimental.GraphsyncEnabled),
 		fx.Provide(DNSResolver),
-		fx.Provide(Namesys(ipnsCacheSize)),
+		fx.Provide(Namesys(ipnsCacheSize, int(cfg.Ipns.MaxDelegations.WithDefault(DefaultIpnsMaxDelegations)))),
 		fx.Provide(Peering),
-		PeerWith(cfg.Peering.Peers...),
+		PeerWith(cfg.Swarm.PrivateNetwork, cfg.Peering.Peers...),
 
 		fx.Invoke(IpnsRepublisher(repubPeriod, recordLifetime)),
 
 		fx.Provide(p2p.New),
 
 		LibP2P(bcfg, cfg),
-		OnlineProviders(cfg.Experimental.StrategicProviding, cfg.Experimental.AcceleratedDHTClient, cfg.Reprovider.Strategy, cfg.Reprovider.Interval),
+		providers,
+		maybeInvoke(AnnounceService(cfg.Provider.AnnounceFor), len(cfg.Provider.AnnounceFor) > 0),
+		maybeInvoke(PinGossip(cfg.Pinning.Gossip), len(cfg.Pinning.Gossip.Peers) > 0),
+		maybeInvoke(Caretaker(cfg.Caretaker), len(cfg.Caretaker.Names) > 0 || len(cfg.Caretaker.Cids) > 0),
+		maybeProvide(StandbyService(cfg.Standby), cfg.Standby.Primary != "" || len(cfg.Standby.Standbys) > 0),
 	)
 }
 
@@ -312,7 +359,7 @@ func Offline(cfg *config.Config) fx.Option {
 	return fx.Options(
 		fx.Provide(offline.Exchange),
 		fx.Provide(DNSResolver),
-		fx.Provide(Namesys(0)),
+		fx.Provide(Namesys(0, int(cfg.Ipns.MaxDelegations.WithDefault(DefaultIpnsMaxDelegations)))),
 		fx.Provide(offroute.NewOfflineRouter),
 		OfflineProviders(cfg.Experimental.StrategicProviding, cfg.Experimental.AcceleratedDHTClient, cfg.Reprovider.Strategy, cfg.Reprovider.Interval),
 	)
@@ -371,5 +418,7 @@ func IPFS(ctx context.Context, bcfg *BuildCfg) fx.Option {
 		Networked(bcfg, cfg),
 
 		Core,
+		fx.Provide(WalkPool(cfg.Internal.DAGWalkWorkers.WithDefault(0))),
+		fx.Provide(MFSPinning(cfg.Pinning.MFS)),
 	)
 }
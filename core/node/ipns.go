@@ -27,8 +27,9 @@ func RecordValidator(ps peerstore.Peerstore) record.Validator {
 	}
 }
 
-// Namesys creates new name system
-func Namesys(cacheSize int) func(rt routing.Routing, rslv *madns.Resolver, repo repo.Repo) (namesys.NameSystem, error) {
+// Namesys creates new name system, resolving names through at most
+// maxDelegations other names
+func Namesys(cacheSize int, maxDelegations int) func(rt routing.Routing, rslv *madns.Resolver, repo repo.Repo) (namesys.NameSystem, error) {
 	return func(rt routing.Routing, rslv *madns.Resolver, repo repo.Repo) (namesys.NameSystem, error) {
 		opts := []namesys.Option{
 			namesys.WithDatastore(repo.Datastore()),
@@ -39,14 +40,20 @@ func Namesys(cacheSize int) func(rt routing.Routing, rslv *madns.Resolver, repo
 			opts = append(opts, namesys.WithCache(cacheSize))
 		}
 
-		return namesys.NewNameSystem(rt, opts...)
+		ns, err := namesys.NewNameSystem(rt, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return LimitDelegations(newSequenceNameSystem(ns, rt, repo.Datastore()), maxDelegations), nil
 	}
 }
 
 // IpnsRepublisher runs new IPNS republisher service
 func IpnsRepublisher(repubPeriod time.Duration, recordLifetime time.Duration) func(lcProcess, namesys.NameSystem, repo.Repo, crypto.PrivKey) error {
 	return func(lc lcProcess, namesys namesys.NameSystem, repo repo.Repo, privKey crypto.PrivKey) error {
-		repub := republisher.NewRepublisher(namesys, repo.Datastore(), privKey, repo.Keystore())
+		// the keys mirrored from a primary are republished once promoted
+		repub := republisher.NewRepublisher(namesys, repo.Datastore(), privKey, withoutStandbyKeys{repo.Keystore()})
 
 		if repubPeriod != 0 {
 			if !util.Debug && (repubPeriod < time.Minute || repubPeriod > (time.Hour*24)) {
@@ -0,0 +1,50 @@
+package node
+
+import (
+	"context"
+
+	"github.com/ipfs/go-namesys"
+	path "github.com/ipfs/go-path"
+	nsopts "github.com/ipfs/interface-go-ipfs-core/options/namesys"
+)
+
+// DefaultIpnsMaxDelegations is the default number of names a name can be
+// delegated through, as in Ipns.MaxDelegations. It's the depth namesys
+// resolves names to by default.
+const DefaultIpnsMaxDelegations = nsopts.DefaultDepthLimit - 1
+
+// LimitDelegations wraps ns so that resolving a name follows at most max
+// delegations to other names, IPNS records or DNSLinks pointing at another
+// /ipns/ path, whatever depth is requested. Past it, resolving fails with
+// namesys.ErrResolveRecursion.
+func LimitDelegations(ns namesys.NameSystem, max int) namesys.NameSystem {
+	if max < 0 {
+		max = 0
+	}
+	return &delegationLimitedNameSystem{NameSystem: ns, depth: uint(max) + 1}
+}
+
+type delegationLimitedNameSystem struct {
+	namesys.NameSystem
+
+	depth uint
+}
+
+// limit appends the depth limit to opts, when they request a greater
+// depth, 0 being unlimited.
+func (ns *delegationLimitedNameSystem) limit(opts []nsopts.ResolveOpt) []nsopts.ResolveOpt {
+	if d := nsopts.ProcessOpts(opts).Depth; d != 0 && d <= ns.depth {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], nsopts.Depth(ns.depth))
+}
+
+// Resolve implements namesys.Resolver
+func (ns *delegationLimitedNameSystem) Resolve(ctx context.Context, name string, opts ...nsopts.ResolveOpt) (path.Path, error) {
+	return ns.NameSystem.Resolve(ctx, name, ns.limit(opts)...)
+}
+
+// ResolveAsync implements namesys.Resolver
+func (ns *delegationLimitedNameSystem) ResolveAsync(ctx context.Context, name string, opts ...nsopts.ResolveOpt) <-chan namesys.Result {
+	return ns.NameSystem.ResolveAsync(ctx, name, ns.limit(opts)...)
+}
@@ -0,0 +1,64 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	offroute "github.com/ipfs/go-ipfs-routing/offline"
+	"github.com/ipfs/go-namesys"
+	path "github.com/ipfs/go-path"
+	nsopts "github.com/ipfs/interface-go-ipfs-core/options/namesys"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-peerstore/pstoremem"
+)
+
+func TestLimitDelegations(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	ps, err := pstoremem.NewPeerstore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := namesys.NewNameSystem(offroute.NewOfflineRouter(ds, RecordValidator(ps)), namesys.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// team -> publisher -> content
+	content := path.FromString("/ipfs/bafkqaaa")
+	value := content
+	var names []peer.ID
+	for i := 0; i < 2; i++ {
+		k, _, err := crypto.GenerateEd25519Key(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ns.Publish(ctx, k, value); err != nil {
+			t.Fatal(err)
+		}
+		id, err := peer.IDFromPrivateKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, id)
+		value = path.FromString("/ipns/" + id.String())
+	}
+	team := "/ipns/" + names[1].String()
+
+	if p, err := LimitDelegations(ns, 1).Resolve(ctx, team); err != nil || p != content {
+		t.Fatalf("expected the delegated name to resolve to %s, got %s, %v", content, p, err)
+	}
+	if _, err := LimitDelegations(ns, 0).Resolve(ctx, team); err != namesys.ErrResolveRecursion {
+		t.Fatalf("expected the delegation to be refused, got %v", err)
+	}
+	// a greater depth requested is limited, a lower one is kept
+	if _, err := LimitDelegations(ns, 0).Resolve(ctx, team, nsopts.Depth(5)); err != namesys.ErrResolveRecursion {
+		t.Fatalf("expected the requested depth to be limited, got %v", err)
+	}
+	if p, err := LimitDelegations(ns, 5).Resolve(ctx, team, nsopts.Depth(1)); err != namesys.ErrResolveRecursion || p != path.FromString("/ipns/"+names[0].String()) {
+		t.Fatalf("expected the requested depth to be kept, got %s, %v", p, err)
+	}
+}
@@ -0,0 +1,256 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	proto "github.com/gogo/protobuf/proto"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-ipns"
+	pb "github.com/ipfs/go-ipns/pb"
+	"github.com/ipfs/go-log"
+	"github.com/ipfs/go-namesys"
+	path "github.com/ipfs/go-path"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+)
+
+var ipnsSeqLog = log.Logger("ipns-seq")
+
+// IpnsNetworkCheckTimeout bounds the routing lookup finding out what the
+// network currently holds under our key, done before publishing a key without
+// a local record and by 'ipfs name inspect --check-network'.
+const IpnsNetworkCheckTimeout = 10 * time.Second
+
+var ipnsSeqPrefix = datastore.NewKey("/local/ipns-seq")
+
+// IpnsSequenceInfo describes the publishing state of a single IPNS key.
+type IpnsSequenceInfo struct {
+	// Sequence is the highest sequence number this node has published.
+	Sequence uint64
+	// NetworkSequence is the sequence number of the record last seen in the
+	// routing system under this key.
+	NetworkSequence uint64
+	// Conflict is set when the network was seen holding a record this node
+	// did not publish: either a higher sequence number, or the same sequence
+	// number with a different value. It usually means the key is used on
+	// another node (split brain) or has been compromised.
+	Conflict bool
+
+	LastPublished time.Time `json:",omitempty"`
+	LastChecked   time.Time `json:",omitempty"`
+}
+
+// IpnsSequenceStore persists the highest sequence number used for each key
+// this node publishes with. It is kept separately from the namesys record so
+// sequence numbers keep increasing even if the published record is lost,
+// e.g. after the datastore has been moved or re-created.
+type IpnsSequenceStore struct {
+	ds datastore.Datastore
+}
+
+// NewIpnsSequenceStore returns a sequence store backed by the given datastore.
+func NewIpnsSequenceStore(ds datastore.Datastore) *IpnsSequenceStore {
+	return &IpnsSequenceStore{ds: ds}
+}
+
+func (s *IpnsSequenceStore) key(id peer.ID) datastore.Key {
+	return ipnsSeqPrefix.ChildString(id.String())
+}
+
+// Get returns the stored state for the given key. A key that was never
+// published returns a zero IpnsSequenceInfo.
+func (s *IpnsSequenceStore) Get(ctx context.Context, id peer.ID) (IpnsSequenceInfo, error) {
+	var info IpnsSequenceInfo
+	data, err := s.ds.Get(ctx, s.key(id))
+	switch err {
+	case nil:
+	case datastore.ErrNotFound:
+		return info, nil
+	default:
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+// Put persists the state for the given key.
+func (s *IpnsSequenceStore) Put(ctx context.Context, id peer.ID, info IpnsSequenceInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	k := s.key(id)
+	if err := s.ds.Put(ctx, k, data); err != nil {
+		return err
+	}
+	return s.ds.Sync(ctx, k)
+}
+
+// Observe updates info with the record found in the network and reports
+// whether it conflicts with what this node has published. local is the
+// record currently stored by this node, and may be nil.
+func (info *IpnsSequenceInfo) Observe(local, network *pb.IpnsEntry) bool {
+	info.LastChecked = time.Now()
+	if network == nil {
+		return false
+	}
+	info.NetworkSequence = network.GetSequence()
+
+	known := info.Sequence
+	if local.GetSequence() > known {
+		known = local.GetSequence()
+	}
+
+	switch {
+	case info.NetworkSequence > known:
+		info.Conflict = true
+	case local != nil && info.NetworkSequence == local.GetSequence() && !bytes.Equal(network.GetValue(), local.GetValue()):
+		info.Conflict = true
+	default:
+		info.Conflict = false
+	}
+	return info.Conflict
+}
+
+// FetchIpnsRecord looks up the record published under the given key in the
+// routing system. It returns nil if no record could be found.
+func FetchIpnsRecord(ctx context.Context, vs routing.ValueStore, id peer.ID) (*pb.IpnsEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, IpnsNetworkCheckTimeout)
+	defer cancel()
+
+	data, err := vs.GetValue(ctx, ipns.RecordKey(id))
+	if err != nil {
+		ipnsSeqLog.Debugf("looking up IPNS record for %s: %s", id, err)
+		return nil, nil
+	}
+	e := new(pb.IpnsEntry)
+	if err := proto.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// sequenceNameSystem wraps a NameSystem so that sequence numbers never go
+// backwards. When there is no local record of a key, e.g. in a re-created
+// datastore, the network is looked up before publishing, so that the record
+// published supersedes the one found and a record published under the key by
+// someone else is detected. Otherwise publishing doesn't wait for the
+// network.
+type sequenceNameSystem struct {
+	namesys.NameSystem
+
+	routing   routing.ValueStore
+	ds        datastore.Datastore
+	publisher *namesys.IpnsPublisher
+	store     *IpnsSequenceStore
+}
+
+func newSequenceNameSystem(ns namesys.NameSystem, rt routing.ValueStore, ds datastore.Datastore) *sequenceNameSystem {
+	return &sequenceNameSystem{
+		NameSystem: ns,
+		routing:    rt,
+		ds:         ds,
+		publisher:  namesys.NewIpnsPublisher(rt, ds),
+		store:      NewIpnsSequenceStore(ds),
+	}
+}
+
+// Publish implements namesys.Publisher
+func (ns *sequenceNameSystem) Publish(ctx context.Context, k crypto.PrivKey, value path.Path) error {
+	return ns.PublishWithEOL(ctx, k, value, time.Now().Add(namesys.DefaultRecordEOL))
+}
+
+// PublishWithEOL implements namesys.Publisher
+func (ns *sequenceNameSystem) PublishWithEOL(ctx context.Context, k crypto.PrivKey, value path.Path, eol time.Time) error {
+	id, err := peer.IDFromPrivateKey(k)
+	if err != nil {
+		return err
+	}
+
+	info, err := ns.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	local, err := ns.publisher.GetPublished(ctx, id, false)
+	if err != nil {
+		return err
+	}
+	if local == nil {
+		network, err := FetchIpnsRecord(ctx, ns.routing, id)
+		if err != nil {
+			return err
+		}
+		if info.Observe(local, network) {
+			ipnsSeqLog.Warnf("found IPNS record for %s with sequence %d in the network, but last published sequence is %d: is this key in use on another node?",
+				id, info.NetworkSequence, info.Sequence)
+		}
+	}
+
+	// The publisher derives the next sequence number from the record in the
+	// datastore, so seed it whenever the network or our own history knows of
+	// a higher one. The empty value guarantees the sequence gets bumped.
+	seq := info.Sequence
+	if info.NetworkSequence > seq {
+		seq = info.NetworkSequence
+	}
+	var seed, prev []byte
+	if seq > local.GetSequence() {
+		prev, err = ns.ds.Get(ctx, namesys.IpnsDsKey(id))
+		if err != nil && err != datastore.ErrNotFound {
+			return err
+		}
+		rec, err := ipns.Create(k, nil, seq, eol, 0)
+		if err != nil {
+			return err
+		}
+		seed, err = proto.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		if err := ns.ds.Put(ctx, namesys.IpnsDsKey(id), seed); err != nil {
+			return err
+		}
+	}
+
+	pubErr := ns.NameSystem.PublishWithEOL(ctx, k, value, eol)
+	// Failing before signing a record leaves the seed, with its empty value,
+	// in place of the record; put back the previous one.
+	if pubErr != nil && seed != nil && ns.restoreRecord(ctx, id, seed, prev) {
+		return pubErr
+	}
+
+	// Record the sequence we signed even if putting it to the routing system
+	// failed; the record is already stored locally and will be republished.
+	if rec, err := ns.publisher.GetPublished(ctx, id, false); err == nil && rec != nil {
+		info.Sequence = rec.GetSequence()
+		info.LastPublished = time.Now()
+		if err := ns.store.Put(ctx, id, info); err != nil {
+			ipnsSeqLog.Errorf("persisting IPNS sequence for %s: %s", id, err)
+		}
+	}
+
+	return pubErr
+}
+
+// restoreRecord puts back prev as the record of id, or deletes the record when
+// prev is nil, if the record stored is still seed. It reports whether it was.
+func (ns *sequenceNameSystem) restoreRecord(ctx context.Context, id peer.ID, seed, prev []byte) bool {
+	key := namesys.IpnsDsKey(id)
+	stored, err := ns.ds.Get(ctx, key)
+	if err != nil || !bytes.Equal(stored, seed) {
+		return false
+	}
+	if prev != nil {
+		err = ns.ds.Put(ctx, key, prev)
+	} else {
+		err = ns.ds.Delete(ctx, key)
+	}
+	if err != nil {
+		ipnsSeqLog.Errorf("restoring the IPNS record of %s: %s", id, err)
+	}
+	return true
+}
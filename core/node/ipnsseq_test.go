@@ -0,0 +1,168 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/ipfs/go-ipns"
+	"github.com/ipfs/go-namesys"
+	path "github.com/ipfs/go-path"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+)
+
+// countingRouting counts the values looked up in it.
+type countingRouting struct {
+	routing.Routing
+	lookups int32
+}
+
+func (r *countingRouting) GetValue(ctx context.Context, key string, opts ...routing.Option) ([]byte, error) {
+	atomic.AddInt32(&r.lookups, 1)
+	return r.Routing.GetValue(ctx, key, opts...)
+}
+
+// failingNameSystem fails to publish, before signing any record.
+type failingNameSystem struct {
+	namesys.NameSystem
+}
+
+func (failingNameSystem) PublishWithEOL(context.Context, crypto.PrivKey, path.Path, time.Time) error {
+	return errors.New("publish failed")
+}
+
+func TestSequenceNameSystem(t *testing.T) {
+	ctx := context.Background()
+	sk, _, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := &countingRouting{Routing: newTestRouting(t)}
+
+	// the key was published with a higher sequence than the one this node
+	// knows of, by another node or before its datastore was re-created
+	entry, err := ipns.Create(sk, []byte("/ipfs/bafkqaaa"), 5, time.Now().Add(time.Hour), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := entry.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.PutValue(ctx, ipns.RecordKey(id), data); err != nil {
+		t.Fatal(err)
+	}
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	store := NewIpnsSequenceStore(ds)
+	if err := store.Put(ctx, id, IpnsSequenceInfo{Sequence: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	base, err := namesys.NewNameSystem(rt, namesys.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := newSequenceNameSystem(base, rt, ds)
+	published := func() uint64 {
+		t.Helper()
+		rec, err := ns.publisher.GetPublished(ctx, id, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec.GetSequence()
+	}
+
+	if err := ns.Publish(ctx, sk, path.FromString("/ipfs/bafkqaaa")); err != nil {
+		t.Fatal(err)
+	}
+	if seq := published(); seq != 6 {
+		t.Fatalf("expected the record to supersede the one of the network, got sequence %d", seq)
+	}
+	info, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Sequence != 6 || info.NetworkSequence != 5 || !info.Conflict {
+		t.Fatalf("expected the record of the network to be reported, got %+v", info)
+	}
+
+	// with a local record, publishing doesn't wait for the network
+	lookups := atomic.LoadInt32(&rt.lookups)
+	if err := ns.Publish(ctx, sk, path.FromString("/ipfs/bafkqaaa/changed")); err != nil {
+		t.Fatal(err)
+	}
+	if seq := published(); seq != 7 {
+		t.Fatalf("expected the sequence to follow the local record, got %d", seq)
+	}
+	if atomic.LoadInt32(&rt.lookups) != lookups {
+		t.Fatal("expected the network not to be looked up with a local record")
+	}
+}
+
+func TestSequenceNameSystemFailedPublish(t *testing.T) {
+	ctx := context.Background()
+	sk, _, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := newTestRouting(t)
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	store := NewIpnsSequenceStore(ds)
+	if err := store.Put(ctx, id, IpnsSequenceInfo{Sequence: 9}); err != nil {
+		t.Fatal(err)
+	}
+	base, err := namesys.NewNameSystem(rt, namesys.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := newSequenceNameSystem(failingNameSystem{base}, rt, ds)
+
+	// without a previous record, the seed is removed
+	if err := ns.Publish(ctx, sk, path.FromString("/ipfs/bafkqaaa")); err == nil {
+		t.Fatal("expected the publish to fail")
+	}
+	if has, err := ds.Has(ctx, namesys.IpnsDsKey(id)); err != nil || has {
+		t.Fatalf("expected no record to be left, got %v", err)
+	}
+
+	// with one, it is put back
+	entry, err := ipns.Create(sk, []byte("/ipfs/bafkqaaa"), 7, time.Now().Add(time.Hour), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, err := entry.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Put(ctx, namesys.IpnsDsKey(id), prev); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.Publish(ctx, sk, path.FromString("/ipfs/bafkqaaa/changed")); err == nil {
+		t.Fatal("expected the publish to fail")
+	}
+	stored, err := ds.Get(ctx, namesys.IpnsDsKey(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stored, prev) {
+		t.Fatal("expected the previous record to be put back")
+	}
+	if info, err := store.Get(ctx, id); err != nil || info.Sequence != 9 {
+		t.Fatalf("expected the sequence to be kept, got %+v, %v", info, err)
+	}
+}
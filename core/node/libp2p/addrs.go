@@ -10,18 +10,19 @@ import (
 	mamask "github.com/whyrusleeping/multiaddr-filter"
 )
 
-func AddrFilters(filters []string) func() (*ma.Filters, Libp2pOpts, error) {
-	return func() (filter *ma.Filters, opts Libp2pOpts, err error) {
+func AddrFilters(filters []string) func(*InboundAllowlist) (*ma.Filters, *SwarmBrake, Libp2pOpts, error) {
+	return func(allowlist *InboundAllowlist) (filter *ma.Filters, brake *SwarmBrake, opts Libp2pOpts, err error) {
 		filter = ma.NewFilters()
-		opts.Opts = append(opts.Opts, libp2p.ConnectionGater((*filtersConnectionGater)(filter)))
+		brake = new(SwarmBrake)
+		opts.Opts = append(opts.Opts, libp2p.ConnectionGater(&filtersConnectionGater{filters: filter, brake: brake, allowlist: allowlist}))
 		for _, s := range filters {
 			f, err := mamask.NewMask(s)
 			if err != nil {
-				return filter, opts, fmt.Errorf("incorrectly formatted address filter in config: %s", s)
+				return filter, brake, opts, fmt.Errorf("incorrectly formatted address filter in config: %s", s)
 			}
 			filter.AddFilter(*f, ma.ActionDeny)
 		}
-		return filter, opts, nil
+		return filter, brake, opts, nil
 	}
 }
 
@@ -0,0 +1,404 @@
+package libp2p
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/libp2p/go-libp2p-core/record"
+	"github.com/libp2p/go-libp2p/p2p/protocol/identify"
+	"github.com/multiformats/go-multibase"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+)
+
+// PeerCertificateProtocol is the protocol a peer presents its certificate
+// over, to be admitted by a node accepting inbound connections only from the
+// peers on its allowlist.
+const PeerCertificateProtocol protocol.ID = "/ipfs/peer-cert/1.0.0"
+
+const (
+	defaultProbationTimeout = 10 * time.Second
+
+	peerCertificateDomain = "ipfs-peer-certificate"
+	// maxPeerCertificateSize bounds the certificates read, which hold a
+	// public key, an RSA one being the largest.
+	maxPeerCertificateSize = 4 << 10
+)
+
+var peerCertificateCodec = []byte("/ipfs/peer-cert")
+
+// peerCertificate is the record a certificate authority signs to admit Peer
+// until Expires.
+type peerCertificate struct {
+	Peer    peer.ID
+	Expires time.Time
+}
+
+func (c *peerCertificate) Domain() string {
+	return peerCertificateDomain
+}
+
+func (c *peerCertificate) Codec() []byte {
+	return peerCertificateCodec
+}
+
+func (c *peerCertificate) MarshalRecord() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *peerCertificate) UnmarshalRecord(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+// IssuePeerCertificate returns the certificate of p, signed with the key of a
+// certificate authority, valid until expires.
+func IssuePeerCertificate(ca crypto.PrivKey, p peer.ID, expires time.Time) (string, error) {
+	env, err := record.Seal(&peerCertificate{Peer: p, Expires: expires.UTC().Truncate(time.Second)}, ca)
+	if err != nil {
+		return "", err
+	}
+	data, err := env.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return multibase.Encode(multibase.Base64url, data)
+}
+
+// parsePeerCertificate returns the certificate in data, and the peer ID of the
+// key that signed it.
+func parsePeerCertificate(data []byte) (*peerCertificate, peer.ID, error) {
+	var cert peerCertificate
+	env, err := record.ConsumeTypedEnvelope(data, &cert)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid certificate: %w", err)
+	}
+	signer, err := peer.IDFromPublicKey(env.PublicKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid certificate: %w", err)
+	}
+	return &cert, signer, nil
+}
+
+// AdmittedPeer is a peer admitted with its certificate.
+type AdmittedPeer struct {
+	Peer    peer.ID
+	Expires time.Time
+}
+
+// InboundAllowlistStatus is the state of the allowlist.
+type InboundAllowlistStatus struct {
+	Admitted []AdmittedPeer
+	// Probation are the peers connected that have yet to present their
+	// certificate.
+	Probation []peer.ID
+	// Denied is the number of inbound connections denied, or closed because
+	// the peer did not present a valid certificate in time.
+	Denied int64
+}
+
+// InboundAllowlist enforces Swarm.InboundAllowlist: the connection gater only
+// accepts the inbound connections of the peers listed, or admitted with their
+// certificate. When certificate authorities are configured, the other peers
+// are put on probation: they may only identify themselves and present their
+// certificate, and are disconnected when they fail to do so in time.
+type InboundAllowlist struct {
+	peers            map[peer.ID]struct{}
+	authorities      map[peer.ID]struct{}
+	probationTimeout time.Duration
+
+	mu        sync.Mutex
+	admitted  map[peer.ID]time.Time // until their certificate expires
+	probation map[peer.ID]struct{}
+	denied    int64
+}
+
+// InboundAllowlistService creates the allowlist set in
+// Swarm.InboundAllowlist. Without it, every peer may connect.
+func InboundAllowlistService(cfg config.InboundAllowlist) func() (*InboundAllowlist, error) {
+	return func() (*InboundAllowlist, error) {
+		if !cfg.Enabled.WithDefault(false) {
+			return nil, nil
+		}
+		peers, err := decodePeers("Swarm.InboundAllowlist.Peers", cfg.Peers)
+		if err != nil {
+			return nil, err
+		}
+		authorities, err := decodePeers("Swarm.InboundAllowlist.CertificateAuthorities", cfg.CertificateAuthorities)
+		if err != nil {
+			return nil, err
+		}
+		return &InboundAllowlist{
+			peers:            peers,
+			authorities:      authorities,
+			probationTimeout: cfg.ProbationTimeout.WithDefault(defaultProbationTimeout),
+			admitted:         make(map[peer.ID]time.Time),
+			probation:        make(map[peer.ID]struct{}),
+		}, nil
+	}
+}
+
+func decodePeers(field string, ids []string) (map[peer.ID]struct{}, error) {
+	peers := make(map[peer.ID]struct{}, len(ids))
+	for _, s := range ids {
+		p, err := peer.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer ID %q in %s: %w", s, field, err)
+		}
+		peers[p] = struct{}{}
+	}
+	return peers, nil
+}
+
+// admits returns whether an inbound connection of p is accepted, including
+// when p could still present its certificate.
+func (a *InboundAllowlist) admits(p peer.ID) bool {
+	if a == nil {
+		return true
+	}
+	if _, ok := a.peers[p]; ok {
+		return true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if expires, ok := a.admitted[p]; ok {
+		if time.Now().Before(expires) {
+			return true
+		}
+		delete(a.admitted, p)
+	}
+	if len(a.authorities) > 0 {
+		return true
+	}
+	a.denied++
+	return false
+}
+
+// startProbation puts p on probation when it has yet to present its
+// certificate, and returns whether it did. It is called once the inbound
+// connection of p is established: the connections failing before never end
+// the probation.
+func (a *InboundAllowlist) startProbation(p peer.ID) bool {
+	if _, ok := a.peers[p]; ok || len(a.authorities) == 0 {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if expires, ok := a.admitted[p]; ok && time.Now().Before(expires) {
+		return false
+	}
+	a.probation[p] = struct{}{}
+	return true
+}
+
+// onProbation returns whether p has yet to present its certificate.
+func (a *InboundAllowlist) onProbation(p peer.ID) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.probation[p]
+	return ok
+}
+
+// endProbation ends the probation of p, counting it as denied when failed.
+func (a *InboundAllowlist) endProbation(p peer.ID, failed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.probation[p]; ok && failed {
+		a.denied++
+	}
+	delete(a.probation, p)
+}
+
+// admit checks the certificate presented by p, admitting p until it expires.
+func (a *InboundAllowlist) admit(p peer.ID, data []byte) error {
+	cert, signer, err := parsePeerCertificate(data)
+	if err != nil {
+		return err
+	}
+	if _, ok := a.authorities[signer]; !ok {
+		return fmt.Errorf("certificate signed by %s, which is not a certificate authority", signer)
+	}
+	if cert.Peer != p {
+		return fmt.Errorf("certificate of %s presented by %s", cert.Peer, p)
+	}
+	if !time.Now().Before(cert.Expires) {
+		return fmt.Errorf("certificate expired at %s", cert.Expires)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.probation, p)
+	a.admitted[p] = cert.Expires
+	return nil
+}
+
+// Status returns the state of the allowlist.
+func (a *InboundAllowlist) Status() InboundAllowlistStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	st := InboundAllowlistStatus{
+		Admitted:  make([]AdmittedPeer, 0, len(a.admitted)),
+		Probation: make([]peer.ID, 0, len(a.probation)),
+		Denied:    a.denied,
+	}
+	now := time.Now()
+	for p, expires := range a.admitted {
+		if now.Before(expires) {
+			st.Admitted = append(st.Admitted, AdmittedPeer{Peer: p, Expires: expires})
+		}
+	}
+	for p := range a.probation {
+		st.Probation = append(st.Probation, p)
+	}
+	sort.Slice(st.Admitted, func(i, j int) bool { return st.Admitted[i].Peer < st.Admitted[j].Peer })
+	sort.Slice(st.Probation, func(i, j int) bool { return st.Probation[i] < st.Probation[j] })
+	return st
+}
+
+// PeerCertificates admits the peers on probation presenting their
+// certificate, and disconnects them when they fail to in time. It also
+// presents the certificate of the node, if any, to the peers it connects to.
+func PeerCertificates(cfg config.InboundAllowlist) func(helpers.MetricsCtx, fx.Lifecycle, host.Host, *InboundAllowlist) error {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, h host.Host, allowlist *InboundAllowlist) error {
+		var cert []byte
+		if s := cfg.Certificate.WithDefault(""); s != "" {
+			_, data, err := multibase.Decode(s)
+			if err != nil {
+				return fmt.Errorf("invalid Swarm.InboundAllowlist.Certificate: %w", err)
+			}
+			c, _, err := parsePeerCertificate(data)
+			if err != nil {
+				return fmt.Errorf("invalid Swarm.InboundAllowlist.Certificate: %w", err)
+			}
+			if c.Peer != h.ID() {
+				return fmt.Errorf("Swarm.InboundAllowlist.Certificate is the certificate of %s, not of this node", c.Peer)
+			}
+			if !time.Now().Before(c.Expires) {
+				log.Warnf("the certificate of this node expired at %s", c.Expires)
+			}
+			cert = data
+		}
+		if allowlist == nil && cert == nil {
+			return nil
+		}
+
+		ctx := helpers.LifecycleCtx(mctx, lc)
+		if allowlist != nil {
+			if err := allowlist.restrictStreams(h); err != nil {
+				return err
+			}
+			h.SetStreamHandler(PeerCertificateProtocol, allowlist.handleCertificate)
+		}
+		h.Network().Notify(&network.NotifyBundle{
+			ConnectedF: func(n network.Network, c network.Conn) {
+				p := c.RemotePeer()
+				if c.Stat().Direction == network.DirOutbound {
+					if cert != nil {
+						go presentCertificate(ctx, h, p, cert)
+					}
+					return
+				}
+				// the peer can't open streams before the notifications
+				// are done
+				if allowlist != nil && allowlist.startProbation(p) {
+					time.AfterFunc(allowlist.probationTimeout, func() {
+						if allowlist.onProbation(p) {
+							log.Infof("disconnecting %s, which did not present a valid certificate in time", p)
+							allowlist.endProbation(p, true)
+							// forget its addresses, not to dial it back right away
+							n.Peerstore().ClearAddrs(p)
+							_ = n.ClosePeer(p)
+						}
+					})
+				}
+			},
+			DisconnectedF: func(n network.Network, c network.Conn) {
+				if allowlist != nil && len(n.ConnsToPeer(c.RemotePeer())) == 0 {
+					allowlist.endProbation(c.RemotePeer(), false)
+				}
+			},
+		})
+		return nil
+	}
+}
+
+func (a *InboundAllowlist) handleCertificate(s network.Stream) {
+	p := s.Conn().RemotePeer()
+	_ = s.SetDeadline(time.Now().Add(a.probationTimeout))
+	data, err := ioutil.ReadAll(io.LimitReader(s, maxPeerCertificateSize))
+	if err == nil {
+		err = a.admit(p, data)
+	}
+	if err != nil {
+		log.Warnf("refusing the certificate of %s: %s", p, err)
+		_ = s.Reset()
+		return
+	}
+	log.Debugf("admitted %s with its certificate", p)
+	_ = s.Close()
+}
+
+func presentCertificate(ctx context.Context, h host.Host, p peer.ID, cert []byte) {
+	ctx, cancel := context.WithTimeout(ctx, defaultProbationTimeout)
+	defer cancel()
+	s, err := h.NewStream(ctx, p, PeerCertificateProtocol)
+	if err != nil {
+		// the peer does not restrict its inbound connections
+		return
+	}
+	_ = s.SetDeadline(time.Now().Add(defaultProbationTimeout))
+	if _, err := s.Write(cert); err == nil {
+		err = s.CloseWrite()
+	}
+	if err == nil {
+		_, err = ioutil.ReadAll(s)
+	}
+	if err != nil {
+		log.Warnf("%s did not accept the certificate of this node: %s", p, err)
+		_ = s.Reset()
+		return
+	}
+	_ = s.Close()
+}
+
+// restrictStreams rejects the inbound streams of the peers on probation,
+// but the ones to identify themselves and present their certificate.
+func (a *InboundAllowlist) restrictStreams(h host.Host) error {
+	n, ok := h.Network().(interface {
+		StreamHandler() network.StreamHandler
+	})
+	if !ok {
+		return fmt.Errorf("cannot restrict the streams of the peers on probation: network does not expose its stream handler")
+	}
+	handler := n.StreamHandler()
+	h.Network().SetStreamHandler(func(s network.Stream) {
+		handler(&probationStream{Stream: s, allowlist: a})
+	})
+	return nil
+}
+
+// probationStream refuses to be bound to a protocol a peer on probation is
+// not allowed to use, like restrictedStream.
+type probationStream struct {
+	network.Stream
+	allowlist *InboundAllowlist
+}
+
+func (s *probationStream) SetProtocol(p protocol.ID) error {
+	if p != PeerCertificateProtocol && p != identify.ID && s.allowlist.onProbation(s.Conn().RemotePeer()) {
+		return fmt.Errorf("%s is on probation until it presents its certificate", s.Conn().RemotePeer())
+	}
+	return s.Stream.SetProtocol(p)
+}
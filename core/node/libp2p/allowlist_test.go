@@ -0,0 +1,132 @@
+package libp2p
+
+import (
+	"testing"
+	"time"
+
+	config "github.com/ipfs/go-ipfs/config"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/test"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/multiformats/go-multibase"
+)
+
+func testCertificateAuthority(t *testing.T) (crypto.PrivKey, peer.ID) {
+	t.Helper()
+	sk, pk, err := test.RandTestKeyPair(crypto.Ed25519, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk, id
+}
+
+func testCertificate(t *testing.T, ca crypto.PrivKey, p peer.ID, expires time.Time) []byte {
+	t.Helper()
+	s, err := IssuePeerCertificate(ca, p, expires)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, data, err := multibase.Decode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestInboundAllowlist(t *testing.T) {
+	listed, other := test.RandPeerIDFatal(t), test.RandPeerIDFatal(t)
+	addrs := testConnAddrs{ma.StringCast("/ip4/1.2.3.4/tcp/4001")}
+
+	allowlist, err := InboundAllowlistService(config.InboundAllowlist{
+		Enabled: config.True,
+		Peers:   []string{listed.String()},
+	})()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &filtersConnectionGater{filters: ma.NewFilters(), brake: new(SwarmBrake), allowlist: allowlist}
+	if !g.InterceptSecured(network.DirInbound, listed, addrs) {
+		t.Fatal("expected the inbound connections of the peers listed to be accepted")
+	}
+	if g.InterceptSecured(network.DirInbound, other, addrs) {
+		t.Fatal("expected the inbound connections of the other peers to be denied")
+	}
+	if !g.InterceptSecured(network.DirOutbound, other, addrs) {
+		t.Fatal("expected the outbound connections to be accepted")
+	}
+	if allowlist.startProbation(listed) {
+		t.Fatal("expected the peers listed not to be put on probation")
+	}
+	if st := allowlist.Status(); st.Denied != 1 || len(st.Probation) != 0 {
+		t.Fatalf("unexpected status of the allowlist %+v", st)
+	}
+
+	if _, err := InboundAllowlistService(config.InboundAllowlist{Enabled: config.True, Peers: []string{"foo"}})(); err == nil {
+		t.Fatal("expected an invalid peer ID to be refused")
+	}
+}
+
+func TestPeerCertificates(t *testing.T) {
+	ca, caID := testCertificateAuthority(t)
+	untrusted, _ := testCertificateAuthority(t)
+	p, other := test.RandPeerIDFatal(t), test.RandPeerIDFatal(t)
+
+	allowlist, err := InboundAllowlistService(config.InboundAllowlist{
+		Enabled:                config.True,
+		CertificateAuthorities: []string{caID.String()},
+	})()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowlist.admits(p) {
+		t.Fatal("expected the peers not listed to be accepted")
+	}
+	// a connection that fails to be established leaves no probation behind
+	if allowlist.onProbation(p) || len(allowlist.Status().Probation) != 0 {
+		t.Fatal("expected the peer to be put on probation only once connected")
+	}
+	if !allowlist.startProbation(p) || !allowlist.onProbation(p) {
+		t.Fatal("expected the peers not listed to be put on probation")
+	}
+
+	for _, c := range []struct {
+		name string
+		cert []byte
+	}{
+		{"signed by a key that is not a certificate authority", testCertificate(t, untrusted, p, time.Now().Add(time.Hour))},
+		{"of another peer", testCertificate(t, ca, other, time.Now().Add(time.Hour))},
+		{"expired", testCertificate(t, ca, p, time.Now().Add(-time.Hour))},
+		{"invalid", []byte("certificate")},
+	} {
+		if err := allowlist.admit(p, c.cert); err == nil {
+			t.Fatalf("expected a certificate %s to be refused", c.name)
+		}
+	}
+	if !allowlist.onProbation(p) {
+		t.Fatal("expected the peer to stay on probation")
+	}
+
+	if err := allowlist.admit(p, testCertificate(t, ca, p, time.Now().Add(time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if allowlist.onProbation(p) {
+		t.Fatal("expected the peer presenting its certificate to be admitted")
+	}
+	if !allowlist.admits(p) || allowlist.startProbation(p) || allowlist.onProbation(p) {
+		t.Fatal("expected the peer to be admitted again without probation")
+	}
+
+	allowlist.startProbation(other)
+	allowlist.endProbation(other, true)
+	st := allowlist.Status()
+	if len(st.Admitted) != 1 || st.Admitted[0].Peer != p || len(st.Probation) != 0 || st.Denied != 1 {
+		t.Fatalf("unexpected status of the allowlist %+v", st)
+	}
+}
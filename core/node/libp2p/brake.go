@@ -0,0 +1,93 @@
+package libp2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// SwarmBrake pauses the swarm: while paused, the new connections, inbound
+// and outbound, are denied, except with the peers allowed when pausing. The
+// existing connections are kept.
+type SwarmBrake struct {
+	mu     sync.Mutex
+	paused bool
+	since  time.Time
+	allow  func(peer.ID) bool
+
+	deniedInbound  int64
+	deniedOutbound int64
+}
+
+// SwarmBrakeStatus is the state of the brake.
+type SwarmBrakeStatus struct {
+	Paused bool
+	Since  time.Time `json:",omitempty"`
+	// DeniedInbound and DeniedOutbound are the connections denied since the
+	// swarm was last paused.
+	DeniedInbound  int64
+	DeniedOutbound int64
+}
+
+// Pause pauses the swarm, still allowing the connections with the peers
+// allow returns true for. Pausing a paused swarm only changes the peers
+// allowed.
+func (b *SwarmBrake) Pause(allow func(peer.ID) bool) SwarmBrakeStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.paused {
+		b.paused = true
+		b.since = time.Now()
+		b.deniedInbound, b.deniedOutbound = 0, 0
+	}
+	b.allow = allow
+	return b.statusLocked()
+}
+
+// Resume resumes the swarm. The status returned is the one of the pause.
+func (b *SwarmBrake) Resume() SwarmBrakeStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := b.statusLocked()
+	b.paused = false
+	b.allow = nil
+	return st
+}
+
+// Status returns the state of the brake.
+func (b *SwarmBrake) Status() SwarmBrakeStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.statusLocked()
+}
+
+func (b *SwarmBrake) statusLocked() SwarmBrakeStatus {
+	st := SwarmBrakeStatus{Paused: b.paused, DeniedInbound: b.deniedInbound, DeniedOutbound: b.deniedOutbound}
+	if b.paused {
+		st.Since = b.since
+	}
+	return st
+}
+
+// allows returns whether a new connection with p is allowed, counting it
+// when denied.
+func (b *SwarmBrake) allows(p peer.ID, inbound bool) bool {
+	b.mu.Lock()
+	paused, allow := b.paused, b.allow
+	b.mu.Unlock()
+	if !paused || (allow != nil && allow(p)) {
+		return true
+	}
+
+	b.mu.Lock()
+	if b.paused {
+		if inbound {
+			b.deniedInbound++
+		} else {
+			b.deniedOutbound++
+		}
+	}
+	b.mu.Unlock()
+	return false
+}
@@ -0,0 +1,45 @@
+package libp2p
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/test"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+type testConnAddrs struct{ remote ma.Multiaddr }
+
+func (a testConnAddrs) LocalMultiaddr() ma.Multiaddr  { return a.remote }
+func (a testConnAddrs) RemoteMultiaddr() ma.Multiaddr { return a.remote }
+
+func TestSwarmBrake(t *testing.T) {
+	brake := new(SwarmBrake)
+	g := &filtersConnectionGater{filters: ma.NewFilters(), brake: brake}
+	protected, other := test.RandPeerIDFatal(t), test.RandPeerIDFatal(t)
+	addrs := testConnAddrs{ma.StringCast("/ip4/1.2.3.4/tcp/4001")}
+
+	if !g.InterceptPeerDial(other) || !g.InterceptSecured(network.DirInbound, other, addrs) {
+		t.Fatal("expected the connections to be allowed before pausing")
+	}
+
+	brake.Pause(func(p peer.ID) bool { return p == protected })
+	if g.InterceptPeerDial(other) || g.InterceptSecured(network.DirInbound, other, addrs) {
+		t.Fatal("expected the new connections to be denied while paused")
+	}
+	if !g.InterceptPeerDial(protected) || !g.InterceptSecured(network.DirInbound, protected, addrs) {
+		t.Fatal("expected the connections with the allowed peers to be kept while paused")
+	}
+	if !g.InterceptSecured(network.DirOutbound, other, addrs) {
+		t.Fatal("expected the outbound connections to be only denied on dial")
+	}
+
+	st := brake.Resume()
+	if !st.Paused || st.DeniedInbound != 1 || st.DeniedOutbound != 1 {
+		t.Fatalf("unexpected status of the pause %+v", st)
+	}
+	if !g.InterceptPeerDial(other) || brake.Status().Paused {
+		t.Fatal("expected the connections to be allowed after resuming")
+	}
+}
@@ -10,25 +10,36 @@ import (
 )
 
 // filtersConnectionGater is an adapter that turns multiaddr.Filter into a
-// connmgr.ConnectionGater.
-type filtersConnectionGater ma.Filters
+// connmgr.ConnectionGater. It also denies the new connections while the
+// swarm is paused by the brake, and the inbound ones of the peers not on the
+// allowlist, if any.
+type filtersConnectionGater struct {
+	filters   *ma.Filters
+	brake     *SwarmBrake
+	allowlist *InboundAllowlist
+}
 
 var _ connmgr.ConnectionGater = (*filtersConnectionGater)(nil)
 
 func (f *filtersConnectionGater) InterceptAddrDial(_ peer.ID, addr ma.Multiaddr) (allow bool) {
-	return !(*ma.Filters)(f).AddrBlocked(addr)
+	return !f.filters.AddrBlocked(addr)
 }
 
 func (f *filtersConnectionGater) InterceptPeerDial(p peer.ID) (allow bool) {
-	return true
+	return f.brake.allows(p, false)
 }
 
 func (f *filtersConnectionGater) InterceptAccept(connAddr network.ConnMultiaddrs) (allow bool) {
-	return !(*ma.Filters)(f).AddrBlocked(connAddr.RemoteMultiaddr())
+	return !f.filters.AddrBlocked(connAddr.RemoteMultiaddr())
 }
 
-func (f *filtersConnectionGater) InterceptSecured(_ network.Direction, _ peer.ID, connAddr network.ConnMultiaddrs) (allow bool) {
-	return !(*ma.Filters)(f).AddrBlocked(connAddr.RemoteMultiaddr())
+// InterceptSecured is the first time the peer of an inbound connection is
+// known, so the brake and the allowlist deny them here.
+func (f *filtersConnectionGater) InterceptSecured(dir network.Direction, p peer.ID, connAddr network.ConnMultiaddrs) (allow bool) {
+	if f.filters.AddrBlocked(connAddr.RemoteMultiaddr()) {
+		return false
+	}
+	return dir != network.DirInbound || (f.brake.allows(p, true) && f.allowlist.admits(p))
 }
 
 func (f *filtersConnectionGater) InterceptUpgraded(_ network.Conn) (allow bool, reason control.DisconnectReason) {
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"time"
 
+	config "github.com/ipfs/go-ipfs/config"
 	"github.com/ipfs/go-ipfs/repo"
 
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/pnet"
 	"go.uber.org/fx"
 	"golang.org/x/crypto/salsa20"
@@ -71,6 +73,92 @@ func PNetChecker(repo repo.Repo, ph host.Host, lc fx.Lifecycle) error {
 	return nil
 }
 
+// PNetPeersChecker warns about the bootstrap and peering peers that
+// Swarm.PrivateNetwork does not allow when the node is in a private network.
+// They are left out by FilterPNetPeers, so that the node does not try to
+// reach the public network.
+func PNetPeersChecker(cfg *config.Config) func(fp PNetFingerprint) error {
+	return func(fp PNetFingerprint) error {
+		if fp == nil {
+			return nil
+		}
+
+		bootstrap, err := cfg.BootstrapPeers()
+		if err != nil {
+			return fmt.Errorf("invalid Bootstrap: %w", err)
+		}
+		for _, f := range []struct {
+			name  string
+			peers []peer.AddrInfo
+		}{
+			{"Bootstrap", bootstrap},
+			{"Peering.Peers", cfg.Peering.Peers},
+		} {
+			_, denied, err := pnetPeers(f.peers, cfg.Swarm.PrivateNetwork)
+			if err != nil {
+				return err
+			}
+			for _, reason := range denied {
+				log.Warnf("private network: ignoring the %s peer %s", f.name, reason)
+			}
+		}
+		return nil
+	}
+}
+
+// FilterPNetPeers returns the peers Swarm.PrivateNetwork allows when the node
+// is in a private network, and all of them otherwise.
+func FilterPNetPeers(fp PNetFingerprint, peers []peer.AddrInfo, pcfg config.PrivateNetwork) []peer.AddrInfo {
+	if fp == nil {
+		return peers
+	}
+	allowed, _, err := pnetPeers(peers, pcfg)
+	if err != nil {
+		// PNetPeersChecker refuses to start the node then
+		return nil
+	}
+	return allowed
+}
+
+// pnetPeers splits peers into the ones pcfg allows, and the reasons the others
+// are denied.
+func pnetPeers(peers []peer.AddrInfo, pcfg config.PrivateNetwork) (allowed []peer.AddrInfo, denied []string, err error) {
+	var allowlist map[peer.ID]struct{}
+	if len(pcfg.AllowedPeers) > 0 {
+		allowlist = make(map[peer.ID]struct{}, len(pcfg.AllowedPeers))
+		for _, s := range pcfg.AllowedPeers {
+			id, err := peer.Decode(s)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid peer ID in Swarm.PrivateNetwork.AllowedPeers: %q: %w", s, err)
+			}
+			allowlist[id] = struct{}{}
+		}
+	}
+	public := make(map[peer.ID]struct{})
+	if !pcfg.AllowPublicBootstrap.WithDefault(false) {
+		bootstrap, err := config.DefaultBootstrapPeers()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range bootstrap {
+			public[p.ID] = struct{}{}
+		}
+	}
+
+	for _, p := range peers {
+		if _, ok := public[p.ID]; ok {
+			denied = append(denied, fmt.Sprintf("%s: it is a public bootstrap peer, remove it (see 'ipfs bootstrap rm --help') or set Swarm.PrivateNetwork.AllowPublicBootstrap", p.ID))
+			continue
+		}
+		if _, ok := allowlist[p.ID]; allowlist != nil && !ok {
+			denied = append(denied, fmt.Sprintf("%s: it is not in Swarm.PrivateNetwork.AllowedPeers", p.ID))
+			continue
+		}
+		allowed = append(allowed, p)
+	}
+	return allowed, denied, nil
+}
+
 func pnetFingerprint(psk pnet.PSK) []byte {
 	var pskArr [32]byte
 	copy(pskArr[:], psk)
@@ -0,0 +1,50 @@
+package libp2p
+
+import (
+	"encoding/json"
+	"testing"
+
+	config "github.com/ipfs/go-ipfs/config"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPNetPeers(t *testing.T) {
+	public, err := config.DefaultBootstrapPeers()
+	require.NoError(t, err)
+	private, err := config.ParseBootstrapPeers([]string{"/ip4/10.0.0.1/tcp/4001/p2p/QmaCpDMGvV2BGHeYERUEnRQAwe3N8SzbUtfsmvsqQLuvuK"})
+	require.NoError(t, err)
+
+	pnetConfig := func(s string) config.PrivateNetwork {
+		var pcfg config.PrivateNetwork
+		require.NoError(t, json.Unmarshal([]byte(s), &pcfg))
+		return pcfg
+	}
+
+	allowed, denied, err := pnetPeers(private, pnetConfig(`{}`))
+	require.NoError(t, err)
+	require.Equal(t, private, allowed)
+	require.Empty(t, denied)
+
+	allowed, denied, err = pnetPeers(append(private, public...), pnetConfig(`{}`))
+	require.NoError(t, err)
+	require.Equal(t, private, allowed)
+	require.Len(t, denied, len(public))
+
+	allowed, _, err = pnetPeers(public, pnetConfig(`{"AllowPublicBootstrap": true}`))
+	require.NoError(t, err)
+	require.Equal(t, public, allowed)
+
+	allowlist := pnetConfig(`{"AllowedPeers": ["` + private[0].ID.String() + `"]}`)
+	other := peer.AddrInfo{ID: public[0].ID}
+	allowed, denied, err = pnetPeers(append(private, other), allowlist)
+	require.NoError(t, err)
+	require.Equal(t, private, allowed)
+	require.Len(t, denied, 1)
+	_, _, err = pnetPeers(private, pnetConfig(`{"AllowedPeers": ["invalid"]}`))
+	require.Error(t, err)
+
+	require.Equal(t, public, FilterPNetPeers(nil, public, pnetConfig(`{}`)), "expected the peers to be kept outside of a private network")
+	require.Empty(t, FilterPNetPeers(PNetFingerprint("fp"), public, pnetConfig(`{}`)))
+}
@@ -0,0 +1,237 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+)
+
+// topicPeersSweepInterval is how often the peers that unsubscribed from a
+// topic without disconnecting give their slot back.
+const topicPeersSweepInterval = time.Minute
+
+// topicLimit is the state of the limits of a topic.
+type topicLimit struct {
+	maxSize int
+
+	// token bucket holding up to a second of messages
+	rate   float64
+	tokens float64
+	last   time.Time
+
+	maxPeers int
+	peers    map[peer.ID]struct{}
+}
+
+// allow takes a token from the bucket of the topic, if there is one.
+func (t *topicLimit) allow(now time.Time) bool {
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.rate {
+		t.tokens = t.rate
+	}
+	t.last = now
+	if t.tokens < 1 {
+		return false
+	}
+	t.tokens--
+	return true
+}
+
+// TopicLimiter enforces Pubsub.TopicLimits: message rates and sizes are
+// checked by topic validators, and the peers of a topic by a peer filter,
+// which keeps the router from meshing or gossiping with more peers.
+type TopicLimiter struct {
+	mu     sync.Mutex
+	limits map[string]*topicLimit
+}
+
+// NewTopicLimiter returns the limiter of the configured topics, or nil when
+// no topic is limited.
+func NewTopicLimiter(cfg map[string]config.PubsubTopicLimits) (*TopicLimiter, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+
+	l := &TopicLimiter{limits: make(map[string]*topicLimit, len(cfg))}
+	now := time.Now()
+	for topic, c := range cfg {
+		if strings.HasPrefix(topic, "/record/") {
+			// IPNS over pubsub registers its own validators
+			return nil, fmt.Errorf("Pubsub.TopicLimits: can't limit the IPNS topic %q", topic)
+		}
+
+		t := &topicLimit{last: now}
+		if s := c.MaxMessageSize.WithDefault(""); s != "" {
+			size, err := humanize.ParseBytes(s)
+			if err != nil {
+				return nil, fmt.Errorf("Pubsub.TopicLimits[%q].MaxMessageSize: %w", topic, err)
+			}
+			t.maxSize = int(size)
+		}
+		rate := c.MaxMessageRate.WithDefault(0)
+		maxPeers := c.MaxPeers.WithDefault(0)
+		if rate < 0 || maxPeers < 0 {
+			return nil, fmt.Errorf("Pubsub.TopicLimits[%q]: limits must be positive", topic)
+		}
+		t.rate = float64(rate)
+		t.tokens = t.rate
+		if maxPeers > 0 {
+			t.maxPeers = int(maxPeers)
+			t.peers = make(map[peer.ID]struct{})
+		}
+		l.limits[topic] = t
+	}
+	return l, nil
+}
+
+// Options returns the pubsub options enforcing the peer limits.
+func (l *TopicLimiter) Options() []pubsub.Option {
+	return []pubsub.Option{
+		pubsub.WithPeerFilter(l.filterPeer),
+		pubsub.WithRawTracer(&topicPeersTracer{l}),
+	}
+}
+
+// filterPeer lets the peers of a topic in until the topic has MaxPeers.
+func (l *TopicLimiter) filterPeer(p peer.ID, topic string) bool {
+	t, ok := l.limits[topic]
+	if !ok || t.maxPeers == 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := t.peers[p]; ok {
+		return true
+	}
+	if len(t.peers) >= t.maxPeers {
+		return false
+	}
+	t.peers[p] = struct{}{}
+	return true
+}
+
+// removePeer gives back the slots of p in every topic.
+func (l *TopicLimiter) removePeer(p peer.ID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, t := range l.limits {
+		delete(t.peers, p)
+	}
+}
+
+// validator checks the messages of topic against its rate and size limits.
+// Messages over a limit are ignored, not rejected, so that the peers
+// relaying them aren't penalized for local policy.
+func (l *TopicLimiter) validator(topic string) pubsub.ValidatorEx {
+	t := l.limits[topic]
+	return func(ctx context.Context, p peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+		if t.maxSize > 0 && len(msg.GetData()) > t.maxSize {
+			log.Debugf("pubsub: dropping message from %s on %q: %d bytes over the limit", p, topic, len(msg.GetData()))
+			return pubsub.ValidationIgnore
+		}
+		if t.rate > 0 {
+			l.mu.Lock()
+			ok := t.allow(time.Now())
+			l.mu.Unlock()
+			if !ok {
+				log.Debugf("pubsub: dropping message from %s on %q: over the rate limit", p, topic)
+				return pubsub.ValidationIgnore
+			}
+		}
+		return pubsub.ValidationAccept
+	}
+}
+
+// sweep frees the slots of the peers no longer subscribed to their topic.
+func (l *TopicLimiter) sweep(ps *pubsub.PubSub) {
+	for topic, t := range l.limits {
+		if t.maxPeers == 0 {
+			continue
+		}
+		subscribed := make(map[peer.ID]struct{})
+		for _, p := range ps.ListPeers(topic) {
+			subscribed[p] = struct{}{}
+		}
+
+		l.mu.Lock()
+		for p := range t.peers {
+			if _, ok := subscribed[p]; !ok {
+				delete(t.peers, p)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+// Start registers the topic validators on ps, and frees the slots of the
+// peers leaving the topics while the node runs.
+func (l *TopicLimiter) Start(mctx helpers.MetricsCtx, lc fx.Lifecycle, ps *pubsub.PubSub) error {
+	for topic, t := range l.limits {
+		if t.maxSize == 0 && t.rate == 0 {
+			continue
+		}
+		if err := ps.RegisterTopicValidator(topic, l.validator(topic), pubsub.WithValidatorInline(true)); err != nil {
+			return fmt.Errorf("limiting pubsub topic %q: %w", topic, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(helpers.LifecycleCtx(mctx, lc))
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				t := time.NewTicker(topicPeersSweepInterval)
+				defer t.Stop()
+				for {
+					select {
+					case <-t.C:
+						l.sweep(ps)
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+	return nil
+}
+
+// topicPeersTracer frees the slots of the peers disconnecting from pubsub.
+type topicPeersTracer struct {
+	l *TopicLimiter
+}
+
+var _ pubsub.RawTracer = (*topicPeersTracer)(nil)
+
+func (t *topicPeersTracer) RemovePeer(p peer.ID) { t.l.removePeer(p) }
+
+func (t *topicPeersTracer) AddPeer(p peer.ID, proto protocol.ID)        {}
+func (t *topicPeersTracer) Join(topic string)                           {}
+func (t *topicPeersTracer) Leave(topic string)                          {}
+func (t *topicPeersTracer) Graft(p peer.ID, topic string)               {}
+func (t *topicPeersTracer) Prune(p peer.ID, topic string)               {}
+func (t *topicPeersTracer) ValidateMessage(msg *pubsub.Message)         {}
+func (t *topicPeersTracer) DeliverMessage(msg *pubsub.Message)          {}
+func (t *topicPeersTracer) RejectMessage(msg *pubsub.Message, r string) {}
+func (t *topicPeersTracer) DuplicateMessage(msg *pubsub.Message)        {}
+func (t *topicPeersTracer) ThrottlePeer(p peer.ID)                      {}
+func (t *topicPeersTracer) RecvRPC(rpc *pubsub.RPC)                     {}
+func (t *topicPeersTracer) SendRPC(rpc *pubsub.RPC, p peer.ID)          {}
+func (t *topicPeersTracer) DropRPC(rpc *pubsub.RPC, p peer.ID)          {}
+func (t *topicPeersTracer) UndeliverableMessage(msg *pubsub.Message)    {}
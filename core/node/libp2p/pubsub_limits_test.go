@@ -0,0 +1,90 @@
+package libp2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	config "github.com/ipfs/go-ipfs/config"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+)
+
+func TestTopicLimiter(t *testing.T) {
+	if l, err := NewTopicLimiter(nil); err != nil || l != nil {
+		t.Fatalf("expected no limiter without limits, got %v", err)
+	}
+
+	for i, cfg := range []map[string]config.PubsubTopicLimits{
+		{"/record/foo": {MaxPeers: config.NewOptionalInteger(1)}},
+		{"foo": {MaxMessageSize: config.NewOptionalString("lots")}},
+		{"foo": {MaxPeers: config.NewOptionalInteger(-1)}},
+	} {
+		if _, err := NewTopicLimiter(cfg); err == nil {
+			t.Errorf("expected an error for the limits %d", i)
+		}
+	}
+
+	l, err := NewTopicLimiter(map[string]config.PubsubTopicLimits{
+		"foo": {
+			MaxMessageRate: config.NewOptionalInteger(2),
+			MaxMessageSize: config.NewOptionalString("10B"),
+			MaxPeers:       config.NewOptionalInteger(2),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// peers
+	p1, p2, p3 := peer.ID("p1"), peer.ID("p2"), peer.ID("p3")
+	if !l.filterPeer(p1, "foo") || !l.filterPeer(p2, "foo") || !l.filterPeer(p1, "foo") {
+		t.Fatal("expected the first peers to be let in")
+	}
+	if l.filterPeer(p3, "foo") {
+		t.Fatal("expected a third peer to be filtered out")
+	}
+	if !l.filterPeer(p3, "bar") {
+		t.Fatal("expected unlimited topics not to be filtered")
+	}
+	l.removePeer(p1)
+	if !l.filterPeer(p3, "foo") {
+		t.Fatal("expected a peer to be let in after another left")
+	}
+
+	// messages
+	validate := l.validator("foo")
+	msg := func(data string) *pubsub.Message {
+		return &pubsub.Message{Message: &pb.Message{Data: []byte(data)}}
+	}
+	ctx := context.Background()
+	if r := validate(ctx, p1, msg("much too large")); r != pubsub.ValidationIgnore {
+		t.Fatalf("expected large messages to be ignored, got %d", r)
+	}
+	for i := 0; i < 2; i++ {
+		if r := validate(ctx, p1, msg("hi")); r != pubsub.ValidationAccept {
+			t.Fatalf("expected message %d to be accepted, got %d", i, r)
+		}
+	}
+	if r := validate(ctx, p1, msg("hi")); r != pubsub.ValidationIgnore {
+		t.Fatalf("expected messages over the rate to be ignored, got %d", r)
+	}
+}
+
+func TestTopicLimitAllow(t *testing.T) {
+	start := time.Now()
+	tl := &topicLimit{rate: 2, tokens: 2, last: start}
+	if !tl.allow(start) || !tl.allow(start) || tl.allow(start) {
+		t.Fatal("expected a burst of 2 messages")
+	}
+	if !tl.allow(start.Add(500*time.Millisecond)) || tl.allow(start.Add(500*time.Millisecond)) {
+		t.Fatal("expected 1 message after half a second")
+	}
+	// the bucket holds a second of messages at most
+	later := start.Add(time.Hour)
+	if !tl.allow(later) || !tl.allow(later) || tl.allow(later) {
+		t.Fatal("expected a burst of 2 messages after a long pause")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	config "github.com/ipfs/go-ipfs/config"
 	"github.com/ipfs/go-ipfs/repo"
@@ -73,6 +74,12 @@ func ResourceManager(cfg config.SwarmConfig) func(fx.Lifecycle, repo.Repo) (netw
 			if err != nil {
 				return nil, opts, fmt.Errorf("creating libp2p resource manager: %w", err)
 			}
+
+			if cfg.ResourceMgr.ServiceAlert.Enabled.WithDefault(false) {
+				if err := startServiceWatchdog(lc, manager, cfg.ResourceMgr.ServiceAlert); err != nil {
+					return nil, opts, err
+				}
+			}
 		} else {
 			log.Debug("libp2p resource manager is disabled")
 			manager = network.NullResourceManager
@@ -186,6 +193,76 @@ func NetStat(mgr network.ResourceManager, scope string) (NetStatOut, error) {
 	}
 }
 
+// NetDumpOut is a snapshot of the usage of every scope of the resource
+// manager.
+type NetDumpOut struct {
+	Time time.Time
+	NetStatOut
+}
+
+// NetDump takes a snapshot of the usage of the system and transient scopes,
+// and of every service, protocol and peer scope currently tracked by the
+// resource manager.
+func NetDump(mgr network.ResourceManager) (NetDumpOut, error) {
+	result := NetDumpOut{Time: time.Now()}
+	rapi, ok := mgr.(rcmgr.ResourceManagerState)
+	if !ok { // NullResourceManager
+		return result, NoResourceMgrError
+	}
+
+	err := mgr.ViewSystem(func(s network.ResourceScope) error {
+		stat := s.Stat()
+		result.System = &stat
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	err = mgr.ViewTransient(func(s network.ResourceScope) error {
+		stat := s.Stat()
+		result.Transient = &stat
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Services = make(map[string]network.ScopeStat)
+	for _, svc := range rapi.ListServices() {
+		err := mgr.ViewService(svc, func(s network.ServiceScope) error {
+			result.Services[svc] = s.Stat()
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Protocols = make(map[string]network.ScopeStat)
+	for _, proto := range rapi.ListProtocols() {
+		err := mgr.ViewProtocol(proto, func(s network.ProtocolScope) error {
+			result.Protocols[string(proto)] = s.Stat()
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Peers = make(map[string]network.ScopeStat)
+	for _, p := range rapi.ListPeers() {
+		err := mgr.ViewPeer(p, func(s network.PeerScope) error {
+			result.Peers[p.Pretty()] = s.Stat()
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
 func NetLimit(mgr network.ResourceManager, scope string) (rcmgr.BasicLimitConfig, error) {
 	var result rcmgr.BasicLimitConfig
 	getLimit := func(s network.ResourceScope) error {
@@ -0,0 +1,191 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	config "github.com/ipfs/go-ipfs/config"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	rcmgr "github.com/libp2p/go-libp2p-resource-manager"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+const (
+	defaultServiceAlertThreshold = 80
+	defaultServiceAlertDuration  = 5 * time.Minute
+	defaultServiceAlertInterval  = 10 * time.Second
+)
+
+var serviceAlertGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "libp2p_rcmgr_service_alert",
+		Help: "Whether a service has been above Swarm.ResourceMgr.ServiceAlert.Threshold of one of its limits for longer than Duration",
+	},
+	[]string{"service"},
+)
+
+// serviceSample is the utilization of a service scope at a point in time:
+// the highest ratio of usage to limit of its resources.
+type serviceSample struct {
+	time        time.Time
+	utilization float64
+}
+
+// serviceWatchdog samples the utilization of the service scopes of the
+// resource manager, and raises an alert when a service stays above the
+// threshold for longer than the configured duration.
+type serviceWatchdog struct {
+	mgr       network.ResourceManager
+	threshold float64
+	duration  time.Duration
+	interval  time.Duration
+
+	samples map[string][]serviceSample
+	alerts  map[string]bool
+}
+
+func newServiceWatchdog(mgr network.ResourceManager, cfg config.ServiceAlert) *serviceWatchdog {
+	return &serviceWatchdog{
+		mgr:       mgr,
+		threshold: float64(cfg.Threshold.WithDefault(defaultServiceAlertThreshold)) / 100,
+		duration:  cfg.Duration.WithDefault(defaultServiceAlertDuration),
+		interval:  cfg.Interval.WithDefault(defaultServiceAlertInterval),
+		samples:   make(map[string][]serviceSample),
+		alerts:    make(map[string]bool),
+	}
+}
+
+// scopeUtilization returns the highest ratio of usage to limit among the
+// resources of a scope.
+func scopeUtilization(stat network.ScopeStat, limit rcmgr.Limit) float64 {
+	var max float64
+	check := func(used int64, limit int64) {
+		if limit > 0 {
+			if u := float64(used) / float64(limit); u > max {
+				max = u
+			}
+		}
+	}
+	check(int64(stat.NumStreamsInbound), int64(limit.GetStreamLimit(network.DirInbound)))
+	check(int64(stat.NumStreamsOutbound), int64(limit.GetStreamLimit(network.DirOutbound)))
+	check(int64(stat.NumStreamsInbound+stat.NumStreamsOutbound), int64(limit.GetStreamTotalLimit()))
+	check(int64(stat.NumConnsInbound), int64(limit.GetConnLimit(network.DirInbound)))
+	check(int64(stat.NumConnsOutbound), int64(limit.GetConnLimit(network.DirOutbound)))
+	check(int64(stat.NumConnsInbound+stat.NumConnsOutbound), int64(limit.GetConnTotalLimit()))
+	check(int64(stat.NumFD), int64(limit.GetFDLimit()))
+	check(stat.Memory, limit.GetMemoryLimit())
+	return max
+}
+
+// sample records the utilization of every service scope.
+func (w *serviceWatchdog) sample(now time.Time) {
+	rapi, ok := w.mgr.(rcmgr.ResourceManagerState)
+	if !ok {
+		return
+	}
+
+	current := make(map[string]float64)
+	for _, svc := range rapi.ListServices() {
+		_ = w.mgr.ViewService(svc, func(s network.ServiceScope) error {
+			if limiter, ok := s.(rcmgr.ResourceScopeLimiter); ok {
+				current[svc] = scopeUtilization(s.Stat(), limiter.Limit())
+			}
+			return nil
+		})
+	}
+	w.record(now, current)
+}
+
+// record adds a sample per service, and updates their alerts. A service is
+// alerting when all its samples over the last duration are above the
+// threshold.
+func (w *serviceWatchdog) record(now time.Time, utilization map[string]float64) {
+	for svc := range w.samples {
+		if _, ok := utilization[svc]; !ok {
+			// the scope is gone
+			delete(w.samples, svc)
+			w.setAlert(svc, false, 0)
+		}
+	}
+
+	for svc, u := range utilization {
+		// Keep the samples covering the last duration, plus the one just
+		// before it, which tells whether the service was already above the
+		// threshold at the start of the window.
+		samples := append(w.samples[svc], serviceSample{time: now, utilization: u})
+		for len(samples) > 1 && now.Sub(samples[1].time) >= w.duration {
+			samples = samples[1:]
+		}
+		w.samples[svc] = samples
+
+		alert := now.Sub(samples[0].time) >= w.duration
+		for _, s := range samples {
+			if s.utilization < w.threshold {
+				alert = false
+				break
+			}
+		}
+		w.setAlert(svc, alert, u)
+	}
+}
+
+func (w *serviceWatchdog) setAlert(svc string, alert bool, utilization float64) {
+	if w.alerts[svc] == alert {
+		return
+	}
+	if alert {
+		log.Warnf("resource manager: service %q has been above %.0f%% of one of its limits for %s, now at %.0f%%; its limits may be too low, see 'ipfs swarm limit svc:%s'", svc, w.threshold*100, w.duration, utilization*100, svc)
+		w.alerts[svc] = true
+		serviceAlertGauge.WithLabelValues(svc).Set(1)
+	} else {
+		log.Infof("resource manager: service %q is back under %.0f%% of its limits", svc, w.threshold*100)
+		delete(w.alerts, svc)
+		serviceAlertGauge.DeleteLabelValues(svc)
+	}
+}
+
+// startServiceWatchdog samples the service scopes of mgr while the node
+// runs, as configured in Swarm.ResourceMgr.ServiceAlert.
+func startServiceWatchdog(lc fx.Lifecycle, mgr network.ResourceManager, cfg config.ServiceAlert) error {
+	w := newServiceWatchdog(mgr, cfg)
+	if w.threshold <= 0 || w.threshold > 1 {
+		return fmt.Errorf("Swarm.ResourceMgr.ServiceAlert.Threshold must be a percentage between 1 and 100")
+	}
+	if w.interval <= 0 || w.duration <= 0 {
+		return fmt.Errorf("Swarm.ResourceMgr.ServiceAlert: Interval and Duration must be positive")
+	}
+	if err := prometheus.Register(serviceAlertGauge); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go w.run(ctx)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+	return nil
+}
+
+func (w *serviceWatchdog) run(ctx context.Context) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		select {
+		case now := <-t.C:
+			w.sample(now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
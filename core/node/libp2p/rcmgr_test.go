@@ -0,0 +1,106 @@
+package libp2p
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	config "github.com/ipfs/go-ipfs/config"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	rcmgr "github.com/libp2p/go-libp2p-resource-manager"
+)
+
+func TestNetDump(t *testing.T) {
+	if _, err := NetDump(network.NullResourceManager); err != NoResourceMgrError {
+		t.Fatalf("expected NoResourceMgrError, got %v", err)
+	}
+
+	mgr, err := rcmgr.NewResourceManager(rcmgr.NewDefaultLimiter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Close()
+
+	p := peer.ID("testpeer")
+	s, err := mgr.OpenStream(p, network.DirInbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Done()
+	if err := s.SetProtocol("/test/1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetService("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	dump, err := NetDump(mgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump.System == nil || dump.System.NumStreamsInbound != 1 {
+		t.Fatalf("unexpected system scope: %+v", dump.System)
+	}
+	if dump.Transient == nil {
+		t.Fatal("missing transient scope")
+	}
+	if dump.Services["test"].NumStreamsInbound != 1 {
+		t.Fatalf("unexpected service scopes: %+v", dump.Services)
+	}
+	if dump.Protocols["/test/1.0.0"].NumStreamsInbound != 1 {
+		t.Fatalf("unexpected protocol scopes: %+v", dump.Protocols)
+	}
+	if dump.Peers[p.Pretty()].NumStreamsInbound != 1 {
+		t.Fatalf("unexpected peer scopes: %+v", dump.Peers)
+	}
+}
+
+func TestServiceWatchdog(t *testing.T) {
+	mgr, err := rcmgr.NewResourceManager(rcmgr.NewDefaultLimiter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Close()
+
+	s, err := mgr.OpenStream(peer.ID("testpeer"), network.DirInbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetProtocol("/test/1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetService("test.svc"); err != nil {
+		t.Fatal(err)
+	}
+	err = mgr.ViewService("test.svc", func(scope network.ServiceScope) error {
+		scope.(rcmgr.ResourceScopeLimiter).SetLimit(&rcmgr.StaticLimit{
+			Memory:    1 << 20,
+			BaseLimit: rcmgr.BaseLimit{Streams: 2, StreamsInbound: 2, StreamsOutbound: 2},
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.ServiceAlert
+	if err := json.Unmarshal([]byte(`{"Threshold": 50, "Duration": "1m"}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	w := newServiceWatchdog(mgr, cfg)
+	start := time.Now()
+	for i, expected := range []bool{false, false, true, true} {
+		w.sample(start.Add(time.Duration(i) * 30 * time.Second))
+		if w.alerts["test.svc"] != expected {
+			t.Fatalf("sample %d: expected alert to be %t", i, expected)
+		}
+	}
+
+	s.Done()
+	w.sample(start.Add(2 * time.Minute))
+	if w.alerts["test.svc"] {
+		t.Fatal("expected the alert to be cleared once the service is under the threshold")
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	config "github.com/ipfs/go-ipfs/config"
 	"github.com/ipfs/go-ipfs/repo"
+	"github.com/ipfs/go-ipfs/routingtrace"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/routing"
@@ -32,6 +33,8 @@ type Router struct {
 	routing.Routing
 
 	Priority int // less = more important
+	// Name identifies the router in the routing traces.
+	Name string
 }
 
 type p2pRouterOut struct {
@@ -49,6 +52,7 @@ type processInitialRoutingIn struct {
 	Host      host.Host
 	Repo      repo.Repo
 	Validator record.Validator
+	Fprint    PNetFingerprint `optional:"true"`
 }
 
 type processInitialRoutingOut struct {
@@ -84,6 +88,7 @@ func BaseRouting(experimentalDHTClient bool) interface{} {
 			if err != nil {
 				return out, err
 			}
+			bspeers = FilterPNetPeers(in.Fprint, bspeers, cfg.Swarm.PrivateNetwork)
 
 			expClient, err := fullrt.NewFullRT(in.Host,
 				dht.DefaultPrefix,
@@ -108,6 +113,7 @@ func BaseRouting(experimentalDHTClient bool) interface{} {
 				Router: Router{
 					Routing:  expClient,
 					Priority: 1000,
+					Name:     "fullrt",
 				},
 				DHT:       dr,
 				DHTClient: expClient,
@@ -115,10 +121,15 @@ func BaseRouting(experimentalDHTClient bool) interface{} {
 			}, nil
 		}
 
+		name := "default"
+		if dr != nil {
+			name = "dht"
+		}
 		return processInitialRoutingOut{
 			Router: Router{
 				Priority: 1000,
 				Routing:  in.Router,
+				Name:     name,
 			},
 			DHT:       dr,
 			DHTClient: dr,
@@ -134,16 +145,26 @@ type p2pOnlineRoutingIn struct {
 	Validator record.Validator
 }
 
-func Routing(in p2pOnlineRoutingIn) routing.Routing {
-	routers := in.Routers
-
+// namedRouters returns the routers in the order they are queried, all named.
+func namedRouters(routers []Router) []Router {
+	routers = append([]Router(nil), routers...)
 	sort.SliceStable(routers, func(i, j int) bool {
 		return routers[i].Priority < routers[j].Priority
 	})
+	for i := range routers {
+		if routers[i].Name == "" {
+			routers[i].Name = fmt.Sprintf("router%d", i)
+		}
+	}
+	return routers
+}
+
+func Routing(in p2pOnlineRoutingIn) routing.Routing {
+	routers := namedRouters(in.Routers)
 
 	irouters := make([]routing.Routing, len(routers))
 	for i, v := range routers {
-		irouters[i] = v.Routing
+		irouters[i] = routingtrace.Wrap(v.Name, v.Routing)
 	}
 
 	return routinghelpers.Tiered{
@@ -183,12 +204,14 @@ func PubsubRouter(mctx helpers.MetricsCtx, lc fx.Lifecycle, in p2pPSRoutingIn) (
 				},
 			},
 			Priority: 100,
+			Name:     "pubsub",
 		},
 	}, psRouter, nil
 }
 
-func AutoRelayFeeder(cfgPeering config.Peering) func(fx.Lifecycle, host.Host, AddrInfoChan, *ddht.DHT) {
-	return func(lc fx.Lifecycle, h host.Host, peerChan AddrInfoChan, dht *ddht.DHT) {
+func AutoRelayFeeder(cfgPeering config.Peering, pcfg config.PrivateNetwork) func(fx.Lifecycle, host.Host, AddrInfoChan, *ddht.DHT, PNetFingerprint) {
+	return func(lc fx.Lifecycle, h host.Host, peerChan AddrInfoChan, dht *ddht.DHT, fp PNetFingerprint) {
+		trustedPeers := FilterPNetPeers(fp, cfgPeering.Peers, pcfg)
 		ctx, cancel := context.WithCancel(context.Background())
 		done := make(chan struct{})
 
@@ -217,7 +240,7 @@ func AutoRelayFeeder(cfgPeering config.Peering) func(fx.Lifecycle, host.Host, Ad
 				}
 
 				// Always feed trusted IDs (Peering.Peers in the config)
-				for _, trustedPeer := range cfgPeering.Peers {
+				for _, trustedPeer := range trustedPeers {
 					if len(trustedPeer.Addrs) == 0 {
 						continue
 					}
@@ -0,0 +1,269 @@
+package libp2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	routinghelpers "github.com/libp2p/go-libp2p-routing-helpers"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+)
+
+// Health statuses of a router.
+const (
+	RouterUnchecked   = "unchecked"
+	RouterHealthy     = "healthy"
+	RouterUnhealthy   = "unhealthy"
+	RouterUnsupported = "unsupported"
+)
+
+const (
+	defaultHealthCheckInterval = 5 * time.Minute
+	defaultHealthCheckTimeout  = time.Minute
+
+	// healthRetryInterval is the time between two probes of an unhealthy
+	// router, when shorter than the interval of the probes.
+	healthRetryInterval = 10 * time.Second
+)
+
+// RouterHealth is the health of a router, as found by its last probe.
+type RouterHealth struct {
+	Name   string
+	Status string
+	// LastProbe is the time the router was last probed, and Latency the time
+	// it took to answer.
+	LastProbe time.Time
+	Latency   time.Duration
+	// LastHealthy is the last time the router was found healthy.
+	LastHealthy time.Time
+	Error       string `json:",omitempty"`
+}
+
+// RoutingHealth probes the routers of the node periodically, with a lookup of
+// the node itself. A router answering the lookup, even without finding the
+// node, is healthy: it could reach the network.
+type RoutingHealth struct {
+	self    peer.ID
+	timeout time.Duration
+	ready   []string
+
+	mu      sync.Mutex
+	routers []RouterHealth
+	// changed is closed and replaced when a status changes.
+	changed chan struct{}
+}
+
+func newRoutingHealth(self peer.ID, routers []Router, timeout time.Duration, ready []string) (*RoutingHealth, error) {
+	h := &RoutingHealth{
+		self:    self,
+		timeout: timeout,
+		ready:   ready,
+		routers: make([]RouterHealth, len(routers)),
+		changed: make(chan struct{}),
+	}
+	names := make([]string, len(routers))
+	for i, r := range routers {
+		names[i] = r.Name
+		h.routers[i] = RouterHealth{Name: r.Name, Status: RouterUnchecked}
+		if !findsPeers(r.Routing) {
+			h.routers[i].Status = RouterUnsupported
+		}
+	}
+
+next:
+	for _, name := range ready {
+		for _, r := range h.routers {
+			if r.Name != name {
+				continue
+			}
+			if r.Status == RouterUnsupported {
+				return nil, fmt.Errorf("the router %q in Routing.HealthCheck.ReadyRouters can't be probed", name)
+			}
+			continue next
+		}
+		return nil, fmt.Errorf("unknown router %q in Routing.HealthCheck.ReadyRouters, the routers are: %s", name, strings.Join(names, ", "))
+	}
+	return h, nil
+}
+
+// findsPeers returns whether r does peer routing, so that it can be probed.
+func findsPeers(r routing.Routing) bool {
+	switch r := r.(type) {
+	case routinghelpers.Null:
+		return false
+	case *routinghelpers.Compose:
+		return r.PeerRouting != nil
+	}
+	return true
+}
+
+// Status returns the health of the routers, in the order they are queried.
+func (h *RoutingHealth) Status() []RouterHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]RouterHealth(nil), h.routers...)
+}
+
+// ReadyRouters returns the routers that must be healthy for the node to be
+// ready.
+func (h *RoutingHealth) ReadyRouters() []string {
+	return h.ready
+}
+
+// Ready returns whether all the ReadyRouters are healthy.
+func (h *RoutingHealth) Ready() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.readyLocked()
+}
+
+func (h *RoutingHealth) readyLocked() bool {
+	for _, name := range h.ready {
+		for _, r := range h.routers {
+			if r.Name == name && r.Status != RouterHealthy {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// WaitReady waits for all the ReadyRouters to be healthy.
+func (h *RoutingHealth) WaitReady(ctx context.Context) error {
+	for {
+		h.mu.Lock()
+		ready, changed := h.readyLocked(), h.changed
+		h.mu.Unlock()
+		if ready {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// probe probes the router i, and returns whether it is healthy.
+func (h *RoutingHealth) probe(ctx context.Context, i int, r routing.Routing) bool {
+	pctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	start := time.Now()
+	_, err := r.FindPeer(pctx, h.self)
+	latency := time.Since(start)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	status := RouterHealthy
+	switch {
+	case err == nil || errors.Is(err, routing.ErrNotFound):
+		err = nil
+	case errors.Is(err, routing.ErrNotSupported):
+		status = RouterUnsupported
+	case pctx.Err() != nil:
+		status = RouterUnhealthy
+		err = fmt.Errorf("no answer within %s", h.timeout)
+	default:
+		status = RouterUnhealthy
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := &h.routers[i]
+	if st.Status != status {
+		close(h.changed)
+		h.changed = make(chan struct{})
+		log.Infof("router %s is %s", st.Name, status)
+	}
+	st.Status = status
+	st.LastProbe = start
+	st.Latency = latency
+	st.Error = ""
+	if err != nil {
+		st.Error = err.Error()
+	}
+	if status == RouterHealthy {
+		st.LastHealthy = start
+	}
+	return status == RouterHealthy
+}
+
+// run probes the router i every interval, until ctx is done.
+func (h *RoutingHealth) run(ctx context.Context, i int, r routing.Routing, interval time.Duration) {
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+
+		wait := interval
+		if !h.probe(ctx, i, r) {
+			h.mu.Lock()
+			unsupported := h.routers[i].Status == RouterUnsupported
+			h.mu.Unlock()
+			if unsupported {
+				return
+			}
+			if healthRetryInterval < wait {
+				wait = healthRetryInterval
+			}
+		}
+		t.Reset(wait)
+	}
+}
+
+type routingHealthIn struct {
+	fx.In
+
+	Routers []Router `group:"routers"`
+	Host    host.Host
+}
+
+// RoutingHealthService probes the routers of the node, as configured in
+// Routing.HealthCheck.
+func RoutingHealthService(cfg config.RoutingHealthCheck) interface{} {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, in routingHealthIn) (*RoutingHealth, error) {
+		interval := cfg.Interval.WithDefault(defaultHealthCheckInterval)
+		timeout := cfg.Timeout.WithDefault(defaultHealthCheckTimeout)
+		if interval <= 0 || timeout <= 0 {
+			return nil, errors.New("Routing.HealthCheck.Interval and Timeout must be positive")
+		}
+
+		routers := namedRouters(in.Routers)
+		h, err := newRoutingHealth(in.Host.ID(), routers, timeout, cfg.ReadyRouters)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(helpers.LifecycleCtx(mctx, lc))
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				for i, r := range routers {
+					if h.routers[i].Status != RouterUnsupported {
+						go h.run(ctx, i, r.Routing, interval)
+					}
+				}
+				return nil
+			},
+			OnStop: func(_ context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+		return h, nil
+	}
+}
@@ -0,0 +1,91 @@
+package libp2p
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	"github.com/libp2p/go-libp2p-core/test"
+	routinghelpers "github.com/libp2p/go-libp2p-routing-helpers"
+)
+
+// peerRouter answers the lookups with err.
+type peerRouter struct {
+	routinghelpers.Null
+
+	mu  sync.Mutex
+	err error
+}
+
+func (r *peerRouter) FindPeer(ctx context.Context, p peer.ID) (peer.AddrInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return peer.AddrInfo{}, r.err
+}
+
+func (r *peerRouter) setErr(err error) {
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+}
+
+func TestRoutingHealth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dht := &peerRouter{err: errors.New("failed to find any peer in table")}
+	routers := namedRouters([]Router{
+		{Routing: dht, Priority: 1000, Name: "dht"},
+		{Routing: &routinghelpers.Compose{}, Priority: 100, Name: "pubsub"},
+	})
+	if _, err := newRoutingHealth(test.RandPeerIDFatal(t), routers, time.Second, []string{"pubsub"}); err == nil {
+		t.Fatal("expected a router that can't be probed to be refused in ReadyRouters")
+	}
+	if _, err := newRoutingHealth(test.RandPeerIDFatal(t), routers, time.Second, []string{"reframe"}); err == nil {
+		t.Fatal("expected an unknown router to be refused in ReadyRouters")
+	}
+
+	h, err := newRoutingHealth(test.RandPeerIDFatal(t), routers, time.Second, []string{"dht"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := h.Status()
+	if st[0].Name != "pubsub" || st[0].Status != RouterUnsupported || st[1].Status != RouterUnchecked {
+		t.Fatalf("unexpected status before probing %+v", st)
+	}
+
+	if h.probe(ctx, 1, dht) || h.Ready() {
+		t.Fatal("expected a router failing the lookup to be unhealthy")
+	}
+	if st := h.Status()[1]; st.Status != RouterUnhealthy || st.Error == "" || !st.LastHealthy.IsZero() {
+		t.Fatalf("unexpected status of an unhealthy router %+v", st)
+	}
+
+	waited := make(chan error, 1)
+	go func() { waited <- h.WaitReady(ctx) }()
+	select {
+	case <-waited:
+		t.Fatal("expected to wait for the router to be healthy")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	dht.setErr(routing.ErrNotFound)
+	if !h.probe(ctx, 1, dht) {
+		t.Fatal("expected a router answering the lookup to be healthy")
+	}
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the wait to end once the router is healthy")
+	}
+	if st := h.Status()[1]; st.Status != RouterHealthy || st.Error != "" || st.LastHealthy.IsZero() {
+		t.Fatalf("unexpected status of a healthy router %+v", st)
+	}
+}
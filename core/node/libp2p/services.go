@@ -0,0 +1,99 @@
+package libp2p
+
+import (
+	"fmt"
+
+	config "github.com/ipfs/go-ipfs/config"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/libp2p/go-libp2p/p2p/host/autonat"
+	relayproto "github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/proto"
+	"github.com/libp2p/go-libp2p/p2p/protocol/identify"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+// RestrictServices enforces Swarm.Services by rejecting inbound streams for
+// the restricted protocols from peers connected over the wrong kind of
+// address.
+func RestrictServices(cfg config.SwarmServices) func(host.Host) error {
+	return func(h host.Host) error {
+		exposure := make(map[protocol.ID]string)
+		for _, svc := range []struct {
+			name   string
+			opt    *config.OptionalString
+			protos []protocol.ID
+		}{
+			{"Ping", cfg.Ping, []protocol.ID{ping.ID}},
+			{"IdentifyPush", cfg.IdentifyPush, []protocol.ID{identify.IDPush, identify.IDDelta}},
+			{"AutoNAT", cfg.AutoNAT, []protocol.ID{autonat.AutoNATProto}},
+			{"Relay", cfg.Relay, []protocol.ID{relayproto.ProtoIDv2Hop}},
+		} {
+			mode := svc.opt.WithDefault(config.ServiceExposureAll)
+			switch mode {
+			case config.ServiceExposureAll:
+				continue
+			case config.ServiceExposurePrivate, config.ServiceExposurePublic, config.ServiceExposureDisabled:
+			default:
+				return fmt.Errorf("invalid value %q for Swarm.Services.%s", mode, svc.name)
+			}
+			for _, p := range svc.protos {
+				exposure[p] = mode
+			}
+		}
+		if len(exposure) == 0 {
+			return nil
+		}
+
+		n, ok := h.Network().(interface {
+			StreamHandler() network.StreamHandler
+		})
+		if !ok {
+			return fmt.Errorf("cannot restrict services: network does not expose its stream handler")
+		}
+		handler := n.StreamHandler()
+		h.Network().SetStreamHandler(func(s network.Stream) {
+			handler(&restrictedStream{Stream: s, exposure: exposure})
+		})
+		return nil
+	}
+}
+
+// restrictedStream refuses to be bound to a protocol the remote peer is not
+// allowed to use. The host resets the stream when SetProtocol fails, which
+// happens right after protocol negotiation and before the handler runs.
+type restrictedStream struct {
+	network.Stream
+	exposure map[protocol.ID]string
+}
+
+func (s *restrictedStream) SetProtocol(p protocol.ID) error {
+	if mode, ok := s.exposure[p]; ok && !serviceAllowed(mode, s.Conn().RemoteMultiaddr()) {
+		return fmt.Errorf("service %s is not exposed to %s", p, s.Conn().RemotePeer())
+	}
+	return s.Stream.SetProtocol(p)
+}
+
+func serviceAllowed(mode string, remote ma.Multiaddr) bool {
+	// Relayed connections are treated as public, whatever the address of the
+	// relay itself.
+	private := !isRelayAddr(remote) && (manet.IsPrivateAddr(remote) || manet.IsIPLoopback(remote))
+	switch mode {
+	case config.ServiceExposurePrivate:
+		return private
+	case config.ServiceExposurePublic:
+		return !private
+	case config.ServiceExposureDisabled:
+		return false
+	default:
+		return true
+	}
+}
+
+func isRelayAddr(a ma.Multiaddr) bool {
+	_, err := a.ValueForProtocol(ma.P_CIRCUIT)
+	return err == nil
+}
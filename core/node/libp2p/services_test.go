@@ -0,0 +1,67 @@
+package libp2p
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	config "github.com/ipfs/go-ipfs/config"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func optionalString(t *testing.T, v string) *config.OptionalString {
+	var s config.OptionalString
+	require.NoError(t, json.Unmarshal([]byte(`"`+v+`"`), &s))
+	return &s
+}
+
+func TestServiceAllowed(t *testing.T) {
+	private := ma.StringCast("/ip4/192.168.1.2/tcp/4001")
+	loopback := ma.StringCast("/ip4/127.0.0.1/tcp/4001")
+	public := ma.StringCast("/ip4/1.2.3.4/tcp/4001")
+	relayed := ma.StringCast("/ip4/192.168.1.2/tcp/4001/p2p/QmaCpDMGvV2BGHeYERUEnRQAwe3N8SzbUtfsmvsqQLuvuJ/p2p-circuit")
+
+	require.True(t, serviceAllowed(config.ServiceExposurePrivate, private))
+	require.True(t, serviceAllowed(config.ServiceExposurePrivate, loopback))
+	require.False(t, serviceAllowed(config.ServiceExposurePrivate, public))
+	require.False(t, serviceAllowed(config.ServiceExposurePrivate, relayed))
+
+	require.False(t, serviceAllowed(config.ServiceExposurePublic, private))
+	require.True(t, serviceAllowed(config.ServiceExposurePublic, public))
+	require.True(t, serviceAllowed(config.ServiceExposurePublic, relayed))
+
+	require.False(t, serviceAllowed(config.ServiceExposureDisabled, private))
+	require.True(t, serviceAllowed(config.ServiceExposureAll, public))
+}
+
+func TestRestrictServices(t *testing.T) {
+	ctx := context.Background()
+
+	newHost := func() *ping.PingService {
+		h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+		require.NoError(t, err)
+		t.Cleanup(func() { h.Close() })
+		return ping.NewPingService(h)
+	}
+	a, b := newHost(), newHost()
+
+	disabled := optionalString(t, config.ServiceExposureDisabled)
+	require.NoError(t, RestrictServices(config.SwarmServices{Ping: disabled})(b.Host))
+	require.NoError(t, a.Host.Connect(ctx, peer.AddrInfo{ID: b.Host.ID(), Addrs: b.Host.Addrs()}))
+
+	res := <-a.Ping(ctx, b.Host.ID())
+	require.Error(t, res.Error)
+
+	// b can still ping a
+	res = <-b.Ping(ctx, a.Host.ID())
+	require.NoError(t, res.Error)
+
+	invalid := optionalString(t, "sometimes")
+	require.Error(t, RestrictServices(config.SwarmServices{Relay: invalid})(b.Host))
+}
@@ -0,0 +1,361 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/routing"
+	ma "github.com/multiformats/go-multiaddr"
+	mh "github.com/multiformats/go-multihash"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+const (
+	defaultLookupCacheTTL        = 24 * time.Hour
+	defaultLookupCacheMaxEntries = 1024
+
+	// lookupCacheMaxProviders is the number of providers remembered for a
+	// CID, the ones found last.
+	lookupCacheMaxProviders = 8
+	// lookupCacheSaveInterval is how often the cache is saved, besides when
+	// the node stops.
+	lookupCacheSaveInterval = 10 * time.Minute
+)
+
+var lookupCacheKey = datastore.NewKey("/local/lookups")
+
+// cachedLookups are the lookups in the datastore. The providers are keyed by
+// the base58 multihash of the CIDs, the peers by their encoded ID.
+type cachedLookups struct {
+	Providers map[string]cachedProviders
+	Peers     map[string]cachedPeer
+}
+
+type cachedProviders struct {
+	Peers []peer.ID
+	Found time.Time
+}
+
+type cachedPeer struct {
+	Addrs []string
+	Found time.Time
+}
+
+// providersEntry are the providers of a multihash, the last one found last.
+type providersEntry struct {
+	peers []peer.ID
+	found time.Time
+}
+
+type peerEntry struct {
+	addrs []ma.Multiaddr
+	found time.Time
+}
+
+// LookupCache persists the providers bitswap found, and their addresses, as
+// configured in Routing.LookupCache. The providers of a CID remembered are
+// returned first when looking it up again, even after a restart, and the
+// addresses of the providers are restored into the peerstore on start, so
+// that they are dialed without looking them up.
+type LookupCache struct {
+	self       peer.ID
+	ps         peerstore.Peerstore
+	ds         datastore.Datastore
+	ttl        time.Duration
+	maxEntries int
+
+	mu        sync.Mutex
+	providers map[string]*providersEntry // keyed by multihash
+	peers     map[peer.ID]*peerEntry
+}
+
+// LookupCacheService creates the lookup cache when Routing.LookupCache is
+// enabled. Otherwise ContentRouting leaves the routers uncached.
+func LookupCacheService(cfg config.RoutingLookupCache) interface{} {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, h host.Host, r repo.Repo) (*LookupCache, error) {
+		if !cfg.Enabled.WithDefault(false) {
+			return nil, nil
+		}
+		c := &LookupCache{
+			self:       h.ID(),
+			ps:         h.Peerstore(),
+			ds:         r.Datastore(),
+			ttl:        cfg.TTL.WithDefault(defaultLookupCacheTTL),
+			maxEntries: int(cfg.MaxEntries.WithDefault(defaultLookupCacheMaxEntries)),
+			providers:  make(map[string]*providersEntry),
+			peers:      make(map[peer.ID]*peerEntry),
+		}
+
+		ctx := helpers.LifecycleCtx(mctx, lc)
+		if err := c.load(ctx); err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				go func() {
+					defer close(done)
+					c.run(ctx)
+				}()
+				return nil
+			},
+			OnStop: func(stopCtx context.Context) error {
+				cancel()
+				<-done
+				return c.save(stopCtx)
+			},
+		})
+		return c, nil
+	}
+}
+
+func (c *LookupCache) run(ctx context.Context) {
+	t := time.NewTicker(lookupCacheSaveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := c.save(ctx); err != nil {
+				logger.Errorf("saving the lookup cache: %s", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// record remembers p as a provider of h, found now.
+func (c *LookupCache) record(h mh.Multihash, p peer.AddrInfo) {
+	if p.ID == c.self {
+		return
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.providers[string(h)]
+	if !ok {
+		if len(c.providers) >= c.maxEntries {
+			c.evictOldestLocked()
+		}
+		e = new(providersEntry)
+		c.providers[string(h)] = e
+	}
+	for i, known := range e.peers {
+		if known == p.ID {
+			e.peers = append(e.peers[:i], e.peers[i+1:]...)
+			break
+		}
+	}
+	e.peers = append(e.peers, p.ID)
+	if len(e.peers) > lookupCacheMaxProviders {
+		e.peers = e.peers[len(e.peers)-lookupCacheMaxProviders:]
+	}
+	e.found = now
+	c.recordAddrsLocked(p.ID, p.Addrs)
+}
+
+// recordAddrsLocked remembers the addresses of p, or the ones in the
+// peerstore when none are given, which bitswap dials.
+func (c *LookupCache) recordAddrsLocked(p peer.ID, addrs []ma.Multiaddr) {
+	if len(addrs) == 0 {
+		addrs = c.ps.Addrs(p)
+	}
+	if len(addrs) > 0 {
+		c.peers[p] = &peerEntry{addrs: addrs, found: time.Now()}
+	}
+}
+
+func (c *LookupCache) evictOldestLocked() {
+	var oldest string
+	var oldestFound time.Time
+	for h, e := range c.providers {
+		if oldest == "" || e.found.Before(oldestFound) {
+			oldest, oldestFound = h, e.found
+		}
+	}
+	delete(c.providers, oldest)
+}
+
+// cached returns the providers of h remembered, the ones found last first,
+// with the addresses remembered.
+func (c *LookupCache) cached(h mh.Multihash) []peer.AddrInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.providers[string(h)]
+	if !ok || time.Since(e.found) > c.ttl {
+		return nil
+	}
+	res := make([]peer.AddrInfo, 0, len(e.peers))
+	for i := len(e.peers) - 1; i >= 0; i-- {
+		ai := peer.AddrInfo{ID: e.peers[i]}
+		if pe, ok := c.peers[ai.ID]; ok && time.Since(pe.found) <= c.ttl {
+			ai.Addrs = pe.addrs
+		}
+		res = append(res, ai)
+	}
+	return res
+}
+
+// load restores the lookups made within the TTL, and the addresses of the
+// providers into the peerstore, for the rest of the TTL.
+func (c *LookupCache) load(ctx context.Context) error {
+	data, err := c.ds.Get(ctx, lookupCacheKey)
+	if err == datastore.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var cached cachedLookups
+	if err := json.Unmarshal(data, &cached); err != nil {
+		logger.Errorf("ignoring the invalid lookup cache: %s", err)
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s, e := range cached.Providers {
+		age := time.Since(e.Found)
+		if age > c.ttl || len(e.Peers) == 0 {
+			continue
+		}
+		h, err := mh.FromB58String(s)
+		if err != nil {
+			continue
+		}
+		c.providers[string(h)] = &providersEntry{peers: e.Peers, found: e.Found}
+	}
+	for len(c.providers) > c.maxEntries {
+		c.evictOldestLocked()
+	}
+	for s, e := range cached.Peers {
+		age := time.Since(e.Found)
+		if age > c.ttl || len(e.Addrs) == 0 {
+			continue
+		}
+		p, err := peer.Decode(s)
+		if err != nil {
+			continue
+		}
+		addrs := make([]ma.Multiaddr, 0, len(e.Addrs))
+		for _, a := range e.Addrs {
+			if addr, err := ma.NewMultiaddr(a); err == nil {
+				addrs = append(addrs, addr)
+			}
+		}
+		c.peers[p] = &peerEntry{addrs: addrs, found: e.Found}
+		c.ps.AddAddrs(p, addrs, c.ttl-age)
+	}
+	logger.Debugf("lookup cache: restored the providers of %d CIDs", len(c.providers))
+	return nil
+}
+
+// save saves the lookups made within the TTL.
+func (c *LookupCache) save(ctx context.Context) error {
+	c.mu.Lock()
+	cached := cachedLookups{
+		Providers: make(map[string]cachedProviders, len(c.providers)),
+		Peers:     make(map[string]cachedPeer),
+	}
+	for h, e := range c.providers {
+		if time.Since(e.found) > c.ttl {
+			delete(c.providers, h)
+			continue
+		}
+		cached.Providers[mh.Multihash(h).B58String()] = cachedProviders{Peers: e.peers, Found: e.found}
+		for _, p := range e.peers {
+			// the peers found without addresses were likely dialed since
+			c.recordAddrsLocked(p, nil)
+			if pe, ok := c.peers[p]; ok && time.Since(pe.found) <= c.ttl {
+				addrs := make([]string, len(pe.addrs))
+				for i, a := range pe.addrs {
+					addrs[i] = a.String()
+				}
+				cached.Peers[p.String()] = cachedPeer{Addrs: addrs, Found: pe.found}
+			}
+		}
+	}
+	// forget the peers that are no longer providers of a CID remembered
+	for p := range c.peers {
+		if _, ok := cached.Peers[p.String()]; !ok {
+			delete(c.peers, p)
+		}
+	}
+	data, err := json.Marshal(cached)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return c.ds.Put(ctx, lookupCacheKey, data)
+}
+
+// ContentRouting returns cr, returning first the providers remembered, and
+// remembering the providers it finds.
+func (c *LookupCache) ContentRouting(cr routing.ContentRouting) routing.ContentRouting {
+	if c == nil {
+		return cr
+	}
+	return &lookupCacheRouting{ContentRouting: cr, cache: c}
+}
+
+type lookupCacheRouting struct {
+	routing.ContentRouting
+	cache *LookupCache
+}
+
+func (r *lookupCacheRouting) FindProvidersAsync(ctx context.Context, c cid.Cid, count int) <-chan peer.AddrInfo {
+	out := make(chan peer.AddrInfo)
+	go func() {
+		defer close(out)
+		// the providers remembered don't count against count, they may be
+		// gone since
+		cached := r.cache.cached(c.Hash())
+		limit := count
+		if count > 0 {
+			limit += len(cached)
+		}
+		seen := make(map[peer.ID]struct{})
+		emit := func(p peer.AddrInfo) bool {
+			if _, ok := seen[p.ID]; ok || (limit > 0 && len(seen) >= limit) {
+				return true
+			}
+			seen[p.ID] = struct{}{}
+			select {
+			case out <- p:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if len(cached) > 0 {
+			logger.Debugf("lookup cache: returning the %d providers of %s remembered", len(cached), c)
+		}
+		for _, p := range cached {
+			if !emit(p) {
+				return
+			}
+		}
+		for p := range r.ContentRouting.FindProvidersAsync(ctx, c, limit) {
+			r.cache.record(c.Hash(), p)
+			if !emit(p) {
+				return
+			}
+		}
+	}()
+	return out
+}
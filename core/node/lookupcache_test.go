@@ -0,0 +1,131 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	"github.com/libp2p/go-libp2p-core/test"
+	"github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/multiformats/go-multihash"
+)
+
+func newTestLookupCache(t *testing.T, ds datastore.Datastore) *LookupCache {
+	t.Helper()
+	ps, err := pstoremem.NewPeerstore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &LookupCache{
+		ps:         ps,
+		ds:         ds,
+		ttl:        time.Hour,
+		maxEntries: 2,
+		providers:  make(map[string]*providersEntry),
+		peers:      make(map[peer.ID]*peerEntry),
+	}
+}
+
+// testAddrProviders is a content router finding its providers, with their
+// addresses.
+type testAddrProviders struct {
+	routing.ContentRouting
+	providers []peer.AddrInfo
+}
+
+func (r testAddrProviders) FindProvidersAsync(ctx context.Context, c cid.Cid, count int) <-chan peer.AddrInfo {
+	ch := make(chan peer.AddrInfo, len(r.providers))
+	for i, p := range r.providers {
+		if count > 0 && i >= count {
+			break
+		}
+		ch <- p
+	}
+	close(ch)
+	return ch
+}
+
+func testMultihash(t *testing.T, data string) multihash.Multihash {
+	t.Helper()
+	h, err := multihash.Sum([]byte(data), multihash.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestLookupCache(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	first, second, third := test.RandPeerIDFatal(t), test.RandPeerIDFatal(t), test.RandPeerIDFatal(t)
+	addr := ma.StringCast("/ip4/1.2.3.4/tcp/4001")
+	h := testMultihash(t, "block")
+
+	c := newTestLookupCache(t, ds)
+	cr := c.ContentRouting(testAddrProviders{providers: []peer.AddrInfo{{ID: first, Addrs: []ma.Multiaddr{addr}}, {ID: second}}})
+	for range cr.FindProvidersAsync(ctx, cid.NewCidV1(cid.Raw, h), 0) {
+	}
+	// the providers found without addresses were dialed since
+	c.ps.AddAddr(second, addr, time.Hour)
+	if err := c.save(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// a restarted node asks the providers found before first, whatever the
+	// codec of the CID
+	restarted := newTestLookupCache(t, ds)
+	if err := restarted.load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []peer.ID{first, second} {
+		if addrs := restarted.ps.Addrs(p); len(addrs) != 1 || !addrs[0].Equal(addr) {
+			t.Fatalf("expected the addresses of the providers to be restored, got %v", addrs)
+		}
+	}
+	cr = restarted.ContentRouting(testAddrProviders{providers: []peer.AddrInfo{{ID: third}, {ID: first}}})
+	var found []peer.AddrInfo
+	for p := range cr.FindProvidersAsync(ctx, cid.NewCidV1(cid.DagProtobuf, h), 0) {
+		found = append(found, p)
+	}
+	if len(found) != 3 || found[0].ID != second || found[1].ID != first || found[2].ID != third || len(found[1].Addrs) != 1 {
+		t.Fatalf("expected the providers remembered first, the last found first, then the new ones, got %v", found)
+	}
+
+	// the providers of the CIDs found longest ago are forgotten past the
+	// max entries, and past the TTL
+	restarted.providers[string(h)].found = time.Now().Add(-time.Minute)
+	h2, h3 := testMultihash(t, "block 2"), testMultihash(t, "block 3")
+	restarted.record(h2, peer.AddrInfo{ID: third})
+	restarted.record(h3, peer.AddrInfo{ID: first})
+	if len(restarted.cached(h)) != 0 || len(restarted.cached(h2)) != 1 {
+		t.Fatal("expected the providers of the CID found longest ago to be evicted")
+	}
+	restarted.providers[string(h2)].found = time.Now().Add(-2 * time.Hour)
+	if len(restarted.cached(h2)) != 0 {
+		t.Fatal("expected the providers found longer than the TTL ago to be forgotten")
+	}
+	if err := restarted.save(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := restarted.peers[second]; ok {
+		t.Fatal("expected the peers no longer providing a CID remembered to be forgotten")
+	}
+
+	// the providers remembered don't count against the providers asked for
+	fresh := newTestLookupCache(t, ds)
+	fresh.record(h, peer.AddrInfo{ID: first})
+	cr = fresh.ContentRouting(testAddrProviders{providers: []peer.AddrInfo{{ID: first}, {ID: second}}})
+	found = nil
+	for p := range cr.FindProvidersAsync(ctx, cid.NewCidV1(cid.Raw, h), 1) {
+		found = append(found, p)
+	}
+	if len(found) != 2 || found[0].ID != first || found[1].ID != second {
+		t.Fatalf("expected a provider found after the one remembered, got %v", found)
+	}
+}
@@ -0,0 +1,336 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	gopath "path"
+	"strings"
+	"sync"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	format "github.com/ipfs/go-ipld-format"
+	uio "github.com/ipfs/go-unixfs/io"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/journal"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+const (
+	defaultMFSPinsUnpinGracePeriod = time.Hour
+	// mfsPinsCheckInterval is how often the pins are checked, besides when
+	// MFS changes, so that the content gone from the paths is unpinned.
+	mfsPinsCheckInterval = time.Minute
+)
+
+var mfsPinsKey = datastore.NewKey("/local/mfspins")
+
+// MFSPins pins the content of the MFS paths in Pinning.MFS.Paths while it is
+// there, and unpins it a grace period after it was replaced or removed.
+//
+// Only the pins it added are ever removed: content already pinned when it was
+// written to a path is left alone. A nil *MFSPins pins nothing.
+type MFSPins struct {
+	pinning  pin.Pinner
+	dag      format.DAGService
+	gcLocker blockstore.GCLocker
+	ds       datastore.Datastore
+	journal  *journal.Journal
+	paths    []string
+	grace    time.Duration
+
+	changed chan struct{}
+	mu      sync.Mutex
+	root    cid.Cid
+	dirty   bool
+
+	// pins are the CIDs pinned for the paths, and unsaved whether they
+	// changed since they were saved. Only accessed by sync.
+	pins    map[cid.Cid]mfsPin
+	unsaved bool
+}
+
+// mfsPin is a CID pinned for a path.
+type mfsPin struct {
+	Path string
+	// Unpin is when the CID is unpinned, zero while still under a path.
+	Unpin time.Time `json:",omitempty"`
+}
+
+// MFSPinning creates the pinning of the MFS paths. It is nil when no path is
+// configured.
+func MFSPinning(cfg config.PinMFS) interface{} {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, r repo.Repo, pinning pin.Pinner, dag format.DAGService, gcLocker blockstore.GCLocker, j *journal.Journal) (*MFSPins, error) {
+		if len(cfg.Paths) == 0 {
+			return nil, nil
+		}
+		grace := cfg.UnpinGracePeriod.WithDefault(defaultMFSPinsUnpinGracePeriod)
+		if grace < 0 {
+			return nil, fmt.Errorf("Pinning.MFS.UnpinGracePeriod must not be negative")
+		}
+
+		p := &MFSPins{
+			pinning:  pinning,
+			dag:      dag,
+			gcLocker: gcLocker,
+			ds:       r.Datastore(),
+			journal:  j,
+			grace:    grace,
+			changed:  make(chan struct{}, 1),
+			pins:     make(map[cid.Cid]mfsPin),
+		}
+		for _, path := range cfg.Paths {
+			if !strings.HasPrefix(path, "/") {
+				return nil, fmt.Errorf("invalid path in Pinning.MFS.Paths: %q is not absolute", path)
+			}
+			p.paths = append(p.paths, gopath.Clean(path))
+		}
+
+		ctx := helpers.LifecycleCtx(mctx, lc)
+		if err := p.load(ctx); err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				go func() {
+					defer close(done)
+					p.run(ctx)
+				}()
+				return nil
+			},
+			OnStop: func(stopCtx context.Context) error {
+				cancel()
+				<-done
+				// MFS is published a last time when it is closed
+				p.mu.Lock()
+				dirty := p.dirty
+				p.mu.Unlock()
+				if dirty {
+					return p.sync(stopCtx)
+				}
+				return nil
+			},
+		})
+		return p, nil
+	}
+}
+
+// Published is called with the root of MFS each time it is published.
+func (p *MFSPins) Published(root cid.Cid) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.root = root
+	p.dirty = true
+	p.mu.Unlock()
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (p *MFSPins) run(ctx context.Context) {
+	t := time.NewTicker(mfsPinsCheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.changed:
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+		if err := p.sync(ctx); err != nil && ctx.Err() == nil {
+			logger.Errorf("pinning the MFS paths: %s", err)
+		}
+	}
+}
+
+// sync pins the content under the paths in the last published root, and
+// unpins the content gone from them for longer than the grace period.
+func (p *MFSPins) sync(ctx context.Context) error {
+	p.mu.Lock()
+	root := p.root
+	p.dirty = false
+	p.mu.Unlock()
+	if !root.Defined() {
+		return nil
+	}
+
+	// the pins of the paths failing to resolve are kept
+	var errs error
+	current := make(map[cid.Cid]string, len(p.paths))
+	failed := make(map[string]struct{})
+	for _, path := range p.paths {
+		c, err := resolveMFSPath(ctx, p.dag, root, path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("resolving %s: %w", path, err))
+			failed[path] = struct{}{}
+			continue
+		}
+		current[c] = path
+	}
+
+	defer p.gcLocker.PinLock(ctx).Unlock(ctx)
+
+	for c, path := range current {
+		if pinned, ok := p.pins[c]; ok {
+			if !pinned.Unpin.IsZero() || pinned.Path != path {
+				p.pins[c] = mfsPin{Path: path}
+				p.unsaved = true
+			}
+			continue
+		}
+		_, pinned, err := p.pinning.IsPinnedWithType(ctx, c, pin.Recursive)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("pinning %s: %w", path, err))
+			continue
+		}
+		if pinned {
+			continue
+		}
+		if err := p.pin(ctx, c); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("pinning %s: %w", path, err))
+			continue
+		}
+		p.pins[c] = mfsPin{Path: path}
+		p.unsaved = true
+		p.journal.Record(ctx, journal.TypePin, map[string]string{
+			"op":        "add",
+			"cid":       c.String(),
+			"recursive": "true",
+			"mfs":       path,
+		})
+	}
+
+	now := time.Now()
+	for c, pinned := range p.pins {
+		if _, ok := current[c]; ok {
+			continue
+		}
+		if _, ok := failed[pinned.Path]; ok {
+			continue
+		}
+		if pinned.Unpin.IsZero() {
+			p.pins[c] = mfsPin{Path: pinned.Path, Unpin: now.Add(p.grace)}
+			p.unsaved = true
+			continue
+		}
+		if now.Before(pinned.Unpin) {
+			continue
+		}
+		if err := p.pinning.Unpin(ctx, c, true); err != nil && err != pin.ErrNotPinned {
+			errs = multierror.Append(errs, fmt.Errorf("unpinning %s: %w", pinned.Path, err))
+			continue
+		}
+		delete(p.pins, c)
+		p.unsaved = true
+		p.journal.Record(ctx, journal.TypePin, map[string]string{
+			"op":        "rm",
+			"cid":       c.String(),
+			"recursive": "true",
+			"mfs":       pinned.Path,
+		})
+	}
+
+	if !p.unsaved {
+		return errs
+	}
+	if err := p.pinning.Flush(ctx); err != nil {
+		return multierror.Append(errs, err)
+	}
+	if err := p.save(ctx); err != nil {
+		return multierror.Append(errs, err)
+	}
+	p.unsaved = false
+	return errs
+}
+
+// pin pins the DAG of c recursively.
+func (p *MFSPins) pin(ctx context.Context, c cid.Cid) error {
+	nd, err := p.dag.Get(ctx, c)
+	if err != nil {
+		return err
+	}
+	return p.pinning.Pin(ctx, nd, true)
+}
+
+// load restores the pins added for the paths.
+func (p *MFSPins) load(ctx context.Context) error {
+	data, err := p.ds.Get(ctx, mfsPinsKey)
+	if err == datastore.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var saved map[string]mfsPin
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid MFS pins: %w", err)
+	}
+	for s, pinned := range saved {
+		c, err := cid.Decode(s)
+		if err != nil {
+			return fmt.Errorf("invalid MFS pins: %w", err)
+		}
+		p.pins[c] = pinned
+	}
+	return nil
+}
+
+// save saves the pins added for the paths.
+func (p *MFSPins) save(ctx context.Context) error {
+	saved := make(map[string]mfsPin, len(p.pins))
+	for c, pinned := range p.pins {
+		saved[c.String()] = pinned
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	return p.ds.Put(ctx, mfsPinsKey, data)
+}
+
+// resolveMFSPath returns the CID under path in the MFS root, or
+// os.ErrNotExist.
+func resolveMFSPath(ctx context.Context, dag format.DAGService, root cid.Cid, path string) (cid.Cid, error) {
+	c := root
+	for _, name := range strings.Split(path, "/") {
+		if name == "" {
+			continue
+		}
+		nd, err := dag.Get(ctx, c)
+		if err != nil {
+			return cid.Undef, err
+		}
+		dir, err := uio.NewDirectoryFromNode(dag, nd)
+		if err == uio.ErrNotADir {
+			return cid.Undef, os.ErrNotExist
+		}
+		if err != nil {
+			return cid.Undef, err
+		}
+		child, err := dir.Find(ctx, name)
+		if err != nil {
+			return cid.Undef, err
+		}
+		c = child.Cid()
+	}
+	return c, nil
+}
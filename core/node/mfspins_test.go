@@ -0,0 +1,174 @@
+package node
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	"github.com/ipfs/go-ipfs-pinner/dspinner"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	mdtest "github.com/ipfs/go-merkledag/test"
+	ft "github.com/ipfs/go-unixfs"
+)
+
+// testMFSRoot adds an MFS root with the files under /published.
+func testMFSRoot(t *testing.T, dag format.DAGService, files ...*merkledag.ProtoNode) cid.Cid {
+	t.Helper()
+	ctx := context.Background()
+	published := ft.EmptyDirNode()
+	for i, f := range files {
+		if err := dag.Add(ctx, f); err != nil {
+			t.Fatal(err)
+		}
+		if err := published.AddNodeLink(string(rune('a'+i)), f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root := ft.EmptyDirNode()
+	if err := root.AddNodeLink("published", published); err != nil {
+		t.Fatal(err)
+	}
+	if err := dag.Add(ctx, published); err != nil {
+		t.Fatal(err)
+	}
+	if err := dag.Add(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	return root.Cid()
+}
+
+func TestMFSPins(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	dag := mdtest.Mock()
+	pinning, err := dspinner.New(ctx, ds, dag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &MFSPins{
+		pinning:  pinning,
+		dag:      dag,
+		gcLocker: blockstore.NewGCLocker(),
+		ds:       ds,
+		paths:    []string{"/published/a", "/published/b", "/missing"},
+		grace:    time.Hour,
+		changed:  make(chan struct{}, 1),
+		pins:     make(map[cid.Cid]mfsPin),
+	}
+	isPinned := func(c cid.Cid) bool {
+		t.Helper()
+		_, pinned, err := pinning.IsPinnedWithType(ctx, c, pin.Recursive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pinned
+	}
+
+	fileA, fileB := merkledag.NodeWithData(ft.FilePBData([]byte("a"), 1)), merkledag.NodeWithData(ft.FilePBData([]byte("b"), 1))
+	if err := dag.Add(ctx, fileB); err != nil {
+		t.Fatal(err)
+	}
+	// pinned by the user
+	if err := pinning.Pin(ctx, fileB, true); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Published(testMFSRoot(t, dag, fileA, fileB))
+	if err := p.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !isPinned(fileA.Cid()) {
+		t.Fatal("expected the content of the path to be pinned")
+	}
+
+	// the content is gone, and unpinned after the grace period
+	p.Published(testMFSRoot(t, dag))
+	if err := p.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !isPinned(fileA.Cid()) {
+		t.Fatal("expected the content removed to stay pinned during the grace period")
+	}
+
+	// a restarted node knows the pins it added
+	restarted := &MFSPins{pinning: pinning, dag: dag, gcLocker: p.gcLocker, ds: ds, paths: p.paths, changed: make(chan struct{}, 1), pins: make(map[cid.Cid]mfsPin)}
+	if err := restarted.load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	pinned, ok := restarted.pins[fileA.Cid()]
+	if !ok || pinned.Path != "/published/a" || pinned.Unpin.IsZero() {
+		t.Fatalf("expected the pin to be restored, got %+v", restarted.pins)
+	}
+	restarted.pins[fileA.Cid()] = mfsPin{Path: pinned.Path, Unpin: time.Now().Add(-time.Second)}
+	restarted.Published(testMFSRoot(t, dag))
+	if err := restarted.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if isPinned(fileA.Cid()) {
+		t.Fatal("expected the content removed to be unpinned after the grace period")
+	}
+	if !isPinned(fileB.Cid()) {
+		t.Fatal("expected the content pinned by the user to stay pinned")
+	}
+}
+
+func TestMFSPinsErrors(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	dag := mdtest.Mock()
+	pinning, err := dspinner.New(ctx, ds, dag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &MFSPins{
+		pinning:  pinning,
+		dag:      dag,
+		gcLocker: blockstore.NewGCLocker(),
+		ds:       ds,
+		paths:    []string{"/published/a", "/published/b"},
+		grace:    time.Hour,
+		changed:  make(chan struct{}, 1),
+		pins:     make(map[cid.Cid]mfsPin),
+	}
+
+	// the chunk of a is missing
+	missing := merkledag.NodeWithData(ft.FilePBData([]byte("missing"), 7))
+	broken := merkledag.NodeWithData(ft.FilePBData(nil, 7))
+	if err := broken.AddNodeLink("", missing); err != nil {
+		t.Fatal(err)
+	}
+	fileB := merkledag.NodeWithData(ft.FilePBData([]byte("b"), 1))
+	removed := merkledag.NodeWithData(ft.FilePBData([]byte("removed"), 7))
+	if err := dag.Add(ctx, removed); err != nil {
+		t.Fatal(err)
+	}
+	if err := pinning.Pin(ctx, removed, true); err != nil {
+		t.Fatal(err)
+	}
+	p.pins[removed.Cid()] = mfsPin{Path: "/published/c", Unpin: time.Now().Add(-time.Second)}
+
+	p.Published(testMFSRoot(t, dag, broken, fileB))
+	err = p.sync(ctx)
+	if err == nil || !strings.Contains(err.Error(), "/published/a") {
+		t.Fatalf("expected pinning a to fail, got %v", err)
+	}
+	for c, expected := range map[cid.Cid]bool{broken.Cid(): false, fileB.Cid(): true, removed.Cid(): false} {
+		_, pinned, err := pinning.IsPinnedWithType(ctx, c, pin.Recursive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pinned != expected {
+			t.Fatalf("expected the other paths to be pinned and unpinned, %s pinned: %t", c, pinned)
+		}
+	}
+	if _, ok := p.pins[fileB.Cid()]; !ok || p.unsaved {
+		t.Fatal("expected the pins to be saved")
+	}
+}
@@ -3,6 +3,8 @@ package node
 import (
 	"context"
 
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/libp2p"
 	"github.com/ipfs/go-ipfs/peering"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -25,9 +27,9 @@ func Peering(lc fx.Lifecycle, host host.Host) *peering.PeeringService {
 }
 
 // PeerWith configures the peering service to peer with the specified peers.
-func PeerWith(peers ...peer.AddrInfo) fx.Option {
-	return fx.Invoke(func(ps *peering.PeeringService) {
-		for _, ai := range peers {
+func PeerWith(pcfg config.PrivateNetwork, peers ...peer.AddrInfo) fx.Option {
+	return fx.Invoke(func(ps *peering.PeeringService, fp libp2p.PNetFingerprint) {
+		for _, ai := range libp2p.FilterPNetPeers(fp, peers, pcfg) {
 			ps.AddPeer(ai)
 		}
 	})
@@ -0,0 +1,384 @@
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	provider "github.com/ipfs/go-ipfs-provider"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/journal"
+)
+
+// PinGossipProtocol is the protocol trusted peers use to exchange their
+// pinsets, as configured in Pinning.Gossip.
+const PinGossipProtocol = protocol.ID("/ipfs/pin-gossip/0.1.0")
+
+const (
+	defaultPinGossipInterval   = 10 * time.Minute
+	defaultPinGossipMaxPinSize = "1GiB"
+
+	pinGossipTimeout = time.Minute
+	// pinGossipPinTimeout bounds the fetch and pin of each root.
+	pinGossipPinTimeout = 30 * time.Minute
+	// pinGossipMaxResponse bounds the size of the pinsets read from peers.
+	pinGossipMaxResponse = 64 << 20
+
+	pinGossipUnchanged = 0
+	pinGossipChanged   = 1
+)
+
+var (
+	errPinGossipTooLarge = errors.New("DAG is larger than Pinning.Gossip.MaxPinSize")
+	errPinsetTooLarge    = fmt.Errorf("pinset larger than %d bytes", pinGossipMaxResponse)
+)
+
+// pinGossip exchanges pinsets with the trusted peers, and pins the roots
+// they have and this node is missing. Pins are only ever added: roots
+// unpinned by a peer stay pinned here.
+type pinGossip struct {
+	h        host.Host
+	pinning  pin.Pinner
+	dag      format.DAGService
+	gcLocker blockstore.GCLocker
+	provider provider.System
+	journal  *journal.Journal
+
+	peers      map[peer.ID]struct{}
+	maxPinSize int64
+
+	// digests are the last pinsets of the peers fully replicated here, so
+	// they don't send them again when unchanged.
+	digests map[peer.ID][]byte
+	// skipped are the roots too large to replicate.
+	skipped *cid.Set
+}
+
+// pinsetDigest returns the digest of the recursive pins of the node, and
+// their roots.
+func (g *pinGossip) pinsetDigest(ctx context.Context) ([]byte, []cid.Cid, error) {
+	roots, err := g.pinning.RecursiveKeys(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		return bytes.Compare(roots[i].Bytes(), roots[j].Bytes()) < 0
+	})
+	hash := sha256.New()
+	for _, c := range roots {
+		hash.Write(c.Bytes())
+	}
+	return hash.Sum(nil), roots, nil
+}
+
+// handle answers a peer asking for the pinset of this node. The request is
+// the digest of the pinset the peer has last seen, the answer is
+// pinGossipUnchanged if it still matches, or pinGossipChanged followed by
+// the current digest and the roots, each prefixed by its length.
+func (g *pinGossip) handle(s network.Stream) {
+	if _, ok := g.peers[s.Conn().RemotePeer()]; !ok {
+		s.Reset()
+		return
+	}
+	_ = s.SetDeadline(time.Now().Add(pinGossipTimeout))
+
+	seen, err := ioutil.ReadAll(io.LimitReader(s, sha256.Size))
+	if err != nil {
+		s.Reset()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pinGossipTimeout)
+	defer cancel()
+	digest, roots, err := g.pinsetDigest(ctx)
+	if err != nil {
+		logger.Errorf("pin gossip: listing pins: %s", err)
+		s.Reset()
+		return
+	}
+
+	w := bufio.NewWriter(s)
+	if bytes.Equal(seen, digest) {
+		w.WriteByte(pinGossipUnchanged)
+	} else {
+		w.WriteByte(pinGossipChanged)
+		w.Write(digest)
+		buf := make([]byte, binary.MaxVarintLen64)
+		for _, c := range roots {
+			w.Write(buf[:binary.PutUvarint(buf, uint64(c.ByteLen()))])
+			w.Write(c.Bytes())
+		}
+	}
+	if err := w.Flush(); err != nil {
+		s.Reset()
+		return
+	}
+	s.Close()
+}
+
+// request asks p for its pinset. It returns a nil digest when the pinset
+// did not change since the last replicated one.
+func (g *pinGossip) request(ctx context.Context, p peer.ID) ([]byte, []cid.Cid, error) {
+	ctx, cancel := context.WithTimeout(ctx, pinGossipTimeout)
+	defer cancel()
+
+	s, err := g.h.NewStream(ctx, p, PinGossipProtocol)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer s.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.SetDeadline(deadline)
+	}
+
+	if _, err := s.Write(g.digests[p]); err != nil {
+		s.Reset()
+		return nil, nil, err
+	}
+	if err := s.CloseWrite(); err != nil {
+		s.Reset()
+		return nil, nil, err
+	}
+
+	digest, roots, err := readPinset(s, pinGossipMaxResponse)
+	if err != nil {
+		s.Reset()
+		return nil, nil, err
+	}
+	return digest, roots, nil
+}
+
+// readPinset reads the answer of a peer asking for its pinset, failing if it
+// is larger than limit bytes rather than keeping the roots read until then.
+func readPinset(s io.Reader, limit int64) ([]byte, []cid.Cid, error) {
+	lr := &io.LimitedReader{R: s, N: limit + 1}
+	digest, roots, err := parsePinset(bufio.NewReader(lr), limit)
+	if lr.N <= 0 {
+		return nil, nil, errPinsetTooLarge
+	}
+	return digest, roots, err
+}
+
+func parsePinset(r *bufio.Reader, limit int64) ([]byte, []cid.Cid, error) {
+	status, err := r.ReadByte()
+	if err != nil {
+		return nil, nil, err
+	}
+	if status == pinGossipUnchanged {
+		return nil, nil, nil
+	}
+
+	digest := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(r, digest); err != nil {
+		return nil, nil, err
+	}
+	var roots []cid.Cid
+	for {
+		l, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil || l > uint64(limit) {
+			return nil, nil, fmt.Errorf("invalid pinset: %v", err)
+		}
+		b := make([]byte, l)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, nil, err
+		}
+		c, err := cid.Cast(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid pinset: %w", err)
+		}
+		roots = append(roots, c)
+	}
+	return digest, roots, nil
+}
+
+// fetch fetches the whole DAG under root, as long as it is not larger than
+// maxPinSize.
+func (g *pinGossip) fetch(ctx context.Context, root cid.Cid) error {
+	ng := merkledag.NewSession(ctx, g.dag)
+	var size int64
+	getLinks := func(ctx context.Context, c cid.Cid) ([]*format.Link, error) {
+		nd, err := ng.Get(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if atomic.AddInt64(&size, int64(len(nd.RawData()))) > g.maxPinSize {
+			return nil, errPinGossipTooLarge
+		}
+		return nd.Links(), nil
+	}
+	return merkledag.Walk(ctx, getLinks, root, cid.NewSet().Visit, merkledag.Concurrent())
+}
+
+// pin fetches and pins root. The DAG is fetched before taking the pin lock,
+// so a slow peer doesn't hold up garbage collection; the pinner then walks
+// the blocks fetched, only fetching again those collected in between.
+func (g *pinGossip) pin(ctx context.Context, root cid.Cid, from peer.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, pinGossipPinTimeout)
+	defer cancel()
+
+	if err := g.fetch(ctx, root); err != nil {
+		return err
+	}
+
+	defer g.gcLocker.PinLock(ctx).Unlock(ctx)
+	nd, err := g.dag.Get(ctx, root)
+	if err != nil {
+		return err
+	}
+	if err := g.pinning.Pin(ctx, nd, true); err != nil {
+		return err
+	}
+	if err := g.provider.Provide(root); err != nil {
+		return err
+	}
+	if err := g.pinning.Flush(ctx); err != nil {
+		return err
+	}
+
+	g.journal.Record(ctx, journal.TypePin, map[string]string{
+		"op":        "add",
+		"cid":       root.String(),
+		"recursive": "true",
+		"gossip":    from.String(),
+	})
+	return nil
+}
+
+// sync replicates the pinset of p.
+func (g *pinGossip) sync(ctx context.Context, p peer.ID) {
+	digest, roots, err := g.request(ctx, p)
+	if err != nil {
+		logger.Debugf("pin gossip: asking %s for its pins: %s", p, err)
+		return
+	}
+	if digest == nil {
+		return
+	}
+
+	complete := true
+	for _, root := range roots {
+		if g.skipped.Has(root) {
+			continue
+		}
+		_, pinned, err := g.pinning.IsPinnedWithType(ctx, root, pin.Recursive)
+		if err != nil {
+			logger.Errorf("pin gossip: %s", err)
+			return
+		}
+		if pinned {
+			continue
+		}
+
+		err = g.pin(ctx, root, p)
+		switch {
+		case err == nil:
+			logger.Infof("pin gossip: pinned %s from %s", root, p)
+		case errors.Is(err, errPinGossipTooLarge):
+			logger.Warnf("pin gossip: not pinning %s from %s: %s", root, p, err)
+			g.skipped.Add(root)
+		default:
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Errorf("pin gossip: pinning %s from %s: %s", root, p, err)
+			complete = false
+		}
+	}
+	// retry the roots that failed on the next round
+	if complete {
+		g.digests[p] = digest
+	}
+}
+
+func (g *pinGossip) run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		for p := range g.peers {
+			g.sync(ctx, p)
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// PinGossip exchanges pinsets with the peers in Pinning.Gossip.Peers.
+func PinGossip(cfg config.PinGossip) interface{} {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, h host.Host, pinning pin.Pinner, dag format.DAGService, gcLocker blockstore.GCLocker, sys provider.System, j *journal.Journal) error {
+		maxPinSize, err := humanize.ParseBytes(cfg.MaxPinSize.WithDefault(defaultPinGossipMaxPinSize))
+		if err != nil {
+			return fmt.Errorf("invalid Pinning.Gossip.MaxPinSize: %w", err)
+		}
+		interval := cfg.Interval.WithDefault(defaultPinGossipInterval)
+		if interval <= 0 {
+			return fmt.Errorf("Pinning.Gossip.Interval must be positive")
+		}
+
+		g := &pinGossip{
+			h:          h,
+			pinning:    pinning,
+			dag:        dag,
+			gcLocker:   gcLocker,
+			provider:   sys,
+			journal:    j,
+			peers:      make(map[peer.ID]struct{}, len(cfg.Peers)),
+			maxPinSize: int64(maxPinSize),
+			digests:    make(map[peer.ID][]byte),
+			skipped:    cid.NewSet(),
+		}
+		for _, s := range cfg.Peers {
+			id, err := peer.Decode(s)
+			if err != nil {
+				return fmt.Errorf("invalid peer in Pinning.Gossip.Peers: %w", err)
+			}
+			g.peers[id] = struct{}{}
+		}
+
+		h.SetStreamHandler(PinGossipProtocol, g.handle)
+
+		ctx, cancel := context.WithCancel(helpers.LifecycleCtx(mctx, lc))
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				go g.run(ctx, interval)
+				return nil
+			},
+			OnStop: func(_ context.Context) error {
+				cancel()
+				h.RemoveStreamHandler(PinGossipProtocol)
+				return nil
+			},
+		})
+		return nil
+	}
+}
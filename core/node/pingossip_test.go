@@ -0,0 +1,124 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	"github.com/ipfs/go-ipfs-pinner/dspinner"
+	provider "github.com/ipfs/go-ipfs-provider"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	mdtest "github.com/ipfs/go-merkledag/test"
+	ft "github.com/ipfs/go-unixfs"
+	"github.com/libp2p/go-libp2p-core/test"
+)
+
+// countingDAG counts the nodes got from it.
+type countingDAG struct {
+	format.DAGService
+	gets int32
+}
+
+func (d *countingDAG) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	atomic.AddInt32(&d.gets, 1)
+	return d.DAGService.Get(ctx, c)
+}
+
+func TestReadPinset(t *testing.T) {
+	roots := []cid.Cid{
+		merkledag.NodeWithData([]byte("a")).Cid(),
+		merkledag.NodeWithData([]byte("b")).Cid(),
+	}
+	var resp bytes.Buffer
+	resp.WriteByte(pinGossipChanged)
+	resp.Write(make([]byte, sha256.Size))
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, c := range roots {
+		resp.Write(buf[:binary.PutUvarint(buf, uint64(c.ByteLen()))])
+		resp.Write(c.Bytes())
+	}
+	last := 1 + roots[1].ByteLen()
+
+	digest, read, err := readPinset(bytes.NewReader(resp.Bytes()), int64(resp.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digest) != sha256.Size || len(read) != 2 || !read[0].Equals(roots[0]) || !read[1].Equals(roots[1]) {
+		t.Fatalf("unexpected pinset %x %v", digest, read)
+	}
+
+	// cut right before the last root, the pinset would look complete
+	if _, _, err := readPinset(bytes.NewReader(resp.Bytes()), int64(resp.Len()-last)); err != errPinsetTooLarge {
+		t.Fatalf("expected a pinset larger than the limit to be refused, got %v", err)
+	}
+
+	digest, read, err = readPinset(bytes.NewReader([]byte{pinGossipUnchanged}), int64(resp.Len()))
+	if err != nil || digest != nil || read != nil {
+		t.Fatalf("expected an unchanged pinset, got %x %v %v", digest, read, err)
+	}
+}
+
+func TestPinGossipPin(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	dag := &countingDAG{DAGService: mdtest.Mock()}
+	pinning, err := dspinner.New(ctx, ds, dag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &pinGossip{
+		pinning:    pinning,
+		dag:        dag,
+		gcLocker:   blockstore.NewGCLocker(),
+		provider:   provider.NewOfflineProvider(),
+		maxPinSize: 64,
+	}
+
+	small := merkledag.NodeWithData(ft.FilePBData([]byte("small"), 5))
+	large := merkledag.NodeWithData(ft.FilePBData(bytes.Repeat([]byte("l"), 100), 100))
+	for _, nd := range []format.Node{small, large} {
+		if err := dag.Add(ctx, nd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the DAG is fetched while the garbage collector runs, but only pinned
+	// once it is done
+	unlocker := g.gcLocker.GCLock(ctx)
+	done := make(chan error, 1)
+	go func() { done <- g.pin(ctx, small.Cid(), test.RandPeerIDFatal(t)) }()
+	time.Sleep(100 * time.Millisecond)
+	if atomic.LoadInt32(&dag.gets) == 0 {
+		t.Fatal("expected the DAG to be fetched outside of the pin lock")
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("expected the root to be pinned under the pin lock, got %v", err)
+	default:
+	}
+	unlocker.Unlock(ctx)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, pinned, err := pinning.IsPinnedWithType(ctx, small.Cid(), pin.Recursive); err != nil || !pinned {
+		t.Fatalf("expected the root to be pinned, got %v", err)
+	}
+
+	if err := g.pin(ctx, large.Cid(), test.RandPeerIDFatal(t)); !errors.Is(err, errPinGossipTooLarge) {
+		t.Fatalf("expected a DAG larger than the maximum to be refused, got %v", err)
+	}
+	if _, pinned, err := pinning.IsPinnedWithType(ctx, large.Cid(), pin.Recursive); err != nil || pinned {
+		t.Fatalf("expected the large root not to be pinned, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	bloom "github.com/ipfs/bbloom"
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	ipld "github.com/ipfs/go-ipld-format"
+	mh "github.com/multiformats/go-multihash"
+	"go.uber.org/fx"
+
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+const (
+	// presenceIndexHashes is the number of hash functions of the presence
+	// index bloom filter.
+	presenceIndexHashes = 7
+
+	// presenceIndexRebuildRatio triggers a rebuild once the number of
+	// deleted blocks reaches this fraction of the indexed blocks: deleted
+	// blocks can't be removed from a bloom filter, and are looked up in the
+	// blockstore until then.
+	presenceIndexRebuildRatio = 0.1
+
+	presenceIndexCheckInterval = time.Minute
+)
+
+// PresenceIndex is a compact, approximate index of the blocks in the
+// blockstore. Bitswap uses it to answer the wants for the blocks the node
+// doesn't have without reading from the disk.
+//
+// The index never reports a stored block as missing: it is only consulted
+// once it has been built from the whole blockstore, and every block written
+// after that is added to it. The blocks it may have, bloom filter false
+// positives and blocks deleted since the last build included, are looked up
+// in the blockstore.
+type PresenceIndex struct {
+	bs   blockstore.Blockstore
+	bits int
+
+	lk      sync.RWMutex
+	filter  *bloom.Bloom // nil until the first build completes
+	next    *bloom.Bloom // being built, nil otherwise
+	deletes uint64
+}
+
+func newPresenceIndex(bs blockstore.Blockstore, size int) *PresenceIndex {
+	return &PresenceIndex{bs: bs, bits: size * 8}
+}
+
+// missing returns whether k is surely not in the blockstore. When false, the
+// blockstore must be asked.
+func (pi *PresenceIndex) missing(k cid.Cid) bool {
+	if !k.Defined() || k.Prefix().MhType == mh.IDENTITY {
+		// identity blocks are never written to the blockstore
+		return false
+	}
+
+	pi.lk.RLock()
+	defer pi.lk.RUnlock()
+	return pi.filter != nil && !pi.filter.HasTS(k.Hash())
+}
+
+func (pi *PresenceIndex) add(k cid.Cid) {
+	pi.lk.RLock()
+	defer pi.lk.RUnlock()
+	if pi.filter != nil {
+		pi.filter.AddTS(k.Hash())
+	}
+	if pi.next != nil {
+		pi.next.AddTS(k.Hash())
+	}
+}
+
+func (pi *PresenceIndex) remove(k cid.Cid) {
+	pi.lk.Lock()
+	pi.deletes++
+	pi.lk.Unlock()
+}
+
+// build indexes all the blocks in the blockstore in a new filter, and swaps
+// it in when done. Until then, the previous filter keeps being used: it
+// still holds every block, plus some that have since been deleted.
+func (pi *PresenceIndex) build(ctx context.Context) error {
+	next, err := bloom.New(float64(pi.bits), presenceIndexHashes)
+	if err != nil {
+		return err
+	}
+
+	pi.lk.Lock()
+	pi.next = next
+	pi.deletes = 0
+	pi.lk.Unlock()
+
+	ch, err := pi.bs.AllKeysChan(ctx)
+	if err != nil {
+		pi.lk.Lock()
+		pi.next = nil
+		pi.lk.Unlock()
+		return err
+	}
+	for k := range ch {
+		next.AddTS(k.Hash())
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	pi.lk.Lock()
+	pi.filter = next
+	pi.next = nil
+	pi.lk.Unlock()
+	return nil
+}
+
+// needsRebuild returns whether enough blocks were deleted to rebuild the
+// filter.
+func (pi *PresenceIndex) needsRebuild() bool {
+	pi.lk.Lock()
+	defer pi.lk.Unlock()
+	if pi.filter == nil {
+		return false
+	}
+	return pi.deletes > 0 && float64(pi.deletes) >= presenceIndexRebuildRatio*float64(pi.filter.ElementsAdded())
+}
+
+func (pi *PresenceIndex) run(ctx context.Context) {
+	if err := pi.build(ctx); err != nil {
+		if ctx.Err() == nil {
+			logger.Errorf("building the bitswap presence index: %s", err)
+		}
+		return
+	}
+
+	t := time.NewTicker(presenceIndexCheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if !pi.needsRebuild() {
+				continue
+			}
+			if err := pi.build(ctx); err != nil && ctx.Err() == nil {
+				logger.Errorf("rebuilding the bitswap presence index: %s", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// presenceIndexedBlockstore keeps a PresenceIndex up to date with the
+// blocks written to and deleted from the wrapped blockstore.
+type presenceIndexedBlockstore struct {
+	blockstore.Blockstore
+	index *PresenceIndex
+}
+
+var _ blockstore.Viewer = (*presenceIndexedBlockstore)(nil)
+
+func (bs *presenceIndexedBlockstore) Put(ctx context.Context, b blocks.Block) error {
+	if err := bs.Blockstore.Put(ctx, b); err != nil {
+		return err
+	}
+	bs.index.add(b.Cid())
+	return nil
+}
+
+func (bs *presenceIndexedBlockstore) PutMany(ctx context.Context, bls []blocks.Block) error {
+	if err := bs.Blockstore.PutMany(ctx, bls); err != nil {
+		return err
+	}
+	for _, b := range bls {
+		bs.index.add(b.Cid())
+	}
+	return nil
+}
+
+func (bs *presenceIndexedBlockstore) DeleteBlock(ctx context.Context, k cid.Cid) error {
+	if err := bs.Blockstore.DeleteBlock(ctx, k); err != nil {
+		return err
+	}
+	bs.index.remove(k)
+	return nil
+}
+
+func (bs *presenceIndexedBlockstore) View(ctx context.Context, k cid.Cid, callback func([]byte) error) error {
+	if v, ok := bs.Blockstore.(blockstore.Viewer); ok {
+		return v.View(ctx, k, callback)
+	}
+	b, err := bs.Blockstore.Get(ctx, k)
+	if err != nil {
+		return err
+	}
+	return callback(b.RawData())
+}
+
+// presenceBlockstore is the blockstore handed to bitswap: the engine gets
+// block sizes to answer both WANT_HAVE and WANT_BLOCK, so answering from the
+// index for the blocks missing keeps it off the disk for those. The blocks
+// the index may have are looked up, bitswap needing their real size to
+// inline the small ones and to account for the data sent.
+type presenceBlockstore struct {
+	blockstore.GCBlockstore
+	index *PresenceIndex
+}
+
+func (bs *presenceBlockstore) GetSize(ctx context.Context, k cid.Cid) (int, error) {
+	if bs.index.missing(k) {
+		return -1, ipld.ErrNotFound{Cid: k}
+	}
+	return bs.GCBlockstore.GetSize(ctx, k)
+}
+
+// PresenceIndexCtor wraps the base blockstore constructor to keep a
+// PresenceIndex of size bytes of its blocks. When size is 0, no index is
+// kept and the provided PresenceIndex is nil.
+func PresenceIndexCtor(baseCtor func(helpers.MetricsCtx, repo.Repo, fx.Lifecycle) (BaseBlocks, error), size int) func(mctx helpers.MetricsCtx, repo repo.Repo, lc fx.Lifecycle) (BaseBlocks, *PresenceIndex, error) {
+	return func(mctx helpers.MetricsCtx, repo repo.Repo, lc fx.Lifecycle) (BaseBlocks, *PresenceIndex, error) {
+		bb, err := baseCtor(mctx, repo, lc)
+		if err != nil || size <= 0 {
+			return bb, nil, err
+		}
+
+		index := newPresenceIndex(bb, size)
+		ctx, cancel := context.WithCancel(helpers.LifecycleCtx(mctx, lc))
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				go index.run(ctx)
+				return nil
+			},
+			OnStop: func(_ context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+		return &presenceIndexedBlockstore{Blockstore: bb, index: index}, index, nil
+	}
+}
@@ -0,0 +1,64 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	ipld "github.com/ipfs/go-ipld-format"
+)
+
+// countingBlockstore counts the sizes looked up.
+type countingBlockstore struct {
+	blockstore.GCBlockstore
+	lookups int
+}
+
+func (bs *countingBlockstore) GetSize(ctx context.Context, k cid.Cid) (int, error) {
+	bs.lookups++
+	return bs.GCBlockstore.GetSize(ctx, k)
+}
+
+func TestPresenceBlockstore(t *testing.T) {
+	ctx := context.Background()
+	base := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	index := newPresenceIndex(base, 1024)
+	indexed := &presenceIndexedBlockstore{Blockstore: base, index: index}
+
+	stored := blocks.NewBlock([]byte("stored"))
+	deleted := blocks.NewBlock([]byte("deleted"))
+	missing := blocks.NewBlock([]byte("missing"))
+	if err := indexed.PutMany(ctx, []blocks.Block{stored, deleted}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.build(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexed.DeleteBlock(ctx, deleted.Cid()); err != nil {
+		t.Fatal(err)
+	}
+
+	counting := &countingBlockstore{GCBlockstore: blockstore.NewGCBlockstore(indexed, blockstore.NewGCLocker())}
+	bs := &presenceBlockstore{GCBlockstore: counting, index: index}
+
+	size, err := bs.GetSize(ctx, stored.Cid())
+	if err != nil || size != len(stored.RawData()) {
+		t.Fatalf("expected the real size of a stored block, got %d, %v", size, err)
+	}
+	if _, err := bs.GetSize(ctx, deleted.Cid()); !ipld.IsNotFound(err) {
+		t.Fatalf("expected a deleted block still in the index to be not found, got %v", err)
+	}
+	if counting.lookups != 2 {
+		t.Fatalf("expected the blocks in the index to be looked up, got %d lookups", counting.lookups)
+	}
+	if _, err := bs.GetSize(ctx, missing.Cid()); !ipld.IsNotFound(err) {
+		t.Fatalf("expected a missing block to be not found, got %v", err)
+	}
+	if counting.lookups != 2 {
+		t.Fatal("expected the blocks missing from the index not to be looked up")
+	}
+}
@@ -0,0 +1,239 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	bsnet "github.com/ipfs/go-bitswap/network"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p-core/event"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/routing"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+const (
+	defaultProtocolCacheMaxAge = 72 * time.Hour
+	// protocolCacheSaveInterval is how often the cache is saved, besides
+	// when the node stops.
+	protocolCacheSaveInterval = 10 * time.Minute
+)
+
+var protocolCacheKey = datastore.NewKey("/local/protocols")
+
+// bitswapProtocols are the protocols of bitswap, a provider speaking none of
+// them being useless to bitswap.
+var bitswapProtocols = []string{
+	string(bsnet.ProtocolBitswap),
+	string(bsnet.ProtocolBitswapOneOne),
+	string(bsnet.ProtocolBitswapOneZero),
+	string(bsnet.ProtocolBitswapNoVers),
+}
+
+// cachedProtocols are the protocols of a peer, in the datastore, keyed by
+// the encoded peer ID.
+type cachedProtocols struct {
+	Protocols  []string
+	Identified time.Time
+}
+
+// ProtocolCache persists the protocols the peers announced with identify,
+// as configured in Routing.ProtocolCache. They are restored into the
+// peerstore on start, so that the providers known not to speak bitswap are
+// skipped without being dialed, even after a restart.
+type ProtocolCache struct {
+	ps     peerstore.Peerstore
+	ds     datastore.Datastore
+	maxAge time.Duration
+
+	mu         sync.Mutex
+	identified map[peer.ID]time.Time
+}
+
+// ProtocolCacheService creates the protocol cache, if Routing.ProtocolCache
+// enables it.
+func ProtocolCacheService(cfg config.RoutingProtocolCache) interface{} {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, h host.Host, r repo.Repo) (*ProtocolCache, error) {
+		if !cfg.Enabled.WithDefault(false) {
+			return nil, nil
+		}
+		c := &ProtocolCache{
+			ps:         h.Peerstore(),
+			ds:         r.Datastore(),
+			maxAge:     cfg.MaxAge.WithDefault(defaultProtocolCacheMaxAge),
+			identified: make(map[peer.ID]time.Time),
+		}
+
+		ctx := helpers.LifecycleCtx(mctx, lc)
+		if err := c.load(ctx); err != nil {
+			return nil, err
+		}
+		sub, err := h.EventBus().Subscribe([]interface{}{
+			new(event.EvtPeerIdentificationCompleted),
+			new(event.EvtPeerProtocolsUpdated),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				go func() {
+					defer close(done)
+					c.run(ctx, sub)
+				}()
+				return nil
+			},
+			OnStop: func(stopCtx context.Context) error {
+				cancel()
+				<-done
+				sub.Close()
+				return c.save(stopCtx)
+			},
+		})
+		return c, nil
+	}
+}
+
+func (c *ProtocolCache) run(ctx context.Context, sub event.Subscription) {
+	t := time.NewTicker(protocolCacheSaveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case e := <-sub.Out():
+			var p peer.ID
+			switch e := e.(type) {
+			case event.EvtPeerIdentificationCompleted:
+				p = e.Peer
+			case event.EvtPeerProtocolsUpdated:
+				p = e.Peer
+			}
+			c.mu.Lock()
+			c.identified[p] = time.Now()
+			c.mu.Unlock()
+		case <-t.C:
+			if err := c.save(ctx); err != nil {
+				logger.Errorf("saving the protocol cache: %s", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// load restores the protocols of the peers identified within the max age
+// into the peerstore.
+func (c *ProtocolCache) load(ctx context.Context) error {
+	data, err := c.ds.Get(ctx, protocolCacheKey)
+	if err == datastore.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var cached map[string]cachedProtocols
+	if err := json.Unmarshal(data, &cached); err != nil {
+		logger.Errorf("ignoring the invalid protocol cache: %s", err)
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, e := range cached {
+		if time.Since(e.Identified) > c.maxAge || len(e.Protocols) == 0 {
+			continue
+		}
+		p, err := peer.Decode(id)
+		if err != nil {
+			continue
+		}
+		if protos, err := c.ps.GetProtocols(p); err == nil && len(protos) > 0 {
+			continue
+		}
+		if err := c.ps.SetProtocols(p, e.Protocols...); err != nil {
+			continue
+		}
+		c.identified[p] = e.Identified
+	}
+	return nil
+}
+
+// save saves the protocols of the peers identified within the max age.
+func (c *ProtocolCache) save(ctx context.Context) error {
+	c.mu.Lock()
+	cached := make(map[string]cachedProtocols, len(c.identified))
+	for p, identified := range c.identified {
+		if time.Since(identified) > c.maxAge {
+			delete(c.identified, p)
+			continue
+		}
+		protos, err := c.ps.GetProtocols(p)
+		if err != nil || len(protos) == 0 {
+			continue
+		}
+		cached[p.String()] = cachedProtocols{Protocols: protos, Identified: identified}
+	}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	return c.ds.Put(ctx, protocolCacheKey, data)
+}
+
+// speaksNone returns whether p is known to speak none of protos.
+func (c *ProtocolCache) speaksNone(p peer.ID, protos []string) bool {
+	known, err := c.ps.GetProtocols(p)
+	if err != nil || len(known) == 0 {
+		return false
+	}
+	supported, err := c.ps.SupportsProtocols(p, protos...)
+	return err == nil && len(supported) == 0
+}
+
+// ContentRouting returns cr, skipping the providers known not to speak any
+// of protos.
+func (c *ProtocolCache) ContentRouting(cr routing.ContentRouting, protos ...string) routing.ContentRouting {
+	if c == nil {
+		return cr
+	}
+	return &protocolFilter{ContentRouting: cr, cache: c, protos: protos}
+}
+
+type protocolFilter struct {
+	routing.ContentRouting
+	cache  *ProtocolCache
+	protos []string
+}
+
+func (f *protocolFilter) FindProvidersAsync(ctx context.Context, c cid.Cid, count int) <-chan peer.AddrInfo {
+	in := f.ContentRouting.FindProvidersAsync(ctx, c, count)
+	out := make(chan peer.AddrInfo)
+	go func() {
+		defer close(out)
+		for p := range in {
+			if f.cache.speaksNone(p.ID, f.protos) {
+				logger.Debugf("skipping provider %s of %s, known not to speak %v", p.ID, c, f.protos)
+				continue
+			}
+			select {
+			case out <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
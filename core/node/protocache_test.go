@@ -0,0 +1,85 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	"github.com/libp2p/go-libp2p-core/test"
+	"github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	"github.com/multiformats/go-multihash"
+)
+
+func newTestProtocolCache(t *testing.T, ds datastore.Datastore) *ProtocolCache {
+	t.Helper()
+	ps, err := pstoremem.NewPeerstore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ProtocolCache{ps: ps, ds: ds, maxAge: time.Hour, identified: make(map[peer.ID]time.Time)}
+}
+
+// testProviders is a content router finding its providers.
+type testProviders struct {
+	routing.ContentRouting
+	providers []peer.ID
+}
+
+func (r testProviders) FindProvidersAsync(ctx context.Context, c cid.Cid, count int) <-chan peer.AddrInfo {
+	ch := make(chan peer.AddrInfo, len(r.providers))
+	for _, p := range r.providers {
+		ch <- peer.AddrInfo{ID: p}
+	}
+	close(ch)
+	return ch
+}
+
+func TestProtocolCache(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	bitswapPeer, otherPeer, unknownPeer, stalePeer := test.RandPeerIDFatal(t), test.RandPeerIDFatal(t), test.RandPeerIDFatal(t), test.RandPeerIDFatal(t)
+
+	c := newTestProtocolCache(t, ds)
+	if err := c.ps.SetProtocols(bitswapPeer, "/ipfs/id/1.0.0", bitswapProtocols[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ps.SetProtocols(otherPeer, "/ipfs/id/1.0.0", "/x/other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ps.SetProtocols(stalePeer, "/x/other"); err != nil {
+		t.Fatal(err)
+	}
+	c.identified[bitswapPeer] = time.Now()
+	c.identified[otherPeer] = time.Now()
+	c.identified[stalePeer] = time.Now().Add(-2 * time.Hour)
+	if err := c.save(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// a restarted node knows the protocols again
+	restarted := newTestProtocolCache(t, ds)
+	if err := restarted.load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if protos, _ := restarted.ps.GetProtocols(stalePeer); len(protos) != 0 {
+		t.Fatalf("expected the protocols of a peer identified too long ago to be forgotten, got %v", protos)
+	}
+
+	h, err := multihash.Sum([]byte("block"), multihash.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr := restarted.ContentRouting(testProviders{providers: []peer.ID{bitswapPeer, otherPeer, unknownPeer}}, bitswapProtocols...)
+	var found []peer.ID
+	for p := range cr.FindProvidersAsync(ctx, cid.NewCidV1(cid.Raw, h), 0) {
+		found = append(found, p.ID)
+	}
+	if len(found) != 2 || found[0] != bitswapPeer || found[1] != unknownPeer {
+		t.Fatalf("expected the peer known not to speak bitswap to be skipped, got %v", found)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/multiformats/go-multihash"
 	"go.uber.org/fx"
 
+	config "github.com/ipfs/go-ipfs/config"
 	"github.com/ipfs/go-ipfs/core/node/helpers"
 	"github.com/ipfs/go-ipfs/core/node/libp2p"
 	"github.com/ipfs/go-ipfs/repo"
@@ -74,39 +75,42 @@ func BatchedProviderSys(isOnline bool, reprovideInterval string) interface{} {
 		if !ok {
 			return nil, fmt.Errorf("BatchedProviderSys requires a content router that supports provideMany")
 		}
+		return newBatchedProviderSys(lc, r, q, keyProvider, repo, isOnline, reprovideInterval)
+	}
+}
 
-		reprovideIntervalDuration := kReprovideFrequency
-		if reprovideInterval != "" {
-			dur, err := time.ParseDuration(reprovideInterval)
-			if err != nil {
-				return nil, err
-			}
-
-			reprovideIntervalDuration = dur
-		}
-
-		sys, err := batched.New(r, q,
-			batched.ReproviderInterval(reprovideIntervalDuration),
-			batched.Datastore(repo.Datastore()),
-			batched.KeyProvider(keyProvider))
+func newBatchedProviderSys(lc fx.Lifecycle, r provideMany, q *q.Queue, keyProvider simple.KeyChanFunc, repo repo.Repo, isOnline bool, reprovideInterval string) (provider.System, error) {
+	reprovideIntervalDuration := kReprovideFrequency
+	if reprovideInterval != "" {
+		dur, err := time.ParseDuration(reprovideInterval)
 		if err != nil {
 			return nil, err
 		}
 
-		if isOnline {
-			lc.Append(fx.Hook{
-				OnStart: func(ctx context.Context) error {
-					sys.Run()
-					return nil
-				},
-				OnStop: func(ctx context.Context) error {
-					return sys.Close()
-				},
-			})
-		}
+		reprovideIntervalDuration = dur
+	}
 
-		return sys, nil
+	sys, err := batched.New(r, q,
+		batched.ReproviderInterval(reprovideIntervalDuration),
+		batched.Datastore(repo.Datastore()),
+		batched.KeyProvider(keyProvider))
+	if err != nil {
+		return nil, err
 	}
+
+	if isOnline {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				sys.Run()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return sys.Close()
+			},
+		})
+	}
+
+	return sys, nil
 }
 
 // ONLINE/OFFLINE
@@ -137,6 +141,15 @@ func OfflineProviders(useStrategicProviding bool, useBatchedProviding bool, repr
 	)
 }
 
+// BitswapProvides returns whether bitswap announces the blocks it fetches as
+// they arrive.
+func BitswapProvides(cfg *config.Config) bool {
+	/* don't provide from bitswap when the strategic provider service is active,
+	   when announcements are deferred until the DAG is complete, or when
+	   they are delegated to an announcer */
+	return !cfg.Experimental.StrategicProviding && !cfg.Provider.DeferIncomplete.WithDefault(false) && cfg.Provider.Announcer.WithDefault("") == ""
+}
+
 // SimpleProviders creates the simple provider/reprovider dependencies
 func SimpleProviders(reprovideStrategy string, reprovideInterval string) fx.Option {
 	reproviderInterval := kReprovideFrequency
@@ -149,26 +162,28 @@ func SimpleProviders(reprovideStrategy string, reprovideInterval string) fx.Opti
 		reproviderInterval = dur
 	}
 
-	var keyProvider fx.Option
+	return fx.Options(
+		fx.Provide(ProviderQueue),
+		fx.Provide(SimpleProvider),
+		keyProviderStrategy(reprovideStrategy),
+		fx.Provide(SimpleReprovider(reproviderInterval)),
+	)
+}
+
+// keyProviderStrategy provides the keys to reprovide with Reprovider.Strategy
+func keyProviderStrategy(reprovideStrategy string) fx.Option {
 	switch reprovideStrategy {
 	case "all":
 		fallthrough
 	case "":
-		keyProvider = fx.Provide(simple.NewBlockstoreProvider)
+		return fx.Provide(simple.NewBlockstoreProvider)
 	case "roots":
-		keyProvider = fx.Provide(pinnedProviderStrategy(true))
+		return fx.Provide(pinnedProviderStrategy(true))
 	case "pinned":
-		keyProvider = fx.Provide(pinnedProviderStrategy(false))
+		return fx.Provide(pinnedProviderStrategy(false))
 	default:
 		return fx.Error(fmt.Errorf("unknown reprovider strategy '%s'", reprovideStrategy))
 	}
-
-	return fx.Options(
-		fx.Provide(ProviderQueue),
-		fx.Provide(SimpleProvider),
-		keyProvider,
-		fx.Provide(SimpleReprovider(reproviderInterval)),
-	)
 }
 
 func pinnedProviderStrategy(onlyRoots bool) interface{} {
@@ -0,0 +1,82 @@
+package node
+
+import (
+	"context"
+	"sync/atomic"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	ipld "github.com/ipfs/go-ipld-format"
+
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+// readOnlyBlockstore refuses the new blocks and the removals of a read-only
+// repo with repo.ErrReadOnly. Putting blocks already stored is allowed, as
+// nothing is written.
+type readOnlyBlockstore struct {
+	blockstore.Blockstore
+}
+
+func (bs readOnlyBlockstore) Put(ctx context.Context, b blocks.Block) error {
+	return bs.PutMany(ctx, []blocks.Block{b})
+}
+
+func (bs readOnlyBlockstore) PutMany(ctx context.Context, bls []blocks.Block) error {
+	for _, b := range bls {
+		has, err := bs.Has(ctx, b.Cid())
+		if err != nil {
+			return err
+		}
+		if !has {
+			return repo.ErrReadOnly
+		}
+	}
+	return nil
+}
+
+func (readOnlyBlockstore) DeleteBlock(context.Context, cid.Cid) error {
+	return repo.ErrReadOnly
+}
+
+type readOnlyGCBlockstore struct {
+	readOnlyBlockstore
+	blockstore.GCLocker
+}
+
+// readOnlyPinner refuses the changes to the pins of a read-only repo with
+// repo.ErrReadOnly. The changes made with PinWithMode and RemovePinWithMode,
+// which can't fail, are dropped and reported by the next Flush.
+type readOnlyPinner struct {
+	pin.Pinner
+	dropped int32
+}
+
+func (*readOnlyPinner) Pin(context.Context, ipld.Node, bool) error {
+	return repo.ErrReadOnly
+}
+
+func (*readOnlyPinner) Unpin(context.Context, cid.Cid, bool) error {
+	return repo.ErrReadOnly
+}
+
+func (*readOnlyPinner) Update(context.Context, cid.Cid, cid.Cid, bool) error {
+	return repo.ErrReadOnly
+}
+
+func (p *readOnlyPinner) PinWithMode(cid.Cid, pin.Mode) {
+	atomic.StoreInt32(&p.dropped, 1)
+}
+
+func (p *readOnlyPinner) RemovePinWithMode(cid.Cid, pin.Mode) {
+	atomic.StoreInt32(&p.dropped, 1)
+}
+
+func (p *readOnlyPinner) Flush(ctx context.Context) error {
+	if atomic.SwapInt32(&p.dropped, 0) == 1 {
+		return repo.ErrReadOnly
+	}
+	return p.Pinner.Flush(ctx)
+}
@@ -0,0 +1,60 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	"github.com/ipfs/go-ipfs-pinner/dspinner"
+	mdtest "github.com/ipfs/go-merkledag/test"
+
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+func TestReadOnlyRepo(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	stored, other := blocks.NewBlock([]byte("stored")), blocks.NewBlock([]byte("other"))
+	base := blockstore.NewBlockstore(ds)
+	if err := base.Put(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	bs := readOnlyBlockstore{base}
+	if err := bs.Put(ctx, stored); err != nil {
+		t.Fatalf("expected putting a stored block to be allowed, got %s", err)
+	}
+	if err := bs.PutMany(ctx, []blocks.Block{stored, other}); err != repo.ErrReadOnly {
+		t.Fatalf("expected the new blocks to be refused, got %v", err)
+	}
+	if err := bs.DeleteBlock(ctx, stored.Cid()); err != repo.ErrReadOnly {
+		t.Fatalf("expected the removals to be refused, got %v", err)
+	}
+	if has, _ := base.Has(ctx, other.Cid()); has {
+		t.Fatal("expected the refused block not to be stored")
+	}
+
+	dag := mdtest.Mock()
+	pinning, err := dspinner.New(ctx, ds, dag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &readOnlyPinner{Pinner: pinning}
+	if err := p.Unpin(ctx, stored.Cid(), true); err != repo.ErrReadOnly {
+		t.Fatalf("expected unpinning to be refused, got %v", err)
+	}
+	if err := p.Flush(ctx); err != nil {
+		t.Fatalf("expected flushing without changes to succeed, got %s", err)
+	}
+	p.PinWithMode(stored.Cid(), pin.Recursive)
+	if err := p.Flush(ctx); err != repo.ErrReadOnly {
+		t.Fatalf("expected the dropped pin to be reported on flush, got %v", err)
+	}
+	if _, pinned, _ := pinning.IsPinned(ctx, stored.Cid()); pinned {
+		t.Fatal("expected the dropped pin not to be stored")
+	}
+}
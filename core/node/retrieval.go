@@ -0,0 +1,347 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	exchange "github.com/ipfs/go-ipfs-exchange-interface"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+// Types of the sources of Retrieval.Sources.
+const (
+	RetrievalPeers   = "peers"
+	RetrievalRouting = "routing"
+	RetrievalGateway = "gateway"
+)
+
+const (
+	defaultRetrievalPeersTimeout   = time.Second
+	defaultRetrievalRoutingTimeout = 30 * time.Second
+	defaultRetrievalGatewayTimeout = 30 * time.Second
+
+	// retrievalGatewayWorkers bounds the blocks fetched at once from each
+	// gateway.
+	retrievalGatewayWorkers = 8
+	// retrievalMaxBlockSize bounds the size of the blocks read from
+	// gateways.
+	retrievalMaxBlockSize = 4 << 20
+)
+
+type retrievalSource struct {
+	typ     string
+	timeout time.Duration
+	url     string
+}
+
+// RetrievalPolicy is the order of the sources blocks are fetched from, as
+// configured in Retrieval.Sources.
+type RetrievalPolicy struct {
+	sources []retrievalSource
+	client  *http.Client
+}
+
+// NewRetrievalPolicy returns the policy in cfg, or nil when cfg has no
+// sources.
+func NewRetrievalPolicy(cfg config.Retrieval) (*RetrievalPolicy, error) {
+	if len(cfg.Sources) == 0 {
+		return nil, nil
+	}
+
+	p := &RetrievalPolicy{client: &http.Client{}}
+	seen := make(map[string]bool)
+	for i, s := range cfg.Sources {
+		src := retrievalSource{typ: s.Type}
+		switch s.Type {
+		case RetrievalPeers:
+			src.timeout = s.Timeout.WithDefault(defaultRetrievalPeersTimeout)
+		case RetrievalRouting:
+			if !seen[RetrievalPeers] {
+				// bitswap asks the connected peers before the providers
+				return nil, fmt.Errorf("Retrieval.Sources[%d]: %q must come after %q", i, RetrievalRouting, RetrievalPeers)
+			}
+			src.timeout = s.Timeout.WithDefault(defaultRetrievalRoutingTimeout)
+		case RetrievalGateway:
+			u, err := url.Parse(s.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				return nil, fmt.Errorf("Retrieval.Sources[%d]: invalid gateway URL %q", i, s.URL)
+			}
+			src.url = strings.TrimSuffix(s.URL, "/")
+			src.timeout = s.Timeout.WithDefault(defaultRetrievalGatewayTimeout)
+		default:
+			return nil, fmt.Errorf("Retrieval.Sources[%d]: unknown type %q", i, s.Type)
+		}
+		if src.typ != RetrievalGateway && seen[src.typ] {
+			return nil, fmt.Errorf("Retrieval.Sources[%d]: %q is listed twice", i, s.Type)
+		}
+		if src.timeout < 0 {
+			return nil, fmt.Errorf("Retrieval.Sources[%d]: the timeout must be positive", i)
+		}
+		seen[src.typ] = true
+		p.sources = append(p.sources, src)
+	}
+	return p, nil
+}
+
+// has returns whether the policy has a source of type typ.
+func (p *RetrievalPolicy) has(typ string) bool {
+	for _, s := range p.sources {
+		if s.typ == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// providerSearchDelay returns the time bitswap asks the connected peers
+// alone, before searching the providers.
+func (p *RetrievalPolicy) providerSearchDelay() time.Duration {
+	var d time.Duration
+	started := false
+	for _, s := range p.sources {
+		switch {
+		case s.typ == RetrievalPeers:
+			started = true
+		case s.typ == RetrievalRouting:
+			return d
+		}
+		if started {
+			d += s.timeout
+		}
+	}
+	return d
+}
+
+// Exchange returns exch, fetching the blocks from the sources of the
+// policy. The blocks of the sources other than bitswap are passed to exch
+// too, so that they are stored and sent to the peers that want them.
+func (p *RetrievalPolicy) Exchange(exch exchange.SessionExchange) exchange.SessionExchange {
+	return &RetrievalExchange{SessionExchange: exch, policy: p}
+}
+
+// getBlocks fetches keys from the sources of the policy, bitswap through
+// f, in order: each source starts after the previous one was tried alone
+// for its timeout, or gave up.
+func (p *RetrievalPolicy) getBlocks(ctx context.Context, exch exchange.Interface, f exchange.Fetcher, keys []cid.Cid) <-chan blocks.Block {
+	out := make(chan blocks.Block)
+	go func() {
+		defer close(out)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		missing := cid.NewSet()
+		for _, c := range keys {
+			missing.Add(c)
+		}
+		found := make(chan blocks.Block)
+		done := make(chan struct{})
+		running, next := 0, 0
+		var timer <-chan time.Time
+
+		startNext := func() {
+			src := p.sources[next]
+			next++
+			running++
+			pending := missing.Keys()
+			go func() {
+				p.fetch(ctx, src, exch, f, pending, found)
+				select {
+				case done <- struct{}{}:
+				case <-ctx.Done():
+				}
+			}()
+			timer = time.After(src.timeout)
+		}
+
+		startNext()
+		for missing.Len() > 0 {
+			select {
+			case b := <-found:
+				if !missing.Has(b.Cid()) {
+					continue
+				}
+				missing.Remove(b.Cid())
+				select {
+				case out <- b:
+				case <-ctx.Done():
+					return
+				}
+			case <-timer:
+				timer = nil
+				if next < len(p.sources) {
+					startNext()
+				}
+			case <-done:
+				running--
+				if next < len(p.sources) {
+					startNext()
+				} else if running == 0 {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// fetch fetches keys from src, until ctx is done or src gives up.
+func (p *RetrievalPolicy) fetch(ctx context.Context, src retrievalSource, exch exchange.Interface, f exchange.Fetcher, keys []cid.Cid, found chan<- blocks.Block) {
+	switch src.typ {
+	case RetrievalPeers:
+		ch, err := f.GetBlocks(ctx, keys)
+		if err != nil {
+			logger.Debugf("retrieval: bitswap: %s", err)
+			return
+		}
+		for b := range ch {
+			select {
+			case found <- b:
+			case <-ctx.Done():
+				return
+			}
+		}
+	case RetrievalRouting:
+		// bitswap searches the providers itself, after the delay
+		// configured from the policy
+		<-ctx.Done()
+	case RetrievalGateway:
+		sem := make(chan struct{}, retrievalGatewayWorkers)
+		for _, c := range keys {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(c cid.Cid) {
+				defer func() { <-sem }()
+				b, err := p.fetchFromGateway(ctx, src.url, c)
+				if err != nil {
+					logger.Debugf("retrieval: %s from %s: %s", c, src.url, err)
+					return
+				}
+				if err := exch.HasBlock(ctx, b); err != nil {
+					logger.Errorf("retrieval: storing %s: %s", c, err)
+					return
+				}
+				select {
+				case found <- b:
+				case <-ctx.Done():
+				}
+			}(c)
+		}
+		// wait for the last fetches
+		for i := 0; i < cap(sem); i++ {
+			sem <- struct{}{}
+		}
+	}
+}
+
+// fetchFromGateway fetches the block c from the gateway at gw, and checks
+// that it matches c.
+func (p *RetrievalPolicy) fetchFromGateway(ctx context.Context, gw string, c cid.Cid) (blocks.Block, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gw+"/ipfs/"+c.String()+"?format=raw", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.ipld.raw")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gateway returned %s", res.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, retrievalMaxBlockSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > retrievalMaxBlockSize {
+		return nil, fmt.Errorf("block larger than %d bytes", retrievalMaxBlockSize)
+	}
+	actual, err := c.Prefix().Sum(data)
+	if err != nil {
+		return nil, err
+	}
+	if !actual.Equals(c) {
+		return nil, fmt.Errorf("gateway returned a block that doesn't match the CID")
+	}
+	return blocks.NewBlockWithCid(data, c)
+}
+
+func (p *RetrievalPolicy) getBlock(ctx context.Context, exch exchange.Interface, f exchange.Fetcher, c cid.Cid) (blocks.Block, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	b, ok := <-p.getBlocks(ctx, exch, f, []cid.Cid{c})
+	if !ok {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, format.ErrNotFound{Cid: c}
+	}
+	return b, nil
+}
+
+// RetrievalExchange is an exchange fetching blocks as configured in
+// Retrieval.Sources.
+type RetrievalExchange struct {
+	exchange.SessionExchange
+	policy *RetrievalPolicy
+}
+
+// Unwrap returns the exchange behind e.
+func (e *RetrievalExchange) Unwrap() exchange.Interface {
+	return e.SessionExchange
+}
+
+func (e *RetrievalExchange) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	return e.policy.getBlock(ctx, e.SessionExchange, e.SessionExchange, c)
+}
+
+func (e *RetrievalExchange) GetBlocks(ctx context.Context, keys []cid.Cid) (<-chan blocks.Block, error) {
+	return e.policy.getBlocks(ctx, e.SessionExchange, e.SessionExchange, keys), nil
+}
+
+func (e *RetrievalExchange) NewSession(ctx context.Context) exchange.Fetcher {
+	return &retrievalSession{e: e, ses: e.SessionExchange.NewSession(ctx)}
+}
+
+type retrievalSession struct {
+	e   *RetrievalExchange
+	ses exchange.Fetcher
+}
+
+func (s *retrievalSession) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	return s.e.policy.getBlock(ctx, s.e.SessionExchange, s.ses, c)
+}
+
+func (s *retrievalSession) GetBlocks(ctx context.Context, keys []cid.Cid) (<-chan blocks.Block, error) {
+	return s.e.policy.getBlocks(ctx, s.e.SessionExchange, s.ses, keys), nil
+}
+
+// noProviderSearch keeps bitswap from searching providers, while it still
+// announces the blocks it receives.
+type noProviderSearch struct {
+	routing.ContentRouting
+}
+
+func (noProviderSearch) FindProvidersAsync(context.Context, cid.Cid, int) <-chan peer.AddrInfo {
+	ch := make(chan peer.AddrInfo)
+	close(ch)
+	return ch
+}
@@ -0,0 +1,180 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	exchange "github.com/ipfs/go-ipfs-exchange-interface"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+// testExchange is a bitswap returning the blocks it has after a delay.
+type testExchange struct {
+	delay time.Duration
+
+	mu     sync.Mutex
+	blocks map[cid.Cid]blocks.Block
+	added  []cid.Cid
+}
+
+func (e *testExchange) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	panic("unused")
+}
+
+func (e *testExchange) GetBlocks(ctx context.Context, keys []cid.Cid) (<-chan blocks.Block, error) {
+	out := make(chan blocks.Block)
+	go func() {
+		defer close(out)
+		select {
+		case <-time.After(e.delay):
+		case <-ctx.Done():
+			return
+		}
+		e.mu.Lock()
+		var found []blocks.Block
+		for _, c := range keys {
+			if b, ok := e.blocks[c]; ok {
+				found = append(found, b)
+			}
+		}
+		e.mu.Unlock()
+		for _, b := range found {
+			select {
+			case out <- b:
+			case <-ctx.Done():
+				return
+			}
+		}
+		<-ctx.Done()
+	}()
+	return out, nil
+}
+
+func (e *testExchange) HasBlock(ctx context.Context, b blocks.Block) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.added = append(e.added, b.Cid())
+	return nil
+}
+
+func (e *testExchange) IsOnline() bool { return true }
+func (e *testExchange) Close() error   { return nil }
+
+func (e *testExchange) NewSession(ctx context.Context) exchange.Fetcher { return e }
+
+func TestRetrievalPolicyConfig(t *testing.T) {
+	if p, err := NewRetrievalPolicy(config.Retrieval{}); err != nil || p != nil {
+		t.Fatalf("expected no policy without sources, got %v", err)
+	}
+	for i, sources := range [][]config.RetrievalSource{
+		{{Type: "routing"}},
+		{{Type: "peers"}, {Type: "peers"}},
+		{{Type: "gateway", URL: "ftp://example.com"}},
+		{{Type: "lan"}},
+	} {
+		if _, err := NewRetrievalPolicy(config.Retrieval{Sources: sources}); err == nil {
+			t.Errorf("expected an error for the sources %d", i)
+		}
+	}
+
+	p, err := NewRetrievalPolicy(config.Retrieval{Sources: []config.RetrievalSource{
+		{Type: "gateway", URL: "http://127.0.0.1:1", Timeout: config.NewOptionalDuration(5 * time.Second)},
+		{Type: "peers", Timeout: config.NewOptionalDuration(2 * time.Second)},
+		{Type: "gateway", URL: "http://127.0.0.1:2", Timeout: config.NewOptionalDuration(3 * time.Second)},
+		{Type: "routing"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := p.providerSearchDelay(); d != 5*time.Second {
+		t.Fatalf("expected to search providers 5s after asking the peers, got %s", d)
+	}
+}
+
+func TestRetrievalPolicyOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fromGateway := blocks.NewBlock([]byte("from the gateway"))
+	fromPeers := blocks.NewBlock([]byte("from the peers"))
+	var requested []string
+	var mu sync.Mutex
+	gw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/ipfs/"+fromGateway.Cid().String() {
+			w.Write(fromGateway.RawData())
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer gw.Close()
+
+	exch := &testExchange{delay: 10 * time.Millisecond, blocks: map[cid.Cid]blocks.Block{fromPeers.Cid(): fromPeers}}
+	p, err := NewRetrievalPolicy(config.Retrieval{Sources: []config.RetrievalSource{
+		{Type: "peers", Timeout: config.NewOptionalDuration(200 * time.Millisecond)},
+		{Type: "gateway", URL: gw.URL},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := p.Exchange(exch)
+
+	// the peers have it before the gateway is asked
+	b, err := e.GetBlock(ctx, fromPeers.Cid())
+	if err != nil || !b.Cid().Equals(fromPeers.Cid()) {
+		t.Fatalf("expected the block of the peers, got %v", err)
+	}
+	if len(requested) != 0 {
+		t.Fatalf("expected the gateway not to be asked, got %v", requested)
+	}
+
+	// the gateway is asked after the timeout of the peers
+	start := time.Now()
+	ch, err := e.NewSession(ctx).GetBlocks(ctx, []cid.Cid{fromPeers.Cid(), fromGateway.Cid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []cid.Cid
+	for b := range ch {
+		got = append(got, b.Cid())
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 blocks, got %v", got)
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Fatal("expected the gateway to be asked after the timeout of the peers")
+	}
+	if len(exch.added) != 1 || !exch.added[0].Equals(fromGateway.Cid()) {
+		t.Fatalf("expected the block of the gateway to be passed to the exchange, got %v", exch.added)
+	}
+
+	// blocks found nowhere are not found once every source gave up
+	gwOnly, err := NewRetrievalPolicy(config.Retrieval{Sources: []config.RetrievalSource{{Type: "gateway", URL: gw.URL}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gwOnly.Exchange(exch).GetBlock(ctx, blocks.NewBlock([]byte("missing")).Cid()); err == nil {
+		t.Fatal("expected an error for a missing block")
+	}
+}
+
+func TestFetchFromGatewayChecksBlocks(t *testing.T) {
+	gw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not the block"))
+	}))
+	defer gw.Close()
+
+	p := &RetrievalPolicy{client: gw.Client()}
+	if _, err := p.fetchFromGateway(context.Background(), gw.URL, blocks.NewBlock([]byte("block")).Cid()); err == nil {
+		t.Fatal("expected an error for a block not matching its CID")
+	}
+}
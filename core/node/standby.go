@@ -0,0 +1,791 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	proto "github.com/gogo/protobuf/proto"
+	multierror "github.com/hashicorp/go-multierror"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	keystore "github.com/ipfs/go-ipfs-keystore"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	provider "github.com/ipfs/go-ipfs-provider"
+	format "github.com/ipfs/go-ipld-format"
+	pb "github.com/ipfs/go-ipns/pb"
+	"github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-mfs"
+	"github.com/ipfs/go-namesys"
+	path "github.com/ipfs/go-path"
+	uio "github.com/ipfs/go-unixfs/io"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"go.uber.org/fx"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/journal"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+// StandbyProtocol is the protocol a standby uses to mirror its primary, as
+// configured in Standby.
+const StandbyProtocol = protocol.ID("/ipfs/standby/0.1.0")
+
+// StandbyPrimaryKeyName is the name the private key of the primary is
+// imported under when its standby is promoted.
+const StandbyPrimaryKeyName = "primary"
+
+// StandbyKeyPrefix prefixes the names of the keys of the primary in the
+// keystore of its standby, until the standby is promoted.
+const StandbyKeyPrefix = "standby/"
+
+const (
+	defaultStandbyInterval = time.Minute
+
+	standbyTimeout = time.Minute
+	// standbyFetchTimeout bounds the fetch and pin of each root, and of the
+	// MFS root.
+	standbyFetchTimeout = 30 * time.Minute
+	// standbyMaxSnapshot bounds the size of the snapshots read from the
+	// primary.
+	standbyMaxSnapshot = 64 << 20
+)
+
+var standbyKey = datastore.NewKey("/local/standby")
+
+var errNotStandby = errors.New("this node is not a standby, see Standby.Primary")
+
+// standbySnapshot is the state of a primary mirrored by its standbys.
+type standbySnapshot struct {
+	Recursive []cid.Cid
+	Direct    []cid.Cid
+	FilesRoot cid.Cid
+	// Records are the IPNS records published by the primary, by peer ID.
+	Records map[string][]byte `json:",omitempty"`
+	// Keys are the private keys of the primary, by name, when it sends
+	// them. The identity of the primary is named "self". The standby stores
+	// them in its keystore, not with the rest of the snapshot.
+	Keys map[string][]byte `json:",omitempty"`
+}
+
+// StandbyStatus is the state of a standby.
+type StandbyStatus struct {
+	Primary   string
+	LastSync  time.Time `json:",omitempty"`
+	LastError string    `json:",omitempty"`
+	Recursive int
+	Direct    int
+	FilesRoot string `json:",omitempty"`
+	Records   int
+	Keys      int
+	Promoted  bool
+}
+
+// Standby mirrors the state of a primary on a standby, and serves the state
+// of a primary to its standbys.
+type Standby struct {
+	h        host.Host
+	repo     repo.Repo
+	pinning  pin.Pinner
+	dag      format.DAGService
+	gcLocker blockstore.GCLocker
+	provider provider.System
+	files    *mfs.Root
+	namesys  namesys.NameSystem
+	self     crypto.PrivKey
+	journal  *journal.Journal
+
+	standbys map[peer.ID]struct{}
+	sendKeys bool
+	primary  peer.ID
+	cancel   context.CancelFunc
+
+	// syncMu serializes the syncs with the promotions
+	syncMu sync.Mutex
+	// mu guards the fields below, it is not held while fetching
+	mu sync.Mutex
+	// mirrored is the last snapshot of the primary applied here
+	mirrored  standbySnapshot
+	lastSync  time.Time
+	lastError error
+	promoted  bool
+}
+
+// snapshot returns the state of this node sent to its standbys.
+func (s *Standby) snapshot(ctx context.Context) (*standbySnapshot, error) {
+	var snap standbySnapshot
+	var err error
+	if snap.Recursive, err = s.pinning.RecursiveKeys(ctx); err != nil {
+		return nil, err
+	}
+	if snap.Direct, err = s.pinning.DirectKeys(ctx); err != nil {
+		return nil, err
+	}
+	root, err := s.files.GetDirectory().GetNode()
+	if err != nil {
+		return nil, err
+	}
+	snap.FilesRoot = root.Cid()
+
+	keys := map[string]crypto.PrivKey{"self": s.self}
+	ks := s.repo.Keystore()
+	names, err := ks.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		// the keys mirrored from another primary are not passed on
+		if strings.HasPrefix(name, StandbyKeyPrefix) {
+			continue
+		}
+		if keys[name], err = ks.Get(name); err != nil {
+			return nil, err
+		}
+	}
+
+	snap.Records = make(map[string][]byte)
+	if s.sendKeys {
+		snap.Keys = make(map[string][]byte)
+	}
+	for name, k := range keys {
+		id, err := peer.IDFromPrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		rec, err := s.repo.Datastore().Get(ctx, namesys.IpnsDsKey(id))
+		switch err {
+		case nil:
+			snap.Records[id.String()] = rec
+		case datastore.ErrNotFound:
+		default:
+			return nil, err
+		}
+
+		if s.sendKeys {
+			if snap.Keys[name], err = crypto.MarshalPrivateKey(k); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &snap, nil
+}
+
+// handle sends the snapshot of this node to a standby.
+func (s *Standby) handle(st network.Stream) {
+	if _, ok := s.standbys[st.Conn().RemotePeer()]; !ok {
+		st.Reset()
+		return
+	}
+	_ = st.SetDeadline(time.Now().Add(standbyTimeout))
+
+	ctx, cancel := context.WithTimeout(context.Background(), standbyTimeout)
+	defer cancel()
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		logger.Errorf("standby: snapshot for %s: %s", st.Conn().RemotePeer(), err)
+		st.Reset()
+		return
+	}
+	if err := json.NewEncoder(st).Encode(snap); err != nil {
+		st.Reset()
+		return
+	}
+	st.Close()
+}
+
+// request asks the primary for its snapshot.
+func (s *Standby) request(ctx context.Context) (*standbySnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, standbyTimeout)
+	defer cancel()
+
+	st, err := s.h.NewStream(ctx, s.primary, StandbyProtocol)
+	if err != nil {
+		return nil, err
+	}
+	defer st.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = st.SetDeadline(deadline)
+	}
+	if err := st.CloseWrite(); err != nil {
+		st.Reset()
+		return nil, err
+	}
+
+	var snap standbySnapshot
+	if err := json.NewDecoder(io.LimitReader(st, standbyMaxSnapshot)).Decode(&snap); err != nil {
+		st.Reset()
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// syncPins pins the roots pinned on the primary, and unpins the roots
+// mirrored from it that it unpinned. It returns the roots mirrored now: the
+// pins of the standby itself are left alone. The roots failing to be mirrored
+// don't stop the others, they are retried at the next sync.
+func (s *Standby) syncPins(ctx context.Context, roots, mirrored []cid.Cid, recursive bool) ([]cid.Cid, error) {
+	mode := pin.Direct
+	if recursive {
+		mode = pin.Recursive
+	}
+	wasMirrored := cid.NewSet()
+	for _, c := range mirrored {
+		wasMirrored.Add(c)
+	}
+
+	var synced []cid.Cid
+	var errs error
+	current := cid.NewSet()
+	for _, c := range roots {
+		if !current.Visit(c) {
+			continue
+		}
+		_, pinned, err := s.pinning.IsPinnedWithType(ctx, c, mode)
+		switch {
+		case err != nil:
+			errs = multierror.Append(errs, fmt.Errorf("pinning %s: %w", c, err))
+		case pinned:
+			if wasMirrored.Has(c) {
+				synced = append(synced, c)
+			}
+		default:
+			if err := s.pinRoot(ctx, c, recursive); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("pinning %s: %w", c, err))
+				continue
+			}
+			synced = append(synced, c)
+		}
+	}
+
+	for _, c := range mirrored {
+		if current.Has(c) {
+			continue
+		}
+		if err := s.unpinRoot(ctx, c, recursive); err != nil && err != pin.ErrNotPinned {
+			errs = multierror.Append(errs, fmt.Errorf("unpinning %s: %w", c, err))
+			synced = append(synced, c)
+		}
+	}
+	return synced, errs
+}
+
+// pinRoot fetches and pins c. The DAG is fetched before taking the pin lock,
+// so a slow primary doesn't hold up garbage collection; the pinner then walks
+// the blocks fetched, only fetching again those collected in between.
+func (s *Standby) pinRoot(ctx context.Context, c cid.Cid, recursive bool) error {
+	ctx, cancel := context.WithTimeout(ctx, standbyFetchTimeout)
+	defer cancel()
+
+	if recursive {
+		if err := merkledag.FetchGraph(ctx, c, s.dag); err != nil {
+			return err
+		}
+	}
+
+	defer s.gcLocker.PinLock(ctx).Unlock(ctx)
+	nd, err := s.dag.Get(ctx, c)
+	if err != nil {
+		return err
+	}
+	if err := s.pinning.Pin(ctx, nd, recursive); err != nil {
+		return err
+	}
+	if err := s.provider.Provide(c); err != nil {
+		return err
+	}
+	if err := s.pinning.Flush(ctx); err != nil {
+		return err
+	}
+
+	s.journal.Record(ctx, journal.TypePin, map[string]string{
+		"op":        "add",
+		"cid":       c.String(),
+		"recursive": strconv.FormatBool(recursive),
+		"standby":   s.primary.String(),
+	})
+	return nil
+}
+
+func (s *Standby) unpinRoot(ctx context.Context, c cid.Cid, recursive bool) error {
+	defer s.gcLocker.PinLock(ctx).Unlock(ctx)
+
+	if err := s.pinning.Unpin(ctx, c, recursive); err != nil {
+		return err
+	}
+	if err := s.pinning.Flush(ctx); err != nil {
+		return err
+	}
+
+	s.journal.Record(ctx, journal.TypePin, map[string]string{
+		"op":        "rm",
+		"cid":       c.String(),
+		"recursive": strconv.FormatBool(recursive),
+		"standby":   s.primary.String(),
+	})
+	return nil
+}
+
+// syncFiles replaces the content of the MFS root with the one of the
+// primary. Like for the pins, the DAG is fetched before taking the pin lock.
+func (s *Standby) syncFiles(ctx context.Context, root cid.Cid) error {
+	ctx, cancel := context.WithTimeout(ctx, standbyFetchTimeout)
+	defer cancel()
+
+	if err := merkledag.FetchGraph(ctx, root, s.dag); err != nil {
+		return err
+	}
+
+	defer s.gcLocker.PinLock(ctx).Unlock(ctx)
+	nd, err := s.dag.Get(ctx, root)
+	if err != nil {
+		return err
+	}
+	src, err := uio.NewDirectoryFromNode(s.dag, nd)
+	if err != nil {
+		return err
+	}
+
+	dir := s.files.GetDirectory()
+	names, err := dir.ListNames(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := dir.Unlink(name); err != nil {
+			return err
+		}
+	}
+	err = src.ForEachLink(ctx, func(l *format.Link) error {
+		child, err := s.dag.Get(ctx, l.Cid)
+		if err != nil {
+			return err
+		}
+		return dir.AddChild(l.Name, child)
+	})
+	if err != nil {
+		return err
+	}
+	return s.files.Flush()
+}
+
+// syncRecords stores the IPNS records of the primary newer than the local
+// ones, so they are republished with the next sequence numbers once the
+// standby is promoted.
+func (s *Standby) syncRecords(ctx context.Context, records map[string][]byte) error {
+	d := s.repo.Datastore()
+	for p, rec := range records {
+		id, err := peer.Decode(p)
+		if err != nil {
+			return err
+		}
+		entry := new(pb.IpnsEntry)
+		if err := proto.Unmarshal(rec, entry); err != nil {
+			return fmt.Errorf("invalid IPNS record for %s: %w", id, err)
+		}
+
+		local, err := d.Get(ctx, namesys.IpnsDsKey(id))
+		switch err {
+		case nil:
+			localEntry := new(pb.IpnsEntry)
+			if err := proto.Unmarshal(local, localEntry); err == nil && localEntry.GetSequence() >= entry.GetSequence() {
+				continue
+			}
+		case datastore.ErrNotFound:
+		default:
+			return err
+		}
+		if err := d.Put(ctx, namesys.IpnsDsKey(id), rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncKeys stores the keys of the primary in the keystore, named with
+// StandbyKeyPrefix so they are not republished before the promotion, and
+// removes the ones the primary no longer sends.
+func (s *Standby) syncKeys(keys map[string][]byte) error {
+	ks := s.repo.Keystore()
+	stale, err := standbyKeyNames(ks)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for name, b := range keys {
+		stored := StandbyKeyPrefix + name
+		delete(stale, stored)
+		k, err := crypto.UnmarshalPrivateKey(b)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("key %q of the primary: %w", name, err))
+			continue
+		}
+		if old, err := ks.Get(stored); err == nil {
+			if old.Equals(k) {
+				continue
+			}
+			if err := ks.Delete(stored); err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+		}
+		if err := ks.Put(stored, k); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	for stored := range stale {
+		if err := ks.Delete(stored); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// standbyKeyNames returns the names of the keys of the primary in ks.
+func standbyKeyNames(ks keystore.Keystore) (map[string]struct{}, error) {
+	names, err := ks.List()
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]struct{})
+	for _, name := range names {
+		if strings.HasPrefix(name, StandbyKeyPrefix) {
+			stored[name] = struct{}{}
+		}
+	}
+	return stored, nil
+}
+
+// withoutStandbyKeys hides the keys of the primary from the republisher, so
+// the standby doesn't republish its names.
+type withoutStandbyKeys struct {
+	keystore.Keystore
+}
+
+func (ks withoutStandbyKeys) List() ([]string, error) {
+	names, err := ks.Keystore.List()
+	if err != nil {
+		return nil, err
+	}
+	own := names[:0]
+	for _, name := range names {
+		if !strings.HasPrefix(name, StandbyKeyPrefix) {
+			own = append(own, name)
+		}
+	}
+	return own, nil
+}
+
+// sync mirrors the current snapshot of the primary. The parts failing to be
+// mirrored don't stop the others, they are retried at the next sync.
+func (s *Standby) sync(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	snap, err := s.request(ctx)
+	if err != nil {
+		return fmt.Errorf("asking %s for its snapshot: %w", s.primary, err)
+	}
+
+	s.mu.Lock()
+	promoted, mirrored := s.promoted, s.mirrored
+	s.mu.Unlock()
+	if promoted {
+		return nil
+	}
+
+	var errs error
+	recursive, err := s.syncPins(ctx, snap.Recursive, mirrored.Recursive, true)
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	direct, err := s.syncPins(ctx, snap.Direct, mirrored.Direct, false)
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	filesRoot := mirrored.FilesRoot
+	if snap.FilesRoot.Defined() && !snap.FilesRoot.Equals(filesRoot) {
+		if err := s.syncFiles(ctx, snap.FilesRoot); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("mirroring MFS: %w", err))
+		} else {
+			filesRoot = snap.FilesRoot
+		}
+	}
+
+	records := mirrored.Records
+	if err := s.syncRecords(ctx, snap.Records); err != nil {
+		errs = multierror.Append(errs, err)
+	} else {
+		records = snap.Records
+	}
+	if err := s.syncKeys(snap.Keys); err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("storing the keys: %w", err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mirrored = standbySnapshot{
+		Recursive: recursive,
+		Direct:    direct,
+		FilesRoot: filesRoot,
+		Records:   records,
+	}
+	if errs == nil {
+		s.lastSync = time.Now()
+	}
+	if err := s.save(ctx); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
+}
+
+// load restores the last snapshot mirrored before a restart.
+func (s *Standby) load(ctx context.Context) error {
+	b, err := s.repo.Datastore().Get(ctx, standbyKey)
+	if err == datastore.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &s.mirrored); err != nil {
+		return err
+	}
+	if len(s.mirrored.Keys) == 0 {
+		return nil
+	}
+
+	// the keys were saved with the snapshot before, move them to the
+	// keystore
+	if err := s.syncKeys(s.mirrored.Keys); err != nil {
+		return err
+	}
+	s.mirrored.Keys = nil
+	return s.save(ctx)
+}
+
+func (s *Standby) save(ctx context.Context) error {
+	b, err := json.Marshal(&s.mirrored)
+	if err != nil {
+		return err
+	}
+	return s.repo.Datastore().Put(ctx, standbyKey, b)
+}
+
+func (s *Standby) run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		err := s.sync(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Errorf("standby: %s", err)
+		}
+		s.mu.Lock()
+		s.lastError = err
+		s.mu.Unlock()
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Status returns the state of the mirroring of the primary.
+func (s *Standby) Status() (*StandbyStatus, error) {
+	if s == nil || s.primary == "" {
+		return nil, errNotStandby
+	}
+	keys, err := standbyKeyNames(s.repo.Keystore())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := &StandbyStatus{
+		Primary:   s.primary.String(),
+		LastSync:  s.lastSync,
+		Recursive: len(s.mirrored.Recursive),
+		Direct:    len(s.mirrored.Direct),
+		Records:   len(s.mirrored.Records),
+		Keys:      len(keys),
+		Promoted:  s.promoted,
+	}
+	if s.lastError != nil {
+		st.LastError = s.lastError.Error()
+	}
+	if s.mirrored.FilesRoot.Defined() {
+		st.FilesRoot = s.mirrored.FilesRoot.String()
+	}
+	return st, nil
+}
+
+// Promote stops mirroring the primary, and takes over from it: the
+// mirrored keys lose their StandbyKeyPrefix, the identity of the primary is
+// named StandbyPrimaryKeyName, and the names of the primary are republished
+// from this node. Standby.Primary is removed from the config, so the node
+// stays promoted across restarts.
+func (s *Standby) Promote(ctx context.Context) ([]string, error) {
+	if s == nil || s.primary == "" {
+		return nil, errNotStandby
+	}
+	s.cancel()
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	s.mu.Lock()
+	promoted, records := s.promoted, s.mirrored.Records
+	s.mu.Unlock()
+	if promoted {
+		return nil, errors.New("this node was already promoted")
+	}
+
+	ks := s.repo.Keystore()
+	stored, err := standbyKeyNames(ks)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PrivKey, len(stored))
+	renamed := make(map[string]string, len(stored))
+	for st := range stored {
+		k, err := ks.Get(st)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimPrefix(st, StandbyKeyPrefix)
+		if name == "self" {
+			name = StandbyPrimaryKeyName
+		}
+		if has, err := ks.Has(name); err != nil {
+			return nil, err
+		} else if has {
+			return nil, fmt.Errorf("can't import key %q of the primary: a key with that name already exists", name)
+		}
+		keys[name] = k
+		renamed[name] = st
+	}
+
+	var published []string
+	for name, k := range keys {
+		if err := ks.Put(name, k); err != nil {
+			return published, err
+		}
+		if err := ks.Delete(renamed[name]); err != nil {
+			return published, err
+		}
+		id, err := peer.IDFromPrivateKey(k)
+		if err != nil {
+			return published, err
+		}
+		rec, ok := records[id.String()]
+		if !ok {
+			continue
+		}
+		entry := new(pb.IpnsEntry)
+		if err := proto.Unmarshal(rec, entry); err != nil {
+			return published, err
+		}
+		p, err := path.ParsePath(string(entry.GetValue()))
+		if err != nil {
+			return published, err
+		}
+		if err := s.namesys.Publish(ctx, k, p); err != nil {
+			return published, fmt.Errorf("republishing %s: %w", name, err)
+		}
+		published = append(published, name)
+	}
+
+	if err := s.repo.SetConfigKey("Standby.Primary", ""); err != nil {
+		return published, err
+	}
+	s.mu.Lock()
+	s.promoted = true
+	s.mu.Unlock()
+	logger.Infof("standby: promoted, took over from %s", s.primary)
+	return published, nil
+}
+
+// StandbyService mirrors the primary in Standby.Primary, and lets the
+// peers in Standby.Standbys mirror this node.
+func StandbyService(cfg config.Standby) interface{} {
+	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle, h host.Host, r repo.Repo, pinning pin.Pinner, dag format.DAGService, gcLocker blockstore.GCLocker, sys provider.System, files *mfs.Root, ns namesys.NameSystem, sk crypto.PrivKey, j *journal.Journal) (*Standby, error) {
+		interval := cfg.Interval.WithDefault(defaultStandbyInterval)
+		if interval <= 0 {
+			return nil, fmt.Errorf("Standby.Interval must be positive")
+		}
+
+		s := &Standby{
+			h:        h,
+			repo:     r,
+			pinning:  pinning,
+			dag:      dag,
+			gcLocker: gcLocker,
+			provider: sys,
+			files:    files,
+			namesys:  ns,
+			self:     sk,
+			journal:  j,
+			standbys: make(map[peer.ID]struct{}, len(cfg.Standbys)),
+			sendKeys: cfg.Keys.WithDefault(false),
+		}
+		for _, p := range cfg.Standbys {
+			id, err := peer.Decode(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid peer in Standby.Standbys: %w", err)
+			}
+			s.standbys[id] = struct{}{}
+		}
+		if cfg.Primary != "" {
+			id, err := peer.Decode(cfg.Primary)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Standby.Primary: %w", err)
+			}
+			s.primary = id
+		}
+
+		ctx, cancel := context.WithCancel(helpers.LifecycleCtx(mctx, lc))
+		s.cancel = cancel
+		if s.primary != "" {
+			if err := s.load(ctx); err != nil {
+				cancel()
+				return nil, fmt.Errorf("loading the standby state: %w", err)
+			}
+		}
+		if len(s.standbys) > 0 {
+			h.SetStreamHandler(StandbyProtocol, s.handle)
+		}
+
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				if s.primary != "" {
+					go s.run(ctx, interval)
+				}
+				return nil
+			},
+			OnStop: func(_ context.Context) error {
+				cancel()
+				h.RemoveStreamHandler(StandbyProtocol)
+				return nil
+			},
+		})
+		return s, nil
+	}
+}
@@ -0,0 +1,245 @@
+package node
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	pin "github.com/ipfs/go-ipfs-pinner"
+	"github.com/ipfs/go-ipfs-pinner/dspinner"
+	provider "github.com/ipfs/go-ipfs-provider"
+	"github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-mfs"
+	"github.com/ipfs/go-namesys"
+	path "github.com/ipfs/go-path"
+	ft "github.com/ipfs/go-unixfs"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/repo"
+)
+
+type testStandbyNode struct {
+	*Standby
+	bs blockstore.Blockstore
+}
+
+func newTestStandbyNode(t *testing.T, mn mocknet.Mocknet) *testStandbyNode {
+	t.Helper()
+	ctx := context.Background()
+	h, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, _, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	bs := blockstore.NewBlockstore(ds)
+	dag := merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+	pinning, err := dspinner.New(ctx, ds, dag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyDir := ft.EmptyDirNode()
+	if err := dag.Add(ctx, emptyDir); err != nil {
+		t.Fatal(err)
+	}
+	files, err := mfs.NewRoot(ctx, dag, emptyDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := namesys.NewNameSystem(newTestRouting(t), namesys.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Identity: config.Identity{PrivKey: "key"}}
+
+	return &testStandbyNode{
+		Standby: &Standby{
+			h:        h,
+			repo:     repo.NewMemRepo(cfg, ds),
+			pinning:  pinning,
+			dag:      dag,
+			gcLocker: blockstore.NewGCLocker(),
+			provider: provider.NewOfflineProvider(),
+			files:    files,
+			namesys:  ns,
+			self:     sk,
+			standbys: make(map[peer.ID]struct{}),
+			cancel:   func() {},
+		},
+		bs: bs,
+	}
+}
+
+func (n *testStandbyNode) add(t *testing.T, data string) *merkledag.ProtoNode {
+	t.Helper()
+	nd := merkledag.NodeWithData(ft.FilePBData([]byte(data), uint64(len(data))))
+	if err := n.dag.Add(context.Background(), nd); err != nil {
+		t.Fatal(err)
+	}
+	return nd
+}
+
+func (n *testStandbyNode) isPinned(t *testing.T, c cid.Cid, mode pin.Mode) bool {
+	t.Helper()
+	_, pinned, err := n.pinning.IsPinnedWithType(context.Background(), c, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pinned
+}
+
+func TestStandby(t *testing.T) {
+	ctx := context.Background()
+	mn := mocknet.New()
+	defer mn.Close()
+	primary, standby := newTestStandbyNode(t, mn), newTestStandbyNode(t, mn)
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	primary.standbys[standby.h.ID()] = struct{}{}
+	primary.sendKeys = true
+	primary.h.SetStreamHandler(StandbyProtocol, primary.handle)
+	standby.primary = primary.h.ID()
+
+	a, b, d, own := primary.add(t, "a"), primary.add(t, "b"), primary.add(t, "d"), primary.add(t, "own")
+	for _, nd := range []*merkledag.ProtoNode{a, b, own} {
+		if err := primary.pinning.Pin(ctx, nd, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := primary.pinning.Pin(ctx, d, false); err != nil {
+		t.Fatal(err)
+	}
+	key, _, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.repo.Keystore().Put("key", key); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.namesys.Publish(ctx, primary.self, path.FromCid(a.Cid())); err != nil {
+		t.Fatal(err)
+	}
+
+	// the blocks of b are missing, the standby pinned own itself
+	for _, nd := range []*merkledag.ProtoNode{a, d} {
+		if err := standby.bs.Put(ctx, nd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := standby.dag.Add(ctx, own); err != nil {
+		t.Fatal(err)
+	}
+	if err := standby.pinning.Pin(ctx, own, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// the state of the standby can be read while it waits for its fetches
+	unlocker := standby.gcLocker.GCLock(ctx)
+	done := make(chan error, 1)
+	go func() { done <- standby.sync(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	status := make(chan struct{})
+	go func() {
+		_, _ = standby.Status()
+		close(status)
+	}()
+	select {
+	case <-status:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the status to be read during the sync")
+	}
+	unlocker.Unlock(ctx)
+
+	err = <-done
+	if err == nil || !strings.Contains(err.Error(), b.Cid().String()) {
+		t.Fatalf("expected the sync to fail to pin b, got %v", err)
+	}
+	if !standby.isPinned(t, a.Cid(), pin.Recursive) || !standby.isPinned(t, d.Cid(), pin.Direct) {
+		t.Fatal("expected the other roots to be pinned")
+	}
+	st, err := standby.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Recursive != 1 || st.Direct != 1 || st.Records != 1 || st.Keys != 2 {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	saved, err := standby.repo.Datastore().Get(ctx, standbyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(saved), "Keys") {
+		t.Fatal("expected the keys not to be saved with the snapshot")
+	}
+	hidden, err := withoutStandbyKeys{standby.repo.Keystore()}.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hidden) != 0 {
+		t.Fatalf("expected the keys of the primary to be hidden from the republisher, got %v", hidden)
+	}
+
+	// b can be fetched now, and the primary unpinned a and own
+	if err := standby.bs.Put(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	for _, nd := range []*merkledag.ProtoNode{a, own} {
+		if err := primary.pinning.Unpin(ctx, nd.Cid(), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := standby.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !standby.isPinned(t, b.Cid(), pin.Recursive) || standby.isPinned(t, a.Cid(), pin.Recursive) {
+		t.Fatal("expected the pins of the standby to follow the ones of the primary")
+	}
+	if !standby.isPinned(t, own.Cid(), pin.Recursive) {
+		t.Fatal("expected the pins of the standby itself to be left alone")
+	}
+
+	published, err := standby.Promote(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 1 || published[0] != StandbyPrimaryKeyName {
+		t.Fatalf("expected the name of the primary to be republished, got %v", published)
+	}
+	names, err := standby.repo.Keystore().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected the keys of the primary to be renamed, got %v", names)
+	}
+	for _, name := range []string{StandbyPrimaryKeyName, "key"} {
+		k, err := standby.repo.Keystore().Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := map[string]crypto.PrivKey{StandbyPrimaryKeyName: primary.self, "key": key}[name]; !k.Equals(expected) {
+			t.Fatalf("unexpected key %s", name)
+		}
+	}
+	if st, _ := standby.Status(); !st.Promoted {
+		t.Fatal("expected the standby to be promoted")
+	}
+	if err := standby.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/ipfs/go-filestore"
 	"github.com/ipfs/go-ipfs/core/node/helpers"
+	"github.com/ipfs/go-ipfs/ipldlimits"
+	"github.com/ipfs/go-ipfs/journal"
+	"github.com/ipfs/go-ipfs/quarantine"
 	"github.com/ipfs/go-ipfs/repo"
 	"github.com/ipfs/go-ipfs/thirdparty/verifbs"
 )
@@ -22,14 +25,38 @@ func Datastore(repo repo.Repo) datastore.Datastore {
 	return repo.Datastore()
 }
 
+// EventJournal provides the event journal of the repo. It is nil when the
+// journal is disabled.
+func EventJournal(repo repo.Repo) *journal.Journal {
+	return repo.Journal()
+}
+
+// BlockQuarantine creates the quarantine of the invalid blocks of the
+// blockstore, unless Datastore.Quarantine turns it off.
+func BlockQuarantine(enabled bool) func(repo repo.Repo, bs blockstore.Blockstore, j *journal.Journal) *quarantine.Quarantine {
+	return func(repo repo.Repo, bs blockstore.Blockstore, j *journal.Journal) *quarantine.Quarantine {
+		if !enabled {
+			return nil
+		}
+		return quarantine.New(repo.Datastore(), bs, j)
+	}
+}
+
+// IPLDLimits creates the limits of the DAGs set in the IPLD config section.
+func IPLDLimits(cfg config.IPLD) func() (*ipldlimits.Limits, error) {
+	return func() (*ipldlimits.Limits, error) {
+		return ipldlimits.FromConfig(cfg)
+	}
+}
+
 // BaseBlocks is the lower level blockstore without GC or Filestore layers
 type BaseBlocks blockstore.Blockstore
 
 // BaseBlockstoreCtor creates cached blockstore backed by the provided datastore
-func BaseBlockstoreCtor(cacheOpts blockstore.CacheOpts, nilRepo bool, hashOnRead bool) func(mctx helpers.MetricsCtx, repo repo.Repo, lc fx.Lifecycle) (bs BaseBlocks, err error) {
-	return func(mctx helpers.MetricsCtx, repo repo.Repo, lc fx.Lifecycle) (bs BaseBlocks, err error) {
+func BaseBlockstoreCtor(cacheOpts blockstore.CacheOpts, nilRepo bool, hashOnRead bool) func(mctx helpers.MetricsCtx, r repo.Repo, lc fx.Lifecycle) (bs BaseBlocks, err error) {
+	return func(mctx helpers.MetricsCtx, r repo.Repo, lc fx.Lifecycle) (bs BaseBlocks, err error) {
 		// hash security
-		bs = blockstore.NewBlockstore(repo.Datastore())
+		bs = blockstore.NewBlockstore(r.Datastore())
 		bs = &verifbs.VerifBS{Blockstore: bs}
 
 		if !nilRepo {
@@ -45,6 +72,10 @@ func BaseBlockstoreCtor(cacheOpts blockstore.CacheOpts, nilRepo bool, hashOnRead
 			bs.HashOnRead(true)
 		}
 
+		if repo.IsReadOnly(r) {
+			bs = readOnlyBlockstore{bs}
+		}
+
 		return
 	}
 }
@@ -59,14 +90,19 @@ func GcBlockstoreCtor(bb BaseBlocks) (gclocker blockstore.GCLocker, gcbs blockst
 }
 
 // GcBlockstoreCtor wraps GcBlockstore and adds Filestore support
-func FilestoreBlockstoreCtor(repo repo.Repo, bb BaseBlocks) (gclocker blockstore.GCLocker, gcbs blockstore.GCBlockstore, bs blockstore.Blockstore, fstore *filestore.Filestore) {
+func FilestoreBlockstoreCtor(r repo.Repo, bb BaseBlocks) (gclocker blockstore.GCLocker, gcbs blockstore.GCBlockstore, bs blockstore.Blockstore, fstore *filestore.Filestore) {
 	gclocker = blockstore.NewGCLocker()
 
 	// hash security
-	fstore = filestore.NewFilestore(bb, repo.FileManager())
+	fstore = filestore.NewFilestore(bb, r.FileManager())
 	gcbs = blockstore.NewGCBlockstore(fstore, gclocker)
 	gcbs = &verifbs.VerifBSGC{GCBlockstore: gcbs}
 
+	// the filestore writes the references to the files itself
+	if repo.IsReadOnly(r) {
+		gcbs = readOnlyGCBlockstore{readOnlyBlockstore{gcbs}, gcbs}
+	}
+
 	bs = gcbs
 	return
 }
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	bserv "github.com/ipfs/go-blockservice"
 	cid "github.com/ipfs/go-cid"
@@ -17,25 +19,34 @@ import (
 	logging "github.com/ipfs/go-log"
 	dag "github.com/ipfs/go-merkledag"
 	"github.com/ipfs/go-verifcid"
+
+	"github.com/ipfs/go-ipfs/walkpool"
 )
 
 var log = logging.Logger("gc")
 
 // Result represents an incremental output from a garbage collection
-// run.  It contains either an error, or the cid of a removed object.
+// run.  It contains either an error, the cid of a removed object, or the
+// cid of an unpinned object kept by a KeepCodecs rule.
 type Result struct {
 	KeyRemoved cid.Cid
+	KeyKept    cid.Cid
 	Error      error
 }
 
-// converts a set of CIDs with different codecs to a set of CIDs with the raw codec.
-func toRawCids(set *cid.Set) (*cid.Set, error) {
-	newSet := cid.NewSet()
-	err := set.ForEach(func(c cid.Cid) error {
-		newSet.Add(cid.NewCidV1(cid.Raw, c.Hash()))
-		return nil
-	})
-	return newSet, err
+// DefaultMarkWorkers is the default number of pins walked at once when
+// marking, as in Datastore.GCMarkWorkers.
+const DefaultMarkWorkers = 4
+
+// MarkOptions tunes the mark phase of a garbage collection run. The zero
+// value walks the pins one by one and keeps the marked set in memory.
+type MarkOptions struct {
+	// Workers is the number of pins walked at once.
+	Workers int
+	// MaxInMemory is the number of marked blocks kept in memory. Past it,
+	// the marked blocks are written to the datastore. 0 keeps them all in
+	// memory.
+	MaxInMemory int
 }
 
 // GC performs a mark and sweep garbage collection of the blocks in the blockstore
@@ -48,6 +59,13 @@ func toRawCids(set *cid.Set) (*cid.Set, error) {
 // The routine then iterates over every block in the blockstore and
 // deletes any block that is not found in the marked set.
 func GC(ctx context.Context, bs bstore.GCBlockstore, dstor dstore.Datastore, pn pin.Pinner, bestEffortRoots []cid.Cid) <-chan Result {
+	return GCKeep(ctx, bs, dstor, pn, bestEffortRoots, nil, nil, MarkOptions{})
+}
+
+// GCKeep is like GC, but also keeps the unmarked blocks selected by keep,
+// and marks fetching the nodes through walkers, as tuned by mark. keep and
+// walkers may be nil.
+func GCKeep(ctx context.Context, bs bstore.GCBlockstore, dstor dstore.Datastore, pn pin.Pinner, bestEffortRoots []cid.Cid, keep *KeepCodecs, walkers *walkpool.Pool, mark MarkOptions) <-chan Result {
 	ctx, cancel := context.WithCancel(ctx)
 
 	unlocker := bs.GCLock(ctx)
@@ -62,7 +80,7 @@ func GC(ctx context.Context, bs bstore.GCBlockstore, dstor dstore.Datastore, pn
 		defer close(output)
 		defer unlocker.Unlock(ctx)
 
-		gcs, err := ColoredSet(ctx, pn, ds, bestEffortRoots, output)
+		gcs, err := newMarkedSet(ctx, dstor, mark.MaxInMemory)
 		if err != nil {
 			select {
 			case output <- Result{Error: err}:
@@ -70,9 +88,13 @@ func GC(ctx context.Context, bs bstore.GCBlockstore, dstor dstore.Datastore, pn
 			}
 			return
 		}
+		defer func() {
+			if err := gcs.close(); err != nil {
+				log.Errorf("removing the marked set: %s", err)
+			}
+		}()
 
-		// The blockstore reports raw blocks. We need to remove the codecs from the CIDs.
-		gcs, err = toRawCids(gcs)
+		err = markPinned(ctx, pn, walkers.NodeGetter(walkpool.OpGC, ds), bestEffortRoots, output, gcs, mark.Workers)
 		if err != nil {
 			select {
 			case output <- Result{Error: err}:
@@ -100,9 +122,39 @@ func GC(ctx context.Context, bs bstore.GCBlockstore, dstor dstore.Datastore, pn
 				if !ok {
 					break loop
 				}
-				// NOTE: assumes that all CIDs returned by the keychan are _raw_ CIDv1 CIDs.
-				// This means we keep the block as long as we want it somewhere (CIDv1, CIDv0, Raw, other...).
-				if !gcs.Has(k) {
+				// The blocks are marked by multihash: we keep the block as long as
+				// we want it somewhere (CIDv1, CIDv0, Raw, other...).
+				marked, err := gcs.has(k)
+				if err != nil {
+					select {
+					case output <- Result{Error: fmt.Errorf("could not check the marked set for %s: %w", k, err)}:
+					case <-ctx.Done():
+					}
+					return
+				}
+				if !marked {
+					if keep != nil {
+						kept, err := keep.match(ctx, bs, k)
+						if err != nil {
+							// can't tell, keep it
+							errors = true
+							select {
+							case output <- Result{Error: fmt.Errorf("could not check gc keep rules for %s: %w", k, err)}:
+							case <-ctx.Done():
+								break loop
+							}
+							continue loop
+						}
+						if kept.Defined() {
+							select {
+							case output <- Result{KeyKept: kept}:
+							case <-ctx.Done():
+								break loop
+							}
+							continue loop
+						}
+					}
+
 					err := bs.DeleteBlock(ctx, k)
 					removed++
 					if err != nil {
@@ -155,6 +207,14 @@ func GC(ctx context.Context, bs bstore.GCBlockstore, dstor dstore.Datastore, pn
 // adds them to the given cid.Set, using the provided dag.GetLinks function
 // to walk the tree.
 func Descendants(ctx context.Context, getLinks dag.GetLinks, set *cid.Set, roots []cid.Cid) error {
+	return descendants(ctx, getLinks, func(k cid.Cid) bool {
+		return set.Visit(toCidV1(k))
+	}, roots, 1)
+}
+
+// descendants walks the given roots, workers at a time, calling visit with
+// each of their descendants. visit must be safe to call concurrently.
+func descendants(ctx context.Context, getLinks dag.GetLinks, visit func(cid.Cid) bool, roots []cid.Cid, workers int) error {
 	verifyGetLinks := func(ctx context.Context, c cid.Cid) ([]*ipld.Link, error) {
 		err := verifcid.ValidateCid(c)
 		if err != nil {
@@ -175,19 +235,48 @@ func Descendants(ctx context.Context, getLinks dag.GetLinks, set *cid.Set, roots
 		return err
 	}
 
-	for _, c := range roots {
-		// Walk recursively walks the dag and adds the keys to the given set
-		err := dag.Walk(ctx, verifyGetLinks, c, func(k cid.Cid) bool {
-			return set.Visit(toCidV1(k))
-		}, dag.Concurrent())
+	if workers < 1 {
+		workers = 1
+	}
+	walkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	todo := make(chan cid.Cid)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range todo {
+				// Walk recursively walks the dag and adds the keys to the given set
+				err := dag.Walk(walkCtx, verifyGetLinks, c, visit, dag.Concurrent())
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
 
-		if err != nil {
-			err = verboseCidError(err)
-			return err
+feed:
+	for _, c := range roots {
+		select {
+		case todo <- c:
+		case <-walkCtx.Done():
+			break feed
 		}
 	}
-
-	return nil
+	close(todo)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return verboseCidError(err)
+	default:
+		return ctx.Err()
+	}
 }
 
 // toCidV1 converts any CIDv0s to CIDv1s.
@@ -201,14 +290,30 @@ func toCidV1(c cid.Cid) cid.Cid {
 // ColoredSet computes the set of nodes in the graph that are pinned by the
 // pins in the given pinner.
 func ColoredSet(ctx context.Context, pn pin.Pinner, ng ipld.NodeGetter, bestEffortRoots []cid.Cid, output chan<- Result) (*cid.Set, error) {
-	// KeySet currently implemented in memory, in the future, may be bloom filter or
-	// disk backed to conserve memory.
-	errors := false
+	marked, err := newMarkedSet(ctx, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := markPinned(ctx, pn, ng, bestEffortRoots, output, marked, 1); err != nil {
+		return nil, err
+	}
+
 	gcs := cid.NewSet()
+	marked.forEach(func(c cid.Cid) {
+		gcs.Add(c)
+	})
+	return gcs, nil
+}
+
+// markPinned marks the nodes in the graph that are pinned by the pins in the
+// given pinner, walking workers pins at once.
+func markPinned(ctx context.Context, pn pin.Pinner, ng ipld.NodeGetter, bestEffortRoots []cid.Cid, output chan<- Result, gcs *markedSet, workers int) error {
+	// the links are fetched concurrently
+	var errors int32
 	getLinks := func(ctx context.Context, cid cid.Cid) ([]*ipld.Link, error) {
 		links, err := ipld.GetLinks(ctx, ng, cid)
 		if err != nil {
-			errors = true
+			atomic.StoreInt32(&errors, 1)
 			select {
 			case output <- Result{Error: &CannotFetchLinksError{cid, err}}:
 			case <-ctx.Done():
@@ -217,24 +322,27 @@ func ColoredSet(ctx context.Context, pn pin.Pinner, ng ipld.NodeGetter, bestEffo
 		}
 		return links, nil
 	}
+	visit := func(k cid.Cid) bool {
+		return gcs.visit(toCidV1(k))
+	}
 	rkeys, err := pn.RecursiveKeys(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	err = Descendants(ctx, getLinks, gcs, rkeys)
+	err = descendants(ctx, getLinks, visit, rkeys, workers)
 	if err != nil {
-		errors = true
+		atomic.StoreInt32(&errors, 1)
 		select {
 		case output <- Result{Error: err}:
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return ctx.Err()
 		}
 	}
 
 	bestEffortGetLinks := func(ctx context.Context, cid cid.Cid) ([]*ipld.Link, error) {
 		links, err := ipld.GetLinks(ctx, ng, cid)
 		if err != nil && !ipld.IsNotFound(err) {
-			errors = true
+			atomic.StoreInt32(&errors, 1)
 			select {
 			case output <- Result{Error: &CannotFetchLinksError{cid, err}}:
 			case <-ctx.Done():
@@ -243,43 +351,46 @@ func ColoredSet(ctx context.Context, pn pin.Pinner, ng ipld.NodeGetter, bestEffo
 		}
 		return links, nil
 	}
-	err = Descendants(ctx, bestEffortGetLinks, gcs, bestEffortRoots)
+	err = descendants(ctx, bestEffortGetLinks, visit, bestEffortRoots, workers)
 	if err != nil {
-		errors = true
+		atomic.StoreInt32(&errors, 1)
 		select {
 		case output <- Result{Error: err}:
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return ctx.Err()
 		}
 	}
 
 	dkeys, err := pn.DirectKeys(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	for _, k := range dkeys {
-		gcs.Add(toCidV1(k))
+		visit(k)
 	}
 
 	ikeys, err := pn.InternalPins(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	err = Descendants(ctx, getLinks, gcs, ikeys)
+	err = descendants(ctx, getLinks, visit, ikeys, workers)
 	if err != nil {
-		errors = true
+		atomic.StoreInt32(&errors, 1)
 		select {
 		case output <- Result{Error: err}:
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return ctx.Err()
 		}
 	}
 
-	if errors {
-		return nil, ErrCannotFetchAllLinks
+	if err := gcs.failed(); err != nil {
+		return fmt.Errorf("garbage collection aborted: could not mark the pinned blocks: %w", err)
+	}
+	if atomic.LoadInt32(&errors) != 0 {
+		return ErrCannotFetchAllLinks
 	}
 
-	return gcs, nil
+	return nil
 }
 
 // ErrCannotFetchAllLinks is returned as the last Result in the GC output
@@ -0,0 +1,79 @@
+package gc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	cid "github.com/ipfs/go-cid"
+	bstore "github.com/ipfs/go-ipfs-blockstore"
+	"github.com/ipld/go-ipld-prime/codec"
+	"github.com/ipld/go-ipld-prime/multicodec"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	mc "github.com/multiformats/go-multicodec"
+
+	// the codecs the keep rules can name
+	_ "github.com/ipld/go-codec-dagpb"
+	_ "github.com/ipld/go-ipld-prime/codec/cbor"
+	_ "github.com/ipld/go-ipld-prime/codec/dagcbor"
+	_ "github.com/ipld/go-ipld-prime/codec/dagjson"
+	_ "github.com/ipld/go-ipld-prime/codec/json"
+)
+
+type keepCodec struct {
+	code   uint64
+	decode codec.Decoder
+}
+
+// KeepCodecs selects unpinned blocks that garbage collection must keep
+// because they are of one of a set of IPLD codecs.
+//
+// The blockstore indexes blocks by multihash, so the codec a block was
+// stored with is not known. A block is taken to be of a codec when its data
+// decodes with it: all the blocks of that codec are kept, along with the
+// odd block of another codec that happens to decode as well.
+type KeepCodecs struct {
+	codecs []keepCodec
+}
+
+// NewKeepCodecs validates a list of codec names, as in Datastore.GCKeepCodecs.
+func NewKeepCodecs(names []string) (*KeepCodecs, error) {
+	kc := &KeepCodecs{}
+	for _, name := range names {
+		var code mc.Code
+		if err := code.Set(name); err != nil {
+			return nil, fmt.Errorf("gc keep rule: unknown codec %q", name)
+		}
+		if code == mc.Raw {
+			return nil, fmt.Errorf("gc keep rule: every block is valid %s, use pins to keep raw blocks", name)
+		}
+		dec, err := multicodec.LookupDecoder(uint64(code))
+		if err != nil {
+			return nil, fmt.Errorf("gc keep rule: %q is not a supported IPLD codec", name)
+		}
+		kc.codecs = append(kc.codecs, keepCodec{code: uint64(code), decode: dec})
+	}
+	return kc, nil
+}
+
+// match returns the CID of block k with the first codec its data decodes
+// with, or cid.Undef if none does.
+func (kc *KeepCodecs) match(ctx context.Context, bs bstore.Blockstore, k cid.Cid) (cid.Cid, error) {
+	if len(kc.codecs) == 0 {
+		return cid.Undef, nil
+	}
+
+	blk, err := bs.Get(ctx, k)
+	if err != nil {
+		return cid.Undef, err
+	}
+	for _, c := range kc.codecs {
+		// Decoders stop after the first complete value, the whole block
+		// must have been read for it to be of that codec.
+		r := bytes.NewReader(blk.RawData())
+		if c.decode(basicnode.Prototype.Any.NewBuilder(), r) == nil && r.Len() == 0 {
+			return cid.NewCidV1(c.code, k.Hash()), nil
+		}
+	}
+	return cid.Undef, nil
+}
@@ -0,0 +1,108 @@
+package gc
+
+import (
+	"context"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	bserv "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	dstore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	bstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	"github.com/ipfs/go-ipfs-pinner/dspinner"
+	dag "github.com/ipfs/go-merkledag"
+	mh "github.com/multiformats/go-multihash"
+)
+
+func testBlock(t *testing.T, codec uint64, data []byte) blocks.Block {
+	t.Helper()
+	h, err := mh.Sum(data, mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := blocks.NewBlockWithCid(data, cid.NewCidV1(codec, h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestGCKeepCodecs(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(dstore.NewMapDatastore())
+	bs := bstore.NewGCBlockstore(bstore.NewBlockstore(ds), bstore.NewGCLocker())
+	dserv := dag.NewDAGService(bserv.New(bs, offline.Exchange(bs)))
+	pinner, err := dspinner.New(ctx, ds, dserv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// {"a": 1}, and the same map followed by more data
+	cbor := testBlock(t, cid.DagCBOR, []byte{0xa1, 0x61, 'a', 0x01})
+	trailing := testBlock(t, cid.DagCBOR, []byte{0xa1, 0x61, 'a', 0x01, 0x02})
+	raw := testBlock(t, cid.Raw, []byte("not cbor"))
+	pb := dag.NodeWithData([]byte("dag-pb"))
+	pinned := dag.NodeWithData([]byte("pinned"))
+	for _, b := range []blocks.Block{cbor, trailing, raw, pb, pinned} {
+		if err := bs.Put(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pinner.Pin(ctx, pinned, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := pinner.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	keep, err := NewKeepCodecs([]string{"dag-cbor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := make(map[string]struct{})
+	kept := make(map[string]struct{})
+	for res := range GCKeep(ctx, bs, ds, pinner, nil, keep, nil, MarkOptions{}) {
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+		if res.KeyRemoved.Defined() {
+			removed[res.KeyRemoved.Hash().String()] = struct{}{}
+		}
+		if res.KeyKept.Defined() {
+			kept[res.KeyKept.Hash().String()] = struct{}{}
+			if res.KeyKept.Type() != cid.DagCBOR {
+				t.Errorf("expected %s to be kept as dag-cbor", res.KeyKept)
+			}
+		}
+	}
+
+	for _, c := range []cid.Cid{cbor.Cid(), pinned.Cid()} {
+		if has, err := bs.Has(ctx, c); err != nil || !has {
+			t.Errorf("expected %s to be kept, got %t, %v", c, has, err)
+		}
+	}
+	if _, ok := kept[cbor.Cid().Hash().String()]; !ok || len(kept) != 1 {
+		t.Errorf("expected only %s to be reported as kept, got %v", cbor.Cid(), kept)
+	}
+	for _, c := range []cid.Cid{trailing.Cid(), raw.Cid(), pb.Cid()} {
+		if has, err := bs.Has(ctx, c); err != nil || has {
+			t.Errorf("expected %s to be removed, got %t, %v", c, has, err)
+		}
+		if _, ok := removed[c.Hash().String()]; !ok {
+			t.Errorf("expected %s to be reported as removed", c)
+		}
+	}
+}
+
+func TestNewKeepCodecs(t *testing.T) {
+	for _, names := range [][]string{{"raw"}, {"not-a-codec"}, {"sha2-256"}, {"dag-cbor", "raw"}} {
+		if _, err := NewKeepCodecs(names); err == nil {
+			t.Errorf("expected %v to be refused", names)
+		}
+	}
+	if _, err := NewKeepCodecs([]string{"dag-cbor", "dag-json", "dag-pb"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,227 @@
+package gc
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	bloom "github.com/ipfs/bbloom"
+	cid "github.com/ipfs/go-cid"
+	dstore "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	dshelp "github.com/ipfs/go-ipfs-ds-help"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// markedPrefix is where the marked set writes the multihashes it can't keep
+// in memory.
+var markedPrefix = dstore.NewKey("/local/gc/marked")
+
+const (
+	// markedBloomSpills is the number of spills the bloom filter of the
+	// written multihashes is sized for. Past it, more lookups go to the
+	// datastore.
+	markedBloomSpills         = 16
+	markedBloomFalsePositives = 0.01
+)
+
+// markedSet is the set of blocks marked by a garbage collection run. Blocks
+// are marked by multihash, as the blockstore stores them, along with the
+// codecs they were reached with: the same data reached with another codec
+// may have links to walk.
+//
+// Up to limit multihashes are kept in memory, or all of them when limit is
+// 0. When it's full, the multihashes in memory are written to the datastore
+// and added to a bloom filter, so that looking up the blocks that are not
+// marked seldom reads the datastore.
+type markedSet struct {
+	ctx   context.Context
+	ds    dstore.Datastore
+	limit int
+
+	mu    sync.Mutex
+	mem   map[string][]uint64
+	bloom *bloom.Bloom // nil until the first spill
+	err   error
+}
+
+func newMarkedSet(ctx context.Context, ds dstore.Datastore, limit int) (*markedSet, error) {
+	s := &markedSet{
+		ctx:   ctx,
+		ds:    ds,
+		limit: limit,
+		mem:   make(map[string][]uint64),
+	}
+	if limit > 0 {
+		// left by a run that didn't complete
+		if err := s.clear(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// visit marks c, and returns whether it wasn't marked yet.
+func (s *markedSet) visit(c cid.Cid) bool {
+	h, codec := string(c.Hash()), c.Type()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false
+	}
+
+	codecs, ok := s.mem[h]
+	if !ok && s.bloom != nil && s.bloom.Has([]byte(h)) {
+		codecs, s.err = s.load(h)
+		if s.err != nil {
+			return false
+		}
+	}
+	for _, marked := range codecs {
+		if marked == codec {
+			return false
+		}
+	}
+
+	s.mem[h] = append(codecs, codec)
+	if s.limit > 0 && len(s.mem) >= s.limit {
+		s.err = s.spill()
+	}
+	return true
+}
+
+// has returns whether the block with the multihash of k is marked, with any
+// codec.
+func (s *markedSet) has(k cid.Cid) (bool, error) {
+	h := k.Hash()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false, s.err
+	}
+
+	if _, ok := s.mem[string(h)]; ok {
+		return true, nil
+	}
+	if s.bloom == nil || !s.bloom.Has(h) {
+		return false, nil
+	}
+	return s.ds.Has(s.ctx, markedKey(h))
+}
+
+// forEach calls f with the CIDv1 of each block marked, with each codec it
+// was marked with. It must only be used when all the set is in memory.
+func (s *markedSet) forEach(f func(cid.Cid)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for h, codecs := range s.mem {
+		for _, codec := range codecs {
+			f(cid.NewCidV1(codec, mh.Multihash(h)))
+		}
+	}
+}
+
+// failed returns the error the set failed with, if any.
+func (s *markedSet) failed() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// close removes the multihashes written to the datastore, even when the run
+// was cancelled.
+func (s *markedSet) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mem = nil
+	if s.bloom == nil {
+		return nil
+	}
+	return s.clear(context.Background())
+}
+
+func markedKey(h mh.Multihash) dstore.Key {
+	return markedPrefix.Child(dshelp.MultihashToDsKey(h))
+}
+
+func (s *markedSet) load(h string) ([]uint64, error) {
+	val, err := s.ds.Get(s.ctx, markedKey(mh.Multihash(h)))
+	if err == dstore.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var codecs []uint64
+	for len(val) > 0 {
+		codec, n := binary.Uvarint(val)
+		if n <= 0 {
+			break
+		}
+		codecs = append(codecs, codec)
+		val = val[n:]
+	}
+	return codecs, nil
+}
+
+// spill writes the multihashes in memory to the datastore.
+func (s *markedSet) spill() error {
+	if s.bloom == nil {
+		var err error
+		s.bloom, err = bloom.New(float64(s.limit*markedBloomSpills), markedBloomFalsePositives)
+		if err != nil {
+			return err
+		}
+	}
+
+	var w dstore.Write = s.ds
+	var batch dstore.Batch
+	if bds, ok := s.ds.(dstore.Batching); ok {
+		var err error
+		if batch, err = bds.Batch(s.ctx); err != nil {
+			return err
+		}
+		w = batch
+	}
+
+	for h, codecs := range s.mem {
+		val := make([]byte, 0, len(codecs)*binary.MaxVarintLen64)
+		for _, codec := range codecs {
+			n := binary.PutUvarint(val[len(val):cap(val)], codec)
+			val = val[:len(val)+n]
+		}
+		if err := w.Put(s.ctx, markedKey(mh.Multihash(h)), val); err != nil {
+			return err
+		}
+		s.bloom.Add([]byte(h))
+	}
+	if batch != nil {
+		if err := batch.Commit(s.ctx); err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("gc marked set: spilled %d multihashes to the datastore", len(s.mem))
+	s.mem = make(map[string][]uint64, s.limit)
+	return nil
+}
+
+// clear removes the multihashes written to the datastore.
+func (s *markedSet) clear(ctx context.Context) error {
+	res, err := s.ds.Query(ctx, dsq.Query{Prefix: markedPrefix.String(), KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+	for e := range res.Next() {
+		if e.Error != nil {
+			return e.Error
+		}
+		if err := s.ds.Delete(ctx, dstore.NewKey(e.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package gc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	dstore "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	dssync "github.com/ipfs/go-datastore/sync"
+	mh "github.com/multiformats/go-multihash"
+)
+
+func TestMarkedSetSpill(t *testing.T) {
+	ctx := context.Background()
+	ds := dssync.MutexWrap(dstore.NewMapDatastore())
+	marked, err := newMarkedSet(ctx, ds, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cids []cid.Cid
+	for i := 0; i < 10; i++ {
+		h, err := mh.Sum([]byte(fmt.Sprint(i)), mh.SHA2_256, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := cid.NewCidV1(cid.DagProtobuf, h)
+		cids = append(cids, c)
+		if !marked.visit(c) {
+			t.Fatalf("expected %s not to be marked yet", c)
+		}
+	}
+	if marked.bloom == nil {
+		t.Fatal("expected the marked set to have been written to the datastore")
+	}
+
+	for _, c := range cids {
+		if marked.visit(c) {
+			t.Fatalf("expected %s to be marked", c)
+		}
+		if has, err := marked.has(cid.NewCidV1(cid.Raw, c.Hash())); err != nil || !has {
+			t.Fatalf("expected the block of %s to be marked, got %t, %v", c, has, err)
+		}
+	}
+	// the same data reached with another codec is walked again
+	if !marked.visit(cid.NewCidV1(cid.DagCBOR, cids[0].Hash())) {
+		t.Fatal("expected the block reached with another codec not to be marked yet")
+	}
+
+	h, _ := mh.Sum([]byte("unmarked"), mh.SHA2_256, -1)
+	if has, err := marked.has(cid.NewCidV1(cid.Raw, h)); err != nil || has {
+		t.Fatalf("expected the block not to be marked, got %t, %v", has, err)
+	}
+
+	if err := marked.close(); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ds.Query(ctx, dsq.Query{Prefix: markedPrefix.String(), KeysOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := res.Rest(); len(left) != 0 {
+		t.Fatalf("expected the marked set to be removed from the datastore, %d entries left", len(left))
+	}
+}
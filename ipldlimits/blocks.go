@@ -0,0 +1,122 @@
+package ipldlimits
+
+import (
+	"context"
+	"fmt"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	exchange "github.com/ipfs/go-ipfs-exchange-interface"
+)
+
+// WrapBlocks returns bs, refusing the blocks over the block size limit before
+// they are decoded. The blocks of the blockstore are refused from their size,
+// without being read.
+func (l *Limits) WrapBlocks(bs blockservice.BlockService) blockservice.BlockService {
+	if l == nil || l.MaxBlockSize == 0 {
+		return bs
+	}
+	var rem exchange.Interface
+	if e := bs.Exchange(); e != nil {
+		rem = l.wrapExchange(e)
+	}
+	return blockservice.New(&limitedBlockstore{Blockstore: bs.Blockstore(), l: l}, rem)
+}
+
+// checkSize returns an error if a block of size bytes is over the block size
+// limit.
+func (l *Limits) checkSize(c cid.Cid, size int) error {
+	if size > l.MaxBlockSize {
+		return fmt.Errorf("%w: %s is %d bytes, more than IPLD.MaxBlockSize", ErrLimit, c, size)
+	}
+	return nil
+}
+
+type limitedBlockstore struct {
+	blockstore.Blockstore
+	l *Limits
+}
+
+func (bs *limitedBlockstore) Get(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	size, err := bs.Blockstore.GetSize(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.l.checkSize(c, size); err != nil {
+		return nil, err
+	}
+	return bs.Blockstore.Get(ctx, c)
+}
+
+func (l *Limits) wrapExchange(e exchange.Interface) exchange.Interface {
+	le := &limitedExchange{Interface: e, fetcher: limitedFetcher{Fetcher: e, l: l}}
+	if se, ok := e.(exchange.SessionExchange); ok {
+		return &limitedSessionExchange{limitedExchange: le, se: se}
+	}
+	return le
+}
+
+// limitedFetcher refuses the blocks fetched over the block size limit.
+type limitedFetcher struct {
+	exchange.Fetcher
+	l *Limits
+}
+
+func (f limitedFetcher) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	b, err := f.Fetcher.GetBlock(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.l.checkSize(c, len(b.RawData())); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetBlocks leaves out the blocks over the limit, like the blocks not found.
+func (f limitedFetcher) GetBlocks(ctx context.Context, cids []cid.Cid) (<-chan blocks.Block, error) {
+	in, err := f.Fetcher.GetBlocks(ctx, cids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan blocks.Block)
+	go func() {
+		defer close(out)
+		for b := range in {
+			if err := f.l.checkSize(b.Cid(), len(b.RawData())); err != nil {
+				log.Warn(err)
+				continue
+			}
+			select {
+			case out <- b:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+type limitedExchange struct {
+	exchange.Interface
+	fetcher limitedFetcher
+}
+
+func (e *limitedExchange) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	return e.fetcher.GetBlock(ctx, c)
+}
+
+func (e *limitedExchange) GetBlocks(ctx context.Context, cids []cid.Cid) (<-chan blocks.Block, error) {
+	return e.fetcher.GetBlocks(ctx, cids)
+}
+
+type limitedSessionExchange struct {
+	*limitedExchange
+	se exchange.SessionExchange
+}
+
+func (e *limitedSessionExchange) NewSession(ctx context.Context) exchange.Fetcher {
+	return limitedFetcher{Fetcher: e.se.NewSession(ctx), l: e.fetcher.l}
+}
@@ -0,0 +1,201 @@
+// Package ipldlimits enforces the limits of the IPLD config section on the
+// blocks fetched and the nodes decoded by the DAG layer, and on the depth of
+// the paths and DAGs traversed.
+package ipldlimits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	humanize "github.com/dustin/go-humanize"
+	cid "github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	logging "github.com/ipfs/go-log"
+	"github.com/ipfs/go-merkledag"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+var log = logging.Logger("ipldlimits")
+
+// ErrLimit is wrapped by the errors of the nodes and DAGs over a limit.
+var ErrLimit = errors.New("IPLD limit exceeded")
+
+// Limits are the limits of the DAGs, zero meaning unlimited.
+type Limits struct {
+	MaxBlockSize int
+	MaxLinks     int
+	MaxDepth     int
+}
+
+// FromConfig returns the limits of cfg, or nil when nothing is limited.
+func FromConfig(cfg config.IPLD) (*Limits, error) {
+	var l Limits
+	if s := cfg.MaxBlockSize.WithDefault(""); s != "" {
+		size, err := humanize.ParseBytes(s)
+		if err != nil {
+			return nil, fmt.Errorf("IPLD.MaxBlockSize: %w", err)
+		}
+		l.MaxBlockSize = int(size)
+	}
+	maxLinks := cfg.MaxLinks.WithDefault(0)
+	maxDepth := cfg.MaxDepth.WithDefault(0)
+	if maxLinks < 0 || maxDepth < 0 {
+		return nil, errors.New("IPLD: limits must be positive")
+	}
+	l.MaxLinks = int(maxLinks)
+	l.MaxDepth = int(maxDepth)
+
+	if l == (Limits{}) {
+		return nil, nil
+	}
+	return &l, nil
+}
+
+// Check returns an error if nd is over the link limit. The block size limit
+// is checked on the blocks, see WrapBlocks.
+func (l *Limits) Check(nd format.Node) error {
+	if l == nil {
+		return nil
+	}
+	if l.MaxLinks > 0 && len(nd.Links()) > l.MaxLinks {
+		return fmt.Errorf("%w: %s has %d links, more than IPLD.MaxLinks", ErrLimit, nd.Cid(), len(nd.Links()))
+	}
+	return nil
+}
+
+// CheckPathDepth returns an error if a path following depth links is over
+// the depth limit.
+func (l *Limits) CheckPathDepth(depth int) error {
+	if l == nil || l.MaxDepth == 0 || depth <= l.MaxDepth {
+		return nil
+	}
+	return fmt.Errorf("%w: path of depth %d, more than IPLD.MaxDepth", ErrLimit, depth)
+}
+
+type depthKey struct{}
+
+// depthTracker tracks the depth of the nodes of a DAG as they are fetched.
+type depthTracker struct {
+	max int
+
+	mu     sync.Mutex
+	depths map[string]int
+}
+
+// WithDepthLimit returns ctx in which the nodes of the DAG under root fetched
+// with a DAGService wrapped with l are refused past the depth limit. This
+// checks the depth of a DAG while it is walked, e.g. when it is pinned,
+// instead of walking it once more.
+func (l *Limits) WithDepthLimit(ctx context.Context, root cid.Cid) context.Context {
+	if l == nil || l.MaxDepth == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, depthKey{}, &depthTracker{
+		max:    l.MaxDepth,
+		depths: map[string]int{root.KeyString(): 0},
+	})
+}
+
+// checkDepth returns an error if nd is deeper than the depth limit of ctx in
+// its DAG, and records the depth of its links.
+func checkDepth(ctx context.Context, nd format.Node) error {
+	t, ok := ctx.Value(depthKey{}).(*depthTracker)
+	if !ok {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	depth, ok := t.depths[nd.Cid().KeyString()]
+	if !ok {
+		// not in the DAG
+		return nil
+	}
+	if depth > t.max {
+		return fmt.Errorf("%w: %s is deeper than IPLD.MaxDepth", ErrLimit, nd.Cid())
+	}
+	for _, l := range nd.Links() {
+		k := l.Cid.KeyString()
+		if old, ok := t.depths[k]; !ok || depth+1 < old {
+			t.depths[k] = depth + 1
+		}
+	}
+	return nil
+}
+
+// Wrap returns ds, checking the nodes it returns against the link limit, and
+// the depth limit set with WithDepthLimit.
+func (l *Limits) Wrap(ds format.DAGService) format.DAGService {
+	if l == nil || (l.MaxLinks == 0 && l.MaxDepth == 0) {
+		return ds
+	}
+	return &dagService{DAGService: ds, getter: nodeGetter{NodeGetter: ds, l: l}}
+}
+
+type nodeGetter struct {
+	format.NodeGetter
+	l *Limits
+}
+
+func (ng nodeGetter) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	nd, err := ng.NodeGetter.Get(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := ng.check(ctx, nd); err != nil {
+		return nil, err
+	}
+	return nd, nil
+}
+
+func (ng nodeGetter) GetMany(ctx context.Context, cids []cid.Cid) <-chan *format.NodeOption {
+	out := make(chan *format.NodeOption, len(cids))
+	go func() {
+		defer close(out)
+		for opt := range ng.NodeGetter.GetMany(ctx, cids) {
+			if opt.Err == nil {
+				if err := ng.check(ctx, opt.Node); err != nil {
+					opt = &format.NodeOption{Err: err}
+				}
+			}
+			select {
+			case out <- opt:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func (ng nodeGetter) check(ctx context.Context, nd format.Node) error {
+	if err := ng.l.Check(nd); err != nil {
+		return err
+	}
+	return checkDepth(ctx, nd)
+}
+
+type dagService struct {
+	format.DAGService
+	getter nodeGetter
+}
+
+func (ds *dagService) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	return ds.getter.Get(ctx, c)
+}
+
+func (ds *dagService) GetMany(ctx context.Context, cids []cid.Cid) <-chan *format.NodeOption {
+	return ds.getter.GetMany(ctx, cids)
+}
+
+// Session keeps the sessions of the wrapped DAGService, see
+// merkledag.NewSession.
+func (ds *dagService) Session(ctx context.Context) format.NodeGetter {
+	sm, ok := ds.DAGService.(merkledag.SessionMaker)
+	if !ok {
+		return ds
+	}
+	return nodeGetter{NodeGetter: sm.Session(ctx), l: ds.getter.l}
+}
@@ -0,0 +1,183 @@
+package ipldlimits
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	mdtest "github.com/ipfs/go-merkledag/test"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+func TestFromConfig(t *testing.T) {
+	l, err := FromConfig(config.IPLD{})
+	if err != nil || l != nil {
+		t.Fatalf("expected no limits, got %v, %v", l, err)
+	}
+	l, err = FromConfig(config.IPLD{MaxBlockSize: config.NewOptionalString("1KiB"), MaxLinks: config.NewOptionalInteger(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *l != (Limits{MaxBlockSize: 1024, MaxLinks: 10}) {
+		t.Fatalf("unexpected limits %+v", *l)
+	}
+	for i, cfg := range []config.IPLD{
+		{MaxBlockSize: config.NewOptionalString("lots")},
+		{MaxDepth: config.NewOptionalInteger(-1)},
+	} {
+		if _, err := FromConfig(cfg); err == nil {
+			t.Errorf("expected an error for the config %d", i)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	ctx := context.Background()
+	ds := mdtest.Mock()
+
+	small := merkledag.NodeWithData([]byte("small"))
+	parent := merkledag.NodeWithData(nil)
+	for i := 0; i < 3; i++ {
+		if err := parent.AddNodeLink(string(rune('a'+i)), small); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ds.AddMany(ctx, []format.Node{small, parent}); err != nil {
+		t.Fatal(err)
+	}
+
+	l := &Limits{MaxLinks: 2}
+	lds := l.Wrap(ds)
+	if _, err := lds.Get(ctx, small.Cid()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lds.Get(ctx, parent.Cid()); !errors.Is(err, ErrLimit) {
+		t.Fatalf("expected the link limit, got %v", err)
+	}
+	if _, err := merkledag.NewSession(ctx, lds).Get(ctx, parent.Cid()); !errors.Is(err, ErrLimit) {
+		t.Fatalf("expected the link limit in sessions, got %v", err)
+	}
+	var nilLimits *Limits
+	if nilLimits.Wrap(ds) != ds {
+		t.Fatal("expected nil limits not to wrap")
+	}
+}
+
+// countingBlockstore counts the blocks read from it.
+type countingBlockstore struct {
+	blockstore.Blockstore
+	gets int
+}
+
+func (bs *countingBlockstore) Get(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	bs.gets++
+	return bs.Blockstore.Get(ctx, c)
+}
+
+func TestWrapBlocks(t *testing.T) {
+	ctx := context.Background()
+	bstore := &countingBlockstore{Blockstore: blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))}
+	bserv := blockservice.New(bstore, offline.Exchange(bstore))
+
+	big := merkledag.NodeWithData(make([]byte, 2048))
+	small := merkledag.NodeWithData([]byte("small"))
+	for _, nd := range []format.Node{big, small} {
+		if err := bstore.Put(ctx, nd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := &Limits{MaxBlockSize: 1024}
+	lds := merkledag.NewDAGService(l.WrapBlocks(bserv))
+	if _, err := lds.Get(ctx, small.Cid()); err != nil {
+		t.Fatal(err)
+	}
+	bstore.gets = 0
+	if _, err := lds.Get(ctx, big.Cid()); !errors.Is(err, ErrLimit) {
+		t.Fatalf("expected the block size limit, got %v", err)
+	}
+	if bstore.gets != 0 {
+		t.Fatal("expected the block over the limit not to be read")
+	}
+	if _, err := merkledag.NewSession(ctx, lds).Get(ctx, big.Cid()); !errors.Is(err, ErrLimit) {
+		t.Fatalf("expected the block size limit in sessions, got %v", err)
+	}
+
+	// the exchange refuses the blocks over the limit too
+	fetched := blocks.NewBlock(make([]byte, 2048))
+	ex := l.wrapExchange(testExchange{fetched})
+	if _, err := ex.GetBlock(ctx, fetched.Cid()); !errors.Is(err, ErrLimit) {
+		t.Fatalf("expected the block size limit in the exchange, got %v", err)
+	}
+
+	var nilLimits *Limits
+	if nilLimits.WrapBlocks(bserv) != bserv {
+		t.Fatal("expected nil limits not to wrap")
+	}
+}
+
+// testExchange returns its block.
+type testExchange struct {
+	blocks.Block
+}
+
+func (e testExchange) GetBlock(context.Context, cid.Cid) (blocks.Block, error) {
+	return e.Block, nil
+}
+
+func (e testExchange) GetBlocks(context.Context, []cid.Cid) (<-chan blocks.Block, error) {
+	ch := make(chan blocks.Block, 1)
+	ch <- e.Block
+	close(ch)
+	return ch, nil
+}
+
+func (testExchange) HasBlock(context.Context, blocks.Block) error { return nil }
+func (testExchange) IsOnline() bool                               { return true }
+func (testExchange) Close() error                                 { return nil }
+
+func TestWithDepthLimit(t *testing.T) {
+	ctx := context.Background()
+	ds := mdtest.Mock()
+
+	// a chain of 4 links
+	nd := merkledag.NodeWithData([]byte("leaf"))
+	if err := ds.Add(ctx, nd); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		parent := merkledag.NodeWithData(nil)
+		if err := parent.AddNodeLink("next", nd); err != nil {
+			t.Fatal(err)
+		}
+		if err := ds.Add(ctx, parent); err != nil {
+			t.Fatal(err)
+		}
+		nd = parent
+	}
+
+	l := &Limits{MaxDepth: 4}
+	if err := merkledag.FetchGraph(l.WithDepthLimit(ctx, nd.Cid()), nd.Cid(), l.Wrap(ds)); err != nil {
+		t.Fatal(err)
+	}
+	l = &Limits{MaxDepth: 3}
+	if err := merkledag.FetchGraph(l.WithDepthLimit(ctx, nd.Cid()), nd.Cid(), l.Wrap(ds)); !errors.Is(err, ErrLimit) {
+		t.Fatalf("expected the depth limit, got %v", err)
+	}
+	if err := merkledag.FetchGraph(ctx, nd.Cid(), l.Wrap(ds)); err != nil {
+		t.Fatalf("expected the depth to be limited only with WithDepthLimit, got %v", err)
+	}
+	if err := l.CheckPathDepth(4); !errors.Is(err, ErrLimit) {
+		t.Fatalf("expected the depth limit for paths, got %v", err)
+	}
+}
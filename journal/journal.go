@@ -0,0 +1,226 @@
+// Package journal keeps a persistent record of significant node events in
+// the repo datastore.
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	logging "github.com/ipfs/go-log"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+var log = logging.Logger("journal")
+
+// Event types recorded in the journal.
+const (
+	TypePin        = "pin"
+	TypePublish    = "publish"
+	TypeGC         = "gc"
+	TypeConfig     = "config"
+	TypeQuarantine = "quarantine"
+	TypeScan       = "scan"
+)
+
+const (
+	// DefaultMaxEntries is the default value of Journal.MaxEntries.
+	DefaultMaxEntries = 10000
+	// DefaultMaxAge is the default value of Journal.MaxAge.
+	DefaultMaxAge = 30 * 24 * time.Hour
+
+	// pruneInterval is the number of entries recorded between two
+	// enforcements of the retention limits.
+	pruneInterval = 100
+)
+
+// Prefix is the datastore key under which the journal entries are stored.
+var Prefix = datastore.NewKey("/journal")
+
+// Entry is a single event of the journal.
+type Entry struct {
+	Time   time.Time
+	Type   string
+	Fields map[string]string `json:",omitempty"`
+}
+
+// entryKey returns the key of the entry recorded at t, in nanoseconds since
+// the epoch. Keys are zero-padded so that they sort in time order.
+func entryKey(t int64) datastore.Key {
+	return Prefix.ChildString(fmt.Sprintf("%020d", t))
+}
+
+// Journal records events in the repo datastore. The entries are only
+// appended, and the oldest are removed to keep within the retention limits.
+//
+// A datastore holds the entries of a single journal, which keeps the keys of
+// its entries increasing from the last one recorded.
+//
+// New returns nil when the journal is disabled, and Record on it does
+// nothing, so the code recording events doesn't check for it.
+type Journal struct {
+	ds         datastore.Datastore
+	maxEntries int64
+	maxAge     time.Duration
+
+	lk       sync.Mutex
+	loaded   bool
+	last     int64
+	recorded int
+}
+
+// New returns the journal stored in d, or nil if the journal is disabled
+// in cfg.
+func New(d datastore.Datastore, cfg config.Journal) *Journal {
+	if !cfg.Enabled.WithDefault(false) {
+		return nil
+	}
+	return &Journal{
+		ds:         d,
+		maxEntries: cfg.MaxEntries.WithDefault(DefaultMaxEntries),
+		maxAge:     cfg.MaxAge.WithDefault(DefaultMaxAge),
+	}
+}
+
+// Record appends an event of the given type to the journal. Failing to
+// record an event is logged, it never fails the operation that caused it.
+func (j *Journal) Record(ctx context.Context, typ string, fields map[string]string) {
+	if j == nil {
+		return
+	}
+	if err := j.record(ctx, typ, fields); err != nil {
+		log.Errorf("recording %s event: %s", typ, err)
+	}
+}
+
+func (j *Journal) record(ctx context.Context, typ string, fields map[string]string) error {
+	j.lk.Lock()
+	defer j.lk.Unlock()
+
+	if !j.loaded {
+		last, err := j.lastRecorded(ctx)
+		if err != nil {
+			return err
+		}
+		j.last, j.loaded = last, true
+	}
+	t := time.Now().UnixNano()
+	if t <= j.last {
+		t = j.last + 1
+	}
+	j.last = t
+
+	b, err := json.Marshal(&Entry{Time: time.Unix(0, t), Type: typ, Fields: fields})
+	if err != nil {
+		return err
+	}
+	if err := j.ds.Put(ctx, entryKey(t), b); err != nil {
+		return err
+	}
+
+	if j.recorded%pruneInterval == 0 {
+		if err := j.prune(ctx); err != nil {
+			return fmt.Errorf("pruning the journal: %w", err)
+		}
+	}
+	j.recorded++
+	return nil
+}
+
+// lastRecorded returns the time of the last entry of the datastore, in
+// nanoseconds since the epoch, or 0 when it has none.
+func (j *Journal) lastRecorded(ctx context.Context) (int64, error) {
+	res, err := j.ds.Query(ctx, query.Query{
+		Prefix:   Prefix.String(),
+		KeysOnly: true,
+		Orders:   []query.Order{query.OrderByKeyDescending{}},
+		Limit:    1,
+	})
+	if err != nil {
+		return 0, err
+	}
+	entries, err := res.Rest()
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+	var t int64
+	if _, err := fmt.Sscanf(datastore.RawKey(entries[0].Key).BaseNamespace(), "%d", &t); err != nil {
+		return 0, fmt.Errorf("invalid journal entry %s: %w", entries[0].Key, err)
+	}
+	return t, nil
+}
+
+// prune removes the entries over the retention limits.
+func (j *Journal) prune(ctx context.Context) error {
+	res, err := j.ds.Query(ctx, query.Query{
+		Prefix:   Prefix.String(),
+		KeysOnly: true,
+		Orders:   []query.Order{query.OrderByKey{}},
+	})
+	if err != nil {
+		return err
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return err
+	}
+
+	excess := int64(len(entries)) - j.maxEntries
+	cutoff := entryKey(time.Now().Add(-j.maxAge).UnixNano()).String()
+	for i, e := range entries {
+		if int64(i) >= excess && e.Key >= cutoff {
+			break
+		}
+		if err := j.ds.Delete(ctx, datastore.RawKey(e.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns the entries of the journal stored in d recorded at or after
+// since, oldest first. When types is not empty, only the entries of these
+// types are returned.
+func Query(ctx context.Context, d datastore.Datastore, since time.Time, types []string) ([]Entry, error) {
+	var from string
+	if !since.IsZero() {
+		from = entryKey(since.UnixNano()).String()
+	}
+	typeSet := make(map[string]struct{}, len(types))
+	for _, t := range types {
+		typeSet[t] = struct{}{}
+	}
+
+	res, err := d.Query(ctx, query.Query{
+		Prefix: Prefix.String(),
+		Orders: []query.Order{query.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var entries []Entry
+	for r := range res.Next() {
+		if r.Error != nil {
+			return nil, r.Error
+		}
+		if r.Key < from {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(r.Value, &e); err != nil {
+			return nil, fmt.Errorf("invalid journal entry %s: %w", r.Key, err)
+		}
+		if _, ok := typeSet[e.Type]; len(typeSet) > 0 && !ok {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
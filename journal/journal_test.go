@@ -0,0 +1,131 @@
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+func TestDisabled(t *testing.T) {
+	ctx := context.Background()
+	d := dssync.MutexWrap(datastore.NewMapDatastore())
+
+	j := New(d, config.Journal{})
+	if j != nil {
+		t.Fatal("expected a nil journal when disabled")
+	}
+	j.Record(ctx, TypePin, nil)
+
+	entries, err := Query(ctx, d, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestRecordQuery(t *testing.T) {
+	ctx := context.Background()
+	d := dssync.MutexWrap(datastore.NewMapDatastore())
+	j := New(d, config.Journal{Enabled: config.True})
+
+	j.Record(ctx, TypePin, map[string]string{"op": "add"})
+	j.Record(ctx, TypeGC, nil)
+	since := time.Now()
+	j.Record(ctx, TypePin, map[string]string{"op": "rm"})
+
+	entries, err := Query(ctx, d, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Type != TypePin || entries[1].Type != TypeGC || entries[2].Fields["op"] != "rm" {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+
+	entries, err = Query(ctx, d, time.Time{}, []string{TypePin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 pin entries, got %d", len(entries))
+	}
+
+	entries, err = Query(ctx, d, since, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Fields["op"] != "rm" {
+		t.Fatalf("expected the last entry only, got %v", entries)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	ctx := context.Background()
+	d := dssync.MutexWrap(datastore.NewMapDatastore())
+
+	// An old entry, past MaxAge.
+	if err := d.Put(ctx, entryKey(time.Now().Add(-2*time.Hour).UnixNano()), []byte(`{"Type":"gc"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	j := New(d, config.Journal{
+		Enabled:    config.True,
+		MaxEntries: config.NewOptionalInteger(5),
+		MaxAge:     config.NewOptionalDuration(time.Hour),
+	})
+	for i := 0; i < pruneInterval+1; i++ {
+		j.Record(ctx, TypePin, nil)
+	}
+
+	entries, err := Query(ctx, d, time.Time{}, []string{TypeGC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatal("expected the entry past MaxAge to be pruned")
+	}
+
+	// Pruned on the first and the last record.
+	entries, err = Query(ctx, d, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+}
+
+func TestRecordAfterLast(t *testing.T) {
+	ctx := context.Background()
+	d := dssync.MutexWrap(datastore.NewMapDatastore())
+
+	// An entry recorded ahead of the clock, by a previous journal.
+	ahead := time.Now().Add(time.Hour).UnixNano()
+	if err := d.Put(ctx, entryKey(ahead), []byte(`{"Type":"gc"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	j := New(d, config.Journal{Enabled: config.True})
+	j.Record(ctx, TypePin, nil)
+	j.Record(ctx, TypePin, nil)
+
+	entries, err := Query(ctx, d, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Type != TypeGC {
+		t.Fatalf("expected the entries to be recorded after the last one, got %v", entries)
+	}
+	if j.last != ahead+2 {
+		t.Fatalf("expected the keys to follow the last one, got %d after %d", j.last, ahead)
+	}
+}
@@ -0,0 +1,83 @@
+package p2p
+
+import (
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	prometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Directions of the streams, in the metrics.
+const (
+	directionInbound  = "inbound"
+	directionOutbound = "outbound"
+)
+
+var (
+	streamsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ipfs",
+			Subsystem: "p2p",
+			Name:      "streams_total",
+			Help:      "Number of forwarded streams, by protocol, direction and whether they were opened, denied or over the limit.",
+		},
+		[]string{"protocol", "direction", "result"},
+	)
+	streamsActive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ipfs",
+			Subsystem: "p2p",
+			Name:      "streams_active",
+			Help:      "Number of open forwarded streams, by protocol and direction.",
+		},
+		[]string{"protocol", "direction"},
+	)
+	streamBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ipfs",
+			Subsystem: "p2p",
+			Name:      "stream_bytes_total",
+			Help:      "Bytes forwarded over streams, by protocol and whether they were received from or sent to the peers.",
+		},
+		[]string{"protocol", "direction"},
+	)
+)
+
+func registerMetrics() {
+	for _, c := range []prometheus.Collector{streamsTotal, streamsActive, streamBytes} {
+		if err := prometheus.Register(c); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				log.Errorf("registering p2p metrics: %s", err)
+			}
+		}
+	}
+}
+
+// Limits restrict the streams of a listener. The zero value lets any peer
+// open any number of streams, kept open while idle.
+type Limits struct {
+	// AllowedPeers are the only peers allowed to open streams to a remote
+	// listener, any peer when empty.
+	AllowedPeers []peer.ID
+
+	// MaxStreams is the largest number of streams of the listener open at
+	// once, no limit when zero.
+	MaxStreams int
+
+	// IdleTimeout closes the streams with no data forwarded either way for
+	// that long, never when zero.
+	IdleTimeout time.Duration
+}
+
+// allows returns whether p may open streams to the listener.
+func (l Limits) allows(p peer.ID) bool {
+	if len(l.AllowedPeers) == 0 {
+		return true
+	}
+	for _, allowed := range l.AllowedPeers {
+		if allowed == p {
+			return true
+		}
+	}
+	return false
+}
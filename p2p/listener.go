@@ -15,6 +15,7 @@ type Listener interface {
 	Protocol() protocol.ID
 	ListenAddress() ma.Multiaddr
 	TargetAddress() ma.Multiaddr
+	Limits() Limits
 
 	key() string
 
@@ -22,16 +22,19 @@ type localListener struct {
 	laddr ma.Multiaddr
 	peer  peer.ID
 
+	limits Limits
+
 	listener manet.Listener
 }
 
 // ForwardLocal creates new P2P stream to a remote listener
-func (p2p *P2P) ForwardLocal(ctx context.Context, peer peer.ID, proto protocol.ID, bindAddr ma.Multiaddr) (Listener, error) {
+func (p2p *P2P) ForwardLocal(ctx context.Context, peer peer.ID, proto protocol.ID, bindAddr ma.Multiaddr, limits Limits) (Listener, error) {
 	listener := &localListener{
-		ctx:   ctx,
-		p2p:   p2p,
-		proto: proto,
-		peer:  peer,
+		ctx:    ctx,
+		p2p:    p2p,
+		proto:  proto,
+		peer:   peer,
+		limits: limits,
 	}
 
 	maListener, err := manet.Listen(bindAddr)
@@ -73,8 +76,16 @@ func (l *localListener) acceptConns() {
 }
 
 func (l *localListener) setupStream(local manet.Conn) {
+	if !l.p2p.Streams.reserve(l, l.limits.MaxStreams) {
+		local.Close()
+		streamsTotal.WithLabelValues(string(l.proto), directionOutbound, "limited").Inc()
+		log.Debugf("too many streams to %s/%s", l.peer.Pretty(), l.proto)
+		return
+	}
+
 	remote, err := l.dial(l.ctx)
 	if err != nil {
+		l.p2p.Streams.release(l)
 		local.Close()
 		log.Warnf("failed to dial to remote %s/%s", l.peer.Pretty(), l.proto)
 		return
@@ -91,6 +102,10 @@ func (l *localListener) setupStream(local manet.Conn) {
 		Remote: remote,
 
 		Registry: l.p2p.Streams,
+
+		listener:    l,
+		direction:   directionOutbound,
+		idleTimeout: l.limits.IdleTimeout,
 	}
 
 	l.p2p.Streams.Register(stream)
@@ -104,6 +119,10 @@ func (l *localListener) Protocol() protocol.ID {
 	return l.proto
 }
 
+func (l *localListener) Limits() Limits {
+	return l.limits
+}
+
 func (l *localListener) ListenAddress() ma.Multiaddr {
 	return l.laddr
 }
@@ -22,6 +22,7 @@ type P2P struct {
 
 // New creates new P2P struct
 func New(identity peer.ID, peerHost p2phost.Host, peerstore pstore.Peerstore) *P2P {
+	registerMetrics()
 	return &P2P{
 		identity:  identity,
 		peerHost:  peerHost,
@@ -34,6 +35,7 @@ func New(identity peer.ID, peerHost p2phost.Host, peerstore pstore.Peerstore) *P
 			Streams:     map[uint64]*Stream{},
 			ConnManager: peerHost.ConnManager(),
 			conns:       map[peer.ID]int{},
+			active:      map[Listener]int{},
 		},
 	}
 }
@@ -25,10 +25,12 @@ type remoteListener struct {
 	// reportRemote if set to true makes the handler send '<base58 remote peerid>\n'
 	// to target before any data is forwarded
 	reportRemote bool
+
+	limits Limits
 }
 
 // ForwardRemote creates new p2p listener
-func (p2p *P2P) ForwardRemote(ctx context.Context, proto protocol.ID, addr ma.Multiaddr, reportRemote bool) (Listener, error) {
+func (p2p *P2P) ForwardRemote(ctx context.Context, proto protocol.ID, addr ma.Multiaddr, reportRemote bool, limits Limits) (Listener, error) {
 	listener := &remoteListener{
 		p2p: p2p,
 
@@ -36,6 +38,8 @@ func (p2p *P2P) ForwardRemote(ctx context.Context, proto protocol.ID, addr ma.Mu
 		addr:  addr,
 
 		reportRemote: reportRemote,
+
+		limits: limits,
 	}
 
 	if err := p2p.ListenersP2P.Register(listener); err != nil {
@@ -46,16 +50,31 @@ func (p2p *P2P) ForwardRemote(ctx context.Context, proto protocol.ID, addr ma.Mu
 }
 
 func (l *remoteListener) handleStream(remote net.Stream) {
+	peer := remote.Conn().RemotePeer()
+	if !l.limits.allows(peer) {
+		_ = remote.Reset()
+		streamsTotal.WithLabelValues(string(l.proto), directionInbound, "denied").Inc()
+		log.Debugf("denied stream of %s to %s", peer.Pretty(), l.proto)
+		return
+	}
+	if !l.p2p.Streams.reserve(l, l.limits.MaxStreams) {
+		_ = remote.Reset()
+		streamsTotal.WithLabelValues(string(l.proto), directionInbound, "limited").Inc()
+		log.Debugf("too many streams to %s, resetting the stream of %s", l.proto, peer.Pretty())
+		return
+	}
+
 	local, err := manet.Dial(l.addr)
 	if err != nil {
+		l.p2p.Streams.release(l)
 		_ = remote.Reset()
 		return
 	}
 
-	peer := remote.Conn().RemotePeer()
-
 	if l.reportRemote {
 		if _, err := fmt.Fprintf(local, "%s\n", peer.Pretty()); err != nil {
+			l.p2p.Streams.release(l)
+			_ = local.Close()
 			_ = remote.Reset()
 			return
 		}
@@ -63,6 +82,8 @@ func (l *remoteListener) handleStream(remote net.Stream) {
 
 	peerMa, err := ma.NewMultiaddr(maPrefix + peer.Pretty())
 	if err != nil {
+		l.p2p.Streams.release(l)
+		_ = local.Close()
 		_ = remote.Reset()
 		return
 	}
@@ -78,6 +99,10 @@ func (l *remoteListener) handleStream(remote net.Stream) {
 		Remote: remote,
 
 		Registry: l.p2p.Streams,
+
+		listener:    l,
+		direction:   directionInbound,
+		idleTimeout: l.limits.IdleTimeout,
 	}
 
 	l.p2p.Streams.Register(stream)
@@ -87,6 +112,10 @@ func (l *remoteListener) Protocol() protocol.ID {
 	return l.proto
 }
 
+func (l *remoteListener) Limits() Limits {
+	return l.limits
+}
+
 func (l *remoteListener) ListenAddress() ma.Multiaddr {
 	addr, err := ma.NewMultiaddr(maPrefix + l.p2p.identity.Pretty())
 	if err != nil {
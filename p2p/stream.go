@@ -3,6 +3,8 @@ package p2p
 import (
 	"io"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	ifconnmgr "github.com/libp2p/go-libp2p-core/connmgr"
 	net "github.com/libp2p/go-libp2p-core/network"
@@ -10,12 +12,18 @@ import (
 	protocol "github.com/libp2p/go-libp2p-core/protocol"
 	ma "github.com/multiformats/go-multiaddr"
 	manet "github.com/multiformats/go-multiaddr/net"
+	prometheus "github.com/prometheus/client_golang/prometheus"
 )
 
 const cmgrTag = "stream-fwd"
 
 // Stream holds information on active incoming and outgoing p2p streams.
 type Stream struct {
+	// accessed atomically, first for their alignment
+	bytesIn    int64
+	bytesOut   int64
+	lastActive int64
+
 	id uint64
 
 	Protocol protocol.ID
@@ -28,6 +36,34 @@ type Stream struct {
 	Remote net.Stream
 
 	Registry *StreamRegistry
+
+	listener    Listener
+	direction   string
+	idleTimeout time.Duration
+	done        chan struct{}
+}
+
+// Traffic returns the bytes received from and sent to the peer.
+func (s *Stream) Traffic() (in int64, out int64) {
+	return atomic.LoadInt64(&s.bytesIn), atomic.LoadInt64(&s.bytesOut)
+}
+
+// counter counts the bytes read through it as forwarded over s.
+type counter struct {
+	r      io.Reader
+	s      *Stream
+	n      *int64
+	metric prometheus.Counter
+}
+
+func (c *counter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		atomic.AddInt64(c.n, int64(n))
+		atomic.StoreInt64(&c.s.lastActive, time.Now().UnixNano())
+		c.metric.Add(float64(n))
+	}
+	return n, err
 }
 
 // close stream endpoints and deregister it
@@ -41,8 +77,16 @@ func (s *Stream) reset() {
 }
 
 func (s *Stream) startStreaming() {
+	atomic.StoreInt64(&s.lastActive, time.Now().UnixNano())
+	if s.idleTimeout > 0 {
+		go s.closeWhenIdle()
+	}
+
+	received := &counter{r: s.Remote, s: s, n: &s.bytesIn, metric: streamBytes.WithLabelValues(string(s.Protocol), "received")}
+	sent := &counter{r: s.Local, s: s, n: &s.bytesOut, metric: streamBytes.WithLabelValues(string(s.Protocol), "sent")}
+
 	go func() {
-		_, err := io.Copy(s.Local, s.Remote)
+		_, err := io.Copy(s.Local, received)
 		if err != nil {
 			s.reset()
 		} else {
@@ -51,7 +95,7 @@ func (s *Stream) startStreaming() {
 	}()
 
 	go func() {
-		_, err := io.Copy(s.Remote, s.Local)
+		_, err := io.Copy(s.Remote, sent)
 		if err != nil {
 			s.reset()
 		} else {
@@ -60,12 +104,34 @@ func (s *Stream) startStreaming() {
 	}()
 }
 
+// closeWhenIdle resets the stream once nothing was forwarded for its idle
+// timeout.
+func (s *Stream) closeWhenIdle() {
+	t := time.NewTimer(s.idleTimeout)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-s.done:
+			return
+		}
+		idle := time.Since(time.Unix(0, atomic.LoadInt64(&s.lastActive)))
+		if idle >= s.idleTimeout {
+			log.Debugf("closing stream %d of %s, idle for %s", s.id, s.Protocol, idle)
+			s.reset()
+			return
+		}
+		t.Reset(s.idleTimeout - idle)
+	}
+}
+
 // StreamRegistry is a collection of active incoming and outgoing proto app streams.
 type StreamRegistry struct {
 	sync.Mutex
 
 	Streams map[uint64]*Stream
 	conns   map[peer.ID]int
+	active  map[Listener]int
 	nextID  uint64
 
 	ifconnmgr.ConnManager
@@ -79,6 +145,12 @@ func (r *StreamRegistry) Register(streamInfo *Stream) {
 	r.ConnManager.TagPeer(streamInfo.peer, cmgrTag, 20)
 	r.conns[streamInfo.peer]++
 
+	streamInfo.done = make(chan struct{})
+	if streamInfo.direction != "" {
+		streamsTotal.WithLabelValues(string(streamInfo.Protocol), streamInfo.direction, "opened").Inc()
+		streamsActive.WithLabelValues(string(streamInfo.Protocol), streamInfo.direction).Inc()
+	}
+
 	streamInfo.id = r.nextID
 	r.Streams[r.nextID] = streamInfo
 	r.nextID++
@@ -101,10 +173,51 @@ func (r *StreamRegistry) Deregister(streamID uint64) {
 		delete(r.conns, p)
 		r.ConnManager.UntagPeer(p, cmgrTag)
 	}
+	if s.listener != nil {
+		r.releaseLocked(s.listener)
+	}
+	if s.direction != "" {
+		streamsActive.WithLabelValues(string(s.Protocol), s.direction).Dec()
+	}
+	close(s.done)
 
 	delete(r.Streams, streamID)
 }
 
+// Active returns the number of open streams of l.
+func (r *StreamRegistry) Active(l Listener) int {
+	r.Lock()
+	defer r.Unlock()
+	return r.active[l]
+}
+
+// reserve counts a stream of l about to be opened, unless l already has max
+// streams open. max is zero for no limit. The stream is counted until it is
+// deregistered, or released if it is not registered.
+func (r *StreamRegistry) reserve(l Listener, max int) bool {
+	r.Lock()
+	defer r.Unlock()
+	if max > 0 && r.active[l] >= max {
+		return false
+	}
+	r.active[l]++
+	return true
+}
+
+// release stops counting a stream reserved and not registered.
+func (r *StreamRegistry) release(l Listener) {
+	r.Lock()
+	defer r.Unlock()
+	r.releaseLocked(l)
+}
+
+func (r *StreamRegistry) releaseLocked(l Listener) {
+	r.active[l]--
+	if r.active[l] < 1 {
+		delete(r.active, l)
+	}
+}
+
 // Close stream endpoints and deregister it
 func (r *StreamRegistry) Close(s *Stream) {
 	_ = s.Local.Close()
@@ -0,0 +1,112 @@
+package p2p
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+func TestMaxStreams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mn := mocknet.New()
+	server, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the service the streams are forwarded to
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	conns := make(chan net.Conn, 8)
+	go func() {
+		for {
+			c, err := target.Accept()
+			if err != nil {
+				return
+			}
+			conns <- c
+		}
+	}()
+	targetAddr, err := manet.FromNetAddr(target.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const proto = protocol.ID("/x/test")
+	p2p := New(server.ID(), server, server.Peerstore())
+	l, err := p2p.ForwardRemote(ctx, proto, targetAddr, false, Limits{MaxStreams: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// open returns a stream forwarded to the service, or the error of the
+	// stream over the limit.
+	open := func() (network.Stream, error) {
+		t.Helper()
+		s, err := client.NewStream(ctx, server.ID(), proto)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Write([]byte("x")); err != nil {
+			return nil, err
+		}
+		select {
+		case c := <-conns:
+			t.Cleanup(func() { c.Close() })
+			return s, nil
+		case <-time.After(500 * time.Millisecond):
+		}
+		if _, err := s.Read(make([]byte, 1)); err != nil {
+			return nil, err
+		}
+		t.Fatal("expected the stream to be forwarded or reset")
+		return nil, nil
+	}
+
+	first, err := open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := open(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := open(); err == nil {
+		t.Fatal("expected the stream over the limit to be reset")
+	}
+	if active := p2p.Streams.Active(l); active != 2 {
+		t.Fatalf("expected 2 streams open, got %d", active)
+	}
+
+	// closing a stream releases its slot
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for p2p.Streams.Active(l) != 1 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected the closed stream to release its slot")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if _, err := open(); err != nil {
+		t.Fatalf("expected a stream to be forwarded once another one is closed, got %s", err)
+	}
+}
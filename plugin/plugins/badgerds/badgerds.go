@@ -125,3 +125,17 @@ func (c *datastoreConfig) Create(path string) (repo.Datastore, error) {
 
 	return badgerds.NewDatastore(p, &defopts)
 }
+
+func (c *datastoreConfig) CreateReadOnly(path string) (repo.Datastore, error) {
+	p := c.path
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(path, p)
+	}
+
+	defopts := badgerds.DefaultOptions
+	defopts.ReadOnly = true
+	defopts.Truncate = false
+	defopts.GcInterval = 0
+
+	return badgerds.NewDatastore(p, &defopts)
+}
@@ -0,0 +1,176 @@
+package flatfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ipfs/go-ipfs/repo"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	flatfs "github.com/ipfs/go-ds-flatfs"
+	"github.com/jbenet/goprocess"
+)
+
+const readOnlyExtension = ".data"
+
+var errReadOnly = errors.New("the flatfs datastore is opened read-only")
+
+// readOnlyDatastore reads the blocks of a flatfs datastore without writing to
+// its directory, which flatfs.Open does.
+type readOnlyDatastore struct {
+	path   string
+	getDir flatfs.ShardFunc
+}
+
+var _ repo.Datastore = (*readOnlyDatastore)(nil)
+
+func (c *datastoreConfig) CreateReadOnly(path string) (repo.Datastore, error) {
+	p := c.path
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(path, p)
+	}
+
+	shardFun, err := flatfs.ReadShardFunc(p)
+	if err != nil {
+		return nil, err
+	}
+	if shardFun.String() != c.shardFun.String() {
+		return nil, fmt.Errorf("the flatfs datastore at %s is sharded with %s, not %s", p, shardFun, c.shardFun)
+	}
+	return &readOnlyDatastore{path: p, getDir: shardFun.Func()}, nil
+}
+
+// file returns the file of key, or false when key can't be in the datastore.
+func (d *readOnlyDatastore) file(key ds.Key) (string, bool) {
+	noslash := key.String()[1:]
+	if noslash == "" || strings.ContainsAny(noslash, "/.") {
+		return "", false
+	}
+	return filepath.Join(d.path, d.getDir(noslash), noslash+readOnlyExtension), true
+}
+
+func (d *readOnlyDatastore) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	fn, ok := d.file(key)
+	if !ok {
+		return nil, ds.ErrNotFound
+	}
+	data, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil, ds.ErrNotFound
+	}
+	return data, err
+}
+
+func (d *readOnlyDatastore) Has(ctx context.Context, key ds.Key) (bool, error) {
+	_, err := d.GetSize(ctx, key)
+	if err == ds.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (d *readOnlyDatastore) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	fn, ok := d.file(key)
+	if !ok {
+		return -1, ds.ErrNotFound
+	}
+	switch st, err := os.Stat(fn); {
+	case err == nil:
+		return int(st.Size()), nil
+	case os.IsNotExist(err):
+		return -1, ds.ErrNotFound
+	default:
+		return -1, err
+	}
+}
+
+// Query lists the keys of the datastore like flatfs: they are all at the
+// root, so a query with another prefix has no results.
+func (d *readOnlyDatastore) Query(ctx context.Context, q query.Query) (query.Results, error) {
+	if ds.NewKey(q.Prefix).String() != "/" {
+		return query.ResultsWithEntries(q, nil), nil
+	}
+
+	b := query.NewResultBuilder(q)
+	b.Process.Go(func(p goprocess.Process) {
+		if err := d.walk(ctx, b); err != nil {
+			select {
+			case b.Output <- query.Result{Error: err}:
+			case <-p.Closing():
+			}
+		}
+	})
+	go b.Process.CloseAfterChildren() //nolint
+	return query.NaiveQueryApply(q, b.Results()), nil
+}
+
+func (d *readOnlyDatastore) walk(ctx context.Context, b *query.ResultBuilder) error {
+	dirs, err := ioutil.ReadDir(d.path)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() || strings.HasPrefix(dir.Name(), ".") {
+			continue
+		}
+		names, err := readDirNames(filepath.Join(d.path, dir.Name()))
+		if err != nil {
+			return err
+		}
+		for _, fn := range names {
+			if strings.HasPrefix(fn, ".") || !strings.HasSuffix(fn, readOnlyExtension) {
+				continue
+			}
+			key := ds.NewKey(strings.TrimSuffix(fn, readOnlyExtension))
+			r := query.Result{Entry: query.Entry{Key: key.String()}}
+			if !b.Query.KeysOnly {
+				r.Value, r.Error = d.Get(ctx, key)
+				r.Size = len(r.Value)
+			} else if b.Query.ReturnsSizes {
+				r.Size, r.Error = d.GetSize(ctx, key)
+			}
+
+			select {
+			case b.Output <- r:
+			case <-b.Process.Closing():
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func readDirNames(path string) ([]string, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	return dir.Readdirnames(-1)
+}
+
+func (d *readOnlyDatastore) Put(ctx context.Context, key ds.Key, value []byte) error {
+	return errReadOnly
+}
+
+func (d *readOnlyDatastore) Delete(ctx context.Context, key ds.Key) error {
+	return errReadOnly
+}
+
+func (d *readOnlyDatastore) Batch(ctx context.Context) (ds.Batch, error) {
+	return ds.NewBasicBatch(d), nil
+}
+
+func (d *readOnlyDatastore) Sync(ctx context.Context, prefix ds.Key) error {
+	return nil
+}
+
+func (d *readOnlyDatastore) Close() error {
+	return nil
+}
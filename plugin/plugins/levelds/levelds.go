@@ -86,3 +86,15 @@ func (c *datastoreConfig) Create(path string) (repo.Datastore, error) {
 		Compression: c.compression,
 	})
 }
+
+func (c *datastoreConfig) CreateReadOnly(path string) (repo.Datastore, error) {
+	p := c.path
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(path, p)
+	}
+
+	return levelds.NewDatastore(p, &levelds.Options{
+		Compression: c.compression,
+		ReadOnly:    true,
+	})
+}
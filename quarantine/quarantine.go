@@ -0,0 +1,274 @@
+// Package quarantine moves the corrupted blocks found by the DAG layer out
+// of the blockstore, and fetches them again from the network.
+package quarantine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	dshelp "github.com/ipfs/go-ipfs-ds-help"
+	format "github.com/ipfs/go-ipld-format"
+	legacy "github.com/ipfs/go-ipld-legacy"
+	logging "github.com/ipfs/go-log"
+	"github.com/ipfs/go-merkledag"
+
+	"github.com/ipfs/go-ipfs/journal"
+)
+
+var log = logging.Logger("quarantine")
+
+// Prefix is the datastore key under which the quarantined blocks are
+// stored.
+var Prefix = datastore.NewKey("/quarantine")
+
+// Entry is a quarantined block.
+type Entry struct {
+	Cid    cid.Cid
+	Time   time.Time
+	Reason string
+	// Refetched is whether a valid copy of the block was fetched again.
+	Refetched bool
+	Data      []byte `json:",omitempty"`
+}
+
+// Quarantine moves the blocks that fail validation from the blockstore to
+// the quarantine, so they are fetched again the next time they are needed.
+type Quarantine struct {
+	ds      datastore.Datastore
+	bs      blockstore.Blockstore
+	journal *journal.Journal
+}
+
+// New returns a quarantine for the blocks of bs, whose raw data is read
+// from, and quarantined in, the repo datastore d.
+func New(d datastore.Datastore, bs blockstore.Blockstore, j *journal.Journal) *Quarantine {
+	return &Quarantine{ds: d, bs: bs, journal: j}
+}
+
+// Wrap returns a DAGService quarantining the invalid blocks read through
+// ds, and fetching them again. With Datastore.Quarantine off, q is nil and
+// ds is returned as is.
+func (q *Quarantine) Wrap(ds format.DAGService) format.DAGService {
+	if q == nil {
+		return ds
+	}
+	return &dagService{DAGService: ds, getter: nodeGetter{NodeGetter: ds, q: q}}
+}
+
+// validate returns why data is not a valid block for c, or an empty string
+// if it is.
+func validate(ctx context.Context, c cid.Cid, data []byte) string {
+	sum, err := c.Prefix().Sum(data)
+	if err != nil {
+		return fmt.Sprintf("hashing: %s", err)
+	}
+	if !sum.Equals(c) {
+		return "hash mismatch"
+	}
+	blk, err := blocks.NewBlockWithCid(data, c)
+	if err != nil {
+		return err.Error()
+	}
+	if _, err := legacy.DecodeNode(ctx, blk); err != nil {
+		return fmt.Sprintf("decoding: %s", err)
+	}
+	return ""
+}
+
+// check is called when reading c failed with err. If the block is in the
+// blockstore and invalid, it moves it to the quarantine and returns true.
+func (q *Quarantine) check(ctx context.Context, c cid.Cid, err error) bool {
+	if format.IsNotFound(err) || ctx.Err() != nil {
+		return false
+	}
+
+	data, err := q.ds.Get(ctx, blockstore.BlockPrefix.Child(dshelp.MultihashToDsKey(c.Hash())))
+	if err != nil {
+		// not stored in the blockstore datastore (e.g. in the filestore)
+		return false
+	}
+	reason := validate(ctx, c, data)
+	if reason == "" {
+		return false
+	}
+
+	e := &Entry{Cid: c, Time: time.Now(), Reason: reason, Data: data}
+	if err := q.put(ctx, e); err != nil {
+		log.Errorf("quarantining %s: %s", c, err)
+		return false
+	}
+	if err := q.bs.DeleteBlock(ctx, c); err != nil {
+		log.Errorf("removing quarantined block %s: %s", c, err)
+		return false
+	}
+	log.Warnf("quarantined block %s: %s", c, reason)
+	return true
+}
+
+// refetched records the outcome of fetching again the quarantined block c.
+func (q *Quarantine) refetched(ctx context.Context, c cid.Cid, ok bool) {
+	e, err := q.get(ctx, c)
+	if err != nil {
+		log.Errorf("updating quarantined block %s: %s", c, err)
+		return
+	}
+	e.Refetched = ok
+	if err := q.put(ctx, e); err != nil {
+		log.Errorf("updating quarantined block %s: %s", c, err)
+	}
+
+	q.journal.Record(ctx, journal.TypeQuarantine, map[string]string{
+		"cid":       c.String(),
+		"reason":    e.Reason,
+		"refetched": fmt.Sprint(ok),
+	})
+}
+
+func (q *Quarantine) put(ctx context.Context, e *Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return q.ds.Put(ctx, Prefix.ChildString(e.Cid.String()), b)
+}
+
+func (q *Quarantine) get(ctx context.Context, c cid.Cid) (*Entry, error) {
+	b, err := q.ds.Get(ctx, Prefix.ChildString(c.String()))
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	return &e, json.Unmarshal(b, &e)
+}
+
+// List returns the blocks quarantined in d, without their data.
+func List(ctx context.Context, d datastore.Datastore) ([]Entry, error) {
+	res, err := d.Query(ctx, query.Query{
+		Prefix: Prefix.String(),
+		Orders: []query.Order{query.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var entries []Entry
+	for r := range res.Next() {
+		if r.Error != nil {
+			return nil, r.Error
+		}
+		var e Entry
+		if err := json.Unmarshal(r.Value, &e); err != nil {
+			return nil, fmt.Errorf("invalid quarantine entry %s: %w", r.Key, err)
+		}
+		e.Data = nil
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Remove deletes the quarantined block c from d.
+func Remove(ctx context.Context, d datastore.Datastore, c cid.Cid) error {
+	k := Prefix.ChildString(c.String())
+	has, err := d.Has(ctx, k)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return fmt.Errorf("%s is not quarantined", c)
+	}
+	return d.Delete(ctx, k)
+}
+
+type nodeGetter struct {
+	format.NodeGetter
+	q *Quarantine
+}
+
+func (ng nodeGetter) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	nd, err := ng.NodeGetter.Get(ctx, c)
+	if err == nil || !ng.q.check(ctx, c, err) {
+		return nd, err
+	}
+	nd, err = ng.NodeGetter.Get(ctx, c)
+	ng.q.refetched(ctx, c, err == nil)
+	return nd, err
+}
+
+// GetMany stops at the first node failing to be read, without telling which
+// one, like merkledag's. The nodes not returned yet are then read one by one,
+// so that a bad block is quarantined and reported on its own, without losing
+// the others.
+func (ng nodeGetter) GetMany(ctx context.Context, cids []cid.Cid) <-chan *format.NodeOption {
+	out := make(chan *format.NodeOption, len(cids))
+	go func() {
+		defer close(out)
+		pending := cid.NewSet()
+		for _, c := range cids {
+			pending.Add(c)
+		}
+
+		batchCtx, cancel := context.WithCancel(ctx)
+		failed := false
+		for opt := range ng.NodeGetter.GetMany(batchCtx, cids) {
+			if opt.Err != nil {
+				// drain the nodes still coming, then read the rest alone
+				failed = true
+				cancel()
+				continue
+			}
+			if pending.Has(opt.Node.Cid()) {
+				pending.Remove(opt.Node.Cid())
+				out <- opt
+			}
+		}
+		cancel()
+		if !failed {
+			return
+		}
+
+		for _, c := range cids {
+			if !pending.Has(c) {
+				continue
+			}
+			pending.Remove(c)
+			if ctx.Err() != nil {
+				out <- &format.NodeOption{Err: ctx.Err()}
+				return
+			}
+			nd, err := ng.Get(ctx, c)
+			out <- &format.NodeOption{Node: nd, Err: err}
+		}
+	}()
+	return out
+}
+
+type dagService struct {
+	format.DAGService
+	getter nodeGetter
+}
+
+func (ds *dagService) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	return ds.getter.Get(ctx, c)
+}
+
+func (ds *dagService) GetMany(ctx context.Context, cids []cid.Cid) <-chan *format.NodeOption {
+	return ds.getter.GetMany(ctx, cids)
+}
+
+// Session keeps the sessions of the wrapped DAGService, see
+// merkledag.NewSession.
+func (ds *dagService) Session(ctx context.Context) format.NodeGetter {
+	sm, ok := ds.DAGService.(merkledag.SessionMaker)
+	if !ok {
+		return ds
+	}
+	return nodeGetter{NodeGetter: sm.Session(ctx), q: ds.getter.q}
+}
@@ -0,0 +1,135 @@
+package quarantine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	dshelp "github.com/ipfs/go-ipfs-ds-help"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	"github.com/ipfs/go-merkledag"
+)
+
+func TestQuarantine(t *testing.T) {
+	ctx := context.Background()
+	d := dssync.MutexWrap(datastore.NewMapDatastore())
+	bs := blockstore.NewBlockstore(d)
+	dag := merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+
+	nd := merkledag.NodeWithData([]byte("hello"))
+	if err := dag.Add(ctx, nd); err != nil {
+		t.Fatal(err)
+	}
+	good := merkledag.NodeWithData([]byte("world"))
+	if err := dag.Add(ctx, good); err != nil {
+		t.Fatal(err)
+	}
+
+	// corrupt the stored block
+	key := blockstore.BlockPrefix.Child(dshelp.MultihashToDsKey(nd.Cid().Hash()))
+	if err := d.Put(ctx, key, []byte("garbage")); err != nil {
+		t.Fatal(err)
+	}
+
+	q := New(d, bs, nil)
+	qdag := q.Wrap(dag)
+
+	if _, err := qdag.Get(ctx, good.Cid()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qdag.Get(ctx, nd.Cid()); err == nil {
+		t.Fatal("expected an error reading the corrupted block offline")
+	}
+
+	has, err := bs.Has(ctx, nd.Cid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has {
+		t.Fatal("expected the corrupted block to be removed from the blockstore")
+	}
+
+	entries, err := List(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 quarantined block, got %d", len(entries))
+	}
+	e := entries[0]
+	if !e.Cid.Equals(nd.Cid()) || e.Reason != "hash mismatch" || e.Refetched || e.Data != nil {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+
+	// a valid copy is stored again
+	if err := dag.Add(ctx, nd); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qdag.Get(ctx, nd.Cid()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Remove(ctx, d, nd.Cid()); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(ctx, d, nd.Cid()); err == nil {
+		t.Fatal("expected an error removing a block that is not quarantined")
+	}
+	entries, err = List(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no quarantined blocks, got %d", len(entries))
+	}
+}
+
+func TestQuarantineGetMany(t *testing.T) {
+	ctx := context.Background()
+	d := dssync.MutexWrap(datastore.NewMapDatastore())
+	bs := blockstore.NewBlockstore(d)
+	dag := merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+
+	var cids []cid.Cid
+	for _, data := range []string{"first", "bad", "last"} {
+		nd := merkledag.NodeWithData([]byte(data))
+		if err := dag.Add(ctx, nd); err != nil {
+			t.Fatal(err)
+		}
+		cids = append(cids, nd.Cid())
+	}
+	bad := cids[1]
+	key := blockstore.BlockPrefix.Child(dshelp.MultihashToDsKey(bad.Hash()))
+	if err := d.Put(ctx, key, []byte("garbage")); err != nil {
+		t.Fatal(err)
+	}
+
+	qdag := New(d, bs, nil).Wrap(dag)
+	found := cid.NewSet()
+	var errs int
+	for opt := range qdag.GetMany(ctx, cids) {
+		if opt.Err != nil {
+			errs++
+			continue
+		}
+		found.Add(opt.Node.Cid())
+	}
+	if errs != 1 || found.Len() != 2 || found.Has(bad) {
+		t.Fatalf("expected the nodes around the bad block to be returned, and one error, got %d nodes and %d errors", found.Len(), errs)
+	}
+	if entries, err := List(ctx, d); err != nil || len(entries) != 1 || !entries[0].Cid.Equals(bad) {
+		t.Fatalf("expected the bad block to be quarantined, got %v, %v", entries, err)
+	}
+}
+
+func TestNilQuarantine(t *testing.T) {
+	var q *Quarantine
+	dag := merkledag.NewDAGService(blockservice.New(blockstore.NewBlockstore(datastore.NewMapDatastore()), nil))
+	if q.Wrap(dag) != dag {
+		t.Fatal("expected a nil quarantine to return the DAGService as is")
+	}
+}
@@ -1,6 +1,7 @@
 package fsrepo_test
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/ipfs/go-ipfs/plugin/loader"
 	"github.com/ipfs/go-ipfs/repo/fsrepo"
 
+	datastore "github.com/ipfs/go-datastore"
 	"github.com/ipfs/go-ipfs/config"
 )
 
@@ -234,3 +236,43 @@ func TestMeasureConfig(t *testing.T) {
 		t.Errorf("expected '*measure.measure' got '%s'", typ)
 	}
 }
+
+func TestMemConfigMaxSize(t *testing.T) {
+	ctx := context.Background()
+
+	dsc, err := fsrepo.AnyDatastoreConfig(map[string]interface{}{
+		"type":    "mem",
+		"maxSize": "10B",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := dsc.Create("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ds.Put(ctx, datastore.NewKey("a"), make([]byte, 6)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Put(ctx, datastore.NewKey("b"), make([]byte, 6)); err != fsrepo.ErrMemDatastoreFull {
+		t.Fatalf("expected ErrMemDatastoreFull, got %v", err)
+	}
+	// overwriting a value only counts the difference
+	if err := ds.Put(ctx, datastore.NewKey("a"), make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Delete(ctx, datastore.NewKey("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Put(ctx, datastore.NewKey("b"), make([]byte, 6)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fsrepo.AnyDatastoreConfig(map[string]interface{}{
+		"type":    "mem",
+		"maxSize": "lots",
+	}); err == nil {
+		t.Fatal("expected an invalid maxSize to be rejected")
+	}
+}
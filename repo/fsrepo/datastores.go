@@ -2,12 +2,15 @@ package fsrepo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 
 	"github.com/ipfs/go-ipfs/repo"
 
+	humanize "github.com/dustin/go-humanize"
 	ds "github.com/ipfs/go-datastore"
 	"github.com/ipfs/go-datastore/mount"
 	dssync "github.com/ipfs/go-datastore/sync"
@@ -30,6 +33,25 @@ type DatastoreConfig interface {
 	Create(path string) (repo.Datastore, error)
 }
 
+// ReadOnlyDatastoreConfig is implemented by the DatastoreConfigs able to open
+// their datastore without writing to the disk, for the read-only repos.
+type ReadOnlyDatastoreConfig interface {
+	DatastoreConfig
+
+	// CreateReadOnly opens the existing datastore read-only: it must not
+	// write to path, and the writes to the datastore fail.
+	CreateReadOnly(path string) (repo.Datastore, error)
+}
+
+// createReadOnly opens the datastore of c read-only, or fails if it can't be.
+func createReadOnly(c DatastoreConfig, path string) (repo.Datastore, error) {
+	roc, ok := c.(ReadOnlyDatastoreConfig)
+	if !ok {
+		return nil, fmt.Errorf("the %v datastore can't be opened read-only", c.DiskSpec()["type"])
+	}
+	return roc.CreateReadOnly(path)
+}
+
 // DiskSpec is a minimal representation of the characteristic values of the
 // datastore. If two diskspecs are the same, the loader assumes that they refer
 // to exactly the same datastore. If they differ at all, it is assumed they are
@@ -161,13 +183,46 @@ func (c *mountDatastoreConfig) Create(path string) (repo.Datastore, error) {
 	return mount.New(mounts), nil
 }
 
+func (c *mountDatastoreConfig) CreateReadOnly(path string) (repo.Datastore, error) {
+	mounts := make([]mount.Mount, len(c.mounts))
+	for i, m := range c.mounts {
+		ds, err := createReadOnly(m.ds, path)
+		if err != nil {
+			return nil, err
+		}
+		mounts[i].Datastore = ds
+		mounts[i].Prefix = m.prefix
+	}
+	return mount.New(mounts), nil
+}
+
 type memDatastoreConfig struct {
-	cfg map[string]interface{}
+	cfg     map[string]interface{}
+	maxSize uint64
 }
 
 // MemDatastoreConfig returns a memory DatastoreConfig from a spec
 func MemDatastoreConfig(params map[string]interface{}) (DatastoreConfig, error) {
-	return &memDatastoreConfig{params}, nil
+	c := &memDatastoreConfig{cfg: params}
+
+	switch maxSize := params["maxSize"].(type) {
+	case nil:
+	case string:
+		size, err := humanize.ParseBytes(maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'maxSize' field: %w", err)
+		}
+		c.maxSize = size
+	case float64:
+		if maxSize < 0 {
+			return nil, fmt.Errorf("'maxSize' field must not be negative")
+		}
+		c.maxSize = uint64(maxSize)
+	default:
+		return nil, fmt.Errorf("'maxSize' field must be a string or a number")
+	}
+
+	return c, nil
 }
 
 func (c *memDatastoreConfig) DiskSpec() DiskSpec {
@@ -175,9 +230,86 @@ func (c *memDatastoreConfig) DiskSpec() DiskSpec {
 }
 
 func (c *memDatastoreConfig) Create(string) (repo.Datastore, error) {
+	if c.maxSize > 0 {
+		return dssync.MutexWrap(newCappedMapDatastore(c.maxSize)), nil
+	}
 	return dssync.MutexWrap(ds.NewMapDatastore()), nil
 }
 
+// CreateReadOnly creates an in-memory datastore, as there is nothing on the
+// disk to be read.
+func (c *memDatastoreConfig) CreateReadOnly(path string) (repo.Datastore, error) {
+	return c.Create(path)
+}
+
+// ErrMemDatastoreFull is returned when a write would make an in-memory
+// datastore grow past its configured maxSize.
+var ErrMemDatastoreFull = errors.New("in-memory datastore is full")
+
+// cappedMapDatastore is a MapDatastore that refuses writes once the values
+// it holds would exceed maxSize bytes. It is not thread-safe on its own.
+type cappedMapDatastore struct {
+	*ds.MapDatastore
+
+	maxSize uint64
+	size    uint64
+}
+
+func newCappedMapDatastore(maxSize uint64) *cappedMapDatastore {
+	return &cappedMapDatastore{
+		MapDatastore: ds.NewMapDatastore(),
+		maxSize:      maxSize,
+	}
+}
+
+func (d *cappedMapDatastore) sizeOf(ctx context.Context, key ds.Key) (uint64, error) {
+	size, err := d.MapDatastore.GetSize(ctx, key)
+	switch err {
+	case nil:
+		return uint64(size), nil
+	case ds.ErrNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+func (d *cappedMapDatastore) Put(ctx context.Context, key ds.Key, value []byte) error {
+	old, err := d.sizeOf(ctx, key)
+	if err != nil {
+		return err
+	}
+	size := d.size - old + uint64(len(value))
+	if size > d.maxSize {
+		return ErrMemDatastoreFull
+	}
+	if err := d.MapDatastore.Put(ctx, key, value); err != nil {
+		return err
+	}
+	d.size = size
+	return nil
+}
+
+func (d *cappedMapDatastore) Delete(ctx context.Context, key ds.Key) error {
+	old, err := d.sizeOf(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := d.MapDatastore.Delete(ctx, key); err != nil {
+		return err
+	}
+	d.size -= old
+	return nil
+}
+
+func (d *cappedMapDatastore) Batch(ctx context.Context) (ds.Batch, error) {
+	return ds.NewBasicBatch(d), nil
+}
+
+func (d *cappedMapDatastore) DiskUsage(ctx context.Context) (uint64, error) {
+	return d.size, nil
+}
+
 type logDatastoreConfig struct {
 	child DatastoreConfig
 	name  string
@@ -209,6 +341,14 @@ func (c *logDatastoreConfig) Create(path string) (repo.Datastore, error) {
 	return ds.NewLogDatastore(child, c.name), nil
 }
 
+func (c *logDatastoreConfig) CreateReadOnly(path string) (repo.Datastore, error) {
+	child, err := createReadOnly(c.child, path)
+	if err != nil {
+		return nil, err
+	}
+	return ds.NewLogDatastore(child, c.name), nil
+}
+
 func (c *logDatastoreConfig) DiskSpec() DiskSpec {
 	return c.child.DiskSpec()
 }
@@ -246,3 +386,11 @@ func (c measureDatastoreConfig) Create(path string) (repo.Datastore, error) {
 	}
 	return measure.New(c.prefix, child), nil
 }
+
+func (c measureDatastoreConfig) CreateReadOnly(path string) (repo.Datastore, error) {
+	child, err := createReadOnly(c.child, path)
+	if err != nil {
+		return nil, err
+	}
+	return measure.New(c.prefix, child), nil
+}
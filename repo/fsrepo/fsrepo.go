@@ -8,11 +8,14 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 
 	filestore "github.com/ipfs/go-filestore"
 	keystore "github.com/ipfs/go-ipfs-keystore"
+	"github.com/ipfs/go-ipfs/journal"
 	repo "github.com/ipfs/go-ipfs/repo"
 	"github.com/ipfs/go-ipfs/repo/common"
 	dir "github.com/ipfs/go-ipfs/thirdparty/dir"
@@ -100,12 +103,17 @@ type FSRepo struct {
 	// path (see config.Filename for more details).
 	configFilePath string
 	// lockfile is the file system lock to prevent others from opening
-	// the same fsrepo path concurrently
+	// the same fsrepo path concurrently, nil when the repo is read-only
 	lockfile io.Closer
+	// readOnly is set when the repo was opened read-only
+	readOnly bool
 	config   *config.Config
 	ds       repo.Datastore
 	keystore keystore.Keystore
 	filemgr  *filestore.FileManager
+	// journal is the event journal of the repo, nil when disabled or when
+	// the repo is read-only
+	journal *journal.Journal
 }
 
 var _ repo.Repo = (*FSRepo)(nil)
@@ -114,7 +122,7 @@ var _ repo.Repo = (*FSRepo)(nil)
 // initialized.
 func Open(repoPath string) (repo.Repo, error) {
 	fn := func() (repo.Repo, error) {
-		return open(repoPath, "")
+		return open(repoPath, "", false)
 	}
 	return onlyOne.Open(repoPath, fn)
 }
@@ -123,12 +131,12 @@ func Open(repoPath string) (repo.Repo, error) {
 // option to set the configuration file path instead of using the default.
 func OpenWithUserConfig(repoPath string, userConfigFilePath string) (repo.Repo, error) {
 	fn := func() (repo.Repo, error) {
-		return open(repoPath, userConfigFilePath)
+		return open(repoPath, userConfigFilePath, false)
 	}
 	return onlyOne.Open(repoPath, fn)
 }
 
-func open(repoPath string, userConfigFilePath string) (repo.Repo, error) {
+func open(repoPath string, userConfigFilePath string, readOnly bool) (repo.Repo, error) {
 	packageLock.Lock()
 	defer packageLock.Unlock()
 
@@ -142,14 +150,26 @@ func open(repoPath string, userConfigFilePath string) (repo.Repo, error) {
 		return nil, err
 	}
 
-	r.lockfile, err = lockfile.Lock(r.path, LockFile)
-	if err != nil {
-		return nil, err
+	// a repo configured read-only is opened read-only by every command
+	r.readOnly = readOnly
+	if !r.readOnly {
+		conf, err := serialize.Load(r.configFilePath)
+		if err != nil {
+			return nil, err
+		}
+		r.readOnly = conf.Datastore.ReadOnly.WithDefault(false)
+	}
+
+	if !r.readOnly {
+		r.lockfile, err = lockfile.Lock(r.path, LockFile)
+		if err != nil {
+			return nil, err
+		}
 	}
 	keepLocked := false
 	defer func() {
 		// unlock on error, leave it locked on success
-		if !keepLocked {
+		if !keepLocked && r.lockfile != nil {
 			r.lockfile.Close()
 		}
 	}()
@@ -171,8 +191,10 @@ func open(repoPath string, userConfigFilePath string) (repo.Repo, error) {
 	}
 
 	// check repo path, then check all constituent parts.
-	if err := dir.Writable(r.path); err != nil {
-		return nil, err
+	if !r.readOnly {
+		if err := dir.Writable(r.path); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := r.openConfig(); err != nil {
@@ -183,6 +205,19 @@ func open(repoPath string, userConfigFilePath string) (repo.Repo, error) {
 		return nil, err
 	}
 
+	// the config fragments included from IPFS are read from the datastore
+	if len(r.config.Include) > 0 {
+		conf, err := serialize.LoadWithIncludes(r.configFilePath, r.readIPFSInclude)
+		if err != nil {
+			return nil, err
+		}
+		r.config = conf
+	}
+
+	if !r.readOnly {
+		r.journal = journal.New(r.ds, r.config.Journal)
+	}
+
 	if err := r.openKeystore(); err != nil {
 		return nil, err
 	}
@@ -194,6 +229,9 @@ func open(repoPath string, userConfigFilePath string) (repo.Repo, error) {
 	}
 
 	keepLocked = true
+	if r.readOnly {
+		return repo.ReadOnly(r), nil
+	}
 	return r, nil
 }
 
@@ -359,8 +397,13 @@ func (r *FSRepo) Path() string {
 	return r.path
 }
 
-// SetAPIAddr writes the API Addr to the /api file.
+// SetAPIAddr writes the API Addr to the /api file. Nothing is written to a
+// read-only repo, its clients have to be given the address.
 func (r *FSRepo) SetAPIAddr(addr ma.Multiaddr) error {
+	if r.readOnly {
+		return nil
+	}
+
 	// Create a temp file to write the address, so that we don't leave empty file when the
 	// program crashes after creating the file.
 	f, err := os.Create(filepath.Join(r.path, "."+apiFile+".tmp"))
@@ -436,9 +479,18 @@ func (r *FSRepo) openDatastore() error {
 			oldSpec, spec.String())
 	}
 
-	d, err := dsc.Create(r.path)
-	if err != nil {
-		return err
+	var d repo.Datastore
+	if r.readOnly {
+		d, err = createReadOnly(dsc, r.path)
+		if err != nil {
+			return err
+		}
+		d = newOverlayDatastore(d)
+	} else {
+		d, err = dsc.Create(r.path)
+		if err != nil {
+			return err
+		}
 	}
 	r.ds = d
 
@@ -470,9 +522,11 @@ func (r *FSRepo) Close() error {
 		return errors.New("repo is closed")
 	}
 
-	err := os.Remove(filepath.Join(r.path, apiFile))
-	if err != nil && !os.IsNotExist(err) {
-		log.Warn("error removing api file: ", err)
+	if !r.readOnly {
+		err := os.Remove(filepath.Join(r.path, apiFile))
+		if err != nil && !os.IsNotExist(err) {
+			log.Warn("error removing api file: ", err)
+		}
 	}
 
 	if err := r.ds.Close(); err != nil {
@@ -488,6 +542,9 @@ func (r *FSRepo) Close() error {
 	// logging.Configure(logging.Output(os.Stderr))
 
 	r.closed = true
+	if r.lockfile == nil {
+		return nil
+	}
 	return r.lockfile.Close()
 }
 
@@ -566,6 +623,13 @@ func (r *FSRepo) SetConfig(updated *config.Config) error {
 	if err != nil {
 		return err
 	}
+	if r.config != nil && len(r.config.Include) > 0 {
+		current, err := config.ToMap(r.config)
+		if err != nil {
+			return err
+		}
+		m = configChanges(current, m)
+	}
 	mergedMap := common.MapMergeDeep(mapconf, m)
 	if err := serialize.WriteConfigFile(r.configFilePath, mergedMap); err != nil {
 		return err
@@ -573,9 +637,26 @@ func (r *FSRepo) SetConfig(updated *config.Config) error {
 	// Do not use `*r.config = ...`. This will modify the *shared* config
 	// returned by `r.Config`.
 	r.config = updated
+
+	var changed []string
+	for k, v := range mergedMap {
+		if !reflect.DeepEqual(mapconf[k], v) {
+			changed = append(changed, k)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		r.recordConfigChange(strings.Join(changed, ","))
+	}
 	return nil
 }
 
+// recordConfigChange records a change of the given config keys in the event
+// journal. Values are not recorded, they may be secret.
+func (r *FSRepo) recordConfigChange(keys string) {
+	r.journal.Record(context.Background(), journal.TypeConfig, map[string]string{"keys": keys})
+}
+
 // GetConfigKey retrieves only the value of a particular key.
 func (r *FSRepo) GetConfigKey(key string) (interface{}, error) {
 	packageLock.Lock()
@@ -585,8 +666,8 @@ func (r *FSRepo) GetConfigKey(key string) (interface{}, error) {
 		return nil, errors.New("repo is closed")
 	}
 
-	var cfg map[string]interface{}
-	if err := serialize.ReadConfigFile(r.configFilePath, &cfg); err != nil {
+	cfg, err := serialize.ReadConfigMap(r.configFilePath, r.readIPFSInclude)
+	if err != nil {
 		return nil, err
 	}
 	return common.MapGetKV(cfg, key)
@@ -627,7 +708,11 @@ func (r *FSRepo) SetConfigKey(key string, value interface{}) error {
 
 	// This step doubles as to validate the map against the struct
 	// before serialization
-	conf, err := config.FromMap(mapconf)
+	merged, err := serialize.MergeIncludes(mapconf, r.configFilePath, r.readIPFSInclude)
+	if err != nil {
+		return err
+	}
+	conf, err := config.FromMap(merged)
 	if err != nil {
 		return err
 	}
@@ -637,6 +722,7 @@ func (r *FSRepo) SetConfigKey(key string, value interface{}) error {
 		return err
 	}
 
+	r.recordConfigChange(key)
 	return nil
 }
 
@@ -649,6 +735,13 @@ func (r *FSRepo) Datastore() repo.Datastore {
 	return d
 }
 
+// Journal returns the event journal of the repo, as enabled in the config
+// when the repo was opened. It is nil when the journal is disabled or the
+// repo is read-only.
+func (r *FSRepo) Journal() *journal.Journal {
+	return r.journal
+}
+
 // GetStorageUsage computes the storage space taken by the repo in bytes
 func (r *FSRepo) GetStorageUsage(ctx context.Context) (uint64, error) {
 	return ds.DiskUsage(ctx, r.Datastore())
@@ -3,14 +3,21 @@ package fsrepo
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/ipfs/go-ipfs/journal"
+	"github.com/ipfs/go-ipfs/repo"
 	"github.com/ipfs/go-ipfs/thirdparty/assert"
 
 	datastore "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	dssync "github.com/ipfs/go-datastore/sync"
 	config "github.com/ipfs/go-ipfs/config"
 )
 
@@ -117,3 +124,189 @@ func TestOpenMoreThanOnceInSameProcess(t *testing.T) {
 	assert.Nil(r1.Close(), t)
 	assert.Nil(r2.Close(), t)
 }
+
+func TestSetConfigKeepsIncludes(t *testing.T) {
+	t.Parallel()
+	path := testRepoPath("test", t)
+	assert.Nil(Init(path, &config.Config{Identity: config.Identity{PrivKey: "key"}, Datastore: config.DefaultDatastoreConfig()}), t)
+	base := filepath.Join(path, "base.json")
+	assert.Nil(ioutil.WriteFile(base, []byte(`{"Bootstrap": ["/base"]}`), 0600), t)
+
+	r, err := Open(path)
+	assert.Nil(err, t)
+	assert.Nil(r.SetConfigKey("Include", []interface{}{"base.json"}), t)
+	cfg, err := r.Config()
+	assert.Nil(err, t)
+	assert.True(len(cfg.Bootstrap) == 1 && cfg.Bootstrap[0] == "/base", t, "the included fragment should be merged")
+
+	updated, err := cfg.Clone()
+	assert.Nil(err, t)
+	updated.Gateway.NoFetch = true
+	assert.Nil(r.SetConfig(updated), t)
+	assert.Nil(r.Close(), t)
+
+	data, err := ioutil.ReadFile(filepath.Join(path, "config"))
+	assert.Nil(err, t)
+	assert.True(!bytes.Contains(data, []byte("/base")), t, "the included values should not be written to the config file")
+	assert.True(bytes.Contains(data, []byte(`"NoFetch": true`)), t, "the change should be written to the config file")
+}
+
+func TestConfigChangeJournal(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := testRepoPath("journal", t)
+	defer os.RemoveAll(path)
+	cfg := &config.Config{Identity: config.Identity{PrivKey: "key"}, Datastore: config.DefaultDatastoreConfig()}
+	cfg.Journal.Enabled = config.True
+	assert.Nil(Init(path, cfg), t)
+
+	r, err := Open(path)
+	assert.Nil(err, t)
+	j := r.Journal()
+	assert.True(j != nil, t, "the repo should open its journal")
+	assert.Nil(r.SetConfigKey("Gateway.NoFetch", true), t)
+	assert.Nil(r.SetConfigKey("Gateway.NoDNSLink", true), t)
+	assert.True(r.Journal() == j, t, "the config changes should be recorded in the journal of the repo")
+
+	entries, err := journal.Query(ctx, r.Datastore(), time.Time{}, []string{journal.TypeConfig})
+	assert.Nil(err, t)
+	assert.True(len(entries) == 2 && entries[0].Fields["keys"] == "Gateway.NoFetch" && entries[1].Fields["keys"] == "Gateway.NoDNSLink", t,
+		fmt.Sprintf("unexpected entries %v", entries))
+	assert.Nil(r.Close(), t)
+
+	r, err = OpenReadOnly(path)
+	assert.Nil(err, t)
+	assert.Nil(r.SetConfigKey("Gateway.NoFetch", false), t)
+	entries, err = journal.Query(ctx, r.Datastore(), time.Time{}, []string{journal.TypeConfig})
+	assert.Nil(err, t)
+	assert.True(len(entries) == 2, t, "a read-only repo should not record in the journal")
+	assert.Nil(r.Close(), t)
+}
+
+// snapshotDir records the files of the tree at path, with their size and the
+// time they were last modified.
+func snapshotDir(t *testing.T, path string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		files[p] = fmt.Sprint(fi.Mode(), fi.Size(), fi.ModTime().UnixNano())
+		return nil
+	})
+	assert.Nil(err, t)
+	return files
+}
+
+// chmodTree makes the tree at path read-only, or writable again.
+func chmodTree(t *testing.T, path string, writable bool) {
+	t.Helper()
+	err := filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(0400)
+		if fi.IsDir() {
+			mode = 0500
+		}
+		if writable {
+			mode |= 0200
+		}
+		return os.Chmod(p, mode)
+	})
+	assert.Nil(err, t)
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := testRepoPath("readonly", t)
+	defer os.RemoveAll(path)
+
+	assert.Nil(Init(path, &config.Config{Identity: config.Identity{PrivKey: "key"}, Datastore: config.DefaultDatastoreConfig()}), t)
+	stored, block := datastore.NewKey("/stored"), datastore.NewKey("/blocks/CIQAAAA")
+	r, err := Open(path)
+	assert.Nil(err, t)
+	assert.Nil(r.Datastore().Put(ctx, stored, []byte("stored")), t)
+	assert.Nil(r.Datastore().Put(ctx, block, []byte("block")), t)
+	assert.Nil(r.Close(), t)
+
+	chmodTree(t, path, false)
+	defer chmodTree(t, path, true)
+	before := snapshotDir(t, path)
+
+	r, err = OpenReadOnly(path)
+	assert.Nil(err, t, "the repo should be opened from a read-only directory")
+	locked, err := LockedByOtherProcess(path)
+	assert.Nil(err, t)
+	assert.True(!locked, t, "a read-only repo should not be locked")
+
+	d := r.Datastore()
+	value, err := d.Get(ctx, block)
+	assert.Nil(err, t)
+	assert.True(bytes.Equal(value, []byte("block")), t, "the blocks should be read")
+
+	written := datastore.NewKey("/written")
+	assert.Nil(d.Put(ctx, written, []byte("written")), t, "the node should still write its state")
+	assert.Nil(d.Delete(ctx, stored), t)
+	_, err = d.Get(ctx, stored)
+	assert.True(err == datastore.ErrNotFound, t, "a deleted key should not be found")
+	res, err := d.Query(ctx, query.Query{KeysOnly: true})
+	assert.Nil(err, t)
+	entries, err := res.Rest()
+	assert.Nil(err, t)
+	keys := make(map[string]bool)
+	for _, e := range entries {
+		keys[e.Key] = true
+	}
+	assert.True(keys[written.String()] && keys[block.String()] && !keys[stored.String()], t, "the query should list the keys written but not the ones deleted")
+
+	assert.True(r.Keystore().Put("key", nil) == repo.ErrReadOnly, t, "the keystore should refuse changes")
+	assert.Nil(r.Close(), t)
+
+	after := snapshotDir(t, path)
+	assert.True(reflect.DeepEqual(before, after), t, "nothing should be written to a read-only repo")
+
+	chmodTree(t, path, true)
+	r, err = Open(path)
+	assert.Nil(err, t)
+	_, err = r.Datastore().Get(ctx, written)
+	assert.True(err == datastore.ErrNotFound, t, "the writes should only be kept in memory")
+	_, err = r.Datastore().Get(ctx, stored)
+	assert.Nil(err, t, "the deletes should only be kept in memory")
+
+	// a repo configured read-only is opened read-only by Open too
+	assert.Nil(r.SetConfigKey("Datastore.ReadOnly", true), t)
+	assert.Nil(r.Close(), t)
+	r, err = Open(path)
+	assert.Nil(err, t)
+	locked, err = LockedByOtherProcess(path)
+	assert.Nil(err, t)
+	assert.True(!locked, t, "a repo configured read-only should not be locked")
+	assert.Nil(r.Close(), t)
+}
+
+func TestOverlayDatastoreBounded(t *testing.T) {
+	ctx := context.Background()
+	lower := dssync.MutexWrap(datastore.NewMapDatastore())
+	stored := datastore.NewKey("/stored")
+	assert.Nil(lower.Put(ctx, stored, []byte("stored")), t)
+	d := newOverlayDatastore(lower)
+
+	// the keys only written in memory are forgotten once deleted
+	for i := 0; i < 10; i++ {
+		k := datastore.NewKey(fmt.Sprintf("/written/%d", i))
+		assert.Nil(d.Put(ctx, k, []byte("written")), t)
+		assert.Nil(d.Delete(ctx, k), t)
+	}
+	assert.Nil(d.Delete(ctx, datastore.NewKey("/missing")), t)
+	assert.Nil(d.Delete(ctx, stored), t)
+	assert.True(len(d.deleted) == 1, t, "only the deletions of the stored keys should be recorded")
+
+	big := make([]byte, overlayMaxSize/2+1)
+	assert.Nil(d.Put(ctx, datastore.NewKey("/big/1"), big), t)
+	assert.True(d.Put(ctx, datastore.NewKey("/big/2"), big) == ErrMemDatastoreFull, t, "the writes past the maximum size should fail")
+	assert.Nil(d.Delete(ctx, datastore.NewKey("/big/1")), t)
+	assert.Nil(d.Put(ctx, datastore.NewKey("/big/2"), big), t, "deleting a key should free its size")
+}
@@ -0,0 +1,87 @@
+package fsrepo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	uio "github.com/ipfs/go-unixfs/io"
+)
+
+// maxIncludeSize bounds the size of a config fragment read from IPFS.
+const maxIncludeSize = 1 << 20
+
+// readIPFSInclude reads a config fragment included from an /ipfs/ path from
+// the blocks of the repo, without fetching them from the network.
+func (r *FSRepo) readIPFSInclude(p string) ([]byte, error) {
+	ctx := context.Background()
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(p, "/ipfs/"), "/"), "/")
+	c, err := cid.Decode(segments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bs := blockstore.NewIdStore(blockstore.NewBlockstore(r.ds))
+	dag := merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+	nd, err := dag.Get(ctx, c)
+	for _, name := range segments[1:] {
+		if err != nil {
+			break
+		}
+		var dir uio.Directory
+		if dir, err = uio.NewDirectoryFromNode(dag, nd); err == nil {
+			nd, err = dir.Find(ctx, name)
+		}
+	}
+	if ipld.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: the config fragments read from IPFS must be stored in the repo, e.g. pinned", err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	rd, err := uio.NewDagReader(ctx, nd, dag)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(rd, maxIncludeSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxIncludeSize {
+		return nil, fmt.Errorf("config fragment larger than %d bytes", maxIncludeSize)
+	}
+	return data, nil
+}
+
+// configChanges returns the values of updated that differ from current, so
+// that writing a config doesn't copy the values of the config fragments it
+// includes to the config file.
+func configChanges(current, updated map[string]interface{}) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for k, v := range updated {
+		cur, ok := current[k]
+		curMap, curIsMap := cur.(map[string]interface{})
+		vMap, vIsMap := v.(map[string]interface{})
+		switch {
+		case !ok:
+			changes[k] = v
+		case curIsMap && vIsMap:
+			if sub := configChanges(curMap, vMap); len(sub) > 0 {
+				changes[k] = sub
+			}
+		case !reflect.DeepEqual(cur, v):
+			changes[k] = v
+		}
+	}
+	return changes
+}
@@ -0,0 +1,188 @@
+package fsrepo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ipfs/go-ipfs/repo"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+)
+
+// OpenReadOnly opens the FSRepo at path read-only, as when its config sets
+// Datastore.ReadOnly: the repo is not locked and nothing is written to its
+// directory. The datastore is opened read-only, the writes of the node to it,
+// such as the routing records it keeps, are only kept in memory until the
+// repo is closed, up to overlayMaxSize bytes.
+func OpenReadOnly(repoPath string) (repo.Repo, error) {
+	fn := func() (repo.Repo, error) {
+		return open(repoPath, "", true)
+	}
+	return onlyOne.Open(repoPath, fn)
+}
+
+// overlayMaxSize bounds the size of the values written in memory in front of
+// a datastore opened read-only. Past it, writes fail with
+// ErrMemDatastoreFull.
+const overlayMaxSize = 64 << 20
+
+// overlayDatastore keeps the changes made to a datastore opened read-only in
+// memory, in front of it.
+type overlayDatastore struct {
+	lower repo.Datastore
+
+	lk    sync.RWMutex
+	upper *cappedMapDatastore
+	// deleted are the keys of lower deleted in memory
+	deleted map[ds.Key]struct{}
+}
+
+var _ repo.Datastore = (*overlayDatastore)(nil)
+
+func newOverlayDatastore(lower repo.Datastore) *overlayDatastore {
+	return &overlayDatastore{
+		lower:   lower,
+		upper:   newCappedMapDatastore(overlayMaxSize),
+		deleted: make(map[ds.Key]struct{}),
+	}
+}
+
+// lookup reports whether key was written or deleted in memory.
+func (d *overlayDatastore) lookup(ctx context.Context, key ds.Key) (written, deleted bool) {
+	d.lk.RLock()
+	defer d.lk.RUnlock()
+	if _, ok := d.deleted[key]; ok {
+		return false, true
+	}
+	written, _ = d.upper.Has(ctx, key)
+	return written, false
+}
+
+func (d *overlayDatastore) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	switch written, deleted := d.lookup(ctx, key); {
+	case deleted:
+		return nil, ds.ErrNotFound
+	case written:
+		d.lk.RLock()
+		defer d.lk.RUnlock()
+		return d.upper.Get(ctx, key)
+	}
+	return d.lower.Get(ctx, key)
+}
+
+func (d *overlayDatastore) Has(ctx context.Context, key ds.Key) (bool, error) {
+	switch written, deleted := d.lookup(ctx, key); {
+	case deleted:
+		return false, nil
+	case written:
+		return true, nil
+	}
+	return d.lower.Has(ctx, key)
+}
+
+func (d *overlayDatastore) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	switch written, deleted := d.lookup(ctx, key); {
+	case deleted:
+		return -1, ds.ErrNotFound
+	case written:
+		d.lk.RLock()
+		defer d.lk.RUnlock()
+		return d.upper.GetSize(ctx, key)
+	}
+	return d.lower.GetSize(ctx, key)
+}
+
+func (d *overlayDatastore) Put(ctx context.Context, key ds.Key, value []byte) error {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	delete(d.deleted, key)
+	return d.upper.Put(ctx, key, value)
+}
+
+// Delete only records the deletion of the keys of the datastore, the others
+// are just removed from memory.
+func (d *overlayDatastore) Delete(ctx context.Context, key ds.Key) error {
+	inLower, err := d.lower.Has(ctx, key)
+	if err != nil {
+		return err
+	}
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	if inLower {
+		d.deleted[key] = struct{}{}
+	}
+	return d.upper.Delete(ctx, key)
+}
+
+// Query returns the entries written in memory, then the ones of the datastore
+// neither written nor deleted in memory. The orders, offset and limit of q
+// apply to all of them.
+func (d *overlayDatastore) Query(ctx context.Context, q query.Query) (query.Results, error) {
+	inner := q
+	inner.Orders, inner.Offset, inner.Limit = nil, 0, 0
+
+	d.lk.RLock()
+	res, err := d.upper.Query(ctx, inner)
+	if err != nil {
+		d.lk.RUnlock()
+		return nil, err
+	}
+	entries, err := res.Rest()
+	hidden := make(hiddenKeys, len(entries)+len(d.deleted))
+	for k := range d.deleted {
+		hidden[k.String()] = struct{}{}
+	}
+	d.lk.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		hidden[e.Key] = struct{}{}
+	}
+
+	lower, err := d.lower.Query(ctx, inner)
+	if err != nil {
+		return nil, err
+	}
+	lower = query.NaiveFilter(lower, hidden)
+
+	merged := query.ResultsFromIterator(inner, query.Iterator{
+		Next: func() (query.Result, bool) {
+			if len(entries) > 0 {
+				e := entries[0]
+				entries = entries[1:]
+				return query.Result{Entry: e}, true
+			}
+			return lower.NextSync()
+		},
+		Close: lower.Close,
+	})
+	return query.NaiveQueryApply(query.Query{Orders: q.Orders, Offset: q.Offset, Limit: q.Limit}, merged), nil
+}
+
+// hiddenKeys filters out the entries of the datastore superseded in memory.
+type hiddenKeys map[string]struct{}
+
+func (h hiddenKeys) Filter(e query.Entry) bool {
+	_, ok := h[e.Key]
+	return !ok
+}
+
+func (d *overlayDatastore) Batch(ctx context.Context) (ds.Batch, error) {
+	return ds.NewBasicBatch(d), nil
+}
+
+func (d *overlayDatastore) Sync(ctx context.Context, prefix ds.Key) error {
+	return nil
+}
+
+// DiskUsage returns the disk usage of the datastore, the changes in memory
+// don't use any.
+func (d *overlayDatastore) DiskUsage(ctx context.Context) (uint64, error) {
+	return ds.DiskUsage(ctx, d.lower)
+}
+
+func (d *overlayDatastore) Close() error {
+	return d.lower.Close()
+}
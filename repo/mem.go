@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	ds "github.com/ipfs/go-datastore"
+	filestore "github.com/ipfs/go-filestore"
+	keystore "github.com/ipfs/go-ipfs-keystore"
+
+	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/journal"
+	"github.com/ipfs/go-ipfs/repo/common"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+var errMemRepoBackup = errors.New("cannot back up the config of an in-memory repo")
+
+// MemRepo is a Repo that lives entirely in memory. It backs ephemeral nodes:
+// nothing is read from or written to disk, and everything it holds is lost
+// once the process exits.
+type MemRepo struct {
+	lk      sync.Mutex
+	cfg     *config.Config
+	ds      Datastore
+	ks      keystore.Keystore
+	apiAddr ma.Multiaddr
+	journal *journal.Journal
+}
+
+var _ Repo = (*MemRepo)(nil)
+
+// NewMemRepo returns an in-memory repo using the given config and datastore.
+func NewMemRepo(cfg *config.Config, d Datastore) *MemRepo {
+	return &MemRepo{
+		cfg:     cfg,
+		ds:      d,
+		ks:      keystore.NewMemKeystore(),
+		journal: journal.New(d, cfg.Journal),
+	}
+}
+
+func (r *MemRepo) Config() (*config.Config, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.cfg, nil
+}
+
+func (r *MemRepo) BackupConfig(prefix string) (string, error) {
+	return "", errMemRepoBackup
+}
+
+func (r *MemRepo) SetConfig(updated *config.Config) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.cfg = updated
+	return nil
+}
+
+func (r *MemRepo) SetConfigKey(key string, value interface{}) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	mapconf, err := config.ToMap(r.cfg)
+	if err != nil {
+		return err
+	}
+
+	// Guard the private key against being overwritten, like FSRepo does.
+	pkval, err := common.MapGetKV(mapconf, config.PrivKeySelector)
+	if err != nil {
+		return err
+	}
+	if err := common.MapSetKV(mapconf, key, value); err != nil {
+		return err
+	}
+	if err := common.MapSetKV(mapconf, config.PrivKeySelector, pkval); err != nil {
+		return err
+	}
+
+	conf, err := config.FromMap(mapconf)
+	if err != nil {
+		return err
+	}
+	r.cfg = conf
+	return nil
+}
+
+func (r *MemRepo) GetConfigKey(key string) (interface{}, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	mapconf, err := config.ToMap(r.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return common.MapGetKV(mapconf, key)
+}
+
+func (r *MemRepo) Datastore() Datastore { return r.ds }
+
+func (r *MemRepo) GetStorageUsage(ctx context.Context) (uint64, error) {
+	return ds.DiskUsage(ctx, r.ds)
+}
+
+func (r *MemRepo) Keystore() keystore.Keystore { return r.ks }
+
+func (r *MemRepo) FileManager() *filestore.FileManager { return nil }
+
+func (r *MemRepo) SetAPIAddr(addr ma.Multiaddr) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.apiAddr = addr
+	return nil
+}
+
+func (r *MemRepo) SwarmKey() ([]byte, error) { return nil, nil }
+
+func (r *MemRepo) Journal() *journal.Journal { return r.journal }
+
+func (r *MemRepo) Close() error { return r.ds.Close() }
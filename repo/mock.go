@@ -3,11 +3,13 @@ package repo
 import (
 	"context"
 	"errors"
+	"sync"
 
 	filestore "github.com/ipfs/go-filestore"
 	keystore "github.com/ipfs/go-ipfs-keystore"
 
 	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/journal"
 	ma "github.com/multiformats/go-multiaddr"
 )
 
@@ -19,6 +21,9 @@ type Mock struct {
 	D Datastore
 	K keystore.Keystore
 	F *filestore.FileManager
+
+	journal     *journal.Journal
+	journalOnce sync.Once
 }
 
 func (m *Mock) Config() (*config.Config, error) {
@@ -57,3 +62,10 @@ func (m *Mock) SwarmKey() ([]byte, error) {
 }
 
 func (m *Mock) FileManager() *filestore.FileManager { return m.F }
+
+func (m *Mock) Journal() *journal.Journal {
+	m.journalOnce.Do(func() {
+		m.journal = journal.New(m.D, m.C.Journal)
+	})
+	return m.journal
+}
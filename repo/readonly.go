@@ -0,0 +1,48 @@
+package repo
+
+import (
+	keystore "github.com/ipfs/go-ipfs-keystore"
+	"github.com/ipfs/go-ipfs/journal"
+	ci "github.com/libp2p/go-libp2p-core/crypto"
+)
+
+// ReadOnly marks r as read-only: its keystore refuses the changes with
+// ErrReadOnly, and the node built on it refuses to change the blocks and the
+// pins. See fsrepo.OpenReadOnly to also leave the repo on disk untouched.
+func ReadOnly(r Repo) Repo {
+	if IsReadOnly(r) {
+		return r
+	}
+	return &readOnlyRepo{r}
+}
+
+// IsReadOnly returns whether r was marked as read-only with ReadOnly.
+func IsReadOnly(r Repo) bool {
+	_, ok := r.(*readOnlyRepo)
+	return ok
+}
+
+type readOnlyRepo struct {
+	Repo
+}
+
+func (r *readOnlyRepo) Keystore() keystore.Keystore {
+	return readOnlyKeystore{r.Repo.Keystore()}
+}
+
+// Journal returns nil, the events of a read-only repo are not recorded.
+func (r *readOnlyRepo) Journal() *journal.Journal {
+	return nil
+}
+
+type readOnlyKeystore struct {
+	keystore.Keystore
+}
+
+func (readOnlyKeystore) Put(string, ci.PrivKey) error {
+	return ErrReadOnly
+}
+
+func (readOnlyKeystore) Delete(string) error {
+	return ErrReadOnly
+}
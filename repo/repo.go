@@ -10,11 +10,16 @@ import (
 
 	ds "github.com/ipfs/go-datastore"
 	config "github.com/ipfs/go-ipfs/config"
+	"github.com/ipfs/go-ipfs/journal"
 	ma "github.com/multiformats/go-multiaddr"
 )
 
 var (
 	ErrApiNotRunning = errors.New("api not running")
+
+	// ErrReadOnly is returned by the operations changing the content of a
+	// read-only repo.
+	ErrReadOnly = errors.New("the repo is read-only")
 )
 
 // Repo represents all persistent data of a given ipfs node.
@@ -54,6 +59,10 @@ type Repo interface {
 	// SwarmKey returns the configured shared symmetric key for the private networks feature.
 	SwarmKey() ([]byte, error)
 
+	// Journal returns the event journal of the repo, nil when it is
+	// disabled.
+	Journal() *journal.Journal
+
 	io.Closer
 }
 
@@ -0,0 +1,206 @@
+// Package routingtrace records which routers answered the routing queries
+// of a request, to debug how the routers of the node are combined.
+//
+// Tracing is enabled per request by attaching a Trace to its context with
+// WithTrace. The routers wrapped with Wrap then record their queries in it;
+// they add nothing to the requests without a trace.
+package routingtrace
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	ci "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+)
+
+// Query is the query of a request to a router.
+type Query struct {
+	Router string
+	Method string
+	// Start is the time the router was queried, since the trace started.
+	Start time.Duration
+	// Latency is the time the router took to answer, or to find all its
+	// results.
+	Latency time.Duration
+	// Peers are the providers or the peer found by the router, in the order
+	// it found them.
+	Peers []peer.ID `json:",omitempty"`
+	// Values is the number of values found by the router.
+	Values int    `json:",omitempty"`
+	Error  string `json:",omitempty"`
+}
+
+// Trace records the queries of a request to the routers.
+type Trace struct {
+	start time.Time
+
+	mu      sync.Mutex
+	queries []*Query
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context recording the routing queries made with it in
+// the returned trace.
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	t := &Trace{start: time.Now()}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+func fromContext(ctx context.Context) *Trace {
+	t, _ := ctx.Value(traceKey{}).(*Trace)
+	return t
+}
+
+// Queries returns the queries recorded so far, in the order the routers were
+// queried.
+func (t *Trace) Queries() []Query {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	queries := make([]Query, len(t.queries))
+	for i, q := range t.queries {
+		queries[i] = *q
+		queries[i].Peers = append([]peer.ID(nil), q.Peers...)
+	}
+	return queries
+}
+
+func (t *Trace) begin(router, method string) *Query {
+	q := &Query{Router: router, Method: method, Start: time.Since(t.start)}
+	t.mu.Lock()
+	t.queries = append(t.queries, q)
+	t.mu.Unlock()
+	return q
+}
+
+func (t *Trace) found(q *Query, p peer.ID) {
+	t.mu.Lock()
+	q.Peers = append(q.Peers, p)
+	t.mu.Unlock()
+}
+
+func (t *Trace) end(q *Query, values int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	q.Latency = time.Since(t.start) - q.Start
+	q.Values += values
+	if errors.Is(err, routing.ErrNotSupported) {
+		q.Error = "not supported"
+	} else if err != nil {
+		q.Error = err.Error()
+	}
+}
+
+// Wrap returns r recording the queries made to it in the trace of their
+// context, under the name router.
+func Wrap(router string, r routing.Routing) routing.Routing {
+	return &tracedRouter{Routing: r, name: router}
+}
+
+type tracedRouter struct {
+	routing.Routing
+	name string
+}
+
+func (r *tracedRouter) FindProvidersAsync(ctx context.Context, c cid.Cid, count int) <-chan peer.AddrInfo {
+	t := fromContext(ctx)
+	if t == nil {
+		return r.Routing.FindProvidersAsync(ctx, c, count)
+	}
+
+	q := t.begin(r.name, "FindProviders")
+	in := r.Routing.FindProvidersAsync(ctx, c, count)
+	out := make(chan peer.AddrInfo)
+	go func() {
+		defer close(out)
+		defer t.end(q, 0, nil)
+		for p := range in {
+			t.found(q, p.ID)
+			select {
+			case out <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func (r *tracedRouter) FindPeer(ctx context.Context, id peer.ID) (peer.AddrInfo, error) {
+	t := fromContext(ctx)
+	if t == nil {
+		return r.Routing.FindPeer(ctx, id)
+	}
+
+	q := t.begin(r.name, "FindPeer")
+	ai, err := r.Routing.FindPeer(ctx, id)
+	if err == nil {
+		t.found(q, ai.ID)
+	}
+	t.end(q, 0, err)
+	return ai, err
+}
+
+func (r *tracedRouter) GetValue(ctx context.Context, key string, opts ...routing.Option) ([]byte, error) {
+	t := fromContext(ctx)
+	if t == nil {
+		return r.Routing.GetValue(ctx, key, opts...)
+	}
+
+	q := t.begin(r.name, "GetValue")
+	val, err := r.Routing.GetValue(ctx, key, opts...)
+	values := 0
+	if err == nil {
+		values = 1
+	}
+	t.end(q, values, err)
+	return val, err
+}
+
+func (r *tracedRouter) SearchValue(ctx context.Context, key string, opts ...routing.Option) (<-chan []byte, error) {
+	t := fromContext(ctx)
+	if t == nil {
+		return r.Routing.SearchValue(ctx, key, opts...)
+	}
+
+	q := t.begin(r.name, "SearchValue")
+	in, err := r.Routing.SearchValue(ctx, key, opts...)
+	if err != nil {
+		t.end(q, 0, err)
+		return nil, err
+	}
+	out := make(chan []byte)
+	go func() {
+		defer close(out)
+		values := 0
+		defer func() { t.end(q, values, nil) }()
+		for v := range in {
+			values++
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// GetPublicKey keeps using the public key lookup of the wrapped router, when
+// it has one.
+func (r *tracedRouter) GetPublicKey(ctx context.Context, id peer.ID) (ci.PubKey, error) {
+	return routing.GetPublicKey(r.Routing, ctx, id)
+}
+
+func (r *tracedRouter) Close() error {
+	if c, ok := r.Routing.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
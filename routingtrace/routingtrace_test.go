@@ -0,0 +1,76 @@
+package routingtrace
+
+import (
+	"context"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	routinghelpers "github.com/libp2p/go-libp2p-routing-helpers"
+)
+
+type providersRouter struct {
+	routinghelpers.Null
+	providers []peer.ID
+}
+
+func (r providersRouter) FindProvidersAsync(ctx context.Context, c cid.Cid, count int) <-chan peer.AddrInfo {
+	out := make(chan peer.AddrInfo, len(r.providers))
+	for _, p := range r.providers {
+		out <- peer.AddrInfo{ID: p}
+	}
+	close(out)
+	return out
+}
+
+func TestTrace(t *testing.T) {
+	c, err := cid.Decode("bafkqaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := routinghelpers.Tiered{Routers: []routing.Routing{
+		Wrap("first", providersRouter{providers: []peer.ID{"a", "b"}}),
+		Wrap("second", providersRouter{}),
+		Wrap("third", routinghelpers.Null{}),
+	}}
+
+	// Without a trace, the queries are not recorded.
+	for range r.FindProvidersAsync(context.Background(), c, 10) {
+	}
+
+	ctx, trace := WithTrace(context.Background())
+	found := 0
+	for range r.FindProvidersAsync(ctx, c, 10) {
+		found++
+	}
+	if found != 2 {
+		t.Fatalf("expected 2 providers, got %d", found)
+	}
+	if _, err := r.FindPeer(ctx, "a"); err == nil {
+		t.Fatal("expected the peer not to be found")
+	}
+
+	queries := trace.Queries()
+	providers := map[string]int{}
+	var findPeer []Query
+	for _, q := range queries {
+		switch q.Method {
+		case "FindProviders":
+			providers[q.Router] = len(q.Peers)
+		case "FindPeer":
+			findPeer = append(findPeer, q)
+		}
+	}
+	if len(providers) != 3 || providers["first"] != 2 || providers["second"] != 0 {
+		t.Fatalf("unexpected providers per router: %v", providers)
+	}
+	if len(findPeer) != 3 {
+		t.Fatalf("expected the 3 routers to be asked for the peer, got %d", len(findPeer))
+	}
+	for _, q := range findPeer {
+		if q.Error == "" {
+			t.Fatalf("expected router %s to report its error", q.Router)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Package scanner passes the files imported into the repo to an external
+// scanner, like an antivirus or a content policy service, which may reject
+// them before they are pinned and announced.
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	files "github.com/ipfs/go-ipfs-files"
+	logging "github.com/ipfs/go-log"
+
+	config "github.com/ipfs/go-ipfs/config"
+)
+
+var log = logging.Logger("scanner")
+
+// DefaultTimeout is the default time a scanner has to give its verdict on a
+// file.
+const DefaultTimeout = time.Minute
+
+// ErrRejected is the error returned for the content rejected by a scanner.
+var ErrRejected = errors.New("content rejected by the scanner")
+
+// Scanner checks content before it is committed to the repo.
+type Scanner interface {
+	// Scan reads the content of the file at name, its path within the
+	// import, and returns an error wrapping ErrRejected if the file must
+	// not be stored. Scan may return before reading all of r.
+	Scan(ctx context.Context, name string, r io.Reader) error
+}
+
+// HTTP is a Scanner posting the content to an HTTP endpoint. Status 2xx
+// accepts the content, 403 and 451 reject it, with the reason in the first
+// line of the body.
+type HTTP struct {
+	URL    string
+	Client *http.Client
+}
+
+// Scan implements Scanner.
+func (s *HTTP) Scan(ctx context.Context, name string, r io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if name != "" {
+		req.Header.Set("X-Ipfs-Path", name)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return nil
+	case res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusUnavailableForLegalReasons:
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		reason := strings.TrimSpace(strings.SplitN(string(body), "\n", 2)[0])
+		if reason == "" {
+			return ErrRejected
+		}
+		return fmt.Errorf("%w: %s", ErrRejected, reason)
+	default:
+		return fmt.Errorf("scanner returned %s", res.Status)
+	}
+}
+
+// FailOpen wraps s to accept the content when s fails for another reason
+// than rejecting it, like when the scanner is unreachable.
+func FailOpen(s Scanner) Scanner {
+	return failOpen{s}
+}
+
+type failOpen struct {
+	Scanner
+}
+
+func (s failOpen) Scan(ctx context.Context, name string, r io.Reader) error {
+	err := s.Scanner.Scan(ctx, name, r)
+	if err != nil && !errors.Is(err, ErrRejected) {
+		log.Errorf("accepting %q without scanning it: %s", name, err)
+		return nil
+	}
+	return err
+}
+
+// FromConfig returns the scanner of cfg, or nil when scanning is disabled.
+func FromConfig(cfg config.ContentScanner) Scanner {
+	if cfg.URL == "" {
+		return nil
+	}
+	var s Scanner = &HTTP{
+		URL:    cfg.URL,
+		Client: &http.Client{Timeout: cfg.Timeout.WithDefault(DefaultTimeout)},
+	}
+	if cfg.FailOpen.WithDefault(false) {
+		s = FailOpen(s)
+	}
+	return s
+}
+
+// Batch scans the files of an import while they are read by the importer.
+type Batch struct {
+	ctx     context.Context
+	scanner Scanner
+	async   bool
+
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	err error
+}
+
+// NewBatch returns a batch scanning files with s. Unless async is set, the
+// verdict on each file is returned by the reader of the file in place of
+// io.EOF, so that rejected files fail the import before it completes;
+// otherwise the verdicts are only returned by Wait.
+//
+// The scans of an async batch outlive the import, and use ctx rather than
+// the context of the import.
+func NewBatch(ctx context.Context, s Scanner, async bool) *Batch {
+	return &Batch{ctx: ctx, scanner: s, async: async}
+}
+
+// Wait waits for the scans of the batch, and returns the first error.
+func (b *Batch) Wait() error {
+	b.wg.Wait()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
+
+func (b *Batch) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil && b.err == nil {
+		b.err = err
+	}
+}
+
+// Reader returns a reader of r that passes its data to the scanner.
+func (b *Batch) Reader(name string, r io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+	sr := &reader{r: r, pw: pw, verdict: make(chan error, 1), async: b.async}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		err := b.scanner.Scan(b.ctx, name, pr)
+		if err != nil && name != "" {
+			err = fmt.Errorf("%s: %w", name, err)
+		}
+		// unblock the writes of the data the scanner did not read
+		pr.CloseWithError(errScanDone)
+		b.done(err)
+		sr.verdict <- err
+	}()
+	return sr
+}
+
+// Node returns nd, with the content of its files passed to the scanner.
+func (b *Batch) Node(name string, nd files.Node) files.Node {
+	switch nd := nd.(type) {
+	case files.Directory:
+		return &directory{Directory: nd, b: b, name: name}
+	case *files.Symlink:
+		return nd
+	case files.File:
+		f := &file{File: nd, r: b.Reader(name, nd)}
+		if info, ok := nd.(files.FileInfo); ok {
+			return &fileInfo{file: f, info: info}
+		}
+		return f
+	default:
+		return nd
+	}
+}
+
+var errScanDone = errors.New("scan done")
+
+// reader tees the data it reads to the scanner.
+type reader struct {
+	r       io.Reader
+	pw      *io.PipeWriter
+	verdict chan error
+	async   bool
+
+	// scanDone is set once the scanner stopped reading
+	scanDone bool
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 && !r.scanDone {
+		if _, werr := r.pw.Write(p[:n]); werr != nil {
+			r.scanDone = true
+		}
+	}
+	switch {
+	case err == io.EOF:
+		r.pw.Close()
+		if r.async {
+			return n, err
+		}
+		if verdict := <-r.verdict; verdict != nil {
+			r.verdict <- verdict
+			return n, verdict
+		}
+		r.verdict <- nil
+		return n, err
+	case err != nil:
+		r.pw.CloseWithError(err)
+	}
+	return n, err
+}
+
+type file struct {
+	files.File
+	r io.Reader
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+// Close ends the scan of the files that were not read to the end.
+func (f *file) Close() error {
+	if r, ok := f.r.(*reader); ok {
+		r.pw.CloseWithError(io.ErrUnexpectedEOF)
+	}
+	return f.File.Close()
+}
+
+// fileInfo keeps the path of files added with --nocopy.
+type fileInfo struct {
+	*file
+	info files.FileInfo
+}
+
+func (f *fileInfo) AbsPath() string {
+	return f.info.AbsPath()
+}
+
+func (f *fileInfo) Stat() os.FileInfo {
+	return f.info.Stat()
+}
+
+type directory struct {
+	files.Directory
+	b    *Batch
+	name string
+}
+
+func (d *directory) Entries() files.DirIterator {
+	return &dirIterator{DirIterator: d.Directory.Entries(), d: d}
+}
+
+type dirIterator struct {
+	files.DirIterator
+	d  *directory
+	nd files.Node
+}
+
+func (it *dirIterator) Next() bool {
+	it.nd = nil
+	return it.DirIterator.Next()
+}
+
+func (it *dirIterator) Node() files.Node {
+	if it.nd == nil {
+		it.nd = it.d.b.Node(path.Join(it.d.name, it.Name()), it.DirIterator.Node())
+	}
+	return it.nd
+}
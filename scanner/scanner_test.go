@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	files "github.com/ipfs/go-ipfs-files"
+)
+
+// newTestScanner rejects the files containing "virus".
+func newTestScanner(t *testing.T) *HTTP {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if bytes.Contains(data, []byte("virus")) {
+			http.Error(w, "found a virus in "+r.Header.Get("X-Ipfs-Path"), http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &HTTP{URL: srv.URL}
+}
+
+func TestHTTP(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScanner(t)
+
+	if err := s.Scan(ctx, "a", bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatal(err)
+	}
+	err := s.Scan(ctx, "b", bytes.NewReader([]byte("a virus")))
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected the content to be rejected, got %v", err)
+	}
+	if err.Error() != "content rejected by the scanner: found a virus in b" {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	down := &HTTP{URL: "http://127.0.0.1:1"}
+	if err := down.Scan(ctx, "a", bytes.NewReader([]byte("hello"))); err == nil || errors.Is(err, ErrRejected) {
+		t.Fatalf("expected the scan to fail, got %v", err)
+	}
+	if err := FailOpen(down).Scan(ctx, "a", bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatalf("expected the content to be accepted, got %v", err)
+	}
+	if err := FailOpen(s).Scan(ctx, "b", bytes.NewReader([]byte("a virus"))); !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected the content to be rejected, got %v", err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScanner(t)
+
+	// the verdict is returned in place of io.EOF
+	b := NewBatch(ctx, s, false)
+	data, err := ioutil.ReadAll(b.Reader("a", bytes.NewReader([]byte("hello"))))
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("expected to read hello, got %q, %v", data, err)
+	}
+	if _, err := ioutil.ReadAll(b.Reader("b", bytes.NewReader([]byte("a virus")))); !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected the content to be rejected, got %v", err)
+	}
+	if err := b.Wait(); !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected the batch to be rejected, got %v", err)
+	}
+
+	// the verdicts of async batches are only returned by Wait
+	dir := files.NewMapDirectory(map[string]files.Node{
+		"clean": files.NewBytesFile([]byte("hello")),
+		"sub": files.NewMapDirectory(map[string]files.Node{
+			"infected": files.NewBytesFile([]byte("a virus")),
+		}),
+	})
+	b = NewBatch(ctx, s, true)
+	if err := readAll(b.Node("", dir)); err != nil {
+		t.Fatal(err)
+	}
+	err = b.Wait()
+	if !errors.Is(err, ErrRejected) || err.Error() != "sub/infected: content rejected by the scanner: found a virus in sub/infected" {
+		t.Fatalf("expected sub/infected to be rejected, got %v", err)
+	}
+}
+
+// readAll reads the files of nd, like an importer.
+func readAll(nd files.Node) error {
+	return files.Walk(nd, func(fpath string, nd files.Node) error {
+		if f, ok := nd.(files.File); ok {
+			_, err := io.Copy(ioutil.Discard, f)
+			return err
+		}
+		return nil
+	})
+}
@@ -186,6 +186,41 @@ test_expect_success "/p2p addresses work" '
   [ $(ipfsi 0 swarm peers | wc -l) -eq 1 ]
 '
 
+test_expect_success "disconnect --all --dry-run keeps the connections" '
+  ipfsi 0 swarm disconnect --all --dry-run >actual &&
+  grep "$(iptb attr get 1 id).*(dry run)" actual &&
+  [ $(ipfsi 0 swarm peers | wc -l) -eq 1 ]
+'
+
+test_expect_success "disconnect --all filters the connections" '
+  ipfsi 0 swarm disconnect --all --tag=missing >actual &&
+  test_must_be_empty actual &&
+  ipfsi 0 swarm disconnect --all --transport=ws >actual &&
+  test_must_be_empty actual &&
+  ipfsi 0 swarm disconnect --all --direction=outbound --dry-run >actual &&
+  grep "$(iptb attr get 1 id)" actual &&
+  [ $(ipfsi 0 swarm peers | wc -l) -eq 1 ]
+'
+
+test_expect_success "disconnect --all --except-peering keeps the peering peers" '
+  ipfsi 0 swarm peering add "$(ipfsi 0 swarm peers)" &&
+  ipfsi 0 swarm disconnect --all --except-peering >actual &&
+  test_must_be_empty actual &&
+  ipfsi 0 swarm peering rm "$(iptb attr get 1 id)"
+'
+
+test_expect_success "disconnect --all closes every connection" '
+  ipfsi 0 swarm disconnect --all >actual &&
+  grep "$(iptb attr get 1 id).* success" actual &&
+  [ $(ipfsi 0 swarm peers | wc -l) -eq 0 ] &&
+  ipfsi 0 swarm connect "/p2p/$(iptb attr get 1 id)"
+'
+
+test_expect_success "disconnect filters require --all" '
+  test_must_fail ipfsi 0 swarm disconnect --dry-run "/p2p/$(iptb attr get 1 id)" 2>err &&
+  grep -- "--dry-run requires --all" err
+'
+
 test_expect_success "ipfs id is consistent for node 0" '
   ipfsi 1 id "$(iptb attr get 0 id)" > 1see0 &&
   ipfsi 0 id > 0see0 &&
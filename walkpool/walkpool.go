@@ -0,0 +1,266 @@
+// Package walkpool shares a budget of nodes fetched at once between the DAG
+// walks of the node, like pinning, refs, GC marking, dag stat and the
+// gateway, so that one huge walk doesn't starve the others. As the nodes
+// fetched at once are bounded, so is the memory they take.
+package walkpool
+
+import (
+	"context"
+	"sync"
+
+	cid "github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+)
+
+// Operations walking DAGs, sharing the pool fairly.
+const (
+	OpPin     = "pin"
+	OpRefs    = "refs"
+	OpGC      = "gc"
+	OpDagStat = "dag-stat"
+	OpDagWalk = "dag-walk"
+	OpGateway = "gateway"
+)
+
+// Pool bounds the nodes fetched at once by the walks. When walks wait for
+// the pool, it goes round-robin between their operations, the operations
+// holding less than their share first.
+//
+// Unless Internal.DAGWalkWorkers is set the node has no pool, and the
+// methods of the nil *Pool hand back the getters they are given.
+type Pool struct {
+	size int
+
+	mu   sync.Mutex
+	free int
+	held map[string]int
+	// waiting are the waiters of each operation, and order the operations
+	// waiting, in the order they are served
+	waiting map[string][]chan struct{}
+	order   []string
+}
+
+// New returns a pool of size nodes fetched at once.
+func New(size int) *Pool {
+	return &Pool{
+		size:    size,
+		free:    size,
+		held:    make(map[string]int),
+		waiting: make(map[string][]chan struct{}),
+	}
+}
+
+// acquire waits for a node of op to be fetched.
+func (p *Pool) acquire(ctx context.Context, op string) error {
+	p.mu.Lock()
+	if p.free > 0 && len(p.order) == 0 {
+		p.free--
+		p.held[op]++
+		p.mu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	if len(p.waiting[op]) == 0 {
+		p.order = append(p.order, op)
+	}
+	p.waiting[op] = append(p.waiting[op], ch)
+	p.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	q := p.waiting[op]
+	for i, w := range q {
+		if w == ch {
+			p.waiting[op] = append(q[:i:i], q[i+1:]...)
+			if len(p.waiting[op]) == 0 {
+				p.dequeue(op)
+			}
+			p.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+	p.mu.Unlock()
+	// served meanwhile
+	p.release(op)
+	return ctx.Err()
+}
+
+// acquireUpTo waits for a node of op to be fetched, then takes the places
+// free for up to n nodes without waiting. It returns the number of places
+// taken.
+func (p *Pool) acquireUpTo(ctx context.Context, op string, n int) (int, error) {
+	if err := p.acquire(ctx, op); err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	taken := 1
+	for ; taken < n && p.free > 0 && len(p.order) == 0; taken++ {
+		p.free--
+		p.held[op]++
+	}
+	return taken, nil
+}
+
+// release returns the node of op fetched to the pool, passing it to the next
+// waiter.
+func (p *Pool) release(op string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.held[op]--
+	if p.held[op] == 0 {
+		delete(p.held, op)
+	}
+	if len(p.order) == 0 {
+		p.free++
+		return
+	}
+
+	// the first operation under its share, or the first one
+	ops := len(p.held)
+	for _, o := range p.order {
+		if p.held[o] == 0 {
+			ops++
+		}
+	}
+	share := p.size / ops
+	next := p.order[0]
+	for _, o := range p.order {
+		if p.held[o] < share {
+			next = o
+			break
+		}
+	}
+	p.dequeue(next)
+	q := p.waiting[next]
+	ch := q[0]
+	if len(q) == 1 {
+		delete(p.waiting, next)
+	} else {
+		p.waiting[next] = q[1:]
+		p.order = append(p.order, next)
+	}
+	p.held[next]++
+	close(ch)
+}
+
+// dequeue removes op from the order of the operations waiting.
+func (p *Pool) dequeue(op string) {
+	for i, o := range p.order {
+		if o == op {
+			p.order = append(p.order[:i:i], p.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetLinks returns getLinks, fetching the nodes of op through the pool.
+func (p *Pool) GetLinks(op string, getLinks merkledag.GetLinks) merkledag.GetLinks {
+	if p == nil {
+		return getLinks
+	}
+	return func(ctx context.Context, c cid.Cid) ([]*format.Link, error) {
+		if err := p.acquire(ctx, op); err != nil {
+			return nil, err
+		}
+		defer p.release(op)
+		return getLinks(ctx, c)
+	}
+}
+
+// NodeGetter returns ng, fetching the nodes of op through the pool. The
+// nodes of a GetMany call take a single place in the pool.
+func (p *Pool) NodeGetter(op string, ng format.NodeGetter) format.NodeGetter {
+	if p == nil {
+		return ng
+	}
+	return &nodeGetter{ng: ng, p: p, op: op}
+}
+
+// DAGService returns ds, fetching the nodes of op through the pool.
+func (p *Pool) DAGService(op string, ds format.DAGService) format.DAGService {
+	if p == nil {
+		return ds
+	}
+	return &dagService{DAGService: ds, nodeGetter: nodeGetter{ng: ds, p: p, op: op}}
+}
+
+type nodeGetter struct {
+	ng format.NodeGetter
+	p  *Pool
+	op string
+}
+
+func (ng *nodeGetter) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	if err := ng.p.acquire(ctx, ng.op); err != nil {
+		return nil, err
+	}
+	defer ng.p.release(ng.op)
+	return ng.ng.Get(ctx, c)
+}
+
+// GetMany fetches cids in batches of the places free in the pool, each node
+// taking a place until it is fetched.
+func (ng *nodeGetter) GetMany(ctx context.Context, cids []cid.Cid) <-chan *format.NodeOption {
+	out := make(chan *format.NodeOption, len(cids))
+	go func() {
+		defer close(out)
+		for len(cids) > 0 {
+			n, err := ng.p.acquireUpTo(ctx, ng.op, len(cids))
+			if err != nil {
+				out <- &format.NodeOption{Err: err}
+				return
+			}
+			ok := ng.getBatch(ctx, cids[:n], out)
+			cids = cids[n:]
+			if !ok {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// getBatch fetches the nodes of batch, holding a place of the pool each.
+func (ng *nodeGetter) getBatch(ctx context.Context, batch []cid.Cid, out chan<- *format.NodeOption) bool {
+	held := len(batch)
+	defer func() {
+		for ; held > 0; held-- {
+			ng.p.release(ng.op)
+		}
+	}()
+	for opt := range ng.ng.GetMany(ctx, batch) {
+		out <- opt
+		if held > 0 {
+			ng.p.release(ng.op)
+			held--
+		}
+	}
+	return ctx.Err() == nil
+}
+
+// dagService fetches the nodes of a DAGService through the pool, its
+// sessions too.
+type dagService struct {
+	format.DAGService
+	nodeGetter
+}
+
+func (ds *dagService) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	return ds.nodeGetter.Get(ctx, c)
+}
+
+func (ds *dagService) GetMany(ctx context.Context, cids []cid.Cid) <-chan *format.NodeOption {
+	return ds.nodeGetter.GetMany(ctx, cids)
+}
+
+func (ds *dagService) Session(ctx context.Context) format.NodeGetter {
+	return ds.p.NodeGetter(ds.op, merkledag.NewSession(ctx, ds.DAGService))
+}
@@ -0,0 +1,172 @@
+package walkpool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	mdtest "github.com/ipfs/go-merkledag/test"
+)
+
+func TestPoolFairness(t *testing.T) {
+	ctx := context.Background()
+	p := New(2)
+	for i := 0; i < 2; i++ {
+		if err := p.acquire(ctx, OpPin); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var served []string
+	var wg sync.WaitGroup
+	wait := func(op string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p.acquire(ctx, op); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			served = append(served, op)
+			mu.Unlock()
+		}()
+		// queue the waiters in order
+		time.Sleep(20 * time.Millisecond)
+	}
+	wait(OpPin)
+	wait(OpPin)
+	wait(OpGC)
+
+	// the pin walk holds its share, so the GC is served first
+	p.release(OpPin)
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	if len(served) != 1 || served[0] != OpGC {
+		t.Fatalf("expected the GC to be served first, got %v", served)
+	}
+	mu.Unlock()
+
+	p.release(OpPin)
+	p.release(OpGC)
+	wg.Wait()
+	if len(served) != 3 {
+		t.Fatalf("expected every waiter to be served, got %v", served)
+	}
+}
+
+func TestPoolCancel(t *testing.T) {
+	p := New(1)
+	if err := p.acquire(context.Background(), OpPin); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.acquire(ctx, OpRefs); err == nil {
+		t.Fatal("expected an error once the context is done")
+	}
+	p.release(OpPin)
+	if err := p.acquire(context.Background(), OpRefs); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.order) != 0 || p.free != 0 {
+		t.Fatalf("expected the cancelled waiter to be gone, got %v waiting and %d free", p.order, p.free)
+	}
+}
+
+// countingDAG counts the nodes fetched at once.
+type countingDAG struct {
+	format.DAGService
+
+	mu        sync.Mutex
+	fetching  int
+	maxAtOnce int
+}
+
+func (d *countingDAG) fetch(n int) func() {
+	d.mu.Lock()
+	d.fetching += n
+	if d.fetching > d.maxAtOnce {
+		d.maxAtOnce = d.fetching
+	}
+	d.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	return func() {
+		d.mu.Lock()
+		d.fetching -= n
+		d.mu.Unlock()
+	}
+}
+
+func (d *countingDAG) Get(ctx context.Context, c cid.Cid) (format.Node, error) {
+	defer d.fetch(1)()
+	return d.DAGService.Get(ctx, c)
+}
+
+func (d *countingDAG) GetMany(ctx context.Context, cids []cid.Cid) <-chan *format.NodeOption {
+	done := d.fetch(len(cids))
+	out := make(chan *format.NodeOption, len(cids))
+	go func() {
+		defer close(out)
+		defer done()
+		for opt := range d.DAGService.GetMany(ctx, cids) {
+			out <- opt
+		}
+	}()
+	return out
+}
+
+func TestDAGService(t *testing.T) {
+	ctx := context.Background()
+	ds := mdtest.Mock()
+	root := merkledag.NodeWithData(nil)
+	var children []cid.Cid
+	for i := 0; i < 50; i++ {
+		child := merkledag.NodeWithData([]byte{byte(i)})
+		if err := ds.Add(ctx, child); err != nil {
+			t.Fatal(err)
+		}
+		if err := root.AddNodeLink(string(rune('a'+i)), child); err != nil {
+			t.Fatal(err)
+		}
+		children = append(children, child.Cid())
+	}
+	if err := ds.Add(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &countingDAG{DAGService: ds}
+	pooled := New(3).DAGService(OpPin, d)
+	if err := merkledag.FetchGraph(ctx, root.Cid(), pooled); err != nil {
+		t.Fatal(err)
+	}
+	if d.maxAtOnce > 3 {
+		t.Fatalf("expected at most 3 nodes fetched at once, got %d", d.maxAtOnce)
+	}
+
+	// the nodes of GetMany take a place each, until they are fetched
+	read := 0
+	for opt := range pooled.GetMany(ctx, children) {
+		if opt.Err != nil {
+			t.Fatal(opt.Err)
+		}
+		read++
+		time.Sleep(time.Millisecond)
+	}
+	if read != len(children) {
+		t.Fatalf("expected %d nodes, got %d", len(children), read)
+	}
+	if d.maxAtOnce > 3 {
+		t.Fatalf("expected at most 3 nodes fetched at once, got %d", d.maxAtOnce)
+	}
+
+	var nilPool *Pool
+	if nilPool.NodeGetter(OpGC, ds) != ds || nilPool.DAGService(OpPin, ds) != ds {
+		t.Fatal("expected a nil pool not to wrap")
+	}
+}